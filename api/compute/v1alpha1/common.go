@@ -24,12 +24,15 @@ import (
 	vpav1 "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/apis/autoscaling.k8s.io/v1"
 
 	"fmt"
+	"regexp"
 	"strings"
 
+	appsv1 "k8s.io/api/apps/v1"
 	autov2beta2 "k8s.io/api/autoscaling/v2beta2"
 
 	pctlutil "github.com/streamnative/pulsarctl/pkg/pulsar/utils"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/validation/field"
@@ -66,6 +69,11 @@ type PulsarMessaging struct {
 
 	// To replace the AuthSecret
 	AuthConfig *AuthConfig `json:"authConfig,omitempty"`
+
+	// PulsarConnection is the name of the PulsarConnection in the same namespace that provides the
+	// service URLs, TLS and auth settings, it cannot be used together with PulsarConfig. The components
+	// without PulsarConfig use the PulsarConnection named "default" of the namespace.
+	PulsarConnection string `json:"pulsarConnection,omitempty"`
 }
 
 type TLSConfig struct {
@@ -142,15 +150,74 @@ type PulsarStateStore struct {
 
 	// The state store config for Java runtime
 	JavaProvider *PulsarStateStoreJavaProvider `json:"javaProvider,omitempty"`
+
+	// TLSConfig enables TLS for the state store client of a custom Java provider, the certificate is mounted
+	// under /etc/tls/state-store and the settings are passed as the stateStorageTls* system properties
+	TLSConfig *StateStoreTLSConfig `json:"tlsConfig,omitempty"`
+
+	// AuthSecret is the secret with the clientAuthenticationPlugin and clientAuthenticationParameters used by
+	// the state store client of a custom Java provider, it is mounted under /etc/auth/state-store and the paths
+	// of the files are passed as the stateStorageAuthPluginFile and stateStorageAuthParamsFile system properties
+	AuthSecret string `json:"authSecret,omitempty"`
+
+	// Namespace overrides the state store namespace of the tables, which defaults to <tenant>_<namespace>,
+	// it is passed as the stateStorageNamespace system property to a custom Java provider
+	Namespace string `json:"namespace,omitempty"`
+
+	// Table overrides the name of the state table, which defaults to the name of the function, it is passed
+	// as the stateStorageTable system property to a custom Java provider
+	Table string `json:"table,omitempty"`
+
+	// PreflightCheck makes the controller check that the state store service is reachable, the result is
+	// reported by the StateStoreReady condition
+	PreflightCheck bool `json:"preflightCheck,omitempty"`
+}
+
+// StateStoreTLSConfig is the TLS config of the state store client
+type StateStoreTLSConfig struct {
+	TLSConfig `json:",inline"`
+}
+
+func (c *StateStoreTLSConfig) IsEnabled() bool {
+	return c.Enabled
+}
+
+func (c *StateStoreTLSConfig) AllowInsecureConnection() string {
+	return strconv.FormatBool(c.AllowInsecure)
+}
+
+func (c *StateStoreTLSConfig) EnableHostnameVerification() string {
+	return strconv.FormatBool(c.HostnameVerification)
+}
+
+func (c *StateStoreTLSConfig) SecretName() string {
+	return c.CertSecretName
+}
+
+func (c *StateStoreTLSConfig) SecretKey() string {
+	return c.CertSecretKey
+}
+
+func (c *StateStoreTLSConfig) HasSecretVolume() bool {
+	return c.CertSecretName != "" && c.CertSecretKey != ""
 }
 
+func (c *StateStoreTLSConfig) GetMountPath() string {
+	return "/etc/tls/state-store"
+}
+
+// DefaultStateStoreProviderClass is the state store provider of the Java runtime, backed by the BookKeeper
+// table service
+const DefaultStateStoreProviderClass = "org.apache.pulsar.functions.instance.state.BKStateStoreProviderImpl"
+
 type PulsarStateStoreJavaProvider struct {
 	// The java class name of the state store provider implementation
 	// The class must implement `org.apache.pulsar.functions.instance.state.StateStoreProvider` interface
 	// If not set, `org.apache.pulsar.functions.instance.state.BKStateStoreProviderImpl` will be used
 	ClassName string `json:"className"`
 
-	// The configmap of the configuration for the state store provider
+	// The configuration of a custom state store provider, each entry is passed as a system property of the
+	// JVM as the runtime only hands the service url to the provider
 	Config *Config `json:"config,omitempty"`
 }
 
@@ -200,6 +267,11 @@ type PodPolicy struct {
 	// +optional
 	ServiceAccountName string `json:"serviceAccountName,omitempty"`
 
+	// PodManagementPolicy is the pod management policy of the StatefulSet, the default is Parallel. The
+	// StatefulSet is recreated without its pods when it changes, the instance IDs follow the pod ordinals
+	// +kubebuilder:validation:Enum=OrderedReady;Parallel
+	PodManagementPolicy appsv1.PodManagementPolicyType `json:"podManagementPolicy,omitempty"`
+
 	// BuiltinAutoscaler refers to the built-in autoscaling rules
 	// Available values: AverageUtilizationCPUPercent80, AverageUtilizationCPUPercent50, AverageUtilizationCPUPercent20
 	// AverageUtilizationMemoryPercent80, AverageUtilizationMemoryPercent50, AverageUtilizationMemoryPercent20
@@ -244,6 +316,113 @@ type JavaRuntime struct {
 	ExtraDependenciesDir string            `json:"extraDependenciesDir,omitempty"`
 	Log                  *RuntimeLogConfig `json:"log,omitempty"`
 	JavaOpts             []string          `json:"javaOpts,omitempty"`
+	// Sha256 is the expected checksum of the package downloaded from the jarLocation
+	Sha256 string `json:"sha256,omitempty"`
+	// Dependencies are the locations of the jars added to the classpath, either packages of the package service,
+	// http or https urls, or configmap://<name>/<key> and secret://<name>/<key> keys. The downloader fetches them
+	// into /pulsar/java-dependencies, which is added to the classpath after the extraDependenciesDir
+	Dependencies []string `json:"dependencies,omitempty"`
+	// SharedLibraries are the names of the config maps of small jars, e.g. config jars, whose keys are added to
+	// the classpath
+	SharedLibraries []string `json:"sharedLibraries,omitempty"`
+	// JVM sizes the heap, direct memory and metaspace of the instance from the memory limit of the container,
+	// or from the memory request when no limit is set, and configures its garbage collector and diagnostics.
+	// Without it the heap is sized from the memory request
+	JVM *JVMOptions `json:"jvm,omitempty"`
+}
+
+// JVMGarbageCollector is the garbage collector of the JVM
+// +kubebuilder:validation:Enum=G1;Parallel;Serial;ZGC;Shenandoah
+type JVMGarbageCollector string
+
+const (
+	G1GC         JVMGarbageCollector = "G1"
+	ParallelGC   JVMGarbageCollector = "Parallel"
+	SerialGC     JVMGarbageCollector = "Serial"
+	ZGC          JVMGarbageCollector = "ZGC"
+	ShenandoahGC JVMGarbageCollector = "Shenandoah"
+)
+
+const (
+	// DefaultJVMHeapPercentage is the share of the memory used by the heap
+	DefaultJVMHeapPercentage int32 = 60
+	// DefaultSmallJVMHeapPercentage is the share of the memory used by the heap of instances with less than
+	// JVMSmallMemoryThreshold of memory, whose metaspace, code cache and thread stacks take a larger share
+	DefaultSmallJVMHeapPercentage int32 = 50
+	// DefaultJVMDirectMemoryPercentage is the share of the memory used by direct buffers
+	DefaultJVMDirectMemoryPercentage int32 = 20
+	// MaxJVMMemoryPercentage is the largest share of the memory used by the heap and direct buffers together,
+	// the rest is left to the metaspace, code cache and thread stacks
+	MaxJVMMemoryPercentage int32 = 90
+)
+
+// JVMSmallMemoryThreshold is the memory below which the serial collector is used by default
+var JVMSmallMemoryThreshold = resource.MustParse("1Gi")
+
+// JVMOptions contains the memory, garbage collection and diagnostics options of the JVM
+// +kubebuilder:validation:Optional
+type JVMOptions struct {
+	// HeapPercentage is the share of the memory used by the heap, 50 below 1Gi of memory and 60 above by default
+	// +kubebuilder:validation:Minimum=10
+	// +kubebuilder:validation:Maximum=90
+	HeapPercentage *int32 `json:"heapPercentage,omitempty"`
+	// DirectMemoryPercentage is the share of the memory used by direct buffers, e.g. the netty buffers of the
+	// pulsar client, 20 by default
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=80
+	DirectMemoryPercentage *int32 `json:"directMemoryPercentage,omitempty"`
+	// MaxMetaspaceSize bounds the metaspace, unbounded by default
+	MaxMetaspaceSize *resource.Quantity `json:"maxMetaspaceSize,omitempty"`
+	// GC is the garbage collector, the serial collector below 1Gi of memory and G1 above by default.
+	// ZGC and Shenandoah need a runner image with a JDK supporting them
+	GC JVMGarbageCollector `json:"gc,omitempty"`
+	// GCLogging writes the garbage collection logs next to the instance logs
+	GCLogging bool `json:"gcLogging,omitempty"`
+	// HeapDumpPath is the directory the heap is dumped to when the JVM runs out of memory, it must be on a
+	// writable volume mount of the instance
+	HeapDumpPath string `json:"heapDumpPath,omitempty"`
+}
+
+// GetHeapPercentage returns the share of the memory used by the heap
+func (o *JVMOptions) GetHeapPercentage(memory resource.Quantity) int32 {
+	if o.HeapPercentage != nil {
+		return *o.HeapPercentage
+	}
+	if memory.Cmp(JVMSmallMemoryThreshold) < 0 {
+		return DefaultSmallJVMHeapPercentage
+	}
+	return DefaultJVMHeapPercentage
+}
+
+// GetDirectMemoryPercentage returns the share of the memory used by direct buffers
+func (o *JVMOptions) GetDirectMemoryPercentage() int32 {
+	if o.DirectMemoryPercentage != nil {
+		return *o.DirectMemoryPercentage
+	}
+	return DefaultJVMDirectMemoryPercentage
+}
+
+// GetGC returns the garbage collector
+func (o *JVMOptions) GetGC(memory resource.Quantity) JVMGarbageCollector {
+	if o.GC != "" {
+		return o.GC
+	}
+	if memory.Cmp(JVMSmallMemoryThreshold) < 0 {
+		return SerialGC
+	}
+	return G1GC
+}
+
+// GetJVMMemory returns the memory the JVM is sized from, the memory limit of the container or its memory request
+// when no limit is set
+func GetJVMMemory(resources corev1.ResourceRequirements) resource.Quantity {
+	if limit, ok := resources.Limits[corev1.ResourceMemory]; ok && !limit.IsZero() {
+		return limit
+	}
+	if request, ok := resources.Requests[corev1.ResourceMemory]; ok {
+		return request
+	}
+	return resource.Quantity{}
 }
 
 // PythonRuntime contains the python runtime configs
@@ -253,6 +432,47 @@ type PythonRuntime struct {
 	Py         string            `json:"py"`
 	PyLocation string            `json:"pyLocation,omitempty"`
 	Log        *RuntimeLogConfig `json:"log,omitempty"`
+	// Sha256 is the expected checksum of the package downloaded from the pyLocation
+	Sha256 string `json:"sha256,omitempty"`
+	// Requirements are the pip requirement specifiers installed before the function starts, e.g. requests==2.28.1
+	Requirements []string `json:"requirements,omitempty"`
+	// DependencyRepository is the pip index url the dependencies are installed from, e.g. an internal PyPI mirror
+	DependencyRepository string `json:"dependencyRepository,omitempty"`
+	// ExtraDependencyRepository is an additional pip index url the dependencies are installed from
+	ExtraDependencyRepository string `json:"extraDependencyRepository,omitempty"`
+	// DependenciesLocation is the location of a pre-built package of the dependencies, which is installed
+	// without access to a pip index unless a dependency repository is set
+	DependenciesLocation string `json:"dependenciesLocation,omitempty"`
+	// DependenciesType is the kind of the dependencies package, it is inferred for the .whl packages
+	DependenciesType PythonDependenciesType `json:"dependenciesType,omitempty"`
+}
+
+// PythonDependenciesType is the kind of a pre-built package of python dependencies
+// +kubebuilder:validation:Enum=wheel;wheelhouse;virtualenv
+type PythonDependenciesType string
+
+const (
+	// PythonDependenciesWheel is a single wheel file
+	PythonDependenciesWheel PythonDependenciesType = "wheel"
+	// PythonDependenciesWheelhouse is a zip of wheels, the requirements are installed from it, or all of its
+	// wheels when there are no requirements
+	PythonDependenciesWheelhouse PythonDependenciesType = "wheelhouse"
+	// PythonDependenciesVirtualenv is a zip of the site-packages directory of a virtualenv
+	PythonDependenciesVirtualenv PythonDependenciesType = "virtualenv"
+)
+
+// HasDependencies returns whether the dependencies are installed before the function starts, instead of by the
+// python instance from the requirements of the package
+func (p *PythonRuntime) HasDependencies() bool {
+	return len(p.Requirements) > 0 || p.DependenciesLocation != ""
+}
+
+// GetDependenciesType returns the kind of the dependencies package
+func (p *PythonRuntime) GetDependenciesType() PythonDependenciesType {
+	if p.DependenciesType == "" && strings.HasSuffix(strings.ToLower(p.DependenciesLocation), ".whl") {
+		return PythonDependenciesWheel
+	}
+	return p.DependenciesType
 }
 
 // GoRuntime contains the golang runtime configs
@@ -262,6 +482,8 @@ type GoRuntime struct {
 	Go         string            `json:"go"`
 	GoLocation string            `json:"goLocation,omitempty"`
 	Log        *RuntimeLogConfig `json:"log,omitempty"`
+	// Sha256 is the expected checksum of the package downloaded from the goLocation
+	Sha256 string `json:"sha256,omitempty"`
 }
 
 type SecretRef struct {
@@ -337,13 +559,28 @@ const (
 	Service     Component = "Service"
 	HPA         Component = "HorizontalPodAutoscaler"
 	VPA         Component = "VerticalPodAutoscaler"
+	Pause       Component = "Pause"
+	RetryTopics Component = "RetryTopics"
+	StateStore  Component = "StateStore"
 )
 
 // The `Status` of a given `Condition` and the `Action` needed to reach the `Status`
+// PauseStatus records the replicas of a paused workload, so that they can be restored on resume
+type PauseStatus struct {
+	// PausedReplicas is the replica count recorded when the workload was paused
+	PausedReplicas *int32 `json:"pausedReplicas,omitempty"`
+	// PausedSpecReplicas is the spec replica count when the workload was paused, the recorded replica count
+	// is only restored if the spec still has this value on resume
+	PausedSpecReplicas *int32 `json:"pausedSpecReplicas,omitempty"`
+}
+
 type ResourceCondition struct {
 	Condition ResourceConditionType  `json:"condition,omitempty"`
 	Status    metav1.ConditionStatus `json:"status,omitempty"`
 	Action    ReconcileAction        `json:"action,omitempty"`
+	// Reason and Message explain why the resource does not become ready
+	Reason  string `json:"reason,omitempty"`
+	Message string `json:"message,omitempty"`
 }
 
 type ResourceConditionType string
@@ -360,8 +597,28 @@ const (
 	ServiceReady     ResourceConditionType = "ServiceReady"
 	HPAReady         ResourceConditionType = "HPAReady"
 	VPAReady         ResourceConditionType = "VPAReady"
+
+	Paused           ResourceConditionType = "Paused"
+	RetryTopicsReady ResourceConditionType = "RetryTopicsReady"
+	StateStoreReady  ResourceConditionType = "StateStoreReady"
 )
 
+// StateStoreUnreachable is the reason of a StateStoreReady condition when the state store service cannot be
+// reached by the controller
+const StateStoreUnreachable string = "StateStoreUnreachable"
+
+// RetryTopicsProvisionFailed is the reason of a RetryTopicsReady condition when the retry topics cannot be
+// provisioned through the Pulsar admin API
+const RetryTopicsProvisionFailed string = "RetryTopicsProvisionFailed"
+
+// PackageVerificationFailed is the reason of a StatefulSet condition when the downloaded package does not
+// match the expected checksum
+const PackageVerificationFailed string = "PackageVerificationFailed"
+
+// StateRestorePending is the reason of a StatefulSet condition while a FunctionStateSnapshot restores the state
+// of the function, the StatefulSet is not created or is scaled down to zero until the restore finishes
+const StateRestorePending string = "StateRestorePending"
+
 type ReconcileAction string
 
 const (
@@ -414,11 +671,18 @@ const (
 	SourceComponent   string = "source"
 	SinkComponent     string = "sink"
 
-	PackageURLHTTP     string = "http://"
-	PackageURLHTTPS    string = "https://"
-	PackageURLFunction string = "function://"
-	PackageURLSource   string = "source://"
-	PackageURLSink     string = "sink://"
+	PackageURLHTTP      string = "http://"
+	PackageURLHTTPS     string = "https://"
+	PackageURLFunction  string = "function://"
+	PackageURLSource    string = "source://"
+	PackageURLSink      string = "sink://"
+	PackageURLOCI       string = "oci://"
+	PackageURLConfigMap string = "configmap://"
+	PackageURLSecret    string = "secret://"
+
+	// PackageURLImage is reserved for the packages mounted as Kubernetes image volumes, they are rejected as the
+	// image volumes are not part of the Kubernetes API version the operator is built with
+	PackageURLImage string = "image://"
 )
 
 // Config represents untyped YAML configuration.
@@ -456,14 +720,23 @@ func (c *Config) DeepCopyInto(out *Config) {
 }
 
 func validPackageLocation(packageLocation string) error {
+	if strings.HasPrefix(strings.ToLower(packageLocation), PackageURLImage) {
+		return fmt.Errorf("image volume package %s is not supported, use an %s package pulled by the downloader "+
+			"instead", packageLocation, PackageURLOCI)
+	}
 	if hasPackageTypePrefix(packageLocation) {
 		err := isValidPulsarPackageURL(packageLocation)
 		if err != nil {
 			return err
 		}
+	} else if strings.HasPrefix(strings.ToLower(packageLocation), PackageURLOCI) {
+		err := isValidOCIPackageURL(packageLocation)
+		if err != nil {
+			return err
+		}
 	} else {
 		if !isFunctionPackageURLSupported(packageLocation) {
-			return fmt.Errorf("invalid function package url %s, supported url (http/https)", packageLocation)
+			return fmt.Errorf("invalid function package url %s, supported url (http/https/oci)", packageLocation)
 		}
 	}
 
@@ -500,6 +773,19 @@ func isValidPulsarPackageURL(packageLocation string) error {
 	return nil
 }
 
+// ociReferencePattern matches the artifact references pinned to a tag or a digest, e.g.
+// registry.example.com:5000/team/function:v1
+var ociReferencePattern = regexp.MustCompile(`^[a-z0-9]([a-z0-9.-]*[a-z0-9])?(:[0-9]+)?(/[a-z0-9]+([._-][a-z0-9]+)*)+` +
+	`(:[A-Za-z0-9_][A-Za-z0-9_.-]{0,127}|@sha256:[a-f0-9]{64})$`)
+
+func isValidOCIPackageURL(packageLocation string) error {
+	if !ociReferencePattern.MatchString(packageLocation[len(PackageURLOCI):]) {
+		return fmt.Errorf("invalid oci package %s, the format is oci://registry/repository:tag or "+
+			"oci://registry/repository@sha256:digest", packageLocation)
+	}
+	return nil
+}
+
 func isFunctionPackageURLSupported(packageLocation string) bool {
 	// TODO: support file:// schema
 	lowerCase := strings.ToLower(packageLocation)
@@ -603,10 +889,29 @@ const (
 // +kubebuilder:validation:Enum=TimedPolicyWithDaily;TimedPolicyWithWeekly;TimedPolicyWithMonthly;SizedPolicyWith10MB;SizedPolicyWith50MB;SizedPolicyWith100MB
 type TriggeringPolicy string
 
+// LogFormat is the format of the instance logs
+// +kubebuilder:validation:Enum=text;json
+type LogFormat string
+
+const (
+	LogFormatText LogFormat = "text"
+	LogFormatJSON LogFormat = "json"
+)
+
 type RuntimeLogConfig struct {
 	Level        LogLevel          `json:"level,omitempty"`
 	RotatePolicy *TriggeringPolicy `json:"rotatePolicy,omitempty"`
 	LogConfig    *LogConfig        `json:"logConfig,omitempty"`
+	// Format is the format of the instance logs, the default is text
+	Format LogFormat `json:"format,omitempty"`
+	// Pattern is the layout of the text logs, a log4j pattern for the Java runtime and a logging format for the
+	// Python runtime
+	Pattern string `json:"pattern,omitempty"`
+	// Loggers are the levels of the named loggers, available for the Java and Python runtime
+	Loggers map[string]LogLevel `json:"loggers,omitempty"`
+	// StdoutWithLogTopic keeps writing the instance logs to the standard output when they are shipped to the log
+	// topic, available for the Python runtime without a logConfig, the Java and Go runtime always write to both
+	StdoutWithLogTopic bool `json:"stdoutWithLogTopic,omitempty"`
 }
 
 type LogConfig struct {
@@ -614,8 +919,72 @@ type LogConfig struct {
 	Key  string `json:"key"`
 }
 
+// RetryPolicy defines how the messages failed to be processed are redelivered, it takes
+// precedence over the `maxMessageRetry` and `deadLetterTopic` fields
+type RetryPolicy struct {
+	// MaxMessageRetry is the number of redeliveries before a message is sent to the dead letter topic
+	// +kubebuilder:validation:Minimum=0
+	MaxMessageRetry int32 `json:"maxMessageRetry,omitempty"`
+
+	// NegativeAckRedeliveryDelayMs is the delay before a negatively acknowledged message is redelivered
+	// +kubebuilder:validation:Minimum=0
+	NegativeAckRedeliveryDelayMs int32 `json:"negativeAckRedeliveryDelayMs,omitempty"`
+
+	// DeadLetterTopic receives the messages which exceeded the max message retry
+	DeadLetterTopic *RetryTopic `json:"deadLetterTopic,omitempty"`
+
+	// RetryLetterTopic is only provisioned, it can be used by the user code to schedule delayed retries
+	RetryLetterTopic *RetryTopic `json:"retryLetterTopic,omitempty"`
+}
+
+// RetryTopic defines a topic used by the retry policy
+type RetryTopic struct {
+	// +kubebuilder:validation:Required
+	Name string `json:"name"`
+
+	// Provision creates the topic through the Pulsar admin API when it doesn't exist
+	Provision bool `json:"provision,omitempty"`
+
+	// Partitions of the provisioned topic, a non-partitioned topic is created by default
+	// +kubebuilder:validation:Minimum=0
+	Partitions int32 `json:"partitions,omitempty"`
+
+	// Retention of the provisioned topic, the namespace retention applies when not set
+	Retention *TopicRetention `json:"retention,omitempty"`
+
+	// Schema of the provisioned topic, the first producer registers the schema when not set
+	Schema *TopicSchema `json:"schema,omitempty"`
+}
+
+type TopicRetention struct {
+	RetentionTimeInMinutes int32 `json:"retentionTimeInMinutes"`
+	RetentionSizeInMB      int64 `json:"retentionSizeInMB"`
+}
+
+// TopicSchema defines the schema registered for a provisioned topic
+type TopicSchema struct {
+	// Type of the schema, e.g. AVRO, JSON or STRING
+	// +kubebuilder:validation:Required
+	Type string `json:"type"`
+
+	// Definition of the schema, e.g. the AVRO schema of the records, empty for the primitive types
+	Definition string `json:"definition,omitempty"`
+
+	Properties map[string]string `json:"properties,omitempty"`
+}
+
+// The defaults of the window config, they match the window function executor
+const (
+	DefaultWindowMaxLagMs                int64 = 0
+	DefaultWindowWatermarkEmitIntervalMs int64 = 1000
+)
+
+// WindowConfig runs the function on windows of messages. The windows are counted in messages or timed in
+// milliseconds, they tumble unless a sliding interval is set. With a timestamp extractor the windows are timed
+// by the timestamps of the messages, the messages later than the watermark are published to the late data topic.
 type WindowConfig struct {
-	ActualWindowFunctionClassName string  `json:"actualWindowFunctionClassName"`
+	// ActualWindowFunctionClassName is set to the className of the function by the controller
+	ActualWindowFunctionClassName string  `json:"actualWindowFunctionClassName,omitempty"`
 	WindowLengthCount             *int32  `json:"windowLengthCount,omitempty"`
 	WindowLengthDurationMs        *int64  `json:"windowLengthDurationMs,omitempty"`
 	SlidingIntervalCount          *int32  `json:"slidingIntervalCount,omitempty"`
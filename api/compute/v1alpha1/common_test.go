@@ -0,0 +1,57 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package v1alpha1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidPackageLocation(t *testing.T) {
+	validLocations := []string{
+		"function://public/default/test@v1",
+		"sink://public/default/test",
+		"http://example.com/test.jar",
+		"https://example.com/test.jar",
+		"oci://registry.example.com/team/function:v1",
+		"oci://registry.example.com:5000/function:1.0.0-rc1",
+		"oci://ghcr.io/team/functions/test@sha256:2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824",
+	}
+	for _, location := range validLocations {
+		assert.Nil(t, validPackageLocation(location), location)
+	}
+
+	invalidLocations := []string{
+		"function://public/test@v1",
+		"ftp://example.com/test.jar",
+		"oci://registry.example.com/team/function",
+		"oci://registry.example.com/team/function:",
+		"oci://function:v1",
+		"oci://registry.example.com/Team/function:v1",
+		"oci://registry.example.com/team/function@sha256:abc",
+		"oci://registry.example.com/team/function:v1;rm -rf /",
+	}
+	for _, location := range invalidLocations {
+		assert.NotNil(t, validPackageLocation(location), location)
+	}
+
+	err := validPackageLocation("image://registry.example.com/team/function:v1")
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "image volume package image://registry.example.com/team/function:v1 is not supported")
+}
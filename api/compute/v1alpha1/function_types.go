@@ -67,6 +67,15 @@ type FunctionSpec struct {
 	DeadLetterTopic              string           `json:"deadLetterTopic,omitempty"`
 	ForwardSourceMessageProperty *bool            `json:"forwardSourceMessageProperty,omitempty"`
 	MaxPendingAsyncRequests      *int32           `json:"maxPendingAsyncRequests,omitempty"`
+	// MaxBufferedTuples is the maximum number of the tuples buffered by the instance, the default is 100
+	// +kubebuilder:validation:Minimum=1
+	MaxBufferedTuples *int32 `json:"maxBufferedTuples,omitempty"`
+	// FunctionVersion is the version reported by the instance in its metrics and state, it is derived from the
+	// checksum of the package, or from the generation of the resource without a checksum, when it is not set
+	FunctionVersion string `json:"functionVersion,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	RetryPolicy *RetryPolicy `json:"retryPolicy,omitempty"`
 
 	RuntimeFlags         string            `json:"runtimeFlags,omitempty"`
 	SubscriptionName     string            `json:"subscriptionName,omitempty"`
@@ -92,6 +101,12 @@ type FunctionSpec struct {
 
 	// +kubebuilder:validation:Optional
 	StateConfig *Stateful `json:"statefulConfig,omitempty"`
+
+	// Paused scales the workload down to zero and suspends its autoscalers, the replica count
+	// is recorded in the status and restored when the function is resumed, unless the replicas are changed
+	// while it is paused
+	// +kubebuilder:validation:Optional
+	Paused bool `json:"paused,omitempty"`
 }
 
 // FunctionStatus defines the observed state of Function
@@ -100,8 +115,11 @@ type FunctionStatus struct {
 	// Important: Run "make" to regenerate code after modifying this file
 	Conditions         map[Component]ResourceCondition `json:"conditions"`
 	Replicas           int32                           `json:"replicas"`
+	ReadyReplicas      int32                           `json:"readyReplicas,omitempty"`
 	Selector           string                          `json:"selector"`
 	ObservedGeneration int64                           `json:"observedGeneration,omitempty"`
+
+	PauseStatus `json:",inline"`
 }
 
 // +genclient
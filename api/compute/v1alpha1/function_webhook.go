@@ -120,21 +120,35 @@ func (r *Function) Default() {
 		r.Spec.Output.TypeClassName = "[B"
 	}
 
-	// mutate windowConfig
 	if r.Spec.WindowConfig != nil {
-		if r.Spec.WindowConfig.WindowLengthDurationMs != nil && r.Spec.WindowConfig.SlidingIntervalDurationMs == nil {
-			r.Spec.WindowConfig.SlidingIntervalDurationMs = r.Spec.WindowConfig.WindowLengthDurationMs
+		defaultWindowConfig(r.Spec.WindowConfig)
+	}
+}
+
+// defaultWindowConfig infers the missing window arguments the same way as the window function executor: the
+// windows tumble unless a sliding interval is set, and the lag and watermark only apply to the time windows
+// driven by the timestamps of the messages
+func defaultWindowConfig(config *WindowConfig) {
+	if config.SlidingIntervalCount == nil && config.SlidingIntervalDurationMs == nil {
+		if config.WindowLengthDurationMs != nil {
+			slidingInterval := *config.WindowLengthDurationMs
+			config.SlidingIntervalDurationMs = &slidingInterval
 		}
-		if r.Spec.WindowConfig.WindowLengthCount != nil && r.Spec.WindowConfig.SlidingIntervalCount == nil {
-			if r.Spec.WindowConfig.MaxLagMs == nil {
-				*r.Spec.WindowConfig.MaxLagMs = 0
-			}
-			if r.Spec.WindowConfig.WatermarkEmitIntervalMs == nil {
-				*r.Spec.WindowConfig.WatermarkEmitIntervalMs = 1000
-			}
+		if config.WindowLengthCount != nil {
+			slidingInterval := *config.WindowLengthCount
+			config.SlidingIntervalCount = &slidingInterval
+		}
+	}
+	if config.TimestampExtractorClassName != nil {
+		if config.MaxLagMs == nil {
+			maxLag := DefaultWindowMaxLagMs
+			config.MaxLagMs = &maxLag
+		}
+		if config.WatermarkEmitIntervalMs == nil {
+			watermarkInterval := DefaultWindowWatermarkEmitIntervalMs
+			config.WatermarkEmitIntervalMs = &watermarkInterval
 		}
 	}
-
 }
 
 // TODO(user): change verbs to "verbs=create;update;delete" if you want to enable deletion validation.
@@ -179,6 +193,11 @@ func (r *Function) ValidateCreate() error {
 		allErrs = append(allErrs, fieldErrs...)
 	}
 
+	fieldErrs = validateJVMOptions(r.Spec.Java, r.Spec.Resources, r.Spec.VolumeMounts)
+	if len(fieldErrs) > 0 {
+		allErrs = append(allErrs, fieldErrs...)
+	}
+
 	fieldErrs = validatePythonRuntime(r.Spec.Python, r.Spec.ClassName)
 	if len(fieldErrs) > 0 {
 		allErrs = append(allErrs, fieldErrs...)
@@ -261,14 +280,20 @@ func (r *Function) ValidateCreate() error {
 		allErrs = append(allErrs, fieldErr)
 	}
 
+	fieldErrs = validateRetryPolicy(r.Spec.RetryPolicy, r.Spec.MaxMessageRetry, r.Spec.DeadLetterTopic,
+		r.Spec.ProcessingGuarantee)
+	if len(fieldErrs) > 0 {
+		allErrs = append(allErrs, fieldErrs...)
+	}
+
 	fieldErr = validateStatefulFunctionConfigs(r.Spec.StateConfig, r.Spec.Runtime)
 	if fieldErr != nil {
 		allErrs = append(allErrs, fieldErr)
 	}
 
-	fieldErr = validateWindowConfigs(r.Spec.WindowConfig)
-	if fieldErr != nil {
-		allErrs = append(allErrs, fieldErr)
+	fieldErrs = validateWindowConfigs(r.Spec.WindowConfig, r.Spec.Runtime)
+	if len(fieldErrs) > 0 {
+		allErrs = append(allErrs, fieldErrs...)
 	}
 
 	fieldErr = validateMessaging(&r.Spec.Messaging)
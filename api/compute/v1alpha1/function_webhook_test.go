@@ -0,0 +1,149 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package v1alpha1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func makeWindowConfig(count, duration, slidingCount, slidingDuration, maxLag, watermark int64,
+	extractor *string, lateDataTopic string) *WindowConfig {
+	config := &WindowConfig{TimestampExtractorClassName: extractor, LateDataTopic: lateDataTopic}
+	if count != 0 {
+		value := int32(count)
+		config.WindowLengthCount = &value
+	}
+	if slidingCount != 0 {
+		value := int32(slidingCount)
+		config.SlidingIntervalCount = &value
+	}
+	for _, v := range []struct {
+		field **int64
+		value int64
+	}{
+		{&config.WindowLengthDurationMs, duration},
+		{&config.SlidingIntervalDurationMs, slidingDuration},
+		{&config.MaxLagMs, maxLag},
+		{&config.WatermarkEmitIntervalMs, watermark},
+	} {
+		if v.value != 0 {
+			value := v.value
+			*v.field = &value
+		}
+	}
+	return config
+}
+
+func TestDefaultWindowConfig(t *testing.T) {
+	extractor := "org.example.EventTimeExtractor"
+	testData := []struct {
+		name     string
+		config   *WindowConfig
+		expected *WindowConfig
+	}{
+		{"tumbling count window", makeWindowConfig(10, 0, 0, 0, 0, 0, nil, ""),
+			makeWindowConfig(10, 0, 10, 0, 0, 0, nil, "")},
+		{"sliding count window", makeWindowConfig(10, 0, 5, 0, 0, 0, nil, ""),
+			makeWindowConfig(10, 0, 5, 0, 0, 0, nil, "")},
+		{"tumbling time window", makeWindowConfig(0, 60000, 0, 0, 0, 0, nil, ""),
+			makeWindowConfig(0, 60000, 0, 60000, 0, 0, nil, "")},
+		{"sliding time window", makeWindowConfig(0, 60000, 0, 10000, 0, 0, nil, ""),
+			makeWindowConfig(0, 60000, 0, 10000, 0, 0, nil, "")},
+		{"count window sliding by time", makeWindowConfig(10, 0, 0, 10000, 0, 0, nil, ""),
+			makeWindowConfig(10, 0, 0, 10000, 0, 0, nil, "")},
+		{"event time window", makeWindowConfig(0, 60000, 0, 0, 0, 0, &extractor, "late"),
+			makeWindowConfig(0, 60000, 0, 60000, 0, DefaultWindowWatermarkEmitIntervalMs, &extractor, "late")},
+		{"event time window with lag", makeWindowConfig(10, 0, 0, 0, 5000, 500, &extractor, ""),
+			makeWindowConfig(10, 0, 10, 0, 5000, 500, &extractor, "")},
+	}
+	for _, v := range testData {
+		defaultWindowConfig(v.config)
+		if v.expected.TimestampExtractorClassName != nil && v.expected.MaxLagMs == nil {
+			maxLag := DefaultWindowMaxLagMs
+			v.expected.MaxLagMs = &maxLag
+		}
+		assert.Equal(t, v.expected, v.config, v.name)
+	}
+}
+
+func TestFunctionDefaultWindowConfig(t *testing.T) {
+	extractor := "org.example.EventTimeExtractor"
+	function := &Function{
+		ObjectMeta: metav1.ObjectMeta{Name: "word-count"},
+		Spec: FunctionSpec{
+			WindowConfig: makeWindowConfig(10, 0, 0, 0, 0, 0, &extractor, ""),
+		},
+	}
+	assert.NotPanics(t, function.Default)
+	assert.Equal(t, int32(10), *function.Spec.WindowConfig.SlidingIntervalCount)
+	assert.Equal(t, int64(0), *function.Spec.WindowConfig.MaxLagMs)
+	assert.Equal(t, int64(1000), *function.Spec.WindowConfig.WatermarkEmitIntervalMs)
+}
+
+func TestValidateWindowConfigs(t *testing.T) {
+	java := Runtime{Java: &JavaRuntime{Jar: "function.jar"}}
+	extractor := "org.example.EventTimeExtractor"
+	empty := ""
+	testData := []struct {
+		name    string
+		config  *WindowConfig
+		runtime Runtime
+		errors  int
+	}{
+		{"no window", nil, java, 0},
+		{"tumbling count window", makeWindowConfig(10, 0, 10, 0, 0, 0, nil, ""), java, 0},
+		{"sliding count window", makeWindowConfig(10, 0, 5, 0, 0, 0, nil, ""), java, 0},
+		{"tumbling time window", makeWindowConfig(0, 60000, 0, 60000, 0, 0, nil, ""), java, 0},
+		{"sliding time window", makeWindowConfig(0, 60000, 0, 10000, 0, 0, nil, ""), java, 0},
+		{"count window sliding by time", makeWindowConfig(10, 0, 0, 10000, 0, 0, nil, ""), java, 0},
+		{"time window sliding by count", makeWindowConfig(0, 60000, 100, 0, 0, 0, nil, ""), java, 0},
+		{"event time window", makeWindowConfig(0, 60000, 0, 60000, 0, 1000, &extractor,
+			"persistent://public/default/late"), java, 0},
+		{"event count window", makeWindowConfig(10, 0, 10, 0, 5000, 1000, &extractor, ""), java, 0},
+		{"python runtime", makeWindowConfig(10, 0, 0, 0, 0, 0, nil, ""),
+			Runtime{Python: &PythonRuntime{Py: "function.py"}}, 1},
+		{"go runtime", makeWindowConfig(10, 0, 0, 0, 0, 0, nil, ""),
+			Runtime{Golang: &GoRuntime{Go: "function"}}, 1},
+		{"no window length", makeWindowConfig(0, 0, 5, 0, 0, 0, nil, ""), java, 1},
+		{"count and time window length", makeWindowConfig(10, 60000, 0, 0, 0, 0, nil, ""), java, 1},
+		{"negative window count", makeWindowConfig(-1, 0, 0, 0, 0, 0, nil, ""), java, 1},
+		{"negative window duration", makeWindowConfig(0, -1, 0, 0, 0, 0, nil, ""), java, 1},
+		{"count and time sliding interval", makeWindowConfig(10, 0, 5, 10000, 0, 0, nil, ""), java, 1},
+		{"negative sliding count", makeWindowConfig(10, 0, -1, 0, 0, 0, nil, ""), java, 1},
+		{"negative sliding duration", makeWindowConfig(0, 60000, 0, -1, 0, 0, nil, ""), java, 1},
+		{"sliding count longer than window", makeWindowConfig(10, 0, 20, 0, 0, 0, nil, ""), java, 1},
+		{"sliding duration longer than window", makeWindowConfig(0, 60000, 0, 120000, 0, 0, nil, ""), java, 1},
+		{"lag without extractor", makeWindowConfig(10, 0, 0, 0, 5000, 0, nil, ""), java, 1},
+		{"watermark without extractor", makeWindowConfig(10, 0, 0, 0, 0, 1000, nil, ""), java, 1},
+		{"late data topic without extractor", makeWindowConfig(0, 60000, 0, 0, 0, 0, nil,
+			"persistent://public/default/late"), java, 1},
+		{"empty extractor", makeWindowConfig(0, 60000, 0, 0, 0, 0, &empty, ""), java, 1},
+		{"negative lag", makeWindowConfig(0, 60000, 0, 0, -1, 0, &extractor, ""), java, 1},
+		{"negative watermark", makeWindowConfig(0, 60000, 0, 0, 0, -1, &extractor, ""), java, 1},
+		{"invalid late data topic", makeWindowConfig(0, 60000, 0, 0, 0, 0, &extractor,
+			"persistent://public/late"), java, 1},
+		{"several errors", makeWindowConfig(-1, 0, 5, 0, 5000, 0, nil, "late"),
+			Runtime{Python: &PythonRuntime{Py: "function.py"}}, 4},
+	}
+	for _, v := range testData {
+		assert.Len(t, validateWindowConfigs(v.config, v.runtime), v.errors, v.name)
+	}
+}
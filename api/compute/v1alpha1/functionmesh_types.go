@@ -18,6 +18,7 @@
 package v1alpha1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -29,11 +30,57 @@ type FunctionMeshSpec struct {
 	// INSERT ADDITIONAL SPEC FIELDS - desired state of cluster
 	// Important: Run "make" to regenerate code after modifying this file
 
+	// Defaults holds the settings shared by all the functions, sources and sinks of the mesh,
+	// the values set on a component take precedence over the defaults
+	// +kubebuilder:validation:Optional
+	Defaults *FunctionMeshDefaults `json:"defaults,omitempty"`
+
+	// StartupOrder defines how the components are rolled out, with `topological` the
+	// components consuming a topic are started and ready before the components producing
+	// to it, and torn down in the reverse order. Default to `parallel`.
+	// +kubebuilder:validation:Optional
+	StartupOrder StartupOrder `json:"startupOrder,omitempty"`
+
+	// Paused pauses all the components of the mesh, regardless of their own `paused` field
+	// +kubebuilder:validation:Optional
+	Paused bool `json:"paused,omitempty"`
+
 	Sources   []SourceSpec   `json:"sources,omitempty"`
 	Sinks     []SinkSpec     `json:"sinks,omitempty"`
 	Functions []FunctionSpec `json:"functions,omitempty"`
 }
 
+// StartupOrder enum type
+// +kubebuilder:validation:Enum=parallel;topological
+type StartupOrder string
+
+const (
+	StartupOrderParallel    StartupOrder = "parallel"
+	StartupOrderTopological StartupOrder = "topological"
+)
+
+// FunctionMeshDefaults defines the settings inherited by every component of a FunctionMesh
+type FunctionMeshDefaults struct {
+	Tenant      string `json:"tenant,omitempty"`
+	Namespace   string `json:"namespace,omitempty"`
+	ClusterName string `json:"clusterName,omitempty"`
+
+	// Pod is merged into the pod policy of each component, maps are merged by key and
+	// lists (env, tolerations, volumes, containers etc.) are merged by name
+	Pod PodPolicy `json:"pod,omitempty"`
+
+	Messaging `json:",inline"`
+
+	// Image is the container image used to run the component pods.
+	Image string `json:"image,omitempty"`
+
+	// Image pull policy, one of Always, Never, IfNotPresent.
+	ImagePullPolicy corev1.PullPolicy `json:"imagePullPolicy,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	StateConfig *Stateful `json:"statefulConfig,omitempty"`
+}
+
 // FunctionMeshStatus defines the observed state of FunctionMesh
 type FunctionMeshStatus struct {
 	// INSERT ADDITIONAL STATUS FIELD - define observed state of cluster
@@ -43,11 +90,59 @@ type FunctionMeshStatus struct {
 	FunctionConditions map[string]ResourceCondition `json:"functionConditions,omitempty"`
 	ObservedGeneration int64                        `json:"observedGeneration,omitempty"`
 	Condition          *ResourceCondition           `json:"condition,omitempty"`
+	// StartupProgress is only available when the components are started in topological order
+	StartupProgress *StartupProgress `json:"startupProgress,omitempty"`
+	// Components holds the observed state of each function, source and sink of the mesh
+	Components []ComponentStatus `json:"components,omitempty"`
+	// ComponentsReady summarizes the components as `ready/total`
+	ComponentsReady string `json:"componentsReady,omitempty"`
+	// Conditions holds the aggregated `Ready`, `Progressing` and `Degraded` conditions of the mesh
+	// +patchMergeKey=type
+	// +patchStrategy=merge
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type"`
+}
+
+// The condition types of a FunctionMesh
+const (
+	FunctionMeshReady       string = "Ready"
+	FunctionMeshProgressing string = "Progressing"
+	FunctionMeshDegraded    string = "Degraded"
+	FunctionMeshPaused      string = "Paused"
+)
+
+// ComponentStatus describes the observed state of a single component of a FunctionMesh
+type ComponentStatus struct {
+	// Kind is one of `function`, `source` and `sink`
+	Kind string `json:"kind"`
+	Name string `json:"name"`
+	// Generation is the generation of the child object created for the component
+	Generation int64 `json:"generation,omitempty"`
+	// ObservedGeneration is the generation last observed by the controller of the child object
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	Replicas           int32 `json:"replicas"`
+	ReadyReplicas      int32 `json:"readyReplicas"`
+	Ready              bool  `json:"ready"`
+	// LastError is the last error met when reconciling the component
+	LastError string `json:"lastError,omitempty"`
+}
+
+// StartupProgress describes the progress of a topological rollout or teardown
+type StartupProgress struct {
+	// Stage is the stage being rolled out, stage 0 contains the most downstream components
+	Stage       int32 `json:"stage"`
+	TotalStages int32 `json:"totalStages"`
+	// Waiting lists the components of the current stage which are not ready yet
+	Waiting []string `json:"waiting,omitempty"`
 }
 
 // +genclient
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Components",type=string,JSONPath=`.status.componentsReady`
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
 
 // FunctionMesh is the Schema for the functionmeshes API
 type FunctionMesh struct {
@@ -0,0 +1,148 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// StateSnapshotMode is the direction of a FunctionStateSnapshot
+// +kubebuilder:validation:Enum=Export;Restore
+type StateSnapshotMode string
+
+const (
+	// StateSnapshotExport dumps the state of the function to the storage
+	StateSnapshotExport StateSnapshotMode = "Export"
+	// StateSnapshotRestore loads the state from the storage into the function
+	StateSnapshotRestore StateSnapshotMode = "Restore"
+)
+
+// StateSnapshotPhase is the progress of a FunctionStateSnapshot
+type StateSnapshotPhase string
+
+const (
+	StateSnapshotPending   StateSnapshotPhase = "Pending"
+	StateSnapshotRunning   StateSnapshotPhase = "Running"
+	StateSnapshotSucceeded StateSnapshotPhase = "Succeeded"
+	StateSnapshotFailed    StateSnapshotPhase = "Failed"
+)
+
+// FunctionStateSnapshotSpec defines the desired state of FunctionStateSnapshot
+type FunctionStateSnapshotSpec struct {
+	// Function is the name of the Function in the same namespace whose state is exported or restored, the
+	// tenant, namespace and name of its state are taken from the Function
+	Function string `json:"function"`
+
+	Mode StateSnapshotMode `json:"mode"`
+
+	// Keys are the state keys exported, the functions state API cannot enumerate the keys of a state table
+	// so they must be listed for the Export mode. The Restore mode loads all the keys of the snapshot.
+	Keys []string `json:"keys,omitempty"`
+
+	// StateServiceURL is the HTTP URL serving the functions state API, it defaults to the web service URL of
+	// the messaging of the Function. The service must know the Function, such as a function worker running
+	// the Function Mesh worker service, the Job fails if it does not instead of writing an empty snapshot.
+	StateServiceURL string `json:"stateServiceUrl,omitempty"`
+
+	Storage StateSnapshotStorage `json:"storage"`
+
+	// Image runs the snapshot Job, it defaults to the function-mesh-tools image
+	Image           string            `json:"image,omitempty"`
+	ImagePullPolicy corev1.PullPolicy `json:"imagePullPolicy,omitempty"`
+
+	// BackoffLimit is the number of retries of the snapshot Job
+	// +kubebuilder:validation:Minimum=0
+	BackoffLimit *int32 `json:"backoffLimit,omitempty"`
+}
+
+// StateSnapshotStorage is where the snapshot is written to or read from, exactly one of the fields must be set
+type StateSnapshotStorage struct {
+	PersistentVolumeClaim *StateSnapshotVolume `json:"persistentVolumeClaim,omitempty"`
+
+	ObjectStore *StateSnapshotObjectStore `json:"objectStore,omitempty"`
+}
+
+// StateSnapshotVolume stores the snapshot as a file of a PersistentVolumeClaim
+type StateSnapshotVolume struct {
+	ClaimName string `json:"claimName"`
+
+	// Path is the path of the snapshot file in the volume
+	Path string `json:"path"`
+}
+
+// StateSnapshotObjectStore stores the snapshot as an object, it is uploaded with an HTTP PUT and downloaded with
+// an HTTP GET, such as with the pre-signed URLs of S3 or GCS
+type StateSnapshotObjectStore struct {
+	// URLSecret is the secret holding the URL of the object, the URL usually carries credentials
+	URLSecret string `json:"urlSecret"`
+
+	URLSecretKey string `json:"urlSecretKey"`
+}
+
+// FunctionStateSnapshotStatus defines the observed state of FunctionStateSnapshot
+type FunctionStateSnapshotStatus struct {
+	Phase StateSnapshotPhase `json:"phase,omitempty"`
+
+	// JobName is the Job exporting or restoring the state
+	JobName string `json:"jobName,omitempty"`
+
+	StartTime      *metav1.Time `json:"startTime,omitempty"`
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+
+	// Message explains why the snapshot is pending or failed
+	Message string `json:"message,omitempty"`
+
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+}
+
+//+genclient
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Function",type=string,JSONPath=`.spec.function`
+//+kubebuilder:printcolumn:name="Mode",type=string,JSONPath=`.spec.mode`
+//+kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+//+kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// FunctionStateSnapshot is the Schema for the functionstatesnapshots API, it exports the state of a stateful
+// Function or restores it before the Function starts
+type FunctionStateSnapshot struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   FunctionStateSnapshotSpec   `json:"spec,omitempty"`
+	Status FunctionStateSnapshotStatus `json:"status,omitempty"`
+}
+
+// IsFinished returns true once the Job of the snapshot succeeded or failed
+func (s *FunctionStateSnapshot) IsFinished() bool {
+	return s.Status.Phase == StateSnapshotSucceeded || s.Status.Phase == StateSnapshotFailed
+}
+
+//+kubebuilder:object:root=true
+
+// FunctionStateSnapshotList contains a list of FunctionStateSnapshot
+type FunctionStateSnapshotList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []FunctionStateSnapshot `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&FunctionStateSnapshot{}, &FunctionStateSnapshotList{})
+}
@@ -15,9 +15,6 @@
 // specific language governing permissions and limitations
 // under the License.
 
-// Package v1alpha1 contains API Schema definitions for the cloud v1alpha1 API group
-// +kubebuilder:object:generate=true
-// +groupName=compute.functionmesh.io
 package v1alpha1
 
 type BuiltinHPARule string
@@ -0,0 +1,79 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DefaultPulsarConnectionName is the name of the PulsarConnection used by the components of a namespace
+// that neither set a pulsar config nor refer to a PulsarConnection
+const DefaultPulsarConnectionName = "default"
+
+// PulsarConnectionSpec defines the desired state of PulsarConnection
+type PulsarConnectionSpec struct {
+	// WebServiceURL is the HTTP URL of the Pulsar cluster, such as http://pulsar-broker:8080
+	WebServiceURL string `json:"webServiceURL"`
+
+	// BrokerServiceURL is the binary protocol URL of the Pulsar cluster, such as pulsar://pulsar-broker:6650
+	BrokerServiceURL string `json:"brokerServiceURL"`
+
+	// The auth secret should contain the following fields
+	// clientAuthenticationPlugin
+	// clientAuthenticationParameters
+	AuthSecret string `json:"authSecret,omitempty"`
+
+	TLSConfig *PulsarTLSConfig `json:"tlsConfig,omitempty"`
+
+	AuthConfig *AuthConfig `json:"authConfig,omitempty"`
+}
+
+// PulsarConnectionStatus defines the observed state of PulsarConnection
+type PulsarConnectionStatus struct {
+	// PulsarConfig is the config map generated from the connection, it holds the service URLs
+	PulsarConfig       string `json:"pulsarConfig,omitempty"`
+	ObservedGeneration int64  `json:"observedGeneration,omitempty"`
+}
+
+//+genclient
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Web Service URL",type=string,JSONPath=`.spec.webServiceURL`
+//+kubebuilder:printcolumn:name="Broker Service URL",type=string,JSONPath=`.spec.brokerServiceURL`
+
+// PulsarConnection is the Schema for the pulsarconnections API
+type PulsarConnection struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   PulsarConnectionSpec   `json:"spec,omitempty"`
+	Status PulsarConnectionStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// PulsarConnectionList contains a list of PulsarConnection
+type PulsarConnectionList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []PulsarConnection `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&PulsarConnection{}, &PulsarConnectionList{})
+}
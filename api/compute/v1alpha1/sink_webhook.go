@@ -139,6 +139,11 @@ func (r *Sink) ValidateCreate() error {
 		allErrs = append(allErrs, fieldErrs...)
 	}
 
+	fieldErrs = validateJVMOptions(r.Spec.Java, r.Spec.Resources, r.Spec.VolumeMounts)
+	if len(fieldErrs) > 0 {
+		allErrs = append(allErrs, fieldErrs...)
+	}
+
 	fieldErrs = validateReplicasAndMinReplicasAndMaxReplicas(r.Spec.Replicas, r.Spec.MinReplicas, r.Spec.MaxReplicas)
 	if len(fieldErrs) > 0 {
 		allErrs = append(allErrs, fieldErrs...)
@@ -196,6 +201,12 @@ func (r *Sink) ValidateCreate() error {
 		allErrs = append(allErrs, fieldErr)
 	}
 
+	fieldErrs = validateRetryPolicy(r.Spec.RetryPolicy, r.Spec.MaxMessageRetry, r.Spec.DeadLetterTopic,
+		r.Spec.ProcessingGuarantee)
+	if len(fieldErrs) > 0 {
+		allErrs = append(allErrs, fieldErrs...)
+	}
+
 	fieldErr = validateMessaging(&r.Spec.Messaging)
 	if fieldErr != nil {
 		allErrs = append(allErrs, fieldErr)
@@ -30,6 +30,12 @@ const (
 	BatchSourceClassNameKey string = "__BATCHSOURCECLASSNAME__"
 	// BatchSourceClass the source class for batch source
 	BatchSourceClass string = "org.apache.pulsar.functions.source.batch.BatchSourceExecutor"
+
+	// CronTriggererClass runs the discovery on the schedule of the cron expression in CronTriggererConfigKey
+	CronTriggererClass     string = "org.apache.pulsar.io.batchdiscovery.CronTriggerer"
+	CronTriggererConfigKey string = "__CRON__"
+	// ImmediateTriggererClass runs the discovery once when the instances start
+	ImmediateTriggererClass string = "org.apache.pulsar.io.batchdiscovery.ImmediateTriggerer"
 )
 
 // SourceSpec defines the desired state of Source
@@ -68,7 +74,14 @@ type SourceSpec struct {
 	RuntimeFlags                 string                      `json:"runtimeFlags,omitempty"`
 	VolumeMounts                 []corev1.VolumeMount        `json:"volumeMounts,omitempty"`
 	ForwardSourceMessageProperty *bool                       `json:"forwardSourceMessageProperty,omitempty"`
-	Pod                          PodPolicy                   `json:"pod,omitempty"`
+	// MaxBufferedTuples is the maximum number of the tuples buffered by the instance, the default is 100
+	// +kubebuilder:validation:Minimum=1
+	MaxBufferedTuples *int32 `json:"maxBufferedTuples,omitempty"`
+	// FunctionVersion is the version reported by the instance in its metrics and state, it is derived from the
+	// checksum of the package, or from the generation of the resource without a checksum, when it is not set
+	FunctionVersion string `json:"functionVersion,omitempty"`
+
+	Pod PodPolicy `json:"pod,omitempty"`
 
 	// +kubebuilder:validation:Required
 	Messaging `json:",inline"`
@@ -85,14 +98,38 @@ type SourceSpec struct {
 
 	// +kubebuilder:validation:Optional
 	StateConfig *Stateful `json:"statefulConfig,omitempty"`
+
+	// Paused scales the workload down to zero and suspends its autoscalers, the replica count
+	// is recorded in the status and restored when the source is resumed, unless the replicas are changed
+	// while it is paused
+	// +kubebuilder:validation:Optional
+	Paused bool `json:"paused,omitempty"`
 }
 
 type BatchSourceConfig struct {
-	// +kubebuilder:validation:Required
-	DiscoveryTriggererClassName string `json:"discoveryTriggererClassName"`
+	// DiscoveryTriggererClassName is the class triggering the discovery of the batch source, it is required
+	// unless Trigger is set
+	DiscoveryTriggererClassName string `json:"discoveryTriggererClassName,omitempty"`
 	// +kubebuilder:validation:Optional
 	// +kubebuilder:pruning:PreserveUnknownFields
 	DiscoveryTriggererConfig *Config `json:"discoveryTriggererConfig,omitempty"`
+
+	// Trigger is a preset of the discovery triggerer, the triggerer class and config are filled from it
+	// +kubebuilder:validation:Optional
+	Trigger *BatchSourceTrigger `json:"trigger,omitempty"`
+}
+
+// BatchSourceTrigger schedules the discovery of a batch source, exactly one of the fields must be set
+type BatchSourceTrigger struct {
+	// Cron is a Quartz cron expression with seconds, such as "0 0/5 * * * ?"
+	Cron string `json:"cron,omitempty"`
+
+	// Interval runs the discovery at a fixed interval, it must evenly divide a minute, an hour or a day
+	Interval *metav1.Duration `json:"interval,omitempty"`
+
+	// OnDemand runs the discovery when the instances start, and again whenever the
+	// compute.functionmesh.io/trigger-discovery annotation of the source changes
+	OnDemand bool `json:"onDemand,omitempty"`
 }
 
 // SourceStatus defines the observed state of Source
@@ -101,8 +138,15 @@ type SourceStatus struct {
 	// Important: Run "make" to regenerate code after modifying this file
 	Conditions         map[Component]ResourceCondition `json:"conditions"`
 	Replicas           int32                           `json:"replicas"`
+	ReadyReplicas      int32                           `json:"readyReplicas,omitempty"`
 	Selector           string                          `json:"selector"`
 	ObservedGeneration int64                           `json:"observedGeneration,omitempty"`
+
+	PauseStatus `json:",inline"`
+	// DiscoveryTrigger is the trigger-discovery annotation of the last on-demand discovery
+	DiscoveryTrigger string `json:"discoveryTrigger,omitempty"`
+	// LastDiscoveryTriggerTime is when the last on-demand discovery was triggered
+	LastDiscoveryTriggerTime *metav1.Time `json:"lastDiscoveryTriggerTime,omitempty"`
 }
 
 // +genclient
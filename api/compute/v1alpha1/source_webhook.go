@@ -19,6 +19,7 @@ package v1alpha1
 
 import (
 	"fmt"
+	"time"
 
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -116,6 +117,59 @@ func (r *Source) Default() {
 	if r.Spec.Output.TypeClassName == "" {
 		r.Spec.Output.TypeClassName = "[B"
 	}
+
+	if r.Spec.BatchSourceConfig != nil {
+		r.Spec.BatchSourceConfig.ApplyTrigger()
+	}
+}
+
+// ApplyTrigger fills the discovery triggerer class and config from the trigger preset, an invalid preset is
+// left to the validation
+func (c *BatchSourceConfig) ApplyTrigger() {
+	if c.Trigger == nil {
+		return
+	}
+	className, config, err := c.Trigger.discoveryTriggerer()
+	if err != nil {
+		return
+	}
+	c.DiscoveryTriggererClassName = className
+	c.DiscoveryTriggererConfig = config
+}
+
+// discoveryTriggerer returns the triggerer class and config of the preset, the intervals are translated to
+// cron expressions since the triggerers of the batch sources have no fixed rate schedule
+func (t *BatchSourceTrigger) discoveryTriggerer() (string, *Config, error) {
+	switch {
+	case t.Cron != "":
+		return CronTriggererClass, &Config{Data: map[string]interface{}{CronTriggererConfigKey: t.Cron}}, nil
+	case t.Interval != nil:
+		cron, err := makeIntervalCron(t.Interval.Duration)
+		if err != nil {
+			return "", nil, err
+		}
+		return CronTriggererClass, &Config{Data: map[string]interface{}{CronTriggererConfigKey: cron}}, nil
+	case t.OnDemand:
+		return ImmediateTriggererClass, nil, nil
+	}
+	return "", nil, fmt.Errorf("one of cron, interval and onDemand must be set")
+}
+
+func makeIntervalCron(interval time.Duration) (string, error) {
+	if interval <= 0 || interval%time.Second != 0 {
+		return "", fmt.Errorf("interval %s must be a positive number of seconds", interval)
+	}
+	switch {
+	case interval < time.Minute && time.Minute%interval == 0:
+		return fmt.Sprintf("0/%d * * * * ?", interval/time.Second), nil
+	case interval < time.Hour && interval%time.Minute == 0 && time.Hour%interval == 0:
+		return fmt.Sprintf("0 0/%d * * * ?", interval/time.Minute), nil
+	case interval < 24*time.Hour && interval%time.Hour == 0 && 24*time.Hour%interval == 0:
+		return fmt.Sprintf("0 0 0/%d * * ?", interval/time.Hour), nil
+	case interval == 24*time.Hour:
+		return "0 0 0 * * ?", nil
+	}
+	return "", fmt.Errorf("interval %s must evenly divide a minute, an hour or a day", interval)
 }
 
 // TODO(user): change verbs to "verbs=create;update;delete" if you want to enable deletion validation.
@@ -149,6 +203,11 @@ func (r *Source) ValidateCreate() error {
 		allErrs = append(allErrs, fieldErrs...)
 	}
 
+	fieldErrs = validateJVMOptions(r.Spec.Java, r.Spec.Resources, r.Spec.VolumeMounts)
+	if len(fieldErrs) > 0 {
+		allErrs = append(allErrs, fieldErrs...)
+	}
+
 	fieldErrs = validateReplicasAndMinReplicasAndMaxReplicas(r.Spec.Replicas, r.Spec.MinReplicas, r.Spec.MaxReplicas)
 	if len(fieldErrs) > 0 {
 		allErrs = append(allErrs, fieldErrs...)
@@ -191,6 +250,11 @@ func (r *Source) ValidateCreate() error {
 		allErrs = append(allErrs, fieldErr)
 	}
 
+	fieldErrs = validateBatchSourceConfig(r.Spec.BatchSourceConfig)
+	if len(fieldErrs) > 0 {
+		allErrs = append(allErrs, fieldErrs...)
+	}
+
 	if len(allErrs) == 0 {
 		return nil
 	}
@@ -0,0 +1,135 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package v1alpha1
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestSourceDefaultBatchSourceTrigger(t *testing.T) {
+	testData := []struct {
+		trigger   *BatchSourceTrigger
+		className string
+		cron      string
+	}{
+		{&BatchSourceTrigger{Cron: "0 0 2 * * ?"}, CronTriggererClass, "0 0 2 * * ?"},
+		{&BatchSourceTrigger{Interval: &metav1.Duration{Duration: 15 * time.Second}}, CronTriggererClass,
+			"0/15 * * * * ?"},
+		{&BatchSourceTrigger{Interval: &metav1.Duration{Duration: 10 * time.Minute}}, CronTriggererClass,
+			"0 0/10 * * * ?"},
+		{&BatchSourceTrigger{Interval: &metav1.Duration{Duration: 6 * time.Hour}}, CronTriggererClass,
+			"0 0 0/6 * * ?"},
+		{&BatchSourceTrigger{Interval: &metav1.Duration{Duration: 24 * time.Hour}}, CronTriggererClass,
+			"0 0 0 * * ?"},
+		{&BatchSourceTrigger{OnDemand: true}, ImmediateTriggererClass, ""},
+	}
+	for _, v := range testData {
+		source := &Source{Spec: SourceSpec{BatchSourceConfig: &BatchSourceConfig{
+			DiscoveryTriggererClassName: "org.example.PreviousTriggerer",
+			Trigger:                     v.trigger,
+		}}}
+		source.Default()
+		config := source.Spec.BatchSourceConfig
+		assert.Equal(t, v.className, config.DiscoveryTriggererClassName, "%+v", v.trigger)
+		if v.cron == "" {
+			assert.Nil(t, config.DiscoveryTriggererConfig)
+		} else {
+			assert.Equal(t, v.cron, config.DiscoveryTriggererConfig.Data[CronTriggererConfigKey])
+		}
+		assert.Empty(t, validateBatchSourceConfig(config), "%+v", v.trigger)
+	}
+
+	// the invalid presets are left to the validation
+	source := &Source{Spec: SourceSpec{BatchSourceConfig: &BatchSourceConfig{
+		Trigger: &BatchSourceTrigger{Interval: &metav1.Duration{Duration: 7 * time.Minute}},
+	}}}
+	source.Default()
+	assert.Empty(t, source.Spec.BatchSourceConfig.DiscoveryTriggererClassName)
+	assert.Len(t, validateBatchSourceConfig(source.Spec.BatchSourceConfig), 1)
+}
+
+func TestValidateBatchSourceConfig(t *testing.T) {
+	interval := &metav1.Duration{Duration: time.Minute}
+	testData := []struct {
+		config *BatchSourceConfig
+		errors int
+	}{
+		{nil, 0},
+		{&BatchSourceConfig{DiscoveryTriggererClassName: "org.example.Triggerer"}, 0},
+		{&BatchSourceConfig{}, 1},
+		{&BatchSourceConfig{Trigger: &BatchSourceTrigger{}}, 1},
+		{&BatchSourceConfig{Trigger: &BatchSourceTrigger{Cron: "0 * * * * ?", OnDemand: true}}, 1},
+		{&BatchSourceConfig{Trigger: &BatchSourceTrigger{Cron: "0 * * * * ?", Interval: interval}}, 1},
+		{&BatchSourceConfig{Trigger: &BatchSourceTrigger{Cron: "* * * * *"}}, 1},
+		{&BatchSourceConfig{Trigger: &BatchSourceTrigger{Interval: &metav1.Duration{Duration: 0}}}, 1},
+		{&BatchSourceConfig{Trigger: &BatchSourceTrigger{Interval: &metav1.Duration{Duration: 1500 * time.Millisecond}}}, 1},
+		{&BatchSourceConfig{Trigger: &BatchSourceTrigger{Interval: &metav1.Duration{Duration: 48 * time.Hour}}}, 1},
+		{&BatchSourceConfig{DiscoveryTriggererClassName: "org.example.Triggerer",
+			Trigger: &BatchSourceTrigger{OnDemand: true}}, 1},
+	}
+	for _, v := range testData {
+		assert.Len(t, validateBatchSourceConfig(v.config), v.errors, "%+v", v.config)
+	}
+}
+
+func TestValidateCronExpression(t *testing.T) {
+	valid := []string{
+		"0 0/5 * * * ?",
+		"0 0 12 * * ?",
+		"0 15 10 ? * MON-FRI",
+		"0 15 10 ? * 6L",
+		"0 15 10 ? * 6#3",
+		"0 0 12 1/5 * ?",
+		"0 11 11 11 11 ?",
+		"0 15 10 L * ?",
+		"0 15 10 L-2 * ?",
+		"0 15 10 15W * ?",
+		"0 0,15,30,45 8-18 ? JAN-MAR,DEC SUN 2027",
+		"*/10 * * ? * *",
+	}
+	for _, expression := range valid {
+		assert.NoError(t, validateCronExpression(expression), expression)
+	}
+	invalid := []string{
+		"",
+		"* * * * *",
+		"0 0 0 * * ? 2027 extra",
+		"60 * * * * ?",
+		"0 60 * * * ?",
+		"0 0 24 * * ?",
+		"0 0 0 0 * ?",
+		"0 0 0 32 * ?",
+		"0 0 0 * 13 ?",
+		"0 0 0 * FOO ?",
+		"0 0 0 ? * 8",
+		"0 0 0 * * *",
+		"0 0 0 ? * ?",
+		"? 0 0 * * ?",
+		"0/0 * * * * ?",
+		"0/x * * * * ?",
+		"0 0 0 * * ? 1900",
+		"0 15 10 ? * 6#6",
+	}
+	for _, expression := range invalid {
+		assert.Error(t, validateCronExpression(expression), expression)
+	}
+}
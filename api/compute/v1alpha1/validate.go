@@ -20,12 +20,22 @@ package v1alpha1
 import (
 	"encoding/json"
 	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
 
 	corev1 "k8s.io/api/core/v1"
 
+	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 )
 
+var packageSha256Pattern = regexp.MustCompile("^[a-fA-F0-9]{64}$")
+
+// the names of the state store namespaces and tables
+var stateStoreNamePattern = regexp.MustCompile("^[a-zA-Z0-9_-]+$")
+
 func validateJavaRuntime(java *JavaRuntime, className string) []*field.Error {
 	var allErrs field.ErrorList
 	if java != nil {
@@ -45,10 +55,172 @@ func validateJavaRuntime(java *JavaRuntime, className string) []*field.Error {
 				allErrs = append(allErrs, e)
 			}
 		}
+		if e := validatePackageSha256(field.NewPath("spec").Child("java"), java.Sha256, java.JarLocation); e != nil {
+			allErrs = append(allErrs, e)
+		}
+		allErrs = append(allErrs, validateJavaDependencies(java)...)
+		allErrs = append(allErrs, validateRuntimeLogConfig(field.NewPath("spec").Child("java", "log"), java.Log)...)
+	}
+	return allErrs
+}
+
+// the java options set from the jvm block
+var jvmManagedOptionPattern = regexp.MustCompile(
+	`^(-Xmx|-XX:MaxDirectMemorySize=|-XX:MaxMetaspaceSize=|-XX:(Max|Min|Initial)RAMPercentage=|-XX:\+Use\w+GC$|` +
+		`-Xlog:gc|-XX:HeapDumpPath=|-XX:[+-]HeapDumpOnOutOfMemoryError$)`)
+
+// the java options sizing the memory of the JVM
+var jvmSizeOptionPattern = regexp.MustCompile(`^(-Xmx|-Xms|-XX:MaxDirectMemorySize=)(\d+)([kKmMgGtT]?)$`)
+
+func validateJVMOptions(java *JavaRuntime, resources corev1.ResourceRequirements,
+	volumeMounts []corev1.VolumeMount) []*field.Error {
+	var allErrs field.ErrorList
+	if java == nil {
+		return allErrs
+	}
+	path := field.NewPath("spec").Child("java")
+	memory := GetJVMMemory(resources)
+	for i, opts := range java.JavaOpts {
+		for _, opt := range strings.Fields(opts) {
+			if java.JVM != nil && jvmManagedOptionPattern.MatchString(opt) {
+				allErrs = append(allErrs, field.Invalid(path.Child("javaOpts").Index(i), opt,
+					"the option is set from the jvm block and cannot be set in javaOpts"))
+				continue
+			}
+			matches := jvmSizeOptionPattern.FindStringSubmatch(opt)
+			if matches == nil || memory.IsZero() {
+				continue
+			}
+			size, err := parseJVMSize(matches[2], matches[3])
+			if err != nil {
+				allErrs = append(allErrs, field.Invalid(path.Child("javaOpts").Index(i), opt, err.Error()))
+			} else if size > memory.Value() {
+				allErrs = append(allErrs, field.Invalid(path.Child("javaOpts").Index(i), opt,
+					fmt.Sprintf("the size exceeds the memory limit %s of the container", memory.String())))
+			} else if java.JVM != nil && size > memory.Value()*int64(java.JVM.GetHeapPercentage(memory))/100 {
+				allErrs = append(allErrs, field.Invalid(path.Child("javaOpts").Index(i), opt,
+					"the initial heap size exceeds the heap size set from the jvm block"))
+			}
+		}
+	}
+	if java.JVM == nil {
+		return allErrs
+	}
+	jvm := java.JVM
+	path = path.Child("jvm")
+	if memory.IsZero() {
+		allErrs = append(allErrs, field.Required(field.NewPath("spec").Child("resources"),
+			"the memory limit or request must be set to size the jvm"))
+		return allErrs
+	}
+	heapPercentage := jvm.GetHeapPercentage(memory)
+	directMemoryPercentage := jvm.GetDirectMemoryPercentage()
+	if heapPercentage+directMemoryPercentage > MaxJVMMemoryPercentage {
+		allErrs = append(allErrs, field.Invalid(path, fmt.Sprintf("heapPercentage: %d, directMemoryPercentage: %d",
+			heapPercentage, directMemoryPercentage),
+			fmt.Sprintf("the heap and direct memory together cannot use more than %d%% of the memory",
+				MaxJVMMemoryPercentage)))
+	} else if jvm.MaxMetaspaceSize != nil {
+		used := memory.Value() * int64(heapPercentage+directMemoryPercentage) / 100
+		if jvm.MaxMetaspaceSize.Sign() <= 0 {
+			allErrs = append(allErrs, field.Invalid(path.Child("maxMetaspaceSize"), jvm.MaxMetaspaceSize.String(),
+				"maxMetaspaceSize must be positive"))
+		} else if used+jvm.MaxMetaspaceSize.Value() > memory.Value() {
+			allErrs = append(allErrs, field.Invalid(path.Child("maxMetaspaceSize"), jvm.MaxMetaspaceSize.String(),
+				fmt.Sprintf("the heap, direct memory and metaspace together exceed the memory limit %s of the container",
+					memory.String())))
+		}
+	}
+	if jvm.HeapDumpPath != "" {
+		if e := validateHeapDumpPath(path.Child("heapDumpPath"), jvm.HeapDumpPath, volumeMounts); e != nil {
+			allErrs = append(allErrs, e)
+		}
 	}
 	return allErrs
 }
 
+func validateHeapDumpPath(path *field.Path, heapDumpPath string, volumeMounts []corev1.VolumeMount) *field.Error {
+	if !strings.HasPrefix(heapDumpPath, "/") {
+		return field.Invalid(path, heapDumpPath, "heapDumpPath must be an absolute path")
+	}
+	if strings.ContainsAny(heapDumpPath, "\"$`\\ ") {
+		return field.Invalid(path, heapDumpPath, "heapDumpPath cannot contain spaces, quotes or shell expansions")
+	}
+	for _, mount := range volumeMounts {
+		mountPath := strings.TrimSuffix(mount.MountPath, "/")
+		if heapDumpPath == mountPath || strings.HasPrefix(heapDumpPath, mountPath+"/") {
+			if mount.ReadOnly {
+				return field.Invalid(path, heapDumpPath,
+					fmt.Sprintf("the volume mount %s of heapDumpPath is read only", mount.Name))
+			}
+			return nil
+		}
+	}
+	return field.Invalid(path, heapDumpPath, "heapDumpPath must be on a volume mount of the instance")
+}
+
+// parseJVMSize parses a size of a java option, in bytes or with a k, m, g or t unit
+func parseJVMSize(value, unit string) (int64, error) {
+	size, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	shift := map[string]uint{"": 0, "k": 10, "m": 20, "g": 30, "t": 40}[strings.ToLower(unit)]
+	if size > (1<<63-1)>>shift {
+		return 0, fmt.Errorf("size %s%s is too large", value, unit)
+	}
+	return size << shift, nil
+}
+
+func validateJavaDependencies(java *JavaRuntime) []*field.Error {
+	var allErrs field.ErrorList
+	path := field.NewPath("spec").Child("java")
+	ociPackage := strings.HasPrefix(strings.ToLower(java.JarLocation), PackageURLOCI)
+	for i, dependency := range java.Dependencies {
+		lowerCase := strings.ToLower(dependency)
+		var err error
+		switch {
+		case strings.HasPrefix(lowerCase, PackageURLConfigMap) || strings.HasPrefix(lowerCase, PackageURLSecret):
+			err = isValidObjectKeyURL(dependency)
+		case strings.HasPrefix(lowerCase, PackageURLOCI):
+			err = fmt.Errorf("oci packages are not supported for the dependencies")
+		case ociPackage && hasPackageTypePrefix(dependency):
+			err = fmt.Errorf("the dependencies of an oci package cannot be downloaded from the package service")
+		default:
+			err = validPackageLocation(dependency)
+		}
+		if err == nil && strings.ContainsAny(dependency, " '\n") {
+			err = fmt.Errorf("dependency cannot contain whitespaces or quotes")
+		}
+		if err != nil {
+			allErrs = append(allErrs, field.Invalid(path.Child("dependencies").Index(i), dependency, err.Error()))
+		}
+	}
+	for i, library := range java.SharedLibraries {
+		if errs := validation.IsDNS1123Subdomain(library); len(errs) > 0 {
+			allErrs = append(allErrs, field.Invalid(path.Child("sharedLibraries").Index(i), library,
+				strings.Join(errs, ", ")))
+		}
+	}
+	return allErrs
+}
+
+// isValidObjectKeyURL validates a configmap://<name>/<key> or secret://<name>/<key> location
+func isValidObjectKeyURL(location string) error {
+	parts := strings.Split(location[strings.Index(location, "://")+3:], "/")
+	if len(parts) != 2 {
+		return fmt.Errorf("invalid dependency %s, the format is configmap://<name>/<key> or secret://<name>/<key>",
+			location)
+	}
+	if errs := validation.IsDNS1123Subdomain(parts[0]); len(errs) > 0 {
+		return fmt.Errorf("invalid name %s: %s", parts[0], strings.Join(errs, ", "))
+	}
+	if errs := validation.IsConfigMapKey(parts[1]); len(errs) > 0 {
+		return fmt.Errorf("invalid key %s: %s", parts[1], strings.Join(errs, ", "))
+	}
+	return nil
+}
+
 func validatePythonRuntime(python *PythonRuntime, className string) []*field.Error {
 	var allErrs field.ErrorList
 	if python != nil {
@@ -68,10 +240,59 @@ func validatePythonRuntime(python *PythonRuntime, className string) []*field.Err
 				allErrs = append(allErrs, e)
 			}
 		}
+		if e := validatePackageSha256(field.NewPath("spec").Child("python"), python.Sha256, python.PyLocation); e != nil {
+			allErrs = append(allErrs, e)
+		}
+		allErrs = append(allErrs, validatePythonDependencies(python)...)
+		allErrs = append(allErrs, validateRuntimeLogConfig(field.NewPath("spec").Child("python", "log"), python.Log)...)
+	}
+	return allErrs
+}
+
+func validatePythonDependencies(python *PythonRuntime) []*field.Error {
+	var allErrs field.ErrorList
+	path := field.NewPath("spec").Child("python")
+	for i, requirement := range python.Requirements {
+		if strings.TrimSpace(requirement) == "" || strings.ContainsAny(requirement, "'\n") {
+			allErrs = append(allErrs, field.Invalid(path.Child("requirements").Index(i), requirement,
+				"requirement must be a non empty pip requirement specifier"))
+		}
+	}
+	if e := validateDependencyRepository(path.Child("dependencyRepository"), python.DependencyRepository); e != nil {
+		allErrs = append(allErrs, e)
+	}
+	if e := validateDependencyRepository(path.Child("extraDependencyRepository"),
+		python.ExtraDependencyRepository); e != nil {
+		allErrs = append(allErrs, e)
+	}
+	if python.DependenciesLocation == "" {
+		if python.DependenciesType != "" {
+			allErrs = append(allErrs, field.Invalid(path.Child("dependenciesType"), python.DependenciesType,
+				"dependenciesType requires dependenciesLocation"))
+		}
+		return allErrs
+	}
+	if strings.HasPrefix(strings.ToLower(python.DependenciesLocation), PackageURLOCI) {
+		allErrs = append(allErrs, field.Invalid(path.Child("dependenciesLocation"), python.DependenciesLocation,
+			"oci packages are not supported for the dependencies"))
+	} else if err := validPackageLocation(python.DependenciesLocation); err != nil {
+		allErrs = append(allErrs, field.Invalid(path.Child("dependenciesLocation"), python.DependenciesLocation,
+			err.Error()))
+	}
+	if python.GetDependenciesType() == "" {
+		allErrs = append(allErrs, field.Invalid(path.Child("dependenciesType"), python.DependenciesType,
+			"dependenciesType must be set for the packages other than wheels"))
 	}
 	return allErrs
 }
 
+func validateDependencyRepository(path *field.Path, repository string) *field.Error {
+	if repository != "" && (!isFunctionPackageURLSupported(repository) || strings.ContainsAny(repository, " '\n")) {
+		return field.Invalid(path, repository, "dependency repository must be a http or https url")
+	}
+	return nil
+}
+
 func validateGolangRuntime(golang *GoRuntime) []*field.Error {
 	var allErrs field.ErrorList
 	if golang != nil {
@@ -87,10 +308,67 @@ func validateGolangRuntime(golang *GoRuntime) []*field.Error {
 				allErrs = append(allErrs, e)
 			}
 		}
+		if e := validatePackageSha256(field.NewPath("spec").Child("golang"), golang.Sha256, golang.GoLocation); e != nil {
+			allErrs = append(allErrs, e)
+		}
+		path := field.NewPath("spec").Child("golang", "log")
+		allErrs = append(allErrs, validateRuntimeLogConfig(path, golang.Log)...)
+		if golang.Log != nil && golang.Log.Pattern != "" {
+			allErrs = append(allErrs, field.Invalid(path.Child("pattern"), golang.Log.Pattern,
+				"pattern is not available for the go runtime"))
+		}
+		if golang.Log != nil && len(golang.Log.Loggers) > 0 {
+			allErrs = append(allErrs, field.Invalid(path.Child("loggers"), golang.Log.Loggers,
+				"loggers are not available for the go runtime"))
+		}
 	}
 	return allErrs
 }
 
+func validateRuntimeLogConfig(path *field.Path, log *RuntimeLogConfig) []*field.Error {
+	var allErrs field.ErrorList
+	if log == nil {
+		return allErrs
+	}
+	if log.LogConfig != nil && (log.Format != "" || log.Pattern != "" || len(log.Loggers) > 0 ||
+		log.StdoutWithLogTopic) {
+		allErrs = append(allErrs, field.Invalid(path.Child("logConfig"), log.LogConfig.Name,
+			"logConfig cannot be set with the format, the pattern, the loggers or stdoutWithLogTopic"))
+	}
+	if log.Pattern != "" && log.Format == LogFormatJSON {
+		allErrs = append(allErrs, field.Invalid(path.Child("pattern"), log.Pattern,
+			"pattern is only available for the text format"))
+	}
+	if strings.ContainsAny(log.Pattern, "\n") {
+		allErrs = append(allErrs, field.Invalid(path.Child("pattern"), log.Pattern, "pattern must be a single line"))
+	}
+	names := make([]string, 0, len(log.Loggers))
+	for name := range log.Loggers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if name == "" || strings.ContainsAny(name, " ,'\"<>&$`\\\n") {
+			allErrs = append(allErrs, field.Invalid(path.Child("loggers").Key(name), name,
+				"logger name must be a non empty qualified name"))
+		}
+	}
+	return allErrs
+}
+
+func validatePackageSha256(path *field.Path, sha256, location string) *field.Error {
+	if sha256 == "" {
+		return nil
+	}
+	if location == "" {
+		return field.Invalid(path.Child("sha256"), sha256, "sha256 requires the package to be downloaded")
+	}
+	if !packageSha256Pattern.MatchString(sha256) {
+		return field.Invalid(path.Child("sha256"), sha256, "sha256 must be 64 hexadecimal characters")
+	}
+	return nil
+}
+
 func validateReplicasAndMinReplicasAndMaxReplicas(replicas, minReplicas, maxReplicas *int32) []*field.Error {
 	var allErrs field.ErrorList
 	if replicas != nil && *replicas < 0 {
@@ -324,6 +602,48 @@ func validateDeadLetterTopic(deadLetterTopic string) *field.Error {
 	return nil
 }
 
+func validateRetryPolicy(policy *RetryPolicy, maxMessageRetry int32, deadLetterTopic string,
+	processingGuarantee ProcessGuarantee) []*field.Error {
+	var allErrs field.ErrorList
+	if policy == nil {
+		return allErrs
+	}
+	path := field.NewPath("spec").Child("retryPolicy")
+
+	if maxMessageRetry != 0 || deadLetterTopic != "" {
+		e := field.Forbidden(path, "retryPolicy cannot be used together with maxMessageRetry or deadLetterTopic")
+		allErrs = append(allErrs, e)
+	}
+
+	if policy.MaxMessageRetry > 0 && processingGuarantee == EffectivelyOnce {
+		e := field.Invalid(path.Child("maxMessageRetry"), policy.MaxMessageRetry,
+			"MaxMessageRetries and Effectively once are not compatible")
+		allErrs = append(allErrs, e)
+	}
+
+	if policy.DeadLetterTopic != nil {
+		if policy.MaxMessageRetry <= 0 {
+			e := field.Invalid(path.Child("maxMessageRetry"), policy.MaxMessageRetry,
+				"dead letter topic is set but max message retry is set to infinity")
+			allErrs = append(allErrs, e)
+		}
+		if err := isValidTopicName(policy.DeadLetterTopic.Name); err != nil {
+			e := field.Invalid(path.Child("deadLetterTopic", "name"), policy.DeadLetterTopic.Name,
+				fmt.Sprintf("DeadLetter topic %s is invalid", policy.DeadLetterTopic.Name))
+			allErrs = append(allErrs, e)
+		}
+	}
+
+	if policy.RetryLetterTopic != nil {
+		if err := isValidTopicName(policy.RetryLetterTopic.Name); err != nil {
+			e := field.Invalid(path.Child("retryLetterTopic", "name"), policy.RetryLetterTopic.Name,
+				fmt.Sprintf("RetryLetter topic %s is invalid", policy.RetryLetterTopic.Name))
+			allErrs = append(allErrs, e)
+		}
+	}
+	return allErrs
+}
+
 func validateAutoAck(autoAck *bool) *field.Error {
 	if autoAck == nil {
 		return field.Invalid(field.NewPath("spec").Child("autoAck"), autoAck, "autoAck cannot be nil")
@@ -338,63 +658,329 @@ func validateStatefulFunctionConfigs(statefulFunctionConfigs *Stateful, runtime
 				return field.Invalid(field.NewPath("spec").Child("statefulConfig"), runtime.Golang,
 					"Golang function do not support stateful function yet")
 			}
-			if statefulFunctionConfigs.Pulsar.ServiceURL == "" {
-				return field.Invalid(field.NewPath("spec").Child("statefulConfig", "pulsar", "serviceUrl"),
-					statefulFunctionConfigs.Pulsar.ServiceURL, "serviceUrl cannot be empty")
-			}
+			return validatePulsarStateStore(field.NewPath("spec").Child("statefulConfig", "pulsar"),
+				statefulFunctionConfigs.Pulsar, runtime.Java != nil)
 		}
 	}
 	return nil
 }
 
+func validatePulsarStateStore(path *field.Path, store *PulsarStateStore, java bool) *field.Error {
+	if store.ServiceURL == "" {
+		return field.Invalid(path.Child("serviceUrl"), store.ServiceURL, "serviceUrl cannot be empty")
+	}
+	if !java {
+		if store.TLSConfig != nil {
+			return field.Invalid(path.Child("tlsConfig"), store.TLSConfig, "tlsConfig is only supported by the Java runtime")
+		}
+		if store.AuthSecret != "" {
+			return field.Invalid(path.Child("authSecret"), store.AuthSecret,
+				"authSecret is only supported by the Java runtime")
+		}
+		if store.Namespace != "" {
+			return field.Invalid(path.Child("namespace"), store.Namespace,
+				"namespace is only supported by the Java runtime")
+		}
+		if store.Table != "" {
+			return field.Invalid(path.Child("table"), store.Table, "table is only supported by the Java runtime")
+		}
+	}
+	if java && !hasCustomStateStoreProvider(store) {
+		// the BookKeeper state store provider of the Java runtime only reads the service url
+		if store.TLSConfig != nil {
+			return field.Invalid(path.Child("tlsConfig"), store.TLSConfig,
+				"tlsConfig is only supported by a custom javaProvider")
+		}
+		if store.AuthSecret != "" {
+			return field.Invalid(path.Child("authSecret"), store.AuthSecret,
+				"authSecret is only supported by a custom javaProvider")
+		}
+		if store.Namespace != "" {
+			return field.Invalid(path.Child("namespace"), store.Namespace,
+				"namespace is only supported by a custom javaProvider")
+		}
+		if store.Table != "" {
+			return field.Invalid(path.Child("table"), store.Table, "table is only supported by a custom javaProvider")
+		}
+		if store.JavaProvider != nil && store.JavaProvider.Config != nil {
+			return field.Invalid(path.Child("javaProvider", "config"), store.JavaProvider.Config,
+				"config is only supported by a custom javaProvider")
+		}
+	}
+	if store.TLSConfig != nil && (store.TLSConfig.CertSecretName == "") != (store.TLSConfig.CertSecretKey == "") {
+		return field.Invalid(path.Child("tlsConfig"), store.TLSConfig,
+			"certSecretName and certSecretKey must be set together")
+	}
+	if store.AuthSecret != "" {
+		if errs := validation.IsDNS1123Subdomain(store.AuthSecret); len(errs) > 0 {
+			return field.Invalid(path.Child("authSecret"), store.AuthSecret, strings.Join(errs, ", "))
+		}
+	}
+	if store.Namespace != "" && !stateStoreNamePattern.MatchString(store.Namespace) {
+		return field.Invalid(path.Child("namespace"), store.Namespace,
+			"namespace must only contain letters, digits, dashes and underscores")
+	}
+	if store.Table != "" && !stateStoreNamePattern.MatchString(store.Table) {
+		return field.Invalid(path.Child("table"), store.Table,
+			"table must only contain letters, digits, dashes and underscores")
+	}
+	return nil
+}
+
+func hasCustomStateStoreProvider(store *PulsarStateStore) bool {
+	return store.JavaProvider != nil && store.JavaProvider.ClassName != "" &&
+		store.JavaProvider.ClassName != DefaultStateStoreProviderClass
+}
+
 func isGolangRuntime(runtime Runtime) bool {
 	return runtime.Golang != nil && runtime.Python == nil && runtime.Java == nil
 }
 
-func validateWindowConfigs(windowConfig *WindowConfig) *field.Error {
-	if windowConfig != nil {
-		if windowConfig.WindowLengthDurationMs == nil && windowConfig.WindowLengthCount == nil {
-			return field.Invalid(field.NewPath("spec").Child("windowConfig"), windowConfig,
-				"Window length is not specified")
+func validateWindowConfigs(windowConfig *WindowConfig, runtime Runtime) []*field.Error {
+	var allErrs field.ErrorList
+	if windowConfig == nil {
+		return allErrs
+	}
+	path := field.NewPath("spec").Child("windowConfig")
+	if runtime.Java == nil {
+		allErrs = append(allErrs, field.Invalid(path, windowConfig,
+			"Window functions are only supported by the Java runtime"))
+	}
+
+	// count and time windows
+	if windowConfig.WindowLengthDurationMs == nil && windowConfig.WindowLengthCount == nil {
+		allErrs = append(allErrs, field.Invalid(path, windowConfig, "Window length is not specified"))
+	}
+	if windowConfig.WindowLengthDurationMs != nil && windowConfig.WindowLengthCount != nil {
+		allErrs = append(allErrs, field.Invalid(path, windowConfig,
+			"Window length for time and count are set! Please set one or the other"))
+	}
+	if windowConfig.WindowLengthCount != nil && *windowConfig.WindowLengthCount <= 0 {
+		allErrs = append(allErrs, field.Invalid(path.Child("windowLengthCount"), *windowConfig.WindowLengthCount,
+			"Window length must be positive"))
+	}
+	if windowConfig.WindowLengthDurationMs != nil && *windowConfig.WindowLengthDurationMs <= 0 {
+		allErrs = append(allErrs, field.Invalid(path.Child("windowLengthDurationMs"),
+			*windowConfig.WindowLengthDurationMs, "Window length must be positive"))
+	}
+
+	// tumbling and sliding windows
+	if windowConfig.SlidingIntervalCount != nil && windowConfig.SlidingIntervalDurationMs != nil {
+		allErrs = append(allErrs, field.Invalid(path, windowConfig,
+			"Sliding interval for time and count are set! Please set one or the other"))
+	}
+	if windowConfig.SlidingIntervalCount != nil && *windowConfig.SlidingIntervalCount <= 0 {
+		allErrs = append(allErrs, field.Invalid(path.Child("slidingIntervalCount"),
+			*windowConfig.SlidingIntervalCount, "Sliding interval must be positive"))
+	} else if windowConfig.SlidingIntervalCount != nil && windowConfig.WindowLengthCount != nil &&
+		*windowConfig.WindowLengthCount > 0 && *windowConfig.SlidingIntervalCount > *windowConfig.WindowLengthCount {
+		allErrs = append(allErrs, field.Invalid(path.Child("slidingIntervalCount"),
+			*windowConfig.SlidingIntervalCount, "Sliding interval cannot be longer than the window length"))
+	}
+	if windowConfig.SlidingIntervalDurationMs != nil && *windowConfig.SlidingIntervalDurationMs <= 0 {
+		allErrs = append(allErrs, field.Invalid(path.Child("slidingIntervalDurationMs"),
+			*windowConfig.SlidingIntervalDurationMs, "Sliding interval must be positive"))
+	} else if windowConfig.SlidingIntervalDurationMs != nil && windowConfig.WindowLengthDurationMs != nil &&
+		*windowConfig.WindowLengthDurationMs > 0 &&
+		*windowConfig.SlidingIntervalDurationMs > *windowConfig.WindowLengthDurationMs {
+		allErrs = append(allErrs, field.Invalid(path.Child("slidingIntervalDurationMs"),
+			*windowConfig.SlidingIntervalDurationMs, "Sliding interval cannot be longer than the window length"))
+	}
+
+	// the lag, watermark and late data only apply to the windows timed by the timestamps of the messages
+	if windowConfig.TimestampExtractorClassName == nil {
+		if windowConfig.MaxLagMs != nil {
+			allErrs = append(allErrs, field.Invalid(path.Child("maxLagMs"), *windowConfig.MaxLagMs,
+				"Lag duration requires timestampExtractorClassName"))
 		}
-		if windowConfig.WindowLengthDurationMs != nil && windowConfig.WindowLengthCount != nil {
-			return field.Invalid(field.NewPath("spec").Child("windowConfig"), windowConfig,
-				"Window length for time and count are set! Please set one or the other")
+		if windowConfig.WatermarkEmitIntervalMs != nil {
+			allErrs = append(allErrs, field.Invalid(path.Child("watermarkEmitIntervalMs"),
+				*windowConfig.WatermarkEmitIntervalMs, "Watermark interval requires timestampExtractorClassName"))
 		}
-		if windowConfig.WindowLengthCount != nil && *windowConfig.WindowLengthCount <= 0 {
-			return field.Invalid(field.NewPath("spec").Child("windowConfig"), windowConfig.WindowLengthCount,
-				"Window length must be positive")
+		if windowConfig.LateDataTopic != "" {
+			allErrs = append(allErrs, field.Invalid(path.Child("lateDataTopic"), windowConfig.LateDataTopic,
+				"Late data topic requires timestampExtractorClassName"))
 		}
-		if windowConfig.WindowLengthDurationMs != nil && *windowConfig.WindowLengthDurationMs <= 0 {
-			return field.Invalid(field.NewPath("spec").Child("windowConfig"), windowConfig.WindowLengthDurationMs,
-				"Window length must be positive")
+		return allErrs
+	}
+	if *windowConfig.TimestampExtractorClassName == "" {
+		allErrs = append(allErrs, field.Invalid(path.Child("timestampExtractorClassName"),
+			*windowConfig.TimestampExtractorClassName, "Timestamp extractor class name cannot be empty"))
+	}
+	if windowConfig.MaxLagMs != nil && *windowConfig.MaxLagMs < 0 {
+		allErrs = append(allErrs, field.Invalid(path.Child("maxLagMs"), *windowConfig.MaxLagMs,
+			"Lag duration cannot be negative"))
+	}
+	if windowConfig.WatermarkEmitIntervalMs != nil && *windowConfig.WatermarkEmitIntervalMs <= 0 {
+		allErrs = append(allErrs, field.Invalid(path.Child("watermarkEmitIntervalMs"),
+			*windowConfig.WatermarkEmitIntervalMs, "Watermark interval must be positive"))
+	}
+	if windowConfig.LateDataTopic != "" {
+		if err := isValidTopicName(windowConfig.LateDataTopic); err != nil {
+			allErrs = append(allErrs, field.Invalid(path.Child("lateDataTopic"), windowConfig.LateDataTopic,
+				fmt.Sprintf("Late data topic %s is invalid", windowConfig.LateDataTopic)))
+		}
+	}
+	return allErrs
+}
+
+func validateBatchSourceConfig(config *BatchSourceConfig) []*field.Error {
+	var allErrs field.ErrorList
+	if config == nil {
+		return allErrs
+	}
+	path := field.NewPath("spec").Child("batchSourceConfig")
+	if config.Trigger == nil {
+		if config.DiscoveryTriggererClassName == "" {
+			allErrs = append(allErrs, field.Required(path.Child("discoveryTriggererClassName"),
+				"discoveryTriggererClassName is required when trigger is not set"))
+		}
+		return allErrs
+	}
+
+	trigger := config.Trigger
+	presets := 0
+	for _, set := range []bool{trigger.Cron != "", trigger.Interval != nil, trigger.OnDemand} {
+		if set {
+			presets++
+		}
+	}
+	if presets != 1 {
+		allErrs = append(allErrs, field.Invalid(path.Child("trigger"), trigger,
+			"exactly one of cron, interval and onDemand must be set"))
+		return allErrs
+	}
+	if trigger.Cron != "" {
+		if err := validateCronExpression(trigger.Cron); err != nil {
+			allErrs = append(allErrs, field.Invalid(path.Child("trigger", "cron"), trigger.Cron, err.Error()))
+		}
+	}
+	if trigger.Interval != nil {
+		if _, err := makeIntervalCron(trigger.Interval.Duration); err != nil {
+			allErrs = append(allErrs, field.Invalid(path.Child("trigger", "interval"), trigger.Interval.Duration.String(),
+				err.Error()))
+		}
+	}
+	if className, _, err := trigger.discoveryTriggerer(); err == nil && config.DiscoveryTriggererClassName != "" &&
+		config.DiscoveryTriggererClassName != className {
+		allErrs = append(allErrs, field.Invalid(path.Child("discoveryTriggererClassName"),
+			config.DiscoveryTriggererClassName, "discoveryTriggererClassName cannot be used together with trigger"))
+	}
+	return allErrs
+}
+
+// cronField is a field of the Quartz cron expressions run by the cron triggerer
+type cronField struct {
+	name     string
+	min, max int
+	names    []string
+	// special are the characters allowed besides the values, ranges and increments
+	special string
+}
+
+var cronFields = []cronField{
+	{name: "seconds", min: 0, max: 59},
+	{name: "minutes", min: 0, max: 59},
+	{name: "hours", min: 0, max: 23},
+	{name: "day of month", min: 1, max: 31, special: "?LW"},
+	{name: "month", min: 1, max: 12,
+		names: []string{"JAN", "FEB", "MAR", "APR", "MAY", "JUN", "JUL", "AUG", "SEP", "OCT", "NOV", "DEC"}},
+	{name: "day of week", min: 1, max: 7, names: []string{"SUN", "MON", "TUE", "WED", "THU", "FRI", "SAT"},
+		special: "?L#"},
+	{name: "year", min: 1970, max: 2099},
+}
+
+var (
+	cronDayOfMonthPattern = regexp.MustCompile(`^(L(-[0-9]+)?|LW|[0-9]+W)$`)
+	cronDayOfWeekPattern  = regexp.MustCompile(`^([0-9]+|[A-Z]{3})(L|#[1-5])$`)
+)
+
+// validateCronExpression checks the syntax of a Quartz cron expression: seconds, minutes, hours, day of month,
+// month, day of week and an optional year, one of the day fields must be "?"
+func validateCronExpression(expression string) error {
+	fields := strings.Fields(expression)
+	if len(fields) != 6 && len(fields) != 7 {
+		return fmt.Errorf("cron expression must have 6 or 7 fields, the first one is the seconds")
+	}
+	for i, value := range fields {
+		if err := validateCronField(cronFields[i], strings.ToUpper(value)); err != nil {
+			return err
 		}
-		if windowConfig.SlidingIntervalCount != nil && *windowConfig.SlidingIntervalCount <= 0 {
-			return field.Invalid(field.NewPath("spec").Child("windowConfig"), windowConfig.SlidingIntervalCount,
-				"Sliding interval must be positive")
+	}
+	if (fields[3] == "?") == (fields[5] == "?") {
+		return fmt.Errorf("one of the day of month and day of week fields must be \"?\"")
+	}
+	return nil
+}
+
+func validateCronField(f cronField, value string) error {
+	if value == "?" {
+		if !strings.Contains(f.special, "?") {
+			return fmt.Errorf("\"?\" is not allowed in the %s field", f.name)
 		}
-		if windowConfig.SlidingIntervalDurationMs != nil && *windowConfig.SlidingIntervalDurationMs <= 0 {
-			return field.Invalid(field.NewPath("spec").Child("windowConfig"), windowConfig.SlidingIntervalDurationMs,
-				"Sliding interval must be positive")
+		return nil
+	}
+	for _, item := range strings.Split(value, ",") {
+		// the last day, weekday and nth day of week items of the day fields
+		if strings.Contains(f.special, "W") && cronDayOfMonthPattern.MatchString(item) {
+			if day := strings.TrimSuffix(item, "W"); day != item && day != "L" {
+				if err := checkCronValue(f, day); err != nil {
+					return err
+				}
+			}
+			continue
 		}
-		if windowConfig.TimestampExtractorClassName != nil {
-			if windowConfig.MaxLagMs != nil && *windowConfig.MaxLagMs <= 0 {
-				return field.Invalid(field.NewPath("spec").Child("windowConfig"), windowConfig.MaxLagMs,
-					"Lag duration must be positive")
+		if strings.Contains(f.special, "#") && (item == "L" || cronDayOfWeekPattern.MatchString(item)) {
+			if item != "L" {
+				if err := checkCronValue(f, item[:strings.IndexAny(item, "L#")]); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+		base, step, hasStep := strings.Cut(item, "/")
+		if hasStep {
+			if n, err := strconv.Atoi(step); err != nil || n <= 0 {
+				return fmt.Errorf("invalid increment %q in the %s field", step, f.name)
 			}
-			if windowConfig.WatermarkEmitIntervalMs != nil && *windowConfig.WatermarkEmitIntervalMs <= 0 {
-				return field.Invalid(field.NewPath("spec").Child("windowConfig"), windowConfig.WatermarkEmitIntervalMs,
-					"Watermark interval must be positive")
+		}
+		if base == "*" {
+			continue
+		}
+		from, to, isRange := strings.Cut(base, "-")
+		if err := checkCronValue(f, from); err != nil {
+			return err
+		}
+		if isRange {
+			if err := checkCronValue(f, to); err != nil {
+				return err
 			}
 		}
 	}
 	return nil
 }
 
+func checkCronValue(f cronField, value string) error {
+	for _, name := range f.names {
+		if value == name {
+			return nil
+		}
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < f.min || n > f.max {
+		return fmt.Errorf("invalid value %q in the %s field, it must be between %d and %d", value, f.name, f.min,
+			f.max)
+	}
+	return nil
+}
+
 func validateMessaging(messaging *Messaging) *field.Error {
-	if messaging == nil || messaging.Pulsar == nil || messaging.Pulsar.PulsarConfig == "" {
+	// the components without pulsar config fall back to the controller defaults or the default
+	// PulsarConnection of the namespace, which are resolved by the controller
+	if messaging != nil && messaging.Pulsar != nil && messaging.Pulsar.PulsarConfig != "" &&
+		messaging.Pulsar.PulsarConnection != "" {
 		return field.Invalid(field.NewPath("spec").Child("pulsar"), messaging,
-			"Pulsar configuration needs to be set")
+			"pulsarConfig and pulsarConnection cannot be set at the same time")
 	}
 	return nil
 }
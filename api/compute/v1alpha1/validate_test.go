@@ -0,0 +1,207 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package v1alpha1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+func TestValidatePythonDependencies(t *testing.T) {
+	testData := []struct {
+		python *PythonRuntime
+		errors int
+	}{
+		{&PythonRuntime{Py: "function.py"}, 0},
+		{&PythonRuntime{Py: "function.py", Requirements: []string{"requests>=2.28"},
+			DependencyRepository: "https://pypi.example.com/simple"}, 0},
+		{&PythonRuntime{Py: "function.py", DependenciesLocation: "https://example.com/requests.whl"}, 0},
+		{&PythonRuntime{Py: "function.py", DependenciesLocation: "function://public/default/deps@v1",
+			DependenciesType: PythonDependenciesVirtualenv}, 0},
+		{&PythonRuntime{Py: "function.py", Requirements: []string{""}}, 1},
+		{&PythonRuntime{Py: "function.py", Requirements: []string{"requests'; rm -rf /'"}}, 1},
+		{&PythonRuntime{Py: "function.py", DependencyRepository: "ftp://pypi.example.com"}, 1},
+		{&PythonRuntime{Py: "function.py", ExtraDependencyRepository: "https://pypi.example.com/simple extra"}, 1},
+		{&PythonRuntime{Py: "function.py", DependenciesType: PythonDependenciesWheel}, 1},
+		{&PythonRuntime{Py: "function.py", DependenciesLocation: "https://example.com/deps.zip"}, 1},
+		{&PythonRuntime{Py: "function.py", DependenciesLocation: "oci://registry.example.com/deps:v1",
+			DependenciesType: PythonDependenciesWheelhouse}, 1},
+	}
+	for _, v := range testData {
+		assert.Len(t, validatePythonDependencies(v.python), v.errors, "%+v", v.python)
+	}
+}
+
+func TestValidateJavaDependencies(t *testing.T) {
+	testData := []struct {
+		java   *JavaRuntime
+		errors int
+	}{
+		{&JavaRuntime{Jar: "function.jar"}, 0},
+		{&JavaRuntime{Jar: "function.jar", Dependencies: []string{"function://public/default/deps@v1",
+			"https://example.com/libs/commons-lang3.jar", "configmap://java-libs/config.jar",
+			"secret://java-secrets/license.jar"}, SharedLibraries: []string{"shared-jars"}}, 0},
+		{&JavaRuntime{Jar: "function.jar", JarLocation: "oci://registry.example.com/function:v1",
+			Dependencies: []string{"https://example.com/libs/commons-lang3.jar"}}, 0},
+		{&JavaRuntime{Jar: "function.jar", Dependencies: []string{"oci://registry.example.com/deps:v1"}}, 1},
+		{&JavaRuntime{Jar: "function.jar", JarLocation: "oci://registry.example.com/function:v1",
+			Dependencies: []string{"function://public/default/deps@v1"}}, 1},
+		{&JavaRuntime{Jar: "function.jar", Dependencies: []string{"configmap://java-libs"}}, 1},
+		{&JavaRuntime{Jar: "function.jar", Dependencies: []string{"secret://Java_Secrets/license.jar"}}, 1},
+		{&JavaRuntime{Jar: "function.jar", Dependencies: []string{"ftp://example.com/deps.jar"}}, 1},
+		{&JavaRuntime{Jar: "function.jar", Dependencies: []string{"https://example.com/deps.jar 'x'"}}, 1},
+		{&JavaRuntime{Jar: "function.jar", SharedLibraries: []string{"Shared_Jars"}}, 1},
+	}
+	for _, v := range testData {
+		assert.Len(t, validateJavaDependencies(v.java), v.errors, "%+v", v.java)
+	}
+}
+
+func TestValidateRuntimeLogConfig(t *testing.T) {
+	testData := []struct {
+		log    *RuntimeLogConfig
+		errors int
+	}{
+		{nil, 0},
+		{&RuntimeLogConfig{Format: LogFormatJSON, Loggers: map[string]LogLevel{"org.apache.pulsar": LogLevelWarn}}, 0},
+		{&RuntimeLogConfig{Pattern: "%d [%t] %-5level %logger{36} - %msg%n"}, 0},
+		{&RuntimeLogConfig{Format: LogFormatJSON, Pattern: "%m%n"}, 1},
+		{&RuntimeLogConfig{Pattern: "%m\n%n"}, 1},
+		{&RuntimeLogConfig{LogConfig: &LogConfig{Name: "logs", Key: "log4j.xml"}, Format: LogFormatJSON}, 1},
+		{&RuntimeLogConfig{LogConfig: &LogConfig{Name: "logs", Key: "logging.ini"}, StdoutWithLogTopic: true}, 1},
+		{&RuntimeLogConfig{Loggers: map[string]LogLevel{"": LogLevelWarn, "org.apache pulsar": LogLevelWarn}}, 2},
+	}
+	for _, v := range testData {
+		assert.Len(t, validateRuntimeLogConfig(field.NewPath("spec", "java", "log"), v.log), v.errors, "%+v", v.log)
+	}
+
+	golang := &GoRuntime{Go: "go-func", Log: &RuntimeLogConfig{Pattern: "%m",
+		Loggers: map[string]LogLevel{"main": LogLevelDebug}}}
+	assert.Len(t, validateGolangRuntime(golang), 2)
+}
+
+func TestValidateStatefulFunctionConfigs(t *testing.T) {
+	java := Runtime{Java: &JavaRuntime{Jar: "function.jar"}}
+	python := Runtime{Python: &PythonRuntime{Py: "function.py"}}
+	golang := Runtime{Golang: &GoRuntime{Go: "function"}}
+	testData := []struct {
+		store   *PulsarStateStore
+		runtime Runtime
+		valid   bool
+	}{
+		{&PulsarStateStore{ServiceURL: "bk://bookie:4181"}, java, true},
+		{&PulsarStateStore{ServiceURL: "bk://bookie:4181"}, python, true},
+		{&PulsarStateStore{ServiceURL: "bk://bookie:4181"}, golang, false},
+		{&PulsarStateStore{}, java, false},
+		{&PulsarStateStore{ServiceURL: "bk://bookie:4181", Namespace: "public_state", Table: "counts",
+			AuthSecret: "state-auth", TLSConfig: &StateStoreTLSConfig{TLSConfig: TLSConfig{Enabled: true,
+				CertSecretName: "state-tls", CertSecretKey: "ca.crt"}},
+			JavaProvider: &PulsarStateStoreJavaProvider{ClassName: "org.example.StateStoreProvider"}}, java, true},
+		{&PulsarStateStore{ServiceURL: "bk://bookie:4181", Table: "counts"}, java, false},
+		{&PulsarStateStore{ServiceURL: "bk://bookie:4181", AuthSecret: "state-auth",
+			JavaProvider: &PulsarStateStoreJavaProvider{ClassName: DefaultStateStoreProviderClass}}, java, false},
+		{&PulsarStateStore{ServiceURL: "bk://bookie:4181", Table: "counts"}, python, false},
+		{&PulsarStateStore{ServiceURL: "bk://bookie:4181", AuthSecret: "state-auth"}, python, false},
+		{&PulsarStateStore{ServiceURL: "bk://bookie:4181", Namespace: "public/state",
+			JavaProvider: &PulsarStateStoreJavaProvider{ClassName: "org.example.StateStoreProvider"}}, java, false},
+		{&PulsarStateStore{ServiceURL: "bk://bookie:4181", AuthSecret: "State_Auth"}, java, false},
+		{&PulsarStateStore{ServiceURL: "bk://bookie:4181", TLSConfig: &StateStoreTLSConfig{
+			TLSConfig: TLSConfig{Enabled: true, CertSecretName: "state-tls"}}}, java, false},
+	}
+	for _, v := range testData {
+		err := validateStatefulFunctionConfigs(&Stateful{Pulsar: v.store}, v.runtime)
+		assert.Equal(t, v.valid, err == nil, "%+v: %v", v.store, err)
+	}
+}
+
+func TestValidateJVMOptions(t *testing.T) {
+	percentage := func(v int32) *int32 { return &v }
+	quantity := func(v string) *resource.Quantity {
+		q := resource.MustParse(v)
+		return &q
+	}
+	requests := corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("1Gi")},
+	}
+	limits := corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("512Mi")},
+		Limits:   corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("2Gi")},
+	}
+	mounts := []corev1.VolumeMount{
+		{Name: "dumps", MountPath: "/dumps"},
+		{Name: "config", MountPath: "/config", ReadOnly: true},
+	}
+	testData := []struct {
+		java      *JavaRuntime
+		resources corev1.ResourceRequirements
+		errors    int
+	}{
+		{&JavaRuntime{Jar: "function.jar"}, corev1.ResourceRequirements{}, 0},
+		{&JavaRuntime{Jar: "function.jar", JavaOpts: []string{"-Xmx512m -XX:+UseG1GC"}}, requests, 0},
+		{&JavaRuntime{Jar: "function.jar", JavaOpts: []string{"-Xmx2g"}}, requests, 1},
+		{&JavaRuntime{Jar: "function.jar", JavaOpts: []string{"-Xmx2g"}}, limits, 0},
+		{&JavaRuntime{Jar: "function.jar", JavaOpts: []string{"-XX:MaxDirectMemorySize=3G"}}, limits, 1},
+		{&JavaRuntime{Jar: "function.jar", JVM: &JVMOptions{}}, corev1.ResourceRequirements{}, 1},
+		{&JavaRuntime{Jar: "function.jar", JVM: &JVMOptions{}}, requests, 0},
+		{&JavaRuntime{Jar: "function.jar", JVM: &JVMOptions{HeapPercentage: percentage(70),
+			DirectMemoryPercentage: percentage(20), MaxMetaspaceSize: quantity("128Mi"), GC: ZGC, GCLogging: true,
+			HeapDumpPath: "/dumps/function"}, JavaOpts: []string{"-XX:+ExitOnOutOfMemoryError", "-Xms1g"}}, limits, 0},
+		{&JavaRuntime{Jar: "function.jar", JVM: &JVMOptions{HeapPercentage: percentage(80)}}, requests, 1},
+		{&JavaRuntime{Jar: "function.jar", JVM: &JVMOptions{HeapPercentage: percentage(70),
+			MaxMetaspaceSize: quantity("256Mi")}}, requests, 1},
+		{&JavaRuntime{Jar: "function.jar", JVM: &JVMOptions{MaxMetaspaceSize: quantity("0")}}, requests, 1},
+		{&JavaRuntime{Jar: "function.jar", JVM: &JVMOptions{}, JavaOpts: []string{"-Xmx512m",
+			"-XX:+UseParallelGC -XX:MaxRAMPercentage=75"}}, requests, 3},
+		{&JavaRuntime{Jar: "function.jar", JVM: &JVMOptions{}, JavaOpts: []string{"-Xms2g"}}, limits, 1},
+		{&JavaRuntime{Jar: "function.jar", JVM: &JVMOptions{HeapDumpPath: "dumps"}}, requests, 1},
+		{&JavaRuntime{Jar: "function.jar", JVM: &JVMOptions{HeapDumpPath: "/tmp"}}, requests, 1},
+		{&JavaRuntime{Jar: "function.jar", JVM: &JVMOptions{HeapDumpPath: "/config/dumps"}}, requests, 1},
+		{&JavaRuntime{Jar: "function.jar", JVM: &JVMOptions{HeapDumpPath: "/dumps/$(id)"}}, requests, 1},
+	}
+	for _, v := range testData {
+		assert.Len(t, validateJVMOptions(v.java, v.resources, mounts), v.errors, "%+v", v.java)
+	}
+}
+
+func TestJVMOptionsDefaults(t *testing.T) {
+	small := resource.MustParse("512Mi")
+	large := resource.MustParse("4Gi")
+	jvm := &JVMOptions{}
+	assert.Equal(t, DefaultSmallJVMHeapPercentage, jvm.GetHeapPercentage(small))
+	assert.Equal(t, DefaultJVMHeapPercentage, jvm.GetHeapPercentage(large))
+	assert.Equal(t, DefaultJVMDirectMemoryPercentage, jvm.GetDirectMemoryPercentage())
+	assert.Equal(t, SerialGC, jvm.GetGC(small))
+	assert.Equal(t, G1GC, jvm.GetGC(large))
+
+	heap := int32(75)
+	jvm = &JVMOptions{HeapPercentage: &heap, GC: ParallelGC}
+	assert.Equal(t, heap, jvm.GetHeapPercentage(small))
+	assert.Equal(t, ParallelGC, jvm.GetGC(small))
+
+	assert.Equal(t, large, GetJVMMemory(corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{corev1.ResourceMemory: small},
+		Limits:   corev1.ResourceList{corev1.ResourceMemory: large},
+	}))
+	assert.Equal(t, small, GetJVMMemory(corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{corev1.ResourceMemory: small},
+	}))
+}
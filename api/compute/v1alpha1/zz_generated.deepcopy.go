@@ -25,6 +25,7 @@ package v1alpha1
 import (
 	"k8s.io/api/autoscaling/v2beta2"
 	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	autoscaling_k8s_iov1 "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/apis/autoscaling.k8s.io/v1"
 )
@@ -56,6 +57,11 @@ func (in *BatchSourceConfig) DeepCopyInto(out *BatchSourceConfig) {
 		in, out := &in.DiscoveryTriggererConfig, &out.DiscoveryTriggererConfig
 		*out = (*in).DeepCopy()
 	}
+	if in.Trigger != nil {
+		in, out := &in.Trigger, &out.Trigger
+		*out = new(BatchSourceTrigger)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BatchSourceConfig.
@@ -68,6 +74,41 @@ func (in *BatchSourceConfig) DeepCopy() *BatchSourceConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BatchSourceTrigger) DeepCopyInto(out *BatchSourceTrigger) {
+	*out = *in
+	if in.Interval != nil {
+		in, out := &in.Interval, &out.Interval
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BatchSourceTrigger.
+func (in *BatchSourceTrigger) DeepCopy() *BatchSourceTrigger {
+	if in == nil {
+		return nil
+	}
+	out := new(BatchSourceTrigger)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ComponentStatus) DeepCopyInto(out *ComponentStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComponentStatus.
+func (in *ComponentStatus) DeepCopy() *ComponentStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ComponentStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Config.
 func (in *Config) DeepCopy() *Config {
 	if in == nil {
@@ -390,6 +431,28 @@ func (in *FunctionMesh) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FunctionMeshDefaults) DeepCopyInto(out *FunctionMeshDefaults) {
+	*out = *in
+	in.Pod.DeepCopyInto(&out.Pod)
+	in.Messaging.DeepCopyInto(&out.Messaging)
+	if in.StateConfig != nil {
+		in, out := &in.StateConfig, &out.StateConfig
+		*out = new(Stateful)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FunctionMeshDefaults.
+func (in *FunctionMeshDefaults) DeepCopy() *FunctionMeshDefaults {
+	if in == nil {
+		return nil
+	}
+	out := new(FunctionMeshDefaults)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *FunctionMeshList) DeepCopyInto(out *FunctionMeshList) {
 	*out = *in
@@ -425,6 +488,11 @@ func (in *FunctionMeshList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *FunctionMeshSpec) DeepCopyInto(out *FunctionMeshSpec) {
 	*out = *in
+	if in.Defaults != nil {
+		in, out := &in.Defaults, &out.Defaults
+		*out = new(FunctionMeshDefaults)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Sources != nil {
 		in, out := &in.Sources, &out.Sources
 		*out = make([]SourceSpec, len(*in))
@@ -487,6 +555,23 @@ func (in *FunctionMeshStatus) DeepCopyInto(out *FunctionMeshStatus) {
 		*out = new(ResourceCondition)
 		**out = **in
 	}
+	if in.StartupProgress != nil {
+		in, out := &in.StartupProgress, &out.StartupProgress
+		*out = new(StartupProgress)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Components != nil {
+		in, out := &in.Components, &out.Components
+		*out = make([]ComponentStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FunctionMeshStatus.
@@ -553,6 +638,16 @@ func (in *FunctionSpec) DeepCopyInto(out *FunctionSpec) {
 		*out = new(int32)
 		**out = **in
 	}
+	if in.MaxBufferedTuples != nil {
+		in, out := &in.MaxBufferedTuples, &out.MaxBufferedTuples
+		*out = new(int32)
+		**out = **in
+	}
+	if in.RetryPolicy != nil {
+		in, out := &in.RetryPolicy, &out.RetryPolicy
+		*out = new(RetryPolicy)
+		(*in).DeepCopyInto(*out)
+	}
 	in.Pod.DeepCopyInto(&out.Pod)
 	if in.WindowConfig != nil {
 		in, out := &in.WindowConfig, &out.WindowConfig
@@ -578,6 +673,114 @@ func (in *FunctionSpec) DeepCopy() *FunctionSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FunctionStateSnapshot) DeepCopyInto(out *FunctionStateSnapshot) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FunctionStateSnapshot.
+func (in *FunctionStateSnapshot) DeepCopy() *FunctionStateSnapshot {
+	if in == nil {
+		return nil
+	}
+	out := new(FunctionStateSnapshot)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *FunctionStateSnapshot) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FunctionStateSnapshotList) DeepCopyInto(out *FunctionStateSnapshotList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]FunctionStateSnapshot, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FunctionStateSnapshotList.
+func (in *FunctionStateSnapshotList) DeepCopy() *FunctionStateSnapshotList {
+	if in == nil {
+		return nil
+	}
+	out := new(FunctionStateSnapshotList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *FunctionStateSnapshotList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FunctionStateSnapshotSpec) DeepCopyInto(out *FunctionStateSnapshotSpec) {
+	*out = *in
+	if in.Keys != nil {
+		in, out := &in.Keys, &out.Keys
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	in.Storage.DeepCopyInto(&out.Storage)
+	if in.BackoffLimit != nil {
+		in, out := &in.BackoffLimit, &out.BackoffLimit
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FunctionStateSnapshotSpec.
+func (in *FunctionStateSnapshotSpec) DeepCopy() *FunctionStateSnapshotSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(FunctionStateSnapshotSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FunctionStateSnapshotStatus) DeepCopyInto(out *FunctionStateSnapshotStatus) {
+	*out = *in
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FunctionStateSnapshotStatus.
+func (in *FunctionStateSnapshotStatus) DeepCopy() *FunctionStateSnapshotStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(FunctionStateSnapshotStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *FunctionStatus) DeepCopyInto(out *FunctionStatus) {
 	*out = *in
@@ -588,6 +791,7 @@ func (in *FunctionStatus) DeepCopyInto(out *FunctionStatus) {
 			(*out)[key] = val
 		}
 	}
+	in.PauseStatus.DeepCopyInto(&out.PauseStatus)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FunctionStatus.
@@ -661,6 +865,36 @@ func (in *InputConf) DeepCopy() *InputConf {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *JVMOptions) DeepCopyInto(out *JVMOptions) {
+	*out = *in
+	if in.HeapPercentage != nil {
+		in, out := &in.HeapPercentage, &out.HeapPercentage
+		*out = new(int32)
+		**out = **in
+	}
+	if in.DirectMemoryPercentage != nil {
+		in, out := &in.DirectMemoryPercentage, &out.DirectMemoryPercentage
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MaxMetaspaceSize != nil {
+		in, out := &in.MaxMetaspaceSize, &out.MaxMetaspaceSize
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JVMOptions.
+func (in *JVMOptions) DeepCopy() *JVMOptions {
+	if in == nil {
+		return nil
+	}
+	out := new(JVMOptions)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *JavaRuntime) DeepCopyInto(out *JavaRuntime) {
 	*out = *in
@@ -674,6 +908,21 @@ func (in *JavaRuntime) DeepCopyInto(out *JavaRuntime) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.Dependencies != nil {
+		in, out := &in.Dependencies, &out.Dependencies
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.SharedLibraries != nil {
+		in, out := &in.SharedLibraries, &out.SharedLibraries
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.JVM != nil {
+		in, out := &in.JVM, &out.JVM
+		*out = new(JVMOptions)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JavaRuntime.
@@ -778,6 +1027,31 @@ func (in *OutputConf) DeepCopy() *OutputConf {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PauseStatus) DeepCopyInto(out *PauseStatus) {
+	*out = *in
+	if in.PausedReplicas != nil {
+		in, out := &in.PausedReplicas, &out.PausedReplicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.PausedSpecReplicas != nil {
+		in, out := &in.PausedSpecReplicas, &out.PausedSpecReplicas
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PauseStatus.
+func (in *PauseStatus) DeepCopy() *PauseStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PauseStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PodPolicy) DeepCopyInto(out *PodPolicy) {
 	*out = *in
@@ -911,6 +1185,105 @@ func (in *ProducerConfig) DeepCopy() *ProducerConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PulsarConnection) DeepCopyInto(out *PulsarConnection) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	out.Status = in.Status
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PulsarConnection.
+func (in *PulsarConnection) DeepCopy() *PulsarConnection {
+	if in == nil {
+		return nil
+	}
+	out := new(PulsarConnection)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PulsarConnection) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PulsarConnectionList) DeepCopyInto(out *PulsarConnectionList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]PulsarConnection, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PulsarConnectionList.
+func (in *PulsarConnectionList) DeepCopy() *PulsarConnectionList {
+	if in == nil {
+		return nil
+	}
+	out := new(PulsarConnectionList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PulsarConnectionList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PulsarConnectionSpec) DeepCopyInto(out *PulsarConnectionSpec) {
+	*out = *in
+	if in.TLSConfig != nil {
+		in, out := &in.TLSConfig, &out.TLSConfig
+		*out = new(PulsarTLSConfig)
+		**out = **in
+	}
+	if in.AuthConfig != nil {
+		in, out := &in.AuthConfig, &out.AuthConfig
+		*out = new(AuthConfig)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PulsarConnectionSpec.
+func (in *PulsarConnectionSpec) DeepCopy() *PulsarConnectionSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PulsarConnectionSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PulsarConnectionStatus) DeepCopyInto(out *PulsarConnectionStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PulsarConnectionStatus.
+func (in *PulsarConnectionStatus) DeepCopy() *PulsarConnectionStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PulsarConnectionStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PulsarMessaging) DeepCopyInto(out *PulsarMessaging) {
 	*out = *in
@@ -944,6 +1317,11 @@ func (in *PulsarStateStore) DeepCopyInto(out *PulsarStateStore) {
 		*out = new(PulsarStateStoreJavaProvider)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.TLSConfig != nil {
+		in, out := &in.TLSConfig, &out.TLSConfig
+		*out = new(StateStoreTLSConfig)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PulsarStateStore.
@@ -999,6 +1377,11 @@ func (in *PythonRuntime) DeepCopyInto(out *PythonRuntime) {
 		*out = new(RuntimeLogConfig)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Requirements != nil {
+		in, out := &in.Requirements, &out.Requirements
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PythonRuntime.
@@ -1026,6 +1409,56 @@ func (in *ResourceCondition) DeepCopy() *ResourceCondition {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RetryPolicy) DeepCopyInto(out *RetryPolicy) {
+	*out = *in
+	if in.DeadLetterTopic != nil {
+		in, out := &in.DeadLetterTopic, &out.DeadLetterTopic
+		*out = new(RetryTopic)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RetryLetterTopic != nil {
+		in, out := &in.RetryLetterTopic, &out.RetryLetterTopic
+		*out = new(RetryTopic)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RetryPolicy.
+func (in *RetryPolicy) DeepCopy() *RetryPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(RetryPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RetryTopic) DeepCopyInto(out *RetryTopic) {
+	*out = *in
+	if in.Retention != nil {
+		in, out := &in.Retention, &out.Retention
+		*out = new(TopicRetention)
+		**out = **in
+	}
+	if in.Schema != nil {
+		in, out := &in.Schema, &out.Schema
+		*out = new(TopicSchema)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RetryTopic.
+func (in *RetryTopic) DeepCopy() *RetryTopic {
+	if in == nil {
+		return nil
+	}
+	out := new(RetryTopic)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Runtime) DeepCopyInto(out *Runtime) {
 	*out = *in
@@ -1069,6 +1502,13 @@ func (in *RuntimeLogConfig) DeepCopyInto(out *RuntimeLogConfig) {
 		*out = new(LogConfig)
 		**out = **in
 	}
+	if in.Loggers != nil {
+		in, out := &in.Loggers, &out.Loggers
+		*out = make(map[string]LogLevel, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RuntimeLogConfig.
@@ -1198,6 +1638,16 @@ func (in *SinkSpec) DeepCopyInto(out *SinkSpec) {
 		*out = new(bool)
 		**out = **in
 	}
+	if in.MaxBufferedTuples != nil {
+		in, out := &in.MaxBufferedTuples, &out.MaxBufferedTuples
+		*out = new(int32)
+		**out = **in
+	}
+	if in.RetryPolicy != nil {
+		in, out := &in.RetryPolicy, &out.RetryPolicy
+		*out = new(RetryPolicy)
+		(*in).DeepCopyInto(*out)
+	}
 	in.Pod.DeepCopyInto(&out.Pod)
 	in.Messaging.DeepCopyInto(&out.Messaging)
 	in.Runtime.DeepCopyInto(&out.Runtime)
@@ -1228,6 +1678,7 @@ func (in *SinkStatus) DeepCopyInto(out *SinkStatus) {
 			(*out)[key] = val
 		}
 	}
+	in.PauseStatus.DeepCopyInto(&out.PauseStatus)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SinkStatus.
@@ -1347,6 +1798,11 @@ func (in *SourceSpec) DeepCopyInto(out *SourceSpec) {
 		*out = new(bool)
 		**out = **in
 	}
+	if in.MaxBufferedTuples != nil {
+		in, out := &in.MaxBufferedTuples, &out.MaxBufferedTuples
+		*out = new(int32)
+		**out = **in
+	}
 	in.Pod.DeepCopyInto(&out.Pod)
 	in.Messaging.DeepCopyInto(&out.Messaging)
 	in.Runtime.DeepCopyInto(&out.Runtime)
@@ -1377,6 +1833,11 @@ func (in *SourceStatus) DeepCopyInto(out *SourceStatus) {
 			(*out)[key] = val
 		}
 	}
+	in.PauseStatus.DeepCopyInto(&out.PauseStatus)
+	if in.LastDiscoveryTriggerTime != nil {
+		in, out := &in.LastDiscoveryTriggerTime, &out.LastDiscoveryTriggerTime
+		*out = (*in).DeepCopy()
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SourceStatus.
@@ -1389,6 +1850,97 @@ func (in *SourceStatus) DeepCopy() *SourceStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StartupProgress) DeepCopyInto(out *StartupProgress) {
+	*out = *in
+	if in.Waiting != nil {
+		in, out := &in.Waiting, &out.Waiting
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StartupProgress.
+func (in *StartupProgress) DeepCopy() *StartupProgress {
+	if in == nil {
+		return nil
+	}
+	out := new(StartupProgress)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StateSnapshotObjectStore) DeepCopyInto(out *StateSnapshotObjectStore) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StateSnapshotObjectStore.
+func (in *StateSnapshotObjectStore) DeepCopy() *StateSnapshotObjectStore {
+	if in == nil {
+		return nil
+	}
+	out := new(StateSnapshotObjectStore)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StateSnapshotStorage) DeepCopyInto(out *StateSnapshotStorage) {
+	*out = *in
+	if in.PersistentVolumeClaim != nil {
+		in, out := &in.PersistentVolumeClaim, &out.PersistentVolumeClaim
+		*out = new(StateSnapshotVolume)
+		**out = **in
+	}
+	if in.ObjectStore != nil {
+		in, out := &in.ObjectStore, &out.ObjectStore
+		*out = new(StateSnapshotObjectStore)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StateSnapshotStorage.
+func (in *StateSnapshotStorage) DeepCopy() *StateSnapshotStorage {
+	if in == nil {
+		return nil
+	}
+	out := new(StateSnapshotStorage)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StateSnapshotVolume) DeepCopyInto(out *StateSnapshotVolume) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StateSnapshotVolume.
+func (in *StateSnapshotVolume) DeepCopy() *StateSnapshotVolume {
+	if in == nil {
+		return nil
+	}
+	out := new(StateSnapshotVolume)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StateStoreTLSConfig) DeepCopyInto(out *StateStoreTLSConfig) {
+	*out = *in
+	out.TLSConfig = in.TLSConfig
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StateStoreTLSConfig.
+func (in *StateStoreTLSConfig) DeepCopy() *StateStoreTLSConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(StateStoreTLSConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Stateful) DeepCopyInto(out *Stateful) {
 	*out = *in
@@ -1424,6 +1976,43 @@ func (in *TLSConfig) DeepCopy() *TLSConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TopicRetention) DeepCopyInto(out *TopicRetention) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TopicRetention.
+func (in *TopicRetention) DeepCopy() *TopicRetention {
+	if in == nil {
+		return nil
+	}
+	out := new(TopicRetention)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TopicSchema) DeepCopyInto(out *TopicSchema) {
+	*out = *in
+	if in.Properties != nil {
+		in, out := &in.Properties, &out.Properties
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TopicSchema.
+func (in *TopicSchema) DeepCopy() *TopicSchema {
+	if in == nil {
+		return nil
+	}
+	out := new(TopicSchema)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VPASpec) DeepCopyInto(out *VPASpec) {
 	*out = *in
@@ -32,11 +32,13 @@ type ComputeV1alpha1Interface interface {
 	ConnectorCatalogsGetter
 	FunctionsGetter
 	FunctionMeshesGetter
+	FunctionStateSnapshotsGetter
+	PulsarConnectionsGetter
 	SinksGetter
 	SourcesGetter
 }
 
-// ComputeV1alpha1Client is used to interact with features provided by the compute group.
+// ComputeV1alpha1Client is used to interact with features provided by the compute.functionmesh.io group.
 type ComputeV1alpha1Client struct {
 	restClient rest.Interface
 }
@@ -53,6 +55,14 @@ func (c *ComputeV1alpha1Client) FunctionMeshes(namespace string) FunctionMeshInt
 	return newFunctionMeshes(c, namespace)
 }
 
+func (c *ComputeV1alpha1Client) FunctionStateSnapshots(namespace string) FunctionStateSnapshotInterface {
+	return newFunctionStateSnapshots(c, namespace)
+}
+
+func (c *ComputeV1alpha1Client) PulsarConnections(namespace string) PulsarConnectionInterface {
+	return newPulsarConnections(c, namespace)
+}
+
 func (c *ComputeV1alpha1Client) Sinks(namespace string) SinkInterface {
 	return newSinks(c, namespace)
 }
@@ -41,6 +41,14 @@ func (c *FakeComputeV1alpha1) FunctionMeshes(namespace string) v1alpha1.Function
 	return &FakeFunctionMeshes{c, namespace}
 }
 
+func (c *FakeComputeV1alpha1) FunctionStateSnapshots(namespace string) v1alpha1.FunctionStateSnapshotInterface {
+	return &FakeFunctionStateSnapshots{c, namespace}
+}
+
+func (c *FakeComputeV1alpha1) PulsarConnections(namespace string) v1alpha1.PulsarConnectionInterface {
+	return &FakePulsarConnections{c, namespace}
+}
+
 func (c *FakeComputeV1alpha1) Sinks(namespace string) v1alpha1.SinkInterface {
 	return &FakeSinks{c, namespace}
 }
@@ -37,9 +37,9 @@ type FakeConnectorCatalogs struct {
 	ns   string
 }
 
-var connectorcatalogsResource = schema.GroupVersionResource{Group: "compute", Version: "v1alpha1", Resource: "connectorcatalogs"}
+var connectorcatalogsResource = schema.GroupVersionResource{Group: "compute.functionmesh.io", Version: "v1alpha1", Resource: "connectorcatalogs"}
 
-var connectorcatalogsKind = schema.GroupVersionKind{Group: "compute", Version: "v1alpha1", Kind: "ConnectorCatalog"}
+var connectorcatalogsKind = schema.GroupVersionKind{Group: "compute.functionmesh.io", Version: "v1alpha1", Kind: "ConnectorCatalog"}
 
 // Get takes name of the connectorCatalog, and returns the corresponding connectorCatalog object, and an error if there is any.
 func (c *FakeConnectorCatalogs) Get(ctx context.Context, name string, options v1.GetOptions) (result *v1alpha1.ConnectorCatalog, err error) {
@@ -37,9 +37,9 @@ type FakeFunctionMeshes struct {
 	ns   string
 }
 
-var functionmeshesResource = schema.GroupVersionResource{Group: "compute", Version: "v1alpha1", Resource: "functionmeshes"}
+var functionmeshesResource = schema.GroupVersionResource{Group: "compute.functionmesh.io", Version: "v1alpha1", Resource: "functionmeshes"}
 
-var functionmeshesKind = schema.GroupVersionKind{Group: "compute", Version: "v1alpha1", Kind: "FunctionMesh"}
+var functionmeshesKind = schema.GroupVersionKind{Group: "compute.functionmesh.io", Version: "v1alpha1", Kind: "FunctionMesh"}
 
 // Get takes name of the functionMesh, and returns the corresponding functionMesh object, and an error if there is any.
 func (c *FakeFunctionMeshes) Get(ctx context.Context, name string, options v1.GetOptions) (result *v1alpha1.FunctionMesh, err error) {
@@ -37,9 +37,9 @@ type FakeSinks struct {
 	ns   string
 }
 
-var sinksResource = schema.GroupVersionResource{Group: "compute", Version: "v1alpha1", Resource: "sinks"}
+var sinksResource = schema.GroupVersionResource{Group: "compute.functionmesh.io", Version: "v1alpha1", Resource: "sinks"}
 
-var sinksKind = schema.GroupVersionKind{Group: "compute", Version: "v1alpha1", Kind: "Sink"}
+var sinksKind = schema.GroupVersionKind{Group: "compute.functionmesh.io", Version: "v1alpha1", Kind: "Sink"}
 
 // Get takes name of the sink, and returns the corresponding sink object, and an error if there is any.
 func (c *FakeSinks) Get(ctx context.Context, name string, options v1.GetOptions) (result *v1alpha1.Sink, err error) {
@@ -37,9 +37,9 @@ type FakeSources struct {
 	ns   string
 }
 
-var sourcesResource = schema.GroupVersionResource{Group: "compute", Version: "v1alpha1", Resource: "sources"}
+var sourcesResource = schema.GroupVersionResource{Group: "compute.functionmesh.io", Version: "v1alpha1", Resource: "sources"}
 
-var sourcesKind = schema.GroupVersionKind{Group: "compute", Version: "v1alpha1", Kind: "Source"}
+var sourcesKind = schema.GroupVersionKind{Group: "compute.functionmesh.io", Version: "v1alpha1", Kind: "Source"}
 
 // Get takes name of the source, and returns the corresponding source object, and an error if there is any.
 func (c *FakeSources) Get(ctx context.Context, name string, options v1.GetOptions) (result *v1alpha1.Source, err error) {
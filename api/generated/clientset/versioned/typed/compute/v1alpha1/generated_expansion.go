@@ -25,6 +25,10 @@ type FunctionExpansion interface{}
 
 type FunctionMeshExpansion interface{}
 
+type FunctionStateSnapshotExpansion interface{}
+
+type PulsarConnectionExpansion interface{}
+
 type SinkExpansion interface{}
 
 type SourceExpansion interface{}
@@ -53,6 +53,13 @@ Create the name of the service account to use
 {{- end }}
 {{- end }}
 
+{{/*
+Create the name of the leader election lock of the release
+*/}}
+{{- define "function-mesh-operator.leaderElectionID" -}}
+{{- default (printf "%s.functionmesh.io" (include "function-mesh-operator.fullname" .)) .Values.controllerManager.leaderElectionID }}
+{{- end }}
+
 {{/*
 Volume mounts
 */}}
@@ -0,0 +1,242 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// Package admin wraps the Pulsar admin API used by the controllers
+package admin
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/streamnative/function-mesh/api/compute/v1alpha1"
+	"github.com/streamnative/pulsarctl/pkg/cli"
+	"github.com/streamnative/pulsarctl/pkg/pulsar"
+	"github.com/streamnative/pulsarctl/pkg/pulsar/common"
+	"github.com/streamnative/pulsarctl/pkg/pulsar/utils"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	webServiceURLKey = "webServiceURL"
+
+	authPluginKey = "clientAuthenticationPlugin"
+	authParamsKey = "clientAuthenticationParameters"
+
+	tlsAllowInsecureKey     = "tls_allow_insecure"
+	tlsHostnameVerification = "hostname_verification_enabled"
+	tlsTrustCertPathKey     = "tls_trust_cert_path"
+)
+
+// trustCertsDirectory keeps the trusted certificates read from the secrets, the admin client only loads them
+// from files
+var trustCertsDirectory = filepath.Join(os.TempDir(), "function-mesh-trust-certs")
+
+// DataGetter returns the data of the config maps and the secrets of a namespace
+type DataGetter interface {
+	GetConfigMapData(ctx context.Context, namespace, name string) (map[string]string, error)
+	GetSecretData(ctx context.Context, namespace, name string) (map[string]string, error)
+}
+
+// NewPulsarAdmin creates a Pulsar admin client from the config map and the secrets of the messaging spec
+func NewPulsarAdmin(ctx context.Context, reader client.Reader, namespace string,
+	messaging *v1alpha1.PulsarMessaging) (pulsar.Client, error) {
+	config, err := MakePulsarAdminConfig(ctx, readerDataGetter{reader: reader}, namespace, messaging)
+	if err != nil {
+		return nil, err
+	}
+	return pulsar.New(config)
+}
+
+// MakePulsarAdminConfig returns the config of the Pulsar admin client of the messaging spec
+func MakePulsarAdminConfig(ctx context.Context, getter DataGetter, namespace string,
+	messaging *v1alpha1.PulsarMessaging) (*common.Config, error) {
+	if messaging == nil || messaging.PulsarConfig == "" {
+		return nil, errors.New("pulsar config is not specified")
+	}
+	if messaging.AuthConfig != nil && messaging.AuthConfig.OAuth2Config != nil {
+		return nil, errors.New("the oauth2 auth config is not supported by the pulsar admin client")
+	}
+
+	pulsarConfig, err := getter.GetConfigMapData(ctx, namespace, messaging.PulsarConfig)
+	if err != nil {
+		return nil, err
+	}
+	config := &common.Config{
+		WebServiceURL:    pulsarConfig[webServiceURLKey],
+		PulsarAPIVersion: common.V2,
+	}
+	if config.WebServiceURL == "" {
+		return nil, fmt.Errorf("%s is missing in the config map %s", webServiceURLKey, messaging.PulsarConfig)
+	}
+
+	if messaging.AuthSecret != "" {
+		data, err := getter.GetSecretData(ctx, namespace, messaging.AuthSecret)
+		if err != nil {
+			return nil, err
+		}
+		config.AuthPlugin = data[authPluginKey]
+		config.AuthParams = data[authParamsKey]
+	}
+
+	if messaging.TLSConfig != nil && messaging.TLSConfig.Enabled {
+		config.TLSAllowInsecureConnection = messaging.TLSConfig.AllowInsecure
+		config.TLSEnableHostnameVerification = messaging.TLSConfig.HostnameVerification
+		if messaging.TLSConfig.HasSecretVolume() {
+			data, err := getter.GetSecretData(ctx, namespace, messaging.TLSConfig.CertSecretName)
+			if err != nil {
+				return nil, err
+			}
+			cert, ok := data[messaging.TLSConfig.CertSecretKey]
+			if !ok {
+				return nil, fmt.Errorf("%s is missing in the secret %s", messaging.TLSConfig.CertSecretKey,
+					messaging.TLSConfig.CertSecretName)
+			}
+			if config.TLSTrustCertsFilePath, err = writeTrustCerts(cert); err != nil {
+				return nil, err
+			}
+		}
+	} else if messaging.TLSSecret != "" {
+		data, err := getter.GetSecretData(ctx, namespace, messaging.TLSSecret)
+		if err != nil {
+			return nil, err
+		}
+		config.TLSAllowInsecureConnection, _ = strconv.ParseBool(data[tlsAllowInsecureKey])
+		config.TLSEnableHostnameVerification, _ = strconv.ParseBool(data[tlsHostnameVerification])
+		// the path is in the pods of the components, it is only used when the controller has the same file, such
+		// as the CA bundle of the system, otherwise the roots of the system are trusted
+		if path := data[tlsTrustCertPathKey]; path != "" {
+			if _, err := os.Stat(path); err == nil {
+				config.TLSTrustCertsFilePath = path
+			}
+		}
+	}
+	return config, nil
+}
+
+// writeTrustCerts writes the trusted certificates to a file named after their checksum, so the file of a
+// certificate is written once whatever the number of components using it
+func writeTrustCerts(cert string) (string, error) {
+	path := filepath.Join(trustCertsDirectory, fmt.Sprintf("%x.pem", sha256.Sum256([]byte(cert))))
+	if _, err := os.Stat(path); err == nil {
+		return path, nil
+	}
+	if err := os.MkdirAll(trustCertsDirectory, 0700); err != nil {
+		return "", err
+	}
+	file, err := ioutil.TempFile(trustCertsDirectory, "pem-")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(file.Name())
+	if _, err := file.WriteString(cert); err != nil {
+		file.Close()
+		return "", err
+	}
+	if err := file.Close(); err != nil {
+		return "", err
+	}
+	// the rename keeps the concurrent reconciles from reading a partial file
+	return path, os.Rename(file.Name(), path)
+}
+
+type readerDataGetter struct {
+	reader client.Reader
+}
+
+func (g readerDataGetter) GetConfigMapData(ctx context.Context, namespace, name string) (map[string]string, error) {
+	configMap := &corev1.ConfigMap{}
+	err := g.reader.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, configMap)
+	if err != nil {
+		return nil, err
+	}
+	return configMap.Data, nil
+}
+
+func (g readerDataGetter) GetSecretData(ctx context.Context, namespace, name string) (map[string]string, error) {
+	secret := &corev1.Secret{}
+	err := g.reader.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, secret)
+	if err != nil {
+		return nil, err
+	}
+	return MakeSecretData(secret), nil
+}
+
+// MakeSecretData returns the data of a secret, including its string data
+func MakeSecretData(secret *corev1.Secret) map[string]string {
+	data := map[string]string{}
+	for key, value := range secret.Data {
+		data[key] = string(value)
+	}
+	for key, value := range secret.StringData {
+		data[key] = value
+	}
+	return data
+}
+
+// EnsureTopic creates the topic when it doesn't exist yet and applies its retention and schema
+func EnsureTopic(admin pulsar.Client, topic v1alpha1.RetryTopic) error {
+	topicName, err := utils.GetTopicName(topic.Name)
+	if err != nil {
+		return err
+	}
+
+	err = admin.Topics().Create(*topicName, int(topic.Partitions))
+	if err != nil && !isConflict(err) {
+		return fmt.Errorf("failed to create topic %s: %w", topicName.String(), err)
+	}
+
+	if topic.Retention != nil {
+		err = admin.Topics().SetRetention(*topicName, utils.RetentionPolicies{
+			RetentionTimeInMinutes: int(topic.Retention.RetentionTimeInMinutes),
+			RetentionSizeInMB:      topic.Retention.RetentionSizeInMB,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to set the retention of topic %s: %w", topicName.String(), err)
+		}
+	}
+
+	if topic.Schema != nil {
+		// uploading the same schema again keeps its version, an incompatible schema is rejected
+		err = admin.Schemas().CreateSchemaByPayload(topicName.String(), utils.PostSchemaPayload{
+			SchemaType: topic.Schema.Type,
+			Schema:     topic.Schema.Definition,
+			Properties: topic.Schema.Properties,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to set the schema of topic %s: %w", topicName.String(), err)
+		}
+	}
+	return nil
+}
+
+// isConflict returns true if the topic already exists
+func isConflict(err error) bool {
+	var cliErr cli.Error
+	if errors.As(err, &cliErr) {
+		return cliErr.Code == http.StatusConflict
+	}
+	return false
+}
@@ -0,0 +1,232 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/pem"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/streamnative/function-mesh/api/compute/v1alpha1"
+	"github.com/streamnative/pulsarctl/pkg/pulsar"
+	"github.com/streamnative/pulsarctl/pkg/pulsar/common"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// fakeAdminServer mimics the topic and schema endpoints of the Pulsar admin API
+type fakeAdminServer struct {
+	sync.Mutex
+	topics     map[string]int
+	retentions map[string]string
+	schemas    map[string]string
+	headers    http.Header
+}
+
+func newFakeAdminServer(t *testing.T) (*fakeAdminServer, *httptest.Server) {
+	fake, handler := newFakeAdminHandler(t)
+	return fake, httptest.NewServer(handler)
+}
+
+func newFakeAdminHandler(t *testing.T) (*fakeAdminServer, http.Handler) {
+	fake := &fakeAdminServer{topics: map[string]int{}, retentions: map[string]string{}, schemas: map[string]string{}}
+	return fake, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fake.Lock()
+		defer fake.Unlock()
+		fake.headers = r.Header.Clone()
+
+		body, err := ioutil.ReadAll(r.Body)
+		assert.Nil(t, err)
+		path := r.URL.Path
+		switch {
+		case r.Method == http.MethodPut:
+			topic, partitions := path, 0
+			if strings.HasSuffix(path, "/partitions") {
+				assert.Nil(t, json.Unmarshal(body, &partitions))
+				topic = strings.TrimSuffix(path, "/partitions")
+			}
+			if _, ok := fake.topics[topic]; ok {
+				w.WriteHeader(http.StatusConflict)
+				_, _ = w.Write([]byte(`{"reason":"This topic already exists"}`))
+				return
+			}
+			fake.topics[topic] = partitions
+			w.WriteHeader(http.StatusNoContent)
+		case r.Method == http.MethodPost && strings.HasSuffix(path, "/retention"):
+			fake.retentions[strings.TrimSuffix(path, "/retention")] = string(body)
+			w.WriteHeader(http.StatusNoContent)
+		case r.Method == http.MethodPost && strings.HasSuffix(path, "/schema"):
+			topic := strings.TrimSuffix(path, "/schema")
+			// the fake considers any other schema as incompatible
+			if schema, ok := fake.schemas[topic]; ok && schema != string(body) {
+				w.WriteHeader(http.StatusConflict)
+				_, _ = w.Write([]byte(`{"reason":"Incompatible schema"}`))
+				return
+			}
+			fake.schemas[topic] = string(body)
+			w.WriteHeader(http.StatusOK)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+}
+
+func TestEnsureTopic(t *testing.T) {
+	fake, server := newFakeAdminServer(t)
+	defer server.Close()
+
+	admin, err := pulsar.New(&common.Config{WebServiceURL: server.URL, PulsarAPIVersion: common.V2})
+	assert.Nil(t, err)
+
+	dlq := v1alpha1.RetryTopic{
+		Name:      "persistent://public/default/dlq",
+		Provision: true,
+		Retention: &v1alpha1.TopicRetention{RetentionTimeInMinutes: 60, RetentionSizeInMB: 100},
+	}
+	assert.Nil(t, EnsureTopic(admin, dlq))
+	assert.Equal(t, map[string]int{"/admin/v2/persistent/public/default/dlq": 0}, fake.topics)
+	assert.JSONEq(t, `{"retentionTimeInMinutes":60,"retentionSizeInMB":100}`,
+		fake.retentions["/admin/v2/persistent/public/default/dlq"])
+
+	// the topic already exists, the retention is still applied
+	dlq.Retention.RetentionTimeInMinutes = 120
+	assert.Nil(t, EnsureTopic(admin, dlq))
+	assert.JSONEq(t, `{"retentionTimeInMinutes":120,"retentionSizeInMB":100}`,
+		fake.retentions["/admin/v2/persistent/public/default/dlq"])
+
+	retry := v1alpha1.RetryTopic{Name: "retry", Provision: true, Partitions: 3}
+	assert.Nil(t, EnsureTopic(admin, retry))
+	assert.Equal(t, 3, fake.topics["/admin/v2/persistent/public/default/retry"])
+	assert.Equal(t, 1, len(fake.retentions))
+}
+
+func TestEnsureTopicWithSchema(t *testing.T) {
+	fake, server := newFakeAdminServer(t)
+	defer server.Close()
+
+	admin, err := pulsar.New(&common.Config{WebServiceURL: server.URL, PulsarAPIVersion: common.V2})
+	assert.Nil(t, err)
+
+	dlq := v1alpha1.RetryTopic{
+		Name:      "persistent://public/default/dlq",
+		Provision: true,
+		Schema: &v1alpha1.TopicSchema{
+			Type:       "AVRO",
+			Definition: `{"type":"record","name":"Event","fields":[{"name":"id","type":"string"}]}`,
+			Properties: map[string]string{"owner": "mesh"},
+		},
+	}
+	assert.Nil(t, EnsureTopic(admin, dlq))
+	assert.Equal(t, map[string]int{"/admin/v2/persistent/public/default/dlq": 0}, fake.topics)
+	assert.JSONEq(t, `{"type":"AVRO","schema":"{\"type\":\"record\",\"name\":\"Event\",`+
+		`\"fields\":[{\"name\":\"id\",\"type\":\"string\"}]}","properties":{"owner":"mesh"}}`,
+		fake.schemas["/admin/v2/schemas/public/default/dlq"])
+
+	// the topic and its schema already exist
+	assert.Nil(t, EnsureTopic(admin, dlq))
+
+	dlq.Schema = &v1alpha1.TopicSchema{Type: "STRING"}
+	assert.Error(t, EnsureTopic(admin, dlq))
+}
+
+func TestEnsureTopicWithError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	admin, err := pulsar.New(&common.Config{WebServiceURL: server.URL, PulsarAPIVersion: common.V2})
+	assert.Nil(t, err)
+	assert.Error(t, EnsureTopic(admin, v1alpha1.RetryTopic{Name: "dlq", Provision: true}))
+	assert.Error(t, EnsureTopic(admin, v1alpha1.RetryTopic{Name: "persistent://invalid", Provision: true}))
+}
+
+func TestNewPulsarAdmin(t *testing.T) {
+	fake, server := newFakeAdminServer(t)
+	defer server.Close()
+
+	reader := fakeclient.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "pulsar-config"},
+			Data:       map[string]string{"webServiceURL": server.URL},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "pulsar-auth"},
+			Data: map[string][]byte{
+				"clientAuthenticationPlugin":     []byte("org.apache.pulsar.client.impl.auth.AuthenticationToken"),
+				"clientAuthenticationParameters": []byte("token:my-token"),
+			},
+		},
+	).Build()
+
+	admin, err := NewPulsarAdmin(context.Background(), reader, "default", &v1alpha1.PulsarMessaging{
+		PulsarConfig: "pulsar-config",
+		AuthSecret:   "pulsar-auth",
+	})
+	assert.Nil(t, err)
+	assert.Nil(t, EnsureTopic(admin, v1alpha1.RetryTopic{Name: "dlq", Provision: true}))
+	assert.Equal(t, "Bearer my-token", fake.headers.Get("Authorization"))
+
+	_, err = NewPulsarAdmin(context.Background(), reader, "default", &v1alpha1.PulsarMessaging{
+		PulsarConfig: "missing-config",
+	})
+	assert.Error(t, err)
+}
+
+func TestNewPulsarAdminWithTrustCerts(t *testing.T) {
+	fake, handler := newFakeAdminHandler(t)
+	server := httptest.NewTLSServer(handler)
+	defer server.Close()
+	cert := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+
+	reader := fakeclient.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "pulsar-config"},
+			Data:       map[string]string{"webServiceURL": server.URL},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "pulsar-ca"},
+			Data:       map[string][]byte{"ca.crt": cert},
+		},
+	).Build()
+	messaging := &v1alpha1.PulsarMessaging{
+		PulsarConfig: "pulsar-config",
+		TLSConfig: &v1alpha1.PulsarTLSConfig{TLSConfig: v1alpha1.TLSConfig{
+			Enabled:        true,
+			CertSecretName: "pulsar-ca",
+			CertSecretKey:  "ca.crt",
+		}},
+	}
+	admin, err := NewPulsarAdmin(context.Background(), reader, "default", messaging)
+	assert.Nil(t, err)
+	assert.Nil(t, EnsureTopic(admin, v1alpha1.RetryTopic{Name: "dlq", Provision: true}))
+	assert.Contains(t, fake.topics, "/admin/v2/persistent/public/default/dlq")
+
+	messaging.TLSConfig.CertSecretKey = "missing.crt"
+	_, err = NewPulsarAdmin(context.Background(), reader, "default", messaging)
+	assert.EqualError(t, err, "missing.crt is missing in the secret pulsar-ca")
+}
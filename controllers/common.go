@@ -20,19 +20,79 @@ package controllers
 
 import (
 	"context"
+	"fmt"
+	"net"
+	"net/url"
 	"reflect"
+	"sync"
+	"time"
 
 	"github.com/go-logr/logr"
 	"github.com/streamnative/function-mesh/api/compute/v1alpha1"
+	"github.com/streamnative/function-mesh/controllers/admin"
 	"github.com/streamnative/function-mesh/controllers/spec"
+	appsv1 "k8s.io/api/apps/v1"
 	autoscaling "k8s.io/api/autoscaling/v1"
+	autov2beta2 "k8s.io/api/autoscaling/v2beta2"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/types"
 	vpav1 "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/apis/autoscaling.k8s.io/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
 
+const (
+	// configMapReferencesIndex indexes the components by the config maps used by their pods
+	configMapReferencesIndex = "spec.configMapReferences"
+	// secretReferencesIndex indexes the components by the secrets used by their pods
+	secretReferencesIndex = "spec.secretReferences"
+	// pulsarConnectionIndex indexes the components by the PulsarConnection they use
+	pulsarConnectionIndex = "spec.pulsarConnection"
+)
+
+// observePause records the replicas of a paused component, so they can be restored on resume
+func observePause(specReplicas *int32, replicas int32, pause *v1alpha1.PauseStatus,
+	conditions map[v1alpha1.Component]v1alpha1.ResourceCondition) {
+	if pause.PausedReplicas == nil {
+		if replicas == 0 && specReplicas != nil {
+			replicas = *specReplicas
+		}
+		pause.PausedReplicas = &replicas
+		if specReplicas != nil {
+			paused := *specReplicas
+			pause.PausedSpecReplicas = &paused
+		}
+	}
+	conditions[v1alpha1.Pause] = v1alpha1.CreateCondition(
+		v1alpha1.Paused,
+		metav1.ConditionTrue,
+		v1alpha1.NoAction)
+}
+
+// resumeReplicas restores the replica count recorded when a component was paused and clears the pause, the
+// replicas are left as they are when the spec was changed during the pause
+func resumeReplicas(ctx context.Context, c client.Writer, obj client.Object, specReplicas **int32,
+	pause *v1alpha1.PauseStatus, conditions map[v1alpha1.Component]v1alpha1.ResourceCondition) error {
+	replicas := *pause.PausedReplicas
+	unchanged := reflect.DeepEqual(*specReplicas, pause.PausedSpecReplicas)
+	if unchanged && (*specReplicas == nil || **specReplicas != replicas) {
+		*specReplicas = &replicas
+		if err := c.Update(ctx, obj); err != nil {
+			return err
+		}
+	}
+	pause.PausedReplicas = nil
+	pause.PausedSpecReplicas = nil
+	delete(conditions, v1alpha1.Pause)
+	return nil
+}
+
 func observeVPA(ctx context.Context, r client.Reader, name types.NamespacedName, vpaSpec *v1alpha1.VPASpec, conditions map[v1alpha1.Component]v1alpha1.ResourceCondition) error {
 	_, ok := conditions[v1alpha1.VPA]
 	condition := v1alpha1.ResourceCondition{Condition: v1alpha1.VPAReady}
@@ -133,3 +193,448 @@ func applyVPA(ctx context.Context, r client.Client, logger logr.Logger, conditio
 	}
 	return nil
 }
+
+func deleteHPA(ctx context.Context, r client.Client, name types.NamespacedName) error {
+	hpa := &autov2beta2.HorizontalPodAutoscaler{}
+	err := r.Get(ctx, name, hpa)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	err = r.Delete(ctx, hpa)
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+// deleteStatefulSetOnImmutableChange deletes the StatefulSet without its pods when the pod management policy,
+// which cannot be updated, changes. The StatefulSet created again adopts the pods, which keep their ordinals and
+// so their instance IDs
+func deleteStatefulSetOnImmutableChange(ctx context.Context, r client.Client, desired *appsv1.StatefulSet) (bool, error) {
+	statefulSet := &appsv1.StatefulSet{}
+	err := r.Get(ctx, client.ObjectKeyFromObject(desired), statefulSet)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	if statefulSet.DeletionTimestamp != nil {
+		// the StatefulSet is created again once its deletion completes
+		return true, nil
+	}
+	if statefulSet.Spec.PodManagementPolicy == desired.Spec.PodManagementPolicy {
+		return false, nil
+	}
+	err = r.Delete(ctx, statefulSet, client.PropagationPolicy(metav1.DeletePropagationOrphan))
+	if err != nil && !errors.IsNotFound(err) {
+		return false, err
+	}
+	return true, nil
+}
+
+// retryTopicsRetryInterval is how long to wait before provisioning the retry topics again after a failure
+const retryTopicsRetryInterval = 30 * time.Second
+
+// applyRetryTopics provisions the retry topics through the Pulsar admin API. A failure does not block the
+// reconcile of the component, it is recorded in the RetryTopicsReady condition and the component is requeued.
+// It returns when to requeue the component.
+func applyRetryTopics(ctx context.Context, r client.Reader, logger logr.Logger, topics []v1alpha1.RetryTopic,
+	messaging *v1alpha1.PulsarMessaging, conditions map[v1alpha1.Component]v1alpha1.ResourceCondition,
+	component string, namespace string, name string) time.Duration {
+	if err := provisionRetryTopics(ctx, r, namespace, topics, messaging); err != nil {
+		return failRetryTopics(logger, err, conditions, component, namespace, name)
+	}
+	conditions[v1alpha1.RetryTopics] = v1alpha1.CreateCondition(
+		v1alpha1.RetryTopicsReady,
+		metav1.ConditionTrue,
+		v1alpha1.NoAction)
+	return 0
+}
+
+func provisionRetryTopics(ctx context.Context, r client.Reader, namespace string, topics []v1alpha1.RetryTopic,
+	messaging *v1alpha1.PulsarMessaging) error {
+	adminClient, err := admin.NewPulsarAdmin(ctx, r, namespace, messaging)
+	if err != nil {
+		return fmt.Errorf("failed to create pulsar admin client: %w", err)
+	}
+	for _, topic := range topics {
+		if err := admin.EnsureTopic(adminClient, topic); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// failRetryTopics records the failure to provision the retry topics, it returns when to retry
+func failRetryTopics(logger logr.Logger, err error, conditions map[v1alpha1.Component]v1alpha1.ResourceCondition,
+	component string, namespace string, name string) time.Duration {
+	logger.Error(err, "failed to provision retry topics", "name", name, "component", component,
+		"namespace", namespace)
+	condition := v1alpha1.CreateCondition(v1alpha1.RetryTopicsReady, metav1.ConditionFalse, v1alpha1.Wait)
+	condition.Reason = v1alpha1.RetryTopicsProvisionFailed
+	condition.Message = err.Error()
+	conditions[v1alpha1.RetryTopics] = condition
+	return retryTopicsRetryInterval
+}
+
+// minRequeueAfter returns the earliest of the requeue delays, a zero delay means no requeue
+func minRequeueAfter(delays ...time.Duration) time.Duration {
+	var result time.Duration
+	for _, delay := range delays {
+		if delay > 0 && (result == 0 || delay < result) {
+			result = delay
+		}
+	}
+	return result
+}
+
+// stateStoreDialTimeout bounds the pre-flight check of the state store service
+const stateStoreDialTimeout = 5 * time.Second
+
+// stateStoreCheckInterval is how long the result of a pre-flight check is reused, and how long to wait before
+// checking an unreachable state store again
+const stateStoreCheckInterval = 30 * time.Second
+
+// defaultStateStorePort is the port of the BookKeeper table service
+const defaultStateStorePort = "4181"
+
+// stateStoreChecker caches the results of the pre-flight checks by service url, so that a state store is
+// dialed at most once per interval whatever the number of components using it and of reconciles
+type stateStoreChecker struct {
+	interval time.Duration
+	lock     sync.Mutex
+	results  map[string]stateStoreCheckResult
+}
+
+type stateStoreCheckResult struct {
+	err       error
+	checkedAt time.Time
+}
+
+func newStateStoreChecker(interval time.Duration) *stateStoreChecker {
+	return &stateStoreChecker{interval: interval, results: map[string]stateStoreCheckResult{}}
+}
+
+// stateStoreChecks is the checker shared by the function, source and sink reconcilers
+var stateStoreChecks = newStateStoreChecker(stateStoreCheckInterval)
+
+func (c *stateStoreChecker) check(ctx context.Context, serviceURL string) error {
+	c.lock.Lock()
+	result, ok := c.results[serviceURL]
+	c.lock.Unlock()
+	if ok && time.Since(result.checkedAt) < c.interval {
+		return result.err
+	}
+	err := checkStateStore(ctx, serviceURL)
+	c.lock.Lock()
+	c.results[serviceURL] = stateStoreCheckResult{err: err, checkedAt: time.Now()}
+	c.lock.Unlock()
+	return err
+}
+
+// checkStateStore checks that the state store service accepts connections
+func checkStateStore(ctx context.Context, serviceURL string) error {
+	u, err := url.Parse(serviceURL)
+	if err != nil {
+		return fmt.Errorf("invalid state store service url %s: %w", serviceURL, err)
+	}
+	if u.Hostname() == "" {
+		return fmt.Errorf("invalid state store service url %s: missing host", serviceURL)
+	}
+	address := u.Host
+	if u.Port() == "" {
+		address = net.JoinHostPort(u.Hostname(), defaultStateStorePort)
+	}
+	dialer := &net.Dialer{Timeout: stateStoreDialTimeout}
+	conn, err := dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return fmt.Errorf("state store %s is unreachable: %w", serviceURL, err)
+	}
+	return conn.Close()
+}
+
+// applyStateStoreCheck reports whether the state store of a stateful component is reachable with the
+// StateStoreReady condition, the instances are started anyway as the store may become reachable later.
+// It returns how long to wait before checking an unreachable store again, zero otherwise.
+func applyStateStoreCheck(ctx context.Context, logger logr.Logger, state *v1alpha1.Stateful,
+	conditions map[v1alpha1.Component]v1alpha1.ResourceCondition, newGeneration bool,
+	component string, namespace string, name string) time.Duration {
+	if state == nil || state.Pulsar == nil || !state.Pulsar.PreflightCheck {
+		// pre-flight check not enabled, skip further action
+		delete(conditions, v1alpha1.StateStore)
+		return 0
+	}
+	condition, ok := conditions[v1alpha1.StateStore]
+	if ok && condition.Status == metav1.ConditionTrue && !newGeneration {
+		return 0
+	}
+	if err := stateStoreChecks.check(ctx, state.Pulsar.ServiceURL); err != nil {
+		logger.Error(err, "state store pre-flight check failed", "name", name, "component", component,
+			"namespace", namespace)
+		condition = v1alpha1.CreateCondition(v1alpha1.StateStoreReady, metav1.ConditionFalse, v1alpha1.Wait)
+		condition.Reason = v1alpha1.StateStoreUnreachable
+		condition.Message = err.Error()
+		conditions[v1alpha1.StateStore] = condition
+		return stateStoreCheckInterval
+	}
+	conditions[v1alpha1.StateStore] = v1alpha1.CreateCondition(
+		v1alpha1.StateStoreReady,
+		metav1.ConditionTrue,
+		v1alpha1.NoAction)
+	return 0
+}
+
+// resolvePulsarConnection applies the PulsarConnection used by a component to its messaging and pod policy,
+// both are modified in place so they must belong to a copy of the component
+func resolvePulsarConnection(ctx context.Context, r client.Reader, namespace string,
+	messaging *v1alpha1.Messaging, policy *v1alpha1.PodPolicy) error {
+	name := spec.GetPulsarConnectionName(namespace, *messaging)
+	if name == "" {
+		return nil
+	}
+	connection := &v1alpha1.PulsarConnection{}
+	err := r.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, connection)
+	if err != nil {
+		// the default connection of the namespace is optional, without it the component keeps its messaging
+		if errors.IsNotFound(err) && (messaging.Pulsar == nil || messaging.Pulsar.PulsarConnection == "") {
+			return nil
+		}
+		return fmt.Errorf("failed to get pulsar connection %s/%s: %w", namespace, name, err)
+	}
+	spec.ApplyPulsarConnection(messaging, policy, connection)
+	return nil
+}
+
+// makeResolvedPulsarMessaging returns the pulsar messaging of a component with the controller defaults and
+// the PulsarConnection applied
+func makeResolvedPulsarMessaging(ctx context.Context, r client.Reader, namespace string,
+	messaging v1alpha1.Messaging) (*v1alpha1.PulsarMessaging, error) {
+	messaging = *messaging.DeepCopy()
+	if err := resolvePulsarConnection(ctx, r, namespace, &messaging, &v1alpha1.PodPolicy{}); err != nil {
+		return nil, err
+	}
+	return spec.MakePulsarMessaging(namespace, messaging), nil
+}
+
+// indexPulsarConnection returns the index value of the PulsarConnection used by a component
+func indexPulsarConnection(namespace string, messaging v1alpha1.Messaging) []string {
+	if name := spec.GetPulsarConnectionName(namespace, messaging); name != "" {
+		return []string{name}
+	}
+	return nil
+}
+
+// listComponentRequests returns the reconcile requests of the components of a list type whose index contains
+// the value
+func listComponentRequests(ctx context.Context, r client.Reader, list client.ObjectList, namespace, index,
+	value string) ([]reconcile.Request, error) {
+	if err := r.List(ctx, list, client.InNamespace(namespace), client.MatchingFields{index: value}); err != nil {
+		return nil, err
+	}
+	items, err := meta.ExtractList(list)
+	if err != nil {
+		return nil, err
+	}
+	requests := make([]reconcile.Request, 0, len(items))
+	for _, item := range items {
+		object, err := meta.Accessor(item)
+		if err != nil {
+			return nil, err
+		}
+		requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{
+			Namespace: object.GetNamespace(),
+			Name:      object.GetName(),
+		}})
+	}
+	return requests, nil
+}
+
+// findComponentsForPulsarConnection returns a map function enqueueing the components that use a
+// PulsarConnection, so that changes of the connection are rolled out to their pods. newList returns an empty
+// list of the kind of the components
+func findComponentsForPulsarConnection(r client.Reader, log logr.Logger,
+	newList func() client.ObjectList) handler.MapFunc {
+	return func(connection client.Object) []reconcile.Request {
+		requests, err := listComponentRequests(context.Background(), r, newList(), connection.GetNamespace(),
+			pulsarConnectionIndex, connection.GetName())
+		if err != nil {
+			log.Error(err, "failed to list components of the pulsar connection",
+				"namespace", connection.GetNamespace(), "name", connection.GetName())
+			return nil
+		}
+		return requests
+	}
+}
+
+// findComponentsForConfig returns a map function enqueueing the components that use a config map or secret,
+// directly or through their PulsarConnection, so that changes of the contents are rolled out to their pods.
+// newList returns an empty list of the kind of the components
+func findComponentsForConfig(r client.Reader, log logr.Logger, newList func() client.ObjectList,
+	index string) handler.MapFunc {
+	return func(object client.Object) []reconcile.Request {
+		requests, err := listConfigComponentRequests(context.Background(), r, newList, index, object)
+		if err != nil {
+			log.Error(err, "failed to list components of the config",
+				"namespace", object.GetNamespace(), "name", object.GetName())
+		}
+		return requests
+	}
+}
+
+// configReferencedPredicate drops the events of the config maps and secrets that no component of the kind uses,
+// the lookups are served by the indexes of the cache
+func configReferencedPredicate(r client.Reader, log logr.Logger, newList func() client.ObjectList,
+	index string) predicate.Predicate {
+	return predicate.NewPredicateFuncs(func(object client.Object) bool {
+		requests, err := listConfigComponentRequests(context.Background(), r, newList, index, object)
+		if err != nil {
+			log.Error(err, "failed to list components of the config",
+				"namespace", object.GetNamespace(), "name", object.GetName())
+			return true
+		}
+		return len(requests) > 0
+	})
+}
+
+func listConfigComponentRequests(ctx context.Context, r client.Reader, newList func() client.ObjectList,
+	index string, object client.Object) ([]reconcile.Request, error) {
+	requests, err := listComponentRequests(ctx, r, newList(), object.GetNamespace(), index, object.GetName())
+	if err != nil || index != secretReferencesIndex {
+		return requests, err
+	}
+	connections, err := findPulsarConnectionsForSecret(ctx, r, object)
+	if err != nil {
+		return requests, err
+	}
+	for _, connection := range connections {
+		connectionRequests, err := listComponentRequests(ctx, r, newList(), connection.Namespace,
+			pulsarConnectionIndex, connection.Name)
+		if err != nil {
+			return requests, err
+		}
+		requests = append(requests, connectionRequests...)
+	}
+	return requests, nil
+}
+
+// applyConfigChecksum annotates the pod template with the checksum of the config maps and secrets it uses,
+// so that a change of their contents rolls the pods
+func applyConfigChecksum(ctx context.Context, r client.Reader, object metav1.Object,
+	template *corev1.PodTemplateSpec) error {
+	if !spec.IsRestartOnConfigChangeEnabled(object) {
+		return nil
+	}
+	references := spec.GetConfigReferences(template)
+	if len(references.ConfigMaps) == 0 && len(references.Secrets) == 0 {
+		return nil
+	}
+	configMaps := map[string]*corev1.ConfigMap{}
+	for _, name := range references.ConfigMaps {
+		configMap := &corev1.ConfigMap{}
+		err := r.Get(ctx, types.NamespacedName{Namespace: object.GetNamespace(), Name: name}, configMap)
+		if err != nil {
+			if !errors.IsNotFound(err) {
+				return err
+			}
+			configMap = nil
+		} else if !spec.IsRestartOnConfigChangeEnabled(configMap) {
+			continue
+		}
+		configMaps[name] = configMap
+	}
+	secrets := map[string]*corev1.Secret{}
+	for _, name := range references.Secrets {
+		secret := &corev1.Secret{}
+		err := r.Get(ctx, types.NamespacedName{Namespace: object.GetNamespace(), Name: name}, secret)
+		if err != nil {
+			if !errors.IsNotFound(err) {
+				return err
+			}
+			secret = nil
+		} else if !spec.IsRestartOnConfigChangeEnabled(secret) {
+			continue
+		}
+		secrets[name] = secret
+	}
+	if template.Annotations == nil {
+		template.Annotations = map[string]string{}
+	}
+	template.Annotations[spec.AnnotationConfigChecksum] = spec.MakeConfigChecksum(configMaps, secrets)
+	return nil
+}
+
+// findPulsarConnectionsForSecret returns the PulsarConnections that use a secret
+func findPulsarConnectionsForSecret(ctx context.Context, r client.Reader, secret client.Object) ([]v1alpha1.PulsarConnection, error) {
+	connections := &v1alpha1.PulsarConnectionList{}
+	if err := r.List(ctx, connections, client.InNamespace(secret.GetNamespace())); err != nil {
+		return nil, err
+	}
+	var result []v1alpha1.PulsarConnection
+	for _, connection := range connections.Items {
+		names := []string{connection.Spec.AuthSecret}
+		if connection.Spec.TLSConfig != nil {
+			names = append(names, connection.Spec.TLSConfig.CertSecretName)
+		}
+		if connection.Spec.AuthConfig != nil && connection.Spec.AuthConfig.OAuth2Config != nil {
+			names = append(names, connection.Spec.AuthConfig.OAuth2Config.KeySecretName)
+		}
+		for _, name := range names {
+			if name == secret.GetName() {
+				result = append(result, connection)
+				break
+			}
+		}
+	}
+	return result, nil
+}
+
+// observePackageVerification explains the statefulSet condition when the pods fail to verify the downloaded package
+func observePackageVerification(ctx context.Context, r client.Reader, statefulSet *appsv1.StatefulSet,
+	selector labels.Selector, condition *v1alpha1.ResourceCondition) error {
+	condition.Reason, condition.Message = "", ""
+	if statefulSet.Status.ReadyReplicas == *statefulSet.Spec.Replicas {
+		return nil
+	}
+	pods := &corev1.PodList{}
+	if err := r.List(ctx, pods, client.InNamespace(statefulSet.Namespace),
+		client.MatchingLabelsSelector{Selector: selector}); err != nil {
+		return err
+	}
+	for i := range pods.Items {
+		if message, failed := spec.GetPackageVerificationFailure(&pods.Items[i]); failed {
+			condition.Reason = v1alpha1.PackageVerificationFailed
+			condition.Message = message
+			return nil
+		}
+	}
+	return nil
+}
+
+// packageVerificationFailedPredicate passes the pods that failed to verify the downloaded package
+var packageVerificationFailedPredicate = predicate.NewPredicateFuncs(func(object client.Object) bool {
+	pod, ok := object.(*corev1.Pod)
+	if !ok {
+		return false
+	}
+	_, failed := spec.GetPackageVerificationFailure(pod)
+	return failed
+})
+
+// findComponentForPod maps a pod to the function, source or sink running it
+func findComponentForPod(component string) handler.MapFunc {
+	return func(object client.Object) []reconcile.Request {
+		podLabels := object.GetLabels()
+		name := podLabels["compute.functionmesh.io/name"]
+		if podLabels["compute.functionmesh.io/component"] != component || name == "" {
+			return nil
+		}
+		return []reconcile.Request{{NamespacedName: types.NamespacedName{
+			Namespace: object.GetNamespace(),
+			Name:      name,
+		}}}
+	}
+}
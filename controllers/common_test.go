@@ -0,0 +1,319 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package controllers
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/gomega"
+	"github.com/streamnative/function-mesh/api/compute/v1alpha1"
+	"github.com/streamnative/function-mesh/controllers/spec"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestApplyConfigChecksum(t *testing.T) {
+	g := NewWithT(t)
+	scheme := runtime.NewScheme()
+	g.Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+	g.Expect(v1alpha1.AddToScheme(scheme)).To(Succeed())
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "auth"},
+		Data:       map[string][]byte{"token": []byte("a")},
+	}
+	excluded := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "excluded",
+			Annotations: map[string]string{spec.AnnotationRestartOnConfigChange: "false"}},
+		Data: map[string][]byte{"token": []byte("a")},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(secret, excluded).Build()
+	template := func() *corev1.PodTemplateSpec {
+		return &corev1.PodTemplateSpec{Spec: corev1.PodSpec{Containers: []corev1.Container{{
+			EnvFrom: []corev1.EnvFromSource{
+				{SecretRef: &corev1.SecretEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: "auth"}}},
+				{SecretRef: &corev1.SecretEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: "excluded"}}},
+				{ConfigMapRef: &corev1.ConfigMapEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: "missing"}}},
+			},
+		}}}}
+	}
+	function := &v1alpha1.Function{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "fn"}}
+
+	first := template()
+	g.Expect(applyConfigChecksum(context.Background(), c, function, first)).To(Succeed())
+	checksum := first.Annotations[spec.AnnotationConfigChecksum]
+	g.Expect(checksum).NotTo(BeEmpty())
+
+	// changes of an excluded secret do not change the checksum
+	excluded.Data["token"] = []byte("b")
+	g.Expect(c.Update(context.Background(), excluded)).To(Succeed())
+	second := template()
+	g.Expect(applyConfigChecksum(context.Background(), c, function, second)).To(Succeed())
+	g.Expect(second.Annotations[spec.AnnotationConfigChecksum]).To(Equal(checksum))
+
+	secret.Data["token"] = []byte("b")
+	g.Expect(c.Update(context.Background(), secret)).To(Succeed())
+	third := template()
+	g.Expect(applyConfigChecksum(context.Background(), c, function, third)).To(Succeed())
+	g.Expect(third.Annotations[spec.AnnotationConfigChecksum]).NotTo(Equal(checksum))
+
+	// the components can opt out
+	function.Annotations = map[string]string{spec.AnnotationRestartOnConfigChange: "false"}
+	fourth := template()
+	g.Expect(applyConfigChecksum(context.Background(), c, function, fourth)).To(Succeed())
+	g.Expect(fourth.Annotations).NotTo(HaveKey(spec.AnnotationConfigChecksum))
+
+	connection := &v1alpha1.PulsarConnection{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "default"},
+		Spec:       v1alpha1.PulsarConnectionSpec{AuthSecret: "auth"},
+	}
+	g.Expect(c.Create(context.Background(), connection)).To(Succeed())
+	connections, err := findPulsarConnectionsForSecret(context.Background(), c, secret)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(connections).To(HaveLen(1))
+	connections, err = findPulsarConnectionsForSecret(context.Background(), c, excluded)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(connections).To(BeEmpty())
+}
+
+func TestFindComponentsForConfig(t *testing.T) {
+	g := NewWithT(t)
+	scheme := runtime.NewScheme()
+	g.Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+	g.Expect(v1alpha1.AddToScheme(scheme)).To(Succeed())
+
+	function := &v1alpha1.Function{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "fn"},
+		Spec: v1alpha1.FunctionSpec{Messaging: v1alpha1.Messaging{
+			Pulsar: &v1alpha1.PulsarMessaging{PulsarConnection: "team"}}}}
+	connection := &v1alpha1.PulsarConnection{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "team"},
+		Spec:       v1alpha1.PulsarConnectionSpec{AuthSecret: "auth"},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(function, connection).Build()
+	newList := func() client.ObjectList { return &v1alpha1.FunctionList{} }
+	referenced := configReferencedPredicate(c, ctrl.Log, newList, secretReferencesIndex)
+	mapper := findComponentsForConfig(c, ctrl.Log, newList, secretReferencesIndex)
+
+	secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "auth"}}
+	g.Expect(referenced.Generic(event.GenericEvent{Object: secret})).To(BeTrue())
+	g.Expect(mapper(secret)).To(ContainElement(reconcile.Request{
+		NamespacedName: types.NamespacedName{Namespace: "default", Name: "fn"}}))
+
+	// the configs of the namespaces without components are dropped
+	unrelated := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: "other", Name: "auth"}}
+	g.Expect(referenced.Generic(event.GenericEvent{Object: unrelated})).To(BeFalse())
+	g.Expect(mapper(unrelated)).To(BeEmpty())
+}
+
+func TestPauseAndResumeReplicas(t *testing.T) {
+	g := NewWithT(t)
+	scheme := runtime.NewScheme()
+	g.Expect(v1alpha1.AddToScheme(scheme)).To(Succeed())
+
+	replicas := int32(2)
+	function := &v1alpha1.Function{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "fn"},
+		Spec: v1alpha1.FunctionSpec{Replicas: &replicas}}
+	function.Status.Replicas = 3
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(function).Build()
+	g.Expect(c.Get(context.Background(), client.ObjectKeyFromObject(function), function)).To(Succeed())
+
+	conditions := map[v1alpha1.Component]v1alpha1.ResourceCondition{}
+	observePause(function.Spec.Replicas, function.Status.Replicas, &function.Status.PauseStatus, conditions)
+	g.Expect(*function.Status.PausedReplicas).To(Equal(int32(3)))
+	g.Expect(*function.Status.PausedSpecReplicas).To(Equal(int32(2)))
+	g.Expect(conditions).To(HaveKey(v1alpha1.Pause))
+
+	// the replicas running when the function was paused are restored
+	paused := function.Status.PauseStatus
+	g.Expect(resumeReplicas(context.Background(), c, function, &function.Spec.Replicas,
+		&function.Status.PauseStatus, conditions)).To(Succeed())
+	g.Expect(*function.Spec.Replicas).To(Equal(int32(3)))
+	g.Expect(function.Status.PausedReplicas).To(BeNil())
+	g.Expect(conditions).NotTo(HaveKey(v1alpha1.Pause))
+
+	// the replicas changed during the pause are kept
+	function.Status.PauseStatus = paused
+	changed := int32(5)
+	function.Spec.Replicas = &changed
+	g.Expect(resumeReplicas(context.Background(), c, function, &function.Spec.Replicas,
+		&function.Status.PauseStatus, conditions)).To(Succeed())
+	g.Expect(*function.Spec.Replicas).To(Equal(int32(5)))
+	g.Expect(function.Status.PausedSpecReplicas).To(BeNil())
+}
+
+func TestObservePackageVerification(t *testing.T) {
+	g := NewWithT(t)
+	scheme := runtime.NewScheme()
+	g.Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+
+	podLabels := map[string]string{"compute.functionmesh.io/name": "fn"}
+	failedPod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "fn-function-0", Labels: podLabels},
+		Status: corev1.PodStatus{InitContainerStatuses: []corev1.ContainerStatus{{
+			Name: spec.DownloaderName,
+			LastTerminationState: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{
+				ExitCode: 1,
+				Message:  "PackageVerificationFailed: the sha256 checksum of test.jar does not match abc",
+			}},
+		}}},
+	}
+	replicas := int32(1)
+	statefulSet := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "fn-function"},
+		Spec: appsv1.StatefulSetSpec{
+			Replicas: &replicas,
+			Selector: &metav1.LabelSelector{MatchLabels: podLabels},
+		},
+	}
+	selector, err := metav1.LabelSelectorAsSelector(statefulSet.Spec.Selector)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(failedPod).Build()
+	condition := v1alpha1.ResourceCondition{Condition: v1alpha1.StatefulSetReady}
+	g.Expect(observePackageVerification(context.Background(), c, statefulSet, selector, &condition)).To(Succeed())
+	g.Expect(condition.Reason).To(Equal(v1alpha1.PackageVerificationFailed))
+	g.Expect(condition.Message).To(ContainSubstring("test.jar"))
+
+	// the reason is cleared once the pods are ready
+	statefulSet.Status.ReadyReplicas = 1
+	g.Expect(observePackageVerification(context.Background(), c, statefulSet, selector, &condition)).To(Succeed())
+	g.Expect(condition.Reason).To(BeEmpty())
+	g.Expect(condition.Message).To(BeEmpty())
+
+	g.Expect(findComponentForPod(spec.ComponentFunction)(failedPod)).To(BeEmpty())
+	failedPod.Labels["compute.functionmesh.io/component"] = spec.ComponentFunction
+	g.Expect(findComponentForPod(spec.ComponentFunction)(failedPod)).To(HaveLen(1))
+	g.Expect(findComponentForPod(spec.ComponentSink)(failedPod)).To(BeEmpty())
+}
+
+func TestDeleteStatefulSetOnImmutableChange(t *testing.T) {
+	g := NewWithT(t)
+	scheme := runtime.NewScheme()
+	g.Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+
+	statefulSet := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "fn-function"},
+		Spec:       appsv1.StatefulSetSpec{PodManagementPolicy: appsv1.ParallelPodManagement},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(statefulSet).Build()
+	desired := statefulSet.DeepCopy()
+
+	deleted, err := deleteStatefulSetOnImmutableChange(context.Background(), c, desired)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(deleted).To(BeFalse())
+
+	desired.Spec.PodManagementPolicy = appsv1.OrderedReadyPodManagement
+	deleted, err = deleteStatefulSetOnImmutableChange(context.Background(), c, desired)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(deleted).To(BeTrue())
+	g.Expect(c.Get(context.Background(), client.ObjectKeyFromObject(statefulSet), &appsv1.StatefulSet{})).NotTo(Succeed())
+
+	deleted, err = deleteStatefulSetOnImmutableChange(context.Background(), c, desired)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(deleted).To(BeFalse())
+}
+
+func TestApplyStateStoreCheck(t *testing.T) {
+	g := NewWithT(t)
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	g.Expect(err).NotTo(HaveOccurred())
+	defer listener.Close()
+
+	checks := stateStoreChecks
+	defer func() { stateStoreChecks = checks }()
+	stateStoreChecks = newStateStoreChecker(0)
+
+	ctx := context.Background()
+	conditions := map[v1alpha1.Component]v1alpha1.ResourceCondition{}
+	state := &v1alpha1.Stateful{Pulsar: &v1alpha1.PulsarStateStore{
+		ServiceURL:     "bk://" + listener.Addr().String(),
+		PreflightCheck: true,
+	}}
+	requeueAfter := applyStateStoreCheck(ctx, logr.Discard(), state, conditions, true, "function", "default", "fn")
+	g.Expect(requeueAfter).To(BeZero())
+	g.Expect(conditions[v1alpha1.StateStore].Condition).To(Equal(v1alpha1.StateStoreReady))
+	g.Expect(conditions[v1alpha1.StateStore].Status).To(Equal(metav1.ConditionTrue))
+
+	// the store is not checked again until the generation changes
+	listener.Close()
+	applyStateStoreCheck(ctx, logr.Discard(), state, conditions, false, "function", "default", "fn")
+	g.Expect(conditions[v1alpha1.StateStore].Status).To(Equal(metav1.ConditionTrue))
+	requeueAfter = applyStateStoreCheck(ctx, logr.Discard(), state, conditions, true, "function", "default", "fn")
+	g.Expect(requeueAfter).To(Equal(stateStoreCheckInterval))
+	g.Expect(conditions[v1alpha1.StateStore].Status).To(Equal(metav1.ConditionFalse))
+	g.Expect(conditions[v1alpha1.StateStore].Reason).To(Equal(v1alpha1.StateStoreUnreachable))
+	g.Expect(conditions[v1alpha1.StateStore].Message).To(ContainSubstring("is unreachable"))
+
+	state.Pulsar.PreflightCheck = false
+	applyStateStoreCheck(ctx, logr.Discard(), state, conditions, true, "function", "default", "fn")
+	g.Expect(conditions).NotTo(HaveKey(v1alpha1.StateStore))
+
+	g.Expect(checkStateStore(ctx, "bk://")).To(HaveOccurred())
+}
+
+func TestApplyRetryTopicsWithError(t *testing.T) {
+	g := NewWithT(t)
+	reader := fake.NewClientBuilder().Build()
+	conditions := map[v1alpha1.Component]v1alpha1.ResourceCondition{}
+	topics := []v1alpha1.RetryTopic{{Name: "persistent://public/default/dlq", Provision: true}}
+	requeueAfter := applyRetryTopics(context.Background(), reader, logr.Discard(), topics,
+		&v1alpha1.PulsarMessaging{PulsarConfig: "missing-config"}, conditions, "function", "default", "fn")
+	g.Expect(requeueAfter).To(Equal(retryTopicsRetryInterval))
+	g.Expect(conditions[v1alpha1.RetryTopics].Condition).To(Equal(v1alpha1.RetryTopicsReady))
+	g.Expect(conditions[v1alpha1.RetryTopics].Status).To(Equal(metav1.ConditionFalse))
+	g.Expect(conditions[v1alpha1.RetryTopics].Reason).To(Equal(v1alpha1.RetryTopicsProvisionFailed))
+	g.Expect(conditions[v1alpha1.RetryTopics].Message).To(ContainSubstring("missing-config"))
+
+	g.Expect(minRequeueAfter(0, retryTopicsRetryInterval, stateStoreCheckInterval/2)).To(
+		Equal(stateStoreCheckInterval / 2))
+	g.Expect(minRequeueAfter(0, 0)).To(BeZero())
+}
+
+func TestStateStoreCheckerCache(t *testing.T) {
+	g := NewWithT(t)
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	g.Expect(err).NotTo(HaveOccurred())
+	serviceURL := "bk://" + listener.Addr().String()
+	listener.Close()
+
+	ctx := context.Background()
+	checker := newStateStoreChecker(time.Hour)
+	g.Expect(checker.check(ctx, serviceURL)).To(HaveOccurred())
+
+	// the failure is reused within the interval even once the store is reachable
+	listener, err = net.Listen("tcp", listener.Addr().String())
+	g.Expect(err).NotTo(HaveOccurred())
+	defer listener.Close()
+	g.Expect(checker.check(ctx, serviceURL)).To(HaveOccurred())
+
+	checker.interval = 0
+	g.Expect(checker.check(ctx, serviceURL)).To(Succeed())
+}
@@ -0,0 +1,186 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/streamnative/function-mesh/api/compute/v1alpha1"
+	"github.com/streamnative/function-mesh/controllers/spec"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+)
+
+const (
+	// DefaultConfigsReloadInterval is how often the controller config file is checked for changes
+	DefaultConfigsReloadInterval = 10 * time.Second
+
+	configsEventsBuffer = 1024
+)
+
+// ControllerConfigsWatcher reloads the controller configs when the config file changes and triggers the
+// reconciliation of the components affected by the change. The file is polled since a mounted ConfigMap
+// is updated by swapping symlinks, a config that fails to load is rejected and the previous one is kept.
+type ControllerConfigsWatcher struct {
+	Client   client.Reader
+	Log      logr.Logger
+	Path     string
+	Interval time.Duration
+
+	FunctionEvents chan event.GenericEvent
+	SourceEvents   chan event.GenericEvent
+	SinkEvents     chan event.GenericEvent
+
+	// Elected is closed when the replica is elected as the leader, the events are only consumed by the
+	// controllers of the leader. A nil channel means the replica is always the leader.
+	Elected <-chan struct{}
+
+	content []byte
+}
+
+// NewControllerConfigsWatcher creates a watcher of the config file, the file is expected to be loaded already
+func NewControllerConfigsWatcher(reader client.Reader, log logr.Logger, path string,
+	interval time.Duration) (*ControllerConfigsWatcher, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if interval <= 0 {
+		interval = DefaultConfigsReloadInterval
+	}
+	return &ControllerConfigsWatcher{
+		Client:         reader,
+		Log:            log,
+		Path:           path,
+		Interval:       interval,
+		FunctionEvents: make(chan event.GenericEvent, configsEventsBuffer),
+		SourceEvents:   make(chan event.GenericEvent, configsEventsBuffer),
+		SinkEvents:     make(chan event.GenericEvent, configsEventsBuffer),
+		content:        content,
+	}, nil
+}
+
+// Start polls the config file until the context is done
+func (w *ControllerConfigsWatcher) Start(ctx context.Context) error {
+	ticker := time.NewTicker(w.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			w.Reload(ctx)
+		}
+	}
+}
+
+// NeedLeaderElection returns false so the configs are reloaded by every replica, the webhooks of the
+// replicas that are not the leader use the configs as well
+func (w *ControllerConfigsWatcher) NeedLeaderElection() bool {
+	return false
+}
+
+// Reload loads the config file if its content is changed, it returns whether new configs are applied
+func (w *ControllerConfigsWatcher) Reload(ctx context.Context) bool {
+	content, err := ioutil.ReadFile(w.Path)
+	if err != nil {
+		w.Log.Error(err, "failed to read the controller configs", "path", w.Path)
+		return false
+	}
+	if bytes.Equal(content, w.content) {
+		return false
+	}
+	w.content = content
+
+	configs := spec.DefaultConfigs()
+	if len(content) > 0 {
+		configs, err = spec.LoadControllerConfigs(content)
+		if err != nil {
+			w.Log.Error(err, "rejected the invalid controller configs, the previous configs are kept",
+				"path", w.Path)
+			return false
+		}
+	}
+	previous := spec.GetConfigs()
+	spec.SetConfigs(configs)
+	all, namespaces := configs.ChangedNamespaces(previous)
+	w.Log.Info("reloaded the controller configs", "path", w.Path, "allNamespaces", all,
+		"namespaces", namespaces)
+
+	if all {
+		w.enqueue(ctx, "")
+		return true
+	}
+	for _, namespace := range namespaces {
+		w.enqueue(ctx, namespace)
+	}
+	return true
+}
+
+// enqueue triggers the reconciliation of the components in a namespace, or all namespaces if it is empty
+func (w *ControllerConfigsWatcher) enqueue(ctx context.Context, namespace string) {
+	functions := &v1alpha1.FunctionList{}
+	if err := w.Client.List(ctx, functions, client.InNamespace(namespace)); err != nil {
+		w.Log.Error(err, "failed to list functions", "namespace", namespace)
+	}
+	for i := range functions.Items {
+		w.send(ctx, w.FunctionEvents, &functions.Items[i])
+	}
+	sources := &v1alpha1.SourceList{}
+	if err := w.Client.List(ctx, sources, client.InNamespace(namespace)); err != nil {
+		w.Log.Error(err, "failed to list sources", "namespace", namespace)
+	}
+	for i := range sources.Items {
+		w.send(ctx, w.SourceEvents, &sources.Items[i])
+	}
+	sinks := &v1alpha1.SinkList{}
+	if err := w.Client.List(ctx, sinks, client.InNamespace(namespace)); err != nil {
+		w.Log.Error(err, "failed to list sinks", "namespace", namespace)
+	}
+	for i := range sinks.Items {
+		w.send(ctx, w.SinkEvents, &sinks.Items[i])
+	}
+}
+
+// send waits for the controllers to take the event so that no reconcile request is lost. The replicas which are
+// not the leader send nothing, they would wait forever, and the leader reconciles all components when it starts.
+func (w *ControllerConfigsWatcher) send(ctx context.Context, events chan event.GenericEvent, object client.Object) {
+	if !spec.IsManaged(object) || !w.isLeader() {
+		return
+	}
+	select {
+	case events <- event.GenericEvent{Object: object}:
+	case <-ctx.Done():
+	}
+}
+
+func (w *ControllerConfigsWatcher) isLeader() bool {
+	if w.Elected == nil {
+		return true
+	}
+	select {
+	case <-w.Elected:
+		return true
+	default:
+		return false
+	}
+}
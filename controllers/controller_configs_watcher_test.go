@@ -0,0 +1,110 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package controllers
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/streamnative/function-mesh/api/compute/v1alpha1"
+	"github.com/streamnative/function-mesh/controllers/spec"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+)
+
+func TestControllerConfigsWatcherReload(t *testing.T) {
+	g := NewWithT(t)
+	defer spec.SetConfigs(spec.DefaultConfigs())
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	g.Expect(os.WriteFile(path, []byte("downloaderImage: pulsarctl:a\n"), 0644)).To(Succeed())
+	g.Expect(spec.ParseControllerConfigs(path)).To(Succeed())
+
+	scheme := runtime.NewScheme()
+	g.Expect(v1alpha1.AddToScheme(scheme)).To(Succeed())
+	reader := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		&v1alpha1.Function{ObjectMeta: metav1.ObjectMeta{Namespace: "team-a", Name: "fn"}},
+		&v1alpha1.Function{ObjectMeta: metav1.ObjectMeta{Namespace: "team-b", Name: "fn"}},
+		&v1alpha1.Source{ObjectMeta: metav1.ObjectMeta{Namespace: "team-a", Name: "src"}},
+		&v1alpha1.Sink{ObjectMeta: metav1.ObjectMeta{Namespace: "team-b", Name: "sink"}},
+	).Build()
+	watcher, err := NewControllerConfigsWatcher(reader, ctrl.Log, path, 0)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(watcher.Interval).To(Equal(DefaultConfigsReloadInterval))
+
+	// unchanged file
+	g.Expect(watcher.Reload(context.Background())).To(BeFalse())
+
+	// a global change reconciles all components
+	g.Expect(os.WriteFile(path, []byte("downloaderImage: pulsarctl:b\n"), 0644)).To(Succeed())
+	g.Expect(watcher.Reload(context.Background())).To(BeTrue())
+	g.Expect(spec.GetConfigs().DownloaderImage).To(Equal("pulsarctl:b"))
+	g.Expect(watcher.FunctionEvents).To(HaveLen(2))
+	g.Expect(watcher.SourceEvents).To(HaveLen(1))
+	g.Expect(watcher.SinkEvents).To(HaveLen(1))
+	drain(watcher)
+
+	// a namespace override only reconciles the components of the namespace
+	g.Expect(os.WriteFile(path, []byte("downloaderImage: pulsarctl:b\nnamespaces:\n  team-b:\n    downloaderImage: pulsarctl:c\n"),
+		0644)).To(Succeed())
+	g.Expect(watcher.Reload(context.Background())).To(BeTrue())
+	g.Expect(watcher.FunctionEvents).To(HaveLen(1))
+	g.Expect((<-watcher.FunctionEvents).Object.GetNamespace()).To(Equal("team-b"))
+	g.Expect(watcher.SourceEvents).To(HaveLen(0))
+	g.Expect(watcher.SinkEvents).To(HaveLen(1))
+	drain(watcher)
+
+	// an invalid config is rejected and the previous one is kept
+	g.Expect(os.WriteFile(path, []byte("downloaderImage: [\n"), 0644)).To(Succeed())
+	g.Expect(watcher.Reload(context.Background())).To(BeFalse())
+	g.Expect(spec.GetConfigs().DefaultsFor("team-b").DownloaderImage).To(Equal("pulsarctl:c"))
+	g.Expect(watcher.FunctionEvents).To(HaveLen(0))
+
+	// the replicas which are not the leader send no events
+	elected := make(chan struct{})
+	watcher.Elected = elected
+	g.Expect(os.WriteFile(path, []byte("downloaderImage: pulsarctl:d\n"), 0644)).To(Succeed())
+	g.Expect(watcher.Reload(context.Background())).To(BeTrue())
+	g.Expect(watcher.FunctionEvents).To(HaveLen(0))
+
+	// the leader waits for the events to be taken until the context is done
+	close(elected)
+	watcher.FunctionEvents = make(chan event.GenericEvent)
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-watcher.FunctionEvents
+		cancel()
+	}()
+	g.Expect(os.WriteFile(path, []byte("downloaderImage: pulsarctl:e\n"), 0644)).To(Succeed())
+	g.Expect(watcher.Reload(ctx)).To(BeTrue())
+	drain(watcher)
+}
+
+func drain(watcher *ControllerConfigsWatcher) {
+	for _, events := range []chan event.GenericEvent{watcher.FunctionEvents, watcher.SourceEvents, watcher.SinkEvents} {
+		for len(events) > 0 {
+			<-events
+		}
+	}
+}
@@ -19,6 +19,7 @@ package controllers
 
 import (
 	"context"
+	"time"
 
 	autoscaling "k8s.io/api/autoscaling/v1"
 
@@ -34,18 +35,24 @@ import (
 )
 
 func (r *FunctionReconciler) ObserveFunctionStatefulSet(ctx context.Context, function *v1alpha1.Function) error {
+	restoring, err := hasPendingStateRestore(ctx, r.Client, function)
+	if err != nil {
+		return err
+	}
 	condition, ok := function.Status.Conditions[v1alpha1.StatefulSet]
 	if !ok {
-		function.Status.Conditions[v1alpha1.StatefulSet] = v1alpha1.ResourceCondition{
+		condition = v1alpha1.ResourceCondition{
 			Condition: v1alpha1.StatefulSetReady,
 			Status:    metav1.ConditionFalse,
 			Action:    v1alpha1.Create,
 		}
+		function.Status.Conditions[v1alpha1.StatefulSet] = observeStateRestore(condition, restoring)
 		return nil
 	}
+	condition = observeStateRestore(condition, restoring)
 
 	statefulSet := &appsv1.StatefulSet{}
-	err := r.Get(ctx, types.NamespacedName{
+	err = r.Get(ctx, types.NamespacedName{
 		Namespace: function.Namespace,
 		Name:      spec.MakeFunctionObjectMeta(function).Name,
 	}, statefulSet)
@@ -69,20 +76,39 @@ func (r *FunctionReconciler) ObserveFunctionStatefulSet(ctx context.Context, fun
 	}
 	function.Status.Selector = selector.String()
 
-	if r.checkIfStatefulSetNeedUpdate(statefulSet, function) {
+	if restoring {
+		// the instances are stopped while the state is restored, so they do not overwrite the restored state
+		condition.Status = metav1.ConditionFalse
+		condition.Action = v1alpha1.Update
+		function.Status.Replicas = *statefulSet.Spec.Replicas
+		function.Status.ReadyReplicas = statefulSet.Status.ReadyReplicas
+		function.Status.Conditions[v1alpha1.StatefulSet] = condition
+		return nil
+	}
+
+	if err := observePackageVerification(ctx, r, statefulSet, selector, &condition); err != nil {
+		return err
+	}
+
+	needUpdate, err := r.checkIfStatefulSetNeedUpdate(ctx, statefulSet, function)
+	if err != nil {
+		return err
+	}
+	if needUpdate {
 		condition.Status = metav1.ConditionFalse
 		condition.Action = v1alpha1.Update
 		function.Status.Conditions[v1alpha1.StatefulSet] = condition
 		return nil
 	}
 
-	if statefulSet.Status.ReadyReplicas == *function.Spec.Replicas {
+	if statefulSet.Status.ReadyReplicas == *statefulSet.Spec.Replicas {
 		condition.Action = v1alpha1.NoAction
 	} else {
 		condition.Action = v1alpha1.Wait
 	}
 	condition.Status = metav1.ConditionTrue
 	function.Status.Replicas = *statefulSet.Spec.Replicas
+	function.Status.ReadyReplicas = statefulSet.Status.ReadyReplicas
 	function.Status.Conditions[v1alpha1.StatefulSet] = condition
 	return nil
 }
@@ -92,7 +118,21 @@ func (r *FunctionReconciler) ApplyFunctionStatefulSet(ctx context.Context, funct
 	if condition.Status == metav1.ConditionTrue && !newGeneration {
 		return nil
 	}
-	desiredStatefulSet := spec.MakeFunctionStatefulSet(function)
+	restoring := isStateRestorePending(function.Status.Conditions)
+	if restoring && condition.Action == v1alpha1.Create {
+		return nil
+	}
+	desiredStatefulSet, err := r.makeFunctionStatefulSet(ctx, function)
+	if err != nil {
+		return err
+	}
+	if deleted, err := deleteStatefulSetOnImmutableChange(ctx, r.Client, desiredStatefulSet); err != nil || deleted {
+		return err
+	}
+	if restoring {
+		replicas := int32(0)
+		desiredStatefulSet.Spec.Replicas = &replicas
+	}
 	desiredStatefulSetSpec := desiredStatefulSet.Spec
 	if _, err := ctrl.CreateOrUpdate(ctx, r.Client, desiredStatefulSet, func() error {
 		// function statefulSet mutate logic
@@ -167,6 +207,15 @@ func (r *FunctionReconciler) ObserveFunctionHPA(ctx context.Context, function *v
 		return nil
 	}
 
+	if function.Spec.Paused || isStateRestorePending(function.Status.Conditions) {
+		// HPA is suspended while the function is paused or its state is restored
+		function.Status.Conditions[v1alpha1.HPA] = v1alpha1.CreateCondition(
+			v1alpha1.HPAReady,
+			metav1.ConditionFalse,
+			v1alpha1.Delete)
+		return nil
+	}
+
 	condition, ok := function.Status.Conditions[v1alpha1.HPA]
 	if !ok {
 		function.Status.Conditions[v1alpha1.HPA] = v1alpha1.ResourceCondition{
@@ -212,6 +261,17 @@ func (r *FunctionReconciler) ApplyFunctionHPA(ctx context.Context, function *v1a
 		return nil
 	}
 	condition := function.Status.Conditions[v1alpha1.HPA]
+	if condition.Action == v1alpha1.Delete {
+		err := deleteHPA(ctx, r.Client, types.NamespacedName{Namespace: function.Namespace,
+			Name: spec.MakeFunctionObjectMeta(function).Name})
+		if err != nil {
+			r.Log.Error(err, "error delete hpa for function",
+				"namespace", function.Namespace, "name", function.Name)
+			return err
+		}
+		delete(function.Status.Conditions, v1alpha1.HPA)
+		return nil
+	}
 	if condition.Status == metav1.ConditionTrue && !newGeneration {
 		return nil
 	}
@@ -231,8 +291,60 @@ func (r *FunctionReconciler) ApplyFunctionHPA(ctx context.Context, function *v1a
 }
 
 func (r *FunctionReconciler) ObserveFunctionVPA(ctx context.Context, function *v1alpha1.Function) error {
+	vpaSpec := function.Spec.Pod.VPA
+	if function.Spec.Paused {
+		// VPA is suspended while the function is paused
+		vpaSpec = nil
+	}
 	return observeVPA(ctx, r, types.NamespacedName{Namespace: function.Namespace,
-		Name: spec.MakeFunctionObjectMeta(function).Name}, function.Spec.Pod.VPA, function.Status.Conditions)
+		Name: spec.MakeFunctionObjectMeta(function).Name}, vpaSpec, function.Status.Conditions)
+}
+
+// ApplyFunctionRetryTopics provisions the dead letter and retry letter topics of the retry policy, it returns when to
+// requeue the function if they cannot be provisioned
+func (r *FunctionReconciler) ApplyFunctionRetryTopics(ctx context.Context, function *v1alpha1.Function, newGeneration bool) time.Duration {
+	topics := spec.MakeRetryTopics(function.Spec.RetryPolicy)
+	if len(topics) == 0 {
+		// topic provisioning not enabled, skip further action
+		delete(function.Status.Conditions, v1alpha1.RetryTopics)
+		return 0
+	}
+	condition, ok := function.Status.Conditions[v1alpha1.RetryTopics]
+	if ok && condition.Status == metav1.ConditionTrue && !newGeneration {
+		return 0
+	}
+	messaging, err := makeResolvedPulsarMessaging(ctx, r, function.Namespace, function.Spec.Messaging)
+	if err != nil {
+		return failRetryTopics(r.Log, err, function.Status.Conditions, "function", function.Namespace, function.Name)
+	}
+	return applyRetryTopics(ctx, r, r.Log, topics, messaging, function.Status.Conditions,
+		"function", function.Namespace, function.Name)
+}
+
+// CheckFunctionStateStore runs the pre-flight check of the state store of a stateful function, it returns when to
+// requeue the function if the state store is unreachable
+func (r *FunctionReconciler) CheckFunctionStateStore(ctx context.Context, function *v1alpha1.Function, newGeneration bool) time.Duration {
+	return applyStateStoreCheck(ctx, r.Log, function.Spec.StateConfig, function.Status.Conditions, newGeneration,
+		"function", function.Namespace, function.Name)
+}
+
+// ObserveFunctionPause records the replica count of a paused function, so it can be restored on resume
+func (r *FunctionReconciler) ObserveFunctionPause(function *v1alpha1.Function) {
+	if !function.Spec.Paused {
+		return
+	}
+	observePause(function.Spec.Replicas, function.Status.Replicas, &function.Status.PauseStatus, function.Status.Conditions)
+}
+
+// RestoreFunctionReplicas restores the replica count recorded when the function was paused, unless the replicas
+// of the function were changed during the pause
+func (r *FunctionReconciler) RestoreFunctionReplicas(ctx context.Context, function *v1alpha1.Function) error {
+	err := resumeReplicas(ctx, r, function, &function.Spec.Replicas, &function.Status.PauseStatus, function.Status.Conditions)
+	if err != nil {
+		r.Log.Error(err, "failed to restore the replicas of the resumed function",
+			"namespace", function.Namespace, "name", function.Name)
+	}
+	return err
 }
 
 func (r *FunctionReconciler) ApplyFunctionVPA(ctx context.Context, function *v1alpha1.Function) error {
@@ -258,8 +370,31 @@ func (r *FunctionReconciler) ApplyFunctionVPA(ctx context.Context, function *v1a
 	return nil
 }
 
-func (r *FunctionReconciler) checkIfStatefulSetNeedUpdate(statefulSet *appsv1.StatefulSet, function *v1alpha1.Function) bool {
-	return !spec.CheckIfStatefulSetSpecIsEqual(&statefulSet.Spec, &spec.MakeFunctionStatefulSet(function).Spec)
+func (r *FunctionReconciler) checkIfStatefulSetNeedUpdate(ctx context.Context, statefulSet *appsv1.StatefulSet,
+	function *v1alpha1.Function) (bool, error) {
+	desiredStatefulSet, err := r.makeFunctionStatefulSet(ctx, function)
+	if err != nil {
+		return false, err
+	}
+	return !spec.CheckIfStatefulSetSpecIsEqual(&statefulSet.Spec, &desiredStatefulSet.Spec), nil
+}
+
+// makeFunctionStatefulSet makes the desired statefulSet of the function with its PulsarConnection resolved
+func (r *FunctionReconciler) makeFunctionStatefulSet(ctx context.Context, function *v1alpha1.Function) (*appsv1.StatefulSet, error) {
+	function = function.DeepCopy()
+	err := resolvePulsarConnection(ctx, r, function.Namespace, &function.Spec.Messaging, &function.Spec.Pod)
+	if err != nil {
+		r.Log.Error(err, "failed to resolve the pulsar connection of function",
+			"namespace", function.Namespace, "name", function.Name)
+		return nil, err
+	}
+	statefulSet := spec.MakeFunctionStatefulSet(function)
+	if err = applyConfigChecksum(ctx, r, function, &statefulSet.Spec.Template); err != nil {
+		r.Log.Error(err, "failed to compute the config checksum of function",
+			"namespace", function.Namespace, "name", function.Name)
+		return nil, err
+	}
+	return statefulSet, nil
 }
 
 func (r *FunctionReconciler) checkIfHPANeedUpdate(hpa *autov2beta2.HorizontalPodAutoscaler, function *v1alpha1.Function) bool {
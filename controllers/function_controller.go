@@ -29,12 +29,16 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	vpav1 "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/apis/autoscaling.k8s.io/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 )
 
 // FunctionReconciler reconciles a Function object
@@ -43,12 +47,16 @@ type FunctionReconciler struct {
 	Log        logr.Logger
 	Scheme     *runtime.Scheme
 	WatchFlags *utils.WatchFlags
+	// ConfigEvents triggers the reconciliation of the functions affected by reloaded controller configs
+	ConfigEvents <-chan event.GenericEvent
 }
 
 // +kubebuilder:rbac:groups=compute.functionmesh.io,resources=functions,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=compute.functionmesh.io,resources=functions/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=apps,resources=statefulsets,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=core,resources=services,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=core,resources=pods,verbs=get;list;watch
+// +kubebuilder:rbac:groups=core,resources=configmaps,verbs=get;list;watch
 // +kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=autoscaling,resources=horizontalpodautoscalers,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=autoscaling.k8s.io,resources=verticalpodautoscalers,verbs=get;list;watch;create;update;patch;delete
@@ -74,10 +82,19 @@ func (r *FunctionReconciler) Reconcile(ctx context.Context, req ctrl.Request) (c
 	}
 
 	// initialize component status map
+	if !function.Spec.Paused && function.Status.PausedReplicas != nil {
+		err = r.RestoreFunctionReplicas(ctx, function)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+	}
+
 	if function.Status.Conditions == nil {
 		function.Status.Conditions = make(map[v1alpha1.Component]v1alpha1.ResourceCondition)
 	}
 
+	r.ObserveFunctionPause(function)
+
 	err = r.ObserveFunctionStatefulSet(ctx, function)
 	if err != nil {
 		return reconcile.Result{}, err
@@ -104,6 +121,8 @@ func (r *FunctionReconciler) Reconcile(ctx context.Context, req ctrl.Request) (c
 
 	isNewGeneration := r.checkIfFunctionGenerationsIsIncreased(function)
 
+	requeueAfter := minRequeueAfter(r.ApplyFunctionRetryTopics(ctx, function, isNewGeneration),
+		r.CheckFunctionStateStore(ctx, function, isNewGeneration))
 	err = r.ApplyFunctionStatefulSet(ctx, function, isNewGeneration)
 	if err != nil {
 		return reconcile.Result{}, err
@@ -127,7 +146,7 @@ func (r *FunctionReconciler) Reconcile(ctx context.Context, req ctrl.Request) (c
 		r.Log.Error(err, "failed to update function status")
 		return ctrl.Result{}, err
 	}
-	return ctrl.Result{}, nil
+	return ctrl.Result{RequeueAfter: requeueAfter}, nil
 }
 
 func (r *FunctionReconciler) checkIfFunctionGenerationsIsIncreased(function *v1alpha1.Function) bool {
@@ -135,6 +154,24 @@ func (r *FunctionReconciler) checkIfFunctionGenerationsIsIncreased(function *v1a
 }
 
 func (r *FunctionReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &v1alpha1.Function{}, configMapReferencesIndex,
+		func(object client.Object) []string {
+			return spec.MakeFunctionConfigReferences(object.(*v1alpha1.Function)).ConfigMaps
+		}); err != nil {
+		return err
+	}
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &v1alpha1.Function{}, secretReferencesIndex,
+		func(object client.Object) []string {
+			return spec.MakeFunctionConfigReferences(object.(*v1alpha1.Function)).Secrets
+		}); err != nil {
+		return err
+	}
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &v1alpha1.Function{}, pulsarConnectionIndex,
+		func(object client.Object) []string {
+			return indexPulsarConnection(object.GetNamespace(), object.(*v1alpha1.Function).Spec.Messaging)
+		}); err != nil {
+		return err
+	}
 	manager := ctrl.NewControllerManagedBy(mgr).
 		For(&v1alpha1.Function{}).
 		Owns(&appsv1.StatefulSet{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
@@ -145,5 +182,35 @@ func (r *FunctionReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	if r.WatchFlags != nil && r.WatchFlags.WatchVPACRDs {
 		manager.Owns(&vpav1.VerticalPodAutoscaler{})
 	}
+	newList := func() client.ObjectList { return &v1alpha1.FunctionList{} }
+	manager.Watches(&source.Kind{Type: &corev1.ConfigMap{}},
+		handler.EnqueueRequestsFromMapFunc(findComponentsForConfig(r, r.Log, newList, configMapReferencesIndex)),
+		builder.WithPredicates(configReferencedPredicate(r, r.Log, newList, configMapReferencesIndex)))
+	manager.Watches(&source.Kind{Type: &corev1.Secret{}},
+		handler.EnqueueRequestsFromMapFunc(findComponentsForConfig(r, r.Log, newList, secretReferencesIndex)),
+		builder.WithPredicates(configReferencedPredicate(r, r.Log, newList, secretReferencesIndex)))
+	manager.Watches(&source.Kind{Type: &v1alpha1.PulsarConnection{}},
+		handler.EnqueueRequestsFromMapFunc(findComponentsForPulsarConnection(r, r.Log, newList)))
+	manager.Watches(&source.Kind{Type: &v1alpha1.FunctionStateSnapshot{}},
+		handler.EnqueueRequestsFromMapFunc(findFunctionForStateSnapshot))
+	manager.Watches(&source.Kind{Type: &corev1.Pod{}},
+		handler.EnqueueRequestsFromMapFunc(findComponentForPod(spec.ComponentFunction)),
+		builder.WithPredicates(packageVerificationFailedPredicate))
+	if r.ConfigEvents != nil {
+		manager.Watches(&source.Channel{Source: r.ConfigEvents}, &handler.EnqueueRequestForObject{})
+	}
 	return manager.Complete(r)
 }
+
+// findFunctionForStateSnapshot maps a FunctionStateSnapshot to its function, so that the StatefulSet held back
+// by a restore is created once the restore finishes
+func findFunctionForStateSnapshot(object client.Object) []reconcile.Request {
+	snapshot, ok := object.(*v1alpha1.FunctionStateSnapshot)
+	if !ok || snapshot.Spec.Mode != v1alpha1.StateSnapshotRestore {
+		return nil
+	}
+	return []reconcile.Request{{NamespacedName: types.NamespacedName{
+		Namespace: snapshot.Namespace,
+		Name:      snapshot.Spec.Function,
+	}}}
+}
@@ -19,13 +19,16 @@ package controllers
 
 import (
 	"context"
+	"sort"
 
 	"github.com/streamnative/function-mesh/api/compute/v1alpha1"
 	"github.com/streamnative/function-mesh/controllers/spec"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 func (r *FunctionMeshReconciler) ObserveFunctionMesh(ctx context.Context, req ctrl.Request,
@@ -69,6 +72,7 @@ func (r *FunctionMeshReconciler) observeFunctions(ctx context.Context, mesh *v1a
 				Status:    metav1.ConditionFalse,
 				Action:    v1alpha1.Create,
 			}
+			setMeshComponentStatus(mesh, v1alpha1.ComponentStatus{Kind: v1alpha1.FunctionComponent, Name: functionSpec.Name})
 			continue
 		}
 
@@ -82,6 +86,7 @@ func (r *FunctionMeshReconciler) observeFunctions(ctx context.Context, mesh *v1a
 				r.Log.Info("function is not ready", "name", functionSpec.Name)
 				condition.SetCondition(v1alpha1.FunctionReady, v1alpha1.Create, metav1.ConditionFalse)
 				mesh.Status.FunctionConditions[functionSpec.Name] = condition
+				setMeshComponentStatus(mesh, v1alpha1.ComponentStatus{Kind: v1alpha1.FunctionComponent, Name: functionSpec.Name})
 				continue
 			}
 			return err
@@ -96,6 +101,17 @@ func (r *FunctionMeshReconciler) observeFunctions(ctx context.Context, mesh *v1a
 			condition.SetCondition(v1alpha1.FunctionReady, v1alpha1.Wait, metav1.ConditionFalse)
 			mesh.Status.FunctionConditions[functionSpec.Name] = condition
 		}
+
+		setMeshComponentStatus(mesh, v1alpha1.ComponentStatus{
+			Kind:               v1alpha1.FunctionComponent,
+			Name:               functionSpec.Name,
+			Generation:         function.Generation,
+			ObservedGeneration: function.Status.ObservedGeneration,
+			Replicas:           getReplicas(function.Spec.Replicas, function.Spec.Paused),
+			ReadyReplicas:      function.Status.ReadyReplicas,
+			Ready: condition.Status == metav1.ConditionTrue &&
+				function.Status.ReadyReplicas >= getReplicas(function.Spec.Replicas, function.Spec.Paused),
+		})
 	}
 
 	for functionName, isOrphaned := range orphanedFunctions {
@@ -130,6 +146,7 @@ func (r *FunctionMeshReconciler) observeSources(ctx context.Context, mesh *v1alp
 				Status:    metav1.ConditionFalse,
 				Action:    v1alpha1.Create,
 			}
+			setMeshComponentStatus(mesh, v1alpha1.ComponentStatus{Kind: v1alpha1.SourceComponent, Name: sourceSpec.Name})
 			continue
 		}
 
@@ -143,6 +160,7 @@ func (r *FunctionMeshReconciler) observeSources(ctx context.Context, mesh *v1alp
 				r.Log.Info("source is not ready", "name", sourceSpec.Name)
 				condition.SetCondition(v1alpha1.SourceReady, v1alpha1.Create, metav1.ConditionFalse)
 				mesh.Status.SourceConditions[sourceSpec.Name] = condition
+				setMeshComponentStatus(mesh, v1alpha1.ComponentStatus{Kind: v1alpha1.SourceComponent, Name: sourceSpec.Name})
 				continue
 			}
 			return err
@@ -157,6 +175,17 @@ func (r *FunctionMeshReconciler) observeSources(ctx context.Context, mesh *v1alp
 			condition.SetCondition(v1alpha1.SourceReady, v1alpha1.Wait, metav1.ConditionFalse)
 			mesh.Status.SourceConditions[sourceSpec.Name] = condition
 		}
+
+		setMeshComponentStatus(mesh, v1alpha1.ComponentStatus{
+			Kind:               v1alpha1.SourceComponent,
+			Name:               sourceSpec.Name,
+			Generation:         source.Generation,
+			ObservedGeneration: source.Status.ObservedGeneration,
+			Replicas:           getReplicas(source.Spec.Replicas, source.Spec.Paused),
+			ReadyReplicas:      source.Status.ReadyReplicas,
+			Ready: condition.Status == metav1.ConditionTrue &&
+				source.Status.ReadyReplicas >= getReplicas(source.Spec.Replicas, source.Spec.Paused),
+		})
 	}
 
 	for sourceName, isOrphaned := range orphanedSources {
@@ -190,6 +219,7 @@ func (r *FunctionMeshReconciler) observeSinks(ctx context.Context, mesh *v1alpha
 				Status:    metav1.ConditionFalse,
 				Action:    v1alpha1.Create,
 			}
+			setMeshComponentStatus(mesh, v1alpha1.ComponentStatus{Kind: v1alpha1.SinkComponent, Name: sinkSpec.Name})
 			continue
 		}
 
@@ -203,6 +233,7 @@ func (r *FunctionMeshReconciler) observeSinks(ctx context.Context, mesh *v1alpha
 				r.Log.Info("sink is not ready", "name", sinkSpec.Name)
 				condition.SetCondition(v1alpha1.SinkReady, v1alpha1.Create, metav1.ConditionFalse)
 				mesh.Status.SinkConditions[sinkSpec.Name] = condition
+				setMeshComponentStatus(mesh, v1alpha1.ComponentStatus{Kind: v1alpha1.SinkComponent, Name: sinkSpec.Name})
 				continue
 			}
 			return err
@@ -217,6 +248,17 @@ func (r *FunctionMeshReconciler) observeSinks(ctx context.Context, mesh *v1alpha
 			condition.SetCondition(v1alpha1.SinkReady, v1alpha1.Wait, metav1.ConditionFalse)
 			mesh.Status.SinkConditions[sinkSpec.Name] = condition
 		}
+
+		setMeshComponentStatus(mesh, v1alpha1.ComponentStatus{
+			Kind:               v1alpha1.SinkComponent,
+			Name:               sinkSpec.Name,
+			Generation:         sink.Generation,
+			ObservedGeneration: sink.Status.ObservedGeneration,
+			Replicas:           getReplicas(sink.Spec.Replicas, sink.Spec.Paused),
+			ReadyReplicas:      sink.Status.ReadyReplicas,
+			Ready: condition.Status == metav1.ConditionTrue &&
+				sink.Status.ReadyReplicas >= getReplicas(sink.Spec.Replicas, sink.Spec.Paused),
+		})
 	}
 
 	for sinkName, isOrphaned := range orphanedSinks {
@@ -232,6 +274,8 @@ func (r *FunctionMeshReconciler) observeSinks(ctx context.Context, mesh *v1alpha
 }
 
 func (r *FunctionMeshReconciler) observeMeshes(mesh *v1alpha1.FunctionMesh) {
+	pruneMeshComponentStatuses(mesh)
+
 	for _, cond := range mesh.Status.FunctionConditions {
 		if cond.Condition == v1alpha1.FunctionReady && cond.Status == metav1.ConditionTrue {
 			continue
@@ -262,54 +306,33 @@ func (r *FunctionMeshReconciler) observeMeshes(mesh *v1alpha1.FunctionMesh) {
 func (r *FunctionMeshReconciler) UpdateFunctionMesh(ctx context.Context, req ctrl.Request,
 	mesh *v1alpha1.FunctionMesh, newGeneration bool) error {
 	defer func() {
+		observeMeshConditions(mesh)
 		err := r.Status().Update(ctx, mesh)
 		if err != nil {
 			r.Log.Error(err, "failed to update mesh status")
 		}
 	}()
 
-	for _, functionSpec := range mesh.Spec.Functions {
-		condition := mesh.Status.FunctionConditions[functionSpec.Name]
-		if !newGeneration &&
-			functionSpec.MaxReplicas != nil &&
-			condition.Status == metav1.ConditionTrue &&
-			condition.Action == v1alpha1.NoAction {
-			continue
-		}
-		function := spec.MakeFunctionComponent(makeComponentName(mesh.Name, functionSpec.Name), mesh, &functionSpec)
-		if err := r.CreateOrUpdateFunction(ctx, function, function.Spec); err != nil {
-			r.Log.Error(err, "failed to handle function", "name", functionSpec.Name, "action", condition.Action)
+	if mesh.Spec.StartupOrder == v1alpha1.StartupOrderTopological {
+		if err := r.applyFunctionMeshInTopologicalOrder(ctx, mesh, newGeneration); err != nil {
 			return err
 		}
-	}
-
-	for _, sourceSpec := range mesh.Spec.Sources {
-		condition := mesh.Status.SourceConditions[sourceSpec.Name]
-		if !newGeneration &&
-			sourceSpec.MaxReplicas != nil &&
-			condition.Status == metav1.ConditionTrue &&
-			condition.Action == v1alpha1.NoAction {
-			continue
-		}
-		source := spec.MakeSourceComponent(makeComponentName(mesh.Name, sourceSpec.Name), mesh, &sourceSpec)
-		if err := r.CreateOrUpdateSource(ctx, source, source.Spec); err != nil {
-			r.Log.Error(err, "failed to handle soure", "name", sourceSpec.Name, "action", condition.Action)
-			return err
+	} else {
+		mesh.Status.StartupProgress = nil
+		for i := range mesh.Spec.Functions {
+			if err := r.applyFunctionComponent(ctx, mesh, &mesh.Spec.Functions[i], newGeneration); err != nil {
+				return err
+			}
 		}
-	}
-
-	for _, sinkSpec := range mesh.Spec.Sinks {
-		condition := mesh.Status.SinkConditions[sinkSpec.Name]
-		if !newGeneration &&
-			sinkSpec.MaxReplicas != nil &&
-			condition.Status == metav1.ConditionTrue &&
-			condition.Action == v1alpha1.NoAction {
-			continue
+		for i := range mesh.Spec.Sources {
+			if err := r.applySourceComponent(ctx, mesh, &mesh.Spec.Sources[i], newGeneration); err != nil {
+				return err
+			}
 		}
-		sink := spec.MakeSinkComponent(makeComponentName(mesh.Name, sinkSpec.Name), mesh, &sinkSpec)
-		if err := r.CreateOrUpdateSink(ctx, sink, sink.Spec); err != nil {
-			r.Log.Error(err, "failed to handle sink", "name", sinkSpec.Name, "action", condition.Action)
-			return err
+		for i := range mesh.Spec.Sinks {
+			if err := r.applySinkComponent(ctx, mesh, &mesh.Spec.Sinks[i], newGeneration); err != nil {
+				return err
+			}
 		}
 	}
 
@@ -392,9 +415,283 @@ func (r *FunctionMeshReconciler) UpdateFunctionMesh(ctx context.Context, req ctr
 	return nil
 }
 
+func (r *FunctionMeshReconciler) applyFunctionComponent(ctx context.Context, mesh *v1alpha1.FunctionMesh,
+	functionSpec *v1alpha1.FunctionSpec, newGeneration bool) error {
+	condition := mesh.Status.FunctionConditions[functionSpec.Name]
+	if !newGeneration &&
+		functionSpec.MaxReplicas != nil &&
+		condition.Status == metav1.ConditionTrue &&
+		condition.Action == v1alpha1.NoAction {
+		return nil
+	}
+	function := spec.MakeFunctionComponent(makeComponentName(mesh.Name, functionSpec.Name), mesh, functionSpec)
+	if err := r.CreateOrUpdateFunction(ctx, function, function.Spec); err != nil {
+		r.Log.Error(err, "failed to handle function", "name", functionSpec.Name, "action", condition.Action)
+		setMeshComponentError(mesh, v1alpha1.FunctionComponent, functionSpec.Name, err)
+		return err
+	}
+	setMeshComponentError(mesh, v1alpha1.FunctionComponent, functionSpec.Name, nil)
+	setMeshComponentGeneration(mesh, v1alpha1.FunctionComponent, functionSpec.Name, function.Generation)
+	return nil
+}
+
+func (r *FunctionMeshReconciler) applySourceComponent(ctx context.Context, mesh *v1alpha1.FunctionMesh,
+	sourceSpec *v1alpha1.SourceSpec, newGeneration bool) error {
+	condition := mesh.Status.SourceConditions[sourceSpec.Name]
+	if !newGeneration &&
+		sourceSpec.MaxReplicas != nil &&
+		condition.Status == metav1.ConditionTrue &&
+		condition.Action == v1alpha1.NoAction {
+		return nil
+	}
+	source := spec.MakeSourceComponent(makeComponentName(mesh.Name, sourceSpec.Name), mesh, sourceSpec)
+	if err := r.CreateOrUpdateSource(ctx, source, source.Spec); err != nil {
+		r.Log.Error(err, "failed to handle soure", "name", sourceSpec.Name, "action", condition.Action)
+		setMeshComponentError(mesh, v1alpha1.SourceComponent, sourceSpec.Name, err)
+		return err
+	}
+	setMeshComponentError(mesh, v1alpha1.SourceComponent, sourceSpec.Name, nil)
+	setMeshComponentGeneration(mesh, v1alpha1.SourceComponent, sourceSpec.Name, source.Generation)
+	return nil
+}
+
+func (r *FunctionMeshReconciler) applySinkComponent(ctx context.Context, mesh *v1alpha1.FunctionMesh,
+	sinkSpec *v1alpha1.SinkSpec, newGeneration bool) error {
+	condition := mesh.Status.SinkConditions[sinkSpec.Name]
+	if !newGeneration &&
+		sinkSpec.MaxReplicas != nil &&
+		condition.Status == metav1.ConditionTrue &&
+		condition.Action == v1alpha1.NoAction {
+		return nil
+	}
+	sink := spec.MakeSinkComponent(makeComponentName(mesh.Name, sinkSpec.Name), mesh, sinkSpec)
+	if err := r.CreateOrUpdateSink(ctx, sink, sink.Spec); err != nil {
+		r.Log.Error(err, "failed to handle sink", "name", sinkSpec.Name, "action", condition.Action)
+		setMeshComponentError(mesh, v1alpha1.SinkComponent, sinkSpec.Name, err)
+		return err
+	}
+	setMeshComponentError(mesh, v1alpha1.SinkComponent, sinkSpec.Name, nil)
+	setMeshComponentGeneration(mesh, v1alpha1.SinkComponent, sinkSpec.Name, sink.Generation)
+	return nil
+}
+
+// applyFunctionMeshInTopologicalOrder applies the components stage by stage, a stage is only
+// applied once all the components of the previous stages are ready
+func (r *FunctionMeshReconciler) applyFunctionMeshInTopologicalOrder(ctx context.Context,
+	mesh *v1alpha1.FunctionMesh, newGeneration bool) error {
+	stages, err := spec.MakeFunctionMeshStartupStages(mesh)
+	if err != nil {
+		r.Log.Error(err, "failed to compute the startup order, fallback to parallel startup",
+			"namespace", mesh.Namespace, "name", mesh.Name)
+		stages = [][]spec.MeshComponent{makeMeshComponents(mesh)}
+	}
+
+	progress := &v1alpha1.StartupProgress{TotalStages: int32(len(stages))}
+	mesh.Status.StartupProgress = progress
+	for i, stage := range stages {
+		progress.Stage = int32(i)
+		progress.Waiting = nil
+		for _, component := range stage {
+			if err := r.applyMeshComponent(ctx, mesh, component, newGeneration); err != nil {
+				return err
+			}
+			if !isMeshComponentReady(mesh, component) {
+				progress.Waiting = append(progress.Waiting, component.String())
+			}
+		}
+		if len(progress.Waiting) > 0 {
+			r.Log.Info("waiting for components to be ready before starting the upstream components",
+				"namespace", mesh.Namespace, "name", mesh.Name, "stage", i, "waiting", progress.Waiting)
+			return nil
+		}
+	}
+	return nil
+}
+
+func (r *FunctionMeshReconciler) applyMeshComponent(ctx context.Context, mesh *v1alpha1.FunctionMesh,
+	component spec.MeshComponent, newGeneration bool) error {
+	switch component.Kind {
+	case v1alpha1.FunctionComponent:
+		for i := range mesh.Spec.Functions {
+			if mesh.Spec.Functions[i].Name == component.Name {
+				return r.applyFunctionComponent(ctx, mesh, &mesh.Spec.Functions[i], newGeneration)
+			}
+		}
+	case v1alpha1.SourceComponent:
+		for i := range mesh.Spec.Sources {
+			if mesh.Spec.Sources[i].Name == component.Name {
+				return r.applySourceComponent(ctx, mesh, &mesh.Spec.Sources[i], newGeneration)
+			}
+		}
+	case v1alpha1.SinkComponent:
+		for i := range mesh.Spec.Sinks {
+			if mesh.Spec.Sinks[i].Name == component.Name {
+				return r.applySinkComponent(ctx, mesh, &mesh.Spec.Sinks[i], newGeneration)
+			}
+		}
+	}
+	return nil
+}
+
+// TeardownFunctionMesh deletes the components in the reverse startup order, it returns true
+// once all the components are gone
+func (r *FunctionMeshReconciler) TeardownFunctionMesh(ctx context.Context, mesh *v1alpha1.FunctionMesh) (bool, error) {
+	stages, err := spec.MakeFunctionMeshStartupStages(mesh)
+	if err != nil {
+		stages = [][]spec.MeshComponent{makeMeshComponents(mesh)}
+	}
+
+	progress := &v1alpha1.StartupProgress{TotalStages: int32(len(stages))}
+	mesh.Status.StartupProgress = progress
+	for i := len(stages) - 1; i >= 0; i-- {
+		progress.Stage = int32(i)
+		progress.Waiting = nil
+		for _, component := range stages[i] {
+			object := makeMeshComponentObject(component)
+			err := r.Get(ctx, types.NamespacedName{
+				Namespace: mesh.Namespace,
+				Name:      makeComponentName(mesh.Name, component.Name),
+			}, object)
+			if err != nil {
+				if errors.IsNotFound(err) {
+					continue
+				}
+				return false, err
+			}
+			progress.Waiting = append(progress.Waiting, component.String())
+			if object.GetDeletionTimestamp() != nil {
+				continue
+			}
+			if err := r.Delete(ctx, object); err != nil && !errors.IsNotFound(err) {
+				r.Log.Error(err, "failed to delete component", "component", component.String())
+				return false, err
+			}
+		}
+		if len(progress.Waiting) > 0 {
+			r.Log.Info("waiting for components to be deleted before deleting the downstream components",
+				"namespace", mesh.Namespace, "name", mesh.Name, "stage", i, "waiting", progress.Waiting)
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+func makeMeshComponents(mesh *v1alpha1.FunctionMesh) []spec.MeshComponent {
+	components := []spec.MeshComponent{}
+	for _, function := range mesh.Spec.Functions {
+		components = append(components, spec.MeshComponent{Kind: v1alpha1.FunctionComponent, Name: function.Name})
+	}
+	for _, source := range mesh.Spec.Sources {
+		components = append(components, spec.MeshComponent{Kind: v1alpha1.SourceComponent, Name: source.Name})
+	}
+	for _, sink := range mesh.Spec.Sinks {
+		components = append(components, spec.MeshComponent{Kind: v1alpha1.SinkComponent, Name: sink.Name})
+	}
+	return components
+}
+
+func makeMeshComponentObject(component spec.MeshComponent) client.Object {
+	switch component.Kind {
+	case v1alpha1.SourceComponent:
+		return &v1alpha1.Source{}
+	case v1alpha1.SinkComponent:
+		return &v1alpha1.Sink{}
+	default:
+		return &v1alpha1.Function{}
+	}
+}
+
+// isMeshComponentReady returns whether a component is ready, which requires its controller to have observed
+// the latest generation of the child object
+func isMeshComponentReady(mesh *v1alpha1.FunctionMesh, component spec.MeshComponent) bool {
+	for _, status := range mesh.Status.Components {
+		if status.Kind == component.Kind && status.Name == component.Name {
+			return status.Ready && status.Generation != 0 && status.ObservedGeneration == status.Generation
+		}
+	}
+	return false
+}
+
+// setMeshComponentStatus records the observed state of a component, the last error is kept
+// until the component is applied again
+func setMeshComponentStatus(mesh *v1alpha1.FunctionMesh, status v1alpha1.ComponentStatus) {
+	for i := range mesh.Status.Components {
+		component := &mesh.Status.Components[i]
+		if component.Kind == status.Kind && component.Name == status.Name {
+			status.LastError = component.LastError
+			*component = status
+			return
+		}
+	}
+	mesh.Status.Components = append(mesh.Status.Components, status)
+}
+
+// setMeshComponentGeneration records the generation of a child object just applied, so that the component is
+// not considered ready before its controller observes the change
+func setMeshComponentGeneration(mesh *v1alpha1.FunctionMesh, kind, name string, generation int64) {
+	for i := range mesh.Status.Components {
+		component := &mesh.Status.Components[i]
+		if component.Kind == kind && component.Name == name {
+			component.Generation = generation
+			return
+		}
+	}
+}
+
+func setMeshComponentError(mesh *v1alpha1.FunctionMesh, kind, name string, err error) {
+	for i := range mesh.Status.Components {
+		component := &mesh.Status.Components[i]
+		if component.Kind == kind && component.Name == name {
+			if err != nil {
+				component.LastError = err.Error()
+			} else {
+				component.LastError = ""
+			}
+			return
+		}
+	}
+}
+
+// pruneMeshComponentStatuses drops the status of the components removed from the mesh
+func pruneMeshComponentStatuses(mesh *v1alpha1.FunctionMesh) {
+	exists := map[spec.MeshComponent]bool{}
+	for _, component := range makeMeshComponents(mesh) {
+		exists[component] = true
+	}
+	components := []v1alpha1.ComponentStatus{}
+	for _, component := range mesh.Status.Components {
+		if exists[spec.MeshComponent{Kind: component.Kind, Name: component.Name}] {
+			components = append(components, component)
+		}
+	}
+	sort.Slice(components, func(i, j int) bool {
+		if components[i].Kind != components[j].Kind {
+			return components[i].Kind < components[j].Kind
+		}
+		return components[i].Name < components[j].Name
+	})
+	mesh.Status.Components = components
+}
+
+func observeMeshConditions(mesh *v1alpha1.FunctionMesh) {
+	mesh.Status.ComponentsReady = spec.MakeFunctionMeshComponentsReady(mesh)
+	for _, condition := range spec.MakeFunctionMeshConditions(mesh) {
+		meta.SetStatusCondition(&mesh.Status.Conditions, condition)
+	}
+}
+
+func getReplicas(replicas *int32, paused bool) int32 {
+	if replicas == nil || paused {
+		return 0
+	}
+	return *replicas
+}
+
 func (r *FunctionMeshReconciler) CreateOrUpdateFunction(ctx context.Context, function *v1alpha1.Function, functionSpec v1alpha1.FunctionSpec) error {
+	labels := function.Labels
 	if _, err := ctrl.CreateOrUpdate(ctx, r.Client, function, func() error {
 		// function mutate logic
+		function.Labels = mergeLabels(function.Labels, labels)
 		function.Spec = functionSpec
 		return nil
 	}); err != nil {
@@ -405,8 +702,10 @@ func (r *FunctionMeshReconciler) CreateOrUpdateFunction(ctx context.Context, fun
 }
 
 func (r *FunctionMeshReconciler) CreateOrUpdateSink(ctx context.Context, sink *v1alpha1.Sink, sinkSpec v1alpha1.SinkSpec) error {
+	labels := sink.Labels
 	if _, err := ctrl.CreateOrUpdate(ctx, r.Client, sink, func() error {
 		// sink mutate logic
+		sink.Labels = mergeLabels(sink.Labels, labels)
 		sink.Spec = sinkSpec
 		return nil
 	}); err != nil {
@@ -417,8 +716,10 @@ func (r *FunctionMeshReconciler) CreateOrUpdateSink(ctx context.Context, sink *v
 }
 
 func (r *FunctionMeshReconciler) CreateOrUpdateSource(ctx context.Context, source *v1alpha1.Source, sourceSpec v1alpha1.SourceSpec) error {
+	labels := source.Labels
 	if _, err := ctrl.CreateOrUpdate(ctx, r.Client, source, func() error {
 		// source mutate logic
+		source.Labels = mergeLabels(source.Labels, labels)
 		source.Spec = sourceSpec
 		return nil
 	}); err != nil {
@@ -428,6 +729,20 @@ func (r *FunctionMeshReconciler) CreateOrUpdateSource(ctx context.Context, sourc
 	return nil
 }
 
+// mergeLabels adds the desired labels to the existing ones, the labels added by others are kept
+func mergeLabels(existing, desired map[string]string) map[string]string {
+	if len(desired) == 0 {
+		return existing
+	}
+	if existing == nil {
+		existing = make(map[string]string, len(desired))
+	}
+	for k, v := range desired {
+		existing[k] = v
+	}
+	return existing
+}
+
 func makeComponentName(prefix, name string) string {
 	return prefix + "-" + name
 }
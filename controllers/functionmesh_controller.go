@@ -19,6 +19,7 @@ package controllers
 
 import (
 	"context"
+	"time"
 
 	"github.com/streamnative/function-mesh/controllers/spec"
 
@@ -28,6 +29,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
 
@@ -40,6 +42,7 @@ type FunctionMeshReconciler struct {
 
 // +kubebuilder:rbac:groups=compute.functionmesh.io,resources=functionmeshes,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=compute.functionmesh.io,resources=functionmeshes/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=compute.functionmesh.io,resources=functionmeshes/finalizers,verbs=update
 // +kubebuilder:rbac:groups=coordination.k8s.io,resources=leases,verbs=get;list;create;update;delete
 
 func (r *FunctionMeshReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
@@ -62,6 +65,27 @@ func (r *FunctionMeshReconciler) Reconcile(ctx context.Context, req ctrl.Request
 		return reconcile.Result{}, nil
 	}
 
+	if !mesh.DeletionTimestamp.IsZero() {
+		return r.teardown(ctx, mesh)
+	}
+
+	// components of a topologically ordered mesh are deleted in the reverse startup order
+	if mesh.Spec.StartupOrder == v1alpha1.StartupOrderTopological {
+		if !controllerutil.ContainsFinalizer(mesh, spec.FinalizerOrderedTeardown) {
+			controllerutil.AddFinalizer(mesh, spec.FinalizerOrderedTeardown)
+			if err := r.Update(ctx, mesh); err != nil {
+				r.Log.Error(err, "failed to add finalizer to mesh")
+				return ctrl.Result{}, err
+			}
+		}
+	} else if controllerutil.ContainsFinalizer(mesh, spec.FinalizerOrderedTeardown) {
+		controllerutil.RemoveFinalizer(mesh, spec.FinalizerOrderedTeardown)
+		if err := r.Update(ctx, mesh); err != nil {
+			r.Log.Error(err, "failed to remove finalizer from mesh")
+			return ctrl.Result{}, err
+		}
+	}
+
 	// initialize component status map
 	if mesh.Status.FunctionConditions == nil {
 		mesh.Status.FunctionConditions = make(map[string]v1alpha1.ResourceCondition)
@@ -98,12 +122,44 @@ func (r *FunctionMeshReconciler) Reconcile(ctx context.Context, req ctrl.Request
 		return reconcile.Result{}, err
 	}
 
-	mesh.Status.ObservedGeneration = mesh.Generation
+	// a topological rollout is only observed once all the stages have been applied
+	startingUp := mesh.Status.StartupProgress != nil && len(mesh.Status.StartupProgress.Waiting) > 0
+	if !startingUp {
+		mesh.Status.ObservedGeneration = mesh.Generation
+	}
 	err = r.Status().Update(ctx, mesh)
 	if err != nil {
 		r.Log.Error(err, "failed to update functionmesh status")
 		return ctrl.Result{}, err
 	}
+	if startingUp {
+		return ctrl.Result{RequeueAfter: 5 * time.Second}, nil
+	}
+	return ctrl.Result{}, nil
+}
+
+func (r *FunctionMeshReconciler) teardown(ctx context.Context, mesh *v1alpha1.FunctionMesh) (ctrl.Result, error) {
+	if !controllerutil.ContainsFinalizer(mesh, spec.FinalizerOrderedTeardown) {
+		return ctrl.Result{}, nil
+	}
+
+	done, err := r.TeardownFunctionMesh(ctx, mesh)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if !done {
+		if err := r.Status().Update(ctx, mesh); err != nil {
+			r.Log.Error(err, "failed to update functionmesh status")
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{RequeueAfter: 5 * time.Second}, nil
+	}
+
+	controllerutil.RemoveFinalizer(mesh, spec.FinalizerOrderedTeardown)
+	if err := r.Update(ctx, mesh); err != nil {
+		r.Log.Error(err, "failed to remove finalizer from mesh")
+		return ctrl.Result{}, err
+	}
 	return ctrl.Result{}, nil
 }
 
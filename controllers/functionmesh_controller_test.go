@@ -19,6 +19,7 @@ package controllers
 
 import (
 	"context"
+	"testing"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -62,3 +63,23 @@ var _ = Describe("FunctionMesh Controller", func() {
 	})
 
 })
+
+func TestIsMeshComponentReady(t *testing.T) {
+	g := NewWithT(t)
+	component := spec.MeshComponent{Kind: v1alpha1.FunctionComponent, Name: "fn"}
+	mesh := &v1alpha1.FunctionMesh{}
+	g.Expect(isMeshComponentReady(mesh, component)).To(BeFalse())
+
+	mesh.Status.Components = []v1alpha1.ComponentStatus{{Kind: v1alpha1.FunctionComponent, Name: "fn",
+		Generation: 1, ObservedGeneration: 1, Ready: true}}
+	g.Expect(isMeshComponentReady(mesh, component)).To(BeTrue())
+
+	// a child updated by the mesh is not ready until its controller observes the new generation
+	setMeshComponentGeneration(mesh, v1alpha1.FunctionComponent, "fn", 2)
+	g.Expect(isMeshComponentReady(mesh, component)).To(BeFalse())
+	mesh.Status.Components[0].ObservedGeneration = 2
+	g.Expect(isMeshComponentReady(mesh, component)).To(BeTrue())
+
+	mesh.Status.Components[0].Ready = false
+	g.Expect(isMeshComponentReady(mesh, component)).To(BeFalse())
+}
@@ -0,0 +1,241 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/streamnative/function-mesh/api/compute/v1alpha1"
+	"github.com/streamnative/function-mesh/controllers/spec"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// stateSnapshotRetryInterval is how often a snapshot waiting for its Function is retried
+const stateSnapshotRetryInterval = 30 * time.Second
+
+// FunctionStateSnapshotReconciler reconciles a FunctionStateSnapshot object
+type FunctionStateSnapshotReconciler struct {
+	client.Client
+	Log    logr.Logger
+	Scheme *runtime.Scheme
+}
+
+// +kubebuilder:rbac:groups=compute.functionmesh.io,resources=functionstatesnapshots,verbs=get;list;watch
+// +kubebuilder:rbac:groups=compute.functionmesh.io,resources=functionstatesnapshots/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;list;watch;create;update;patch;delete
+
+func (r *FunctionStateSnapshotReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := r.Log.WithValues("functionstatesnapshot", req.NamespacedName)
+
+	snapshot := &v1alpha1.FunctionStateSnapshot{}
+	err := r.Get(ctx, req.NamespacedName, snapshot)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		log.Error(err, "failed to get function state snapshot")
+		return reconcile.Result{}, err
+	}
+	if !spec.IsManaged(snapshot) {
+		log.Info("Skipping function state snapshot not managed by the controller")
+		return reconcile.Result{}, nil
+	}
+	if snapshot.IsFinished() {
+		return ctrl.Result{}, nil
+	}
+	status := snapshot.Status.DeepCopy()
+	status.ObservedGeneration = snapshot.Generation
+
+	result, err := r.observeJob(ctx, snapshot, status)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if !equalStateSnapshotStatus(&snapshot.Status, status) {
+		snapshot.Status = *status
+		if err = r.Status().Update(ctx, snapshot); err != nil {
+			log.Error(err, "failed to update function state snapshot status")
+			return ctrl.Result{}, err
+		}
+	}
+	return result, nil
+}
+
+// observeJob creates the Job of the snapshot when it is missing and reflects its progress in the status
+func (r *FunctionStateSnapshotReconciler) observeJob(ctx context.Context, snapshot *v1alpha1.FunctionStateSnapshot,
+	status *v1alpha1.FunctionStateSnapshotStatus) (ctrl.Result, error) {
+	job := &batchv1.Job{}
+	err := r.Get(ctx, types.NamespacedName{
+		Namespace: snapshot.Namespace,
+		Name:      spec.MakeFunctionStateSnapshotJobName(snapshot),
+	}, job)
+	if err != nil && !errors.IsNotFound(err) {
+		r.Log.Error(err, "failed to get state snapshot job", "namespace", snapshot.Namespace, "name", snapshot.Name)
+		return ctrl.Result{}, err
+	}
+	if errors.IsNotFound(err) {
+		if message := spec.ValidateFunctionStateSnapshot(snapshot); message != "" {
+			status.Phase = v1alpha1.StateSnapshotFailed
+			status.Message = message
+			return ctrl.Result{}, nil
+		}
+		function := &v1alpha1.Function{}
+		err = r.Get(ctx, types.NamespacedName{Namespace: snapshot.Namespace, Name: snapshot.Spec.Function}, function)
+		if errors.IsNotFound(err) {
+			status.Phase = v1alpha1.StateSnapshotPending
+			status.Message = fmt.Sprintf("function %s not found", snapshot.Spec.Function)
+			return ctrl.Result{RequeueAfter: stateSnapshotRetryInterval}, nil
+		} else if err != nil {
+			return ctrl.Result{}, err
+		}
+		if snapshot.Spec.Mode == v1alpha1.StateSnapshotRestore {
+			running, err := hasRunningInstances(ctx, r.Client, function)
+			if err != nil {
+				return ctrl.Result{}, err
+			}
+			if running {
+				status.Phase = v1alpha1.StateSnapshotPending
+				status.Message = fmt.Sprintf("waiting for the instances of function %s to stop", function.Name)
+				return ctrl.Result{RequeueAfter: stateSnapshotRetryInterval}, nil
+			}
+		}
+		messaging, err := makeResolvedPulsarMessaging(ctx, r.Client, snapshot.Namespace, function.Spec.Messaging)
+		if err != nil {
+			status.Phase = v1alpha1.StateSnapshotPending
+			status.Message = err.Error()
+			return ctrl.Result{RequeueAfter: stateSnapshotRetryInterval}, nil
+		}
+		job = spec.MakeFunctionStateSnapshotJob(snapshot, function, messaging)
+		if err = r.Create(ctx, job); err != nil {
+			r.Log.Error(err, "failed to create state snapshot job", "namespace", snapshot.Namespace,
+				"name", snapshot.Name)
+			return ctrl.Result{}, err
+		}
+	}
+
+	status.JobName = job.Name
+	status.Message = ""
+	if status.StartTime == nil {
+		now := metav1.Now()
+		status.StartTime = &now
+	}
+	switch {
+	case job.Status.Succeeded > 0:
+		status.Phase = v1alpha1.StateSnapshotSucceeded
+		status.CompletionTime = job.Status.CompletionTime
+		if status.CompletionTime == nil {
+			now := metav1.Now()
+			status.CompletionTime = &now
+		}
+	case isJobFailed(job):
+		status.Phase = v1alpha1.StateSnapshotFailed
+		status.Message = fmt.Sprintf("job %s failed", job.Name)
+		for _, condition := range job.Status.Conditions {
+			if condition.Type == batchv1.JobFailed && condition.Message != "" {
+				status.Message = condition.Message
+			}
+		}
+	case job.Status.Active > 0:
+		status.Phase = v1alpha1.StateSnapshotRunning
+	default:
+		status.Phase = v1alpha1.StateSnapshotPending
+	}
+	return ctrl.Result{}, nil
+}
+
+// hasRunningInstances returns true while the StatefulSet of a function has pods
+func hasRunningInstances(ctx context.Context, r client.Reader, function *v1alpha1.Function) (bool, error) {
+	statefulSet := &appsv1.StatefulSet{}
+	err := r.Get(ctx, types.NamespacedName{
+		Namespace: function.Namespace,
+		Name:      spec.MakeFunctionObjectMeta(function).Name,
+	}, statefulSet)
+	if errors.IsNotFound(err) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	return statefulSet.Status.Replicas > 0, nil
+}
+
+func isJobFailed(job *batchv1.Job) bool {
+	for _, condition := range job.Status.Conditions {
+		if condition.Type == batchv1.JobFailed && condition.Status == corev1.ConditionTrue {
+			return true
+		}
+	}
+	return false
+}
+
+func equalStateSnapshotStatus(status, desired *v1alpha1.FunctionStateSnapshotStatus) bool {
+	return status.Phase == desired.Phase && status.JobName == desired.JobName &&
+		status.Message == desired.Message && status.ObservedGeneration == desired.ObservedGeneration &&
+		status.StartTime.Equal(desired.StartTime) && status.CompletionTime.Equal(desired.CompletionTime)
+}
+
+// hasPendingStateRestore returns true when an unfinished Restore snapshot targets the function, its
+// instances must not start before the state is restored
+func hasPendingStateRestore(ctx context.Context, r client.Reader, function *v1alpha1.Function) (bool, error) {
+	snapshots := &v1alpha1.FunctionStateSnapshotList{}
+	if err := r.List(ctx, snapshots, client.InNamespace(function.Namespace)); err != nil {
+		return false, err
+	}
+	for i := range snapshots.Items {
+		snapshot := &snapshots.Items[i]
+		if snapshot.Spec.Function == function.Name && snapshot.Spec.Mode == v1alpha1.StateSnapshotRestore &&
+			!snapshot.IsFinished() {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// observeStateRestore explains the StatefulSet condition of a function while its state is restored
+func observeStateRestore(condition v1alpha1.ResourceCondition, restoring bool) v1alpha1.ResourceCondition {
+	if restoring {
+		condition.Reason = v1alpha1.StateRestorePending
+		condition.Message = "waiting for the state of the function to be restored"
+	} else if condition.Reason == v1alpha1.StateRestorePending {
+		condition.Reason = ""
+		condition.Message = ""
+	}
+	return condition
+}
+
+// isStateRestorePending returns true while the state of a function is restored
+func isStateRestorePending(conditions map[v1alpha1.Component]v1alpha1.ResourceCondition) bool {
+	return conditions[v1alpha1.StatefulSet].Reason == v1alpha1.StateRestorePending
+}
+
+func (r *FunctionStateSnapshotReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&v1alpha1.FunctionStateSnapshot{}).
+		Owns(&batchv1.Job{}).
+		Complete(r)
+}
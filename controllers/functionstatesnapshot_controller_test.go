@@ -0,0 +1,155 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/streamnative/function-mesh/api/compute/v1alpha1"
+	"github.com/streamnative/function-mesh/controllers/spec"
+	batchv1 "k8s.io/api/batch/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestFunctionStateSnapshotReconcile(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	g.Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+	g.Expect(v1alpha1.AddToScheme(scheme)).To(Succeed())
+
+	snapshot := &v1alpha1.FunctionStateSnapshot{
+		ObjectMeta: metav1.ObjectMeta{Namespace: TestNameSpace, Name: "restore", Generation: 1},
+		Spec: v1alpha1.FunctionStateSnapshotSpec{
+			Function: "word-count",
+			Mode:     v1alpha1.StateSnapshotRestore,
+			Storage: v1alpha1.StateSnapshotStorage{
+				PersistentVolumeClaim: &v1alpha1.StateSnapshotVolume{ClaimName: "snapshots", Path: "state.json"},
+			},
+		},
+	}
+	key := types.NamespacedName{Namespace: TestNameSpace, Name: "restore"}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(snapshot, makeSamplePulsarConfig()).Build()
+	r := &FunctionStateSnapshotReconciler{Client: c, Log: ctrl.Log, Scheme: scheme}
+
+	// the snapshot waits for its function
+	result, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: key})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(result.RequeueAfter).To(Equal(stateSnapshotRetryInterval))
+	g.Expect(c.Get(ctx, key, snapshot)).To(Succeed())
+	g.Expect(snapshot.Status.Phase).To(Equal(v1alpha1.StateSnapshotPending))
+	g.Expect(snapshot.Status.Message).To(ContainSubstring("word-count"))
+
+	function := makeFunctionSample("word-count")
+	g.Expect(c.Create(ctx, function)).To(Succeed())
+	pending, err := hasPendingStateRestore(ctx, c, function)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(pending).To(BeTrue())
+	g.Expect(findFunctionForStateSnapshot(snapshot)).To(ConsistOf(ctrl.Request{
+		NamespacedName: types.NamespacedName{Namespace: TestNameSpace, Name: "word-count"}}))
+
+	// a running function is scaled down before its state is restored
+	functionReconciler := &FunctionReconciler{Client: c, Log: ctrl.Log, Scheme: scheme}
+	function.Status.Conditions = map[v1alpha1.Component]v1alpha1.ResourceCondition{}
+	g.Expect(functionReconciler.ObserveFunctionStatefulSet(ctx, function)).To(Succeed())
+	g.Expect(function.Status.Conditions[v1alpha1.StatefulSet].Reason).To(Equal(v1alpha1.StateRestorePending))
+	statefulSet := spec.MakeFunctionStatefulSet(function)
+	statefulSet.Status.Replicas = 1
+	g.Expect(c.Create(ctx, statefulSet)).To(Succeed())
+	g.Expect(functionReconciler.ObserveFunctionStatefulSet(ctx, function)).To(Succeed())
+	condition := function.Status.Conditions[v1alpha1.StatefulSet]
+	g.Expect(condition.Reason).To(Equal(v1alpha1.StateRestorePending))
+	g.Expect(condition.Action).To(Equal(v1alpha1.Update))
+	g.Expect(functionReconciler.ApplyFunctionStatefulSet(ctx, function, false)).To(Succeed())
+	g.Expect(c.Get(ctx, types.NamespacedName{Namespace: TestNameSpace, Name: statefulSet.Name},
+		statefulSet)).To(Succeed())
+	g.Expect(*statefulSet.Spec.Replicas).To(BeZero())
+
+	// the restore waits for the instances to stop
+	result, err = r.Reconcile(ctx, ctrl.Request{NamespacedName: key})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(result.RequeueAfter).To(Equal(stateSnapshotRetryInterval))
+	g.Expect(c.Get(ctx, key, snapshot)).To(Succeed())
+	g.Expect(snapshot.Status.Message).To(ContainSubstring("to stop"))
+	statefulSet.Status.Replicas = 0
+	g.Expect(c.Status().Update(ctx, statefulSet)).To(Succeed())
+
+	_, err = r.Reconcile(ctx, ctrl.Request{NamespacedName: key})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(c.Get(ctx, key, snapshot)).To(Succeed())
+	g.Expect(snapshot.Status.JobName).To(Equal("restore-state-snapshot"))
+	g.Expect(snapshot.Status.StartTime).NotTo(BeNil())
+	job := &batchv1.Job{}
+	g.Expect(c.Get(ctx, types.NamespacedName{Namespace: TestNameSpace, Name: "restore-state-snapshot"},
+		job)).To(Succeed())
+	g.Expect(job.Spec.Template.Spec.Containers[0].Command).To(ContainElement("restore"))
+
+	job.Status.Succeeded = 1
+	g.Expect(c.Status().Update(ctx, job)).To(Succeed())
+	_, err = r.Reconcile(ctx, ctrl.Request{NamespacedName: key})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(c.Get(ctx, key, snapshot)).To(Succeed())
+	g.Expect(snapshot.Status.Phase).To(Equal(v1alpha1.StateSnapshotSucceeded))
+	g.Expect(snapshot.Status.CompletionTime).NotTo(BeNil())
+	pending, err = hasPendingStateRestore(ctx, c, function)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(pending).To(BeFalse())
+	g.Expect(functionReconciler.ObserveFunctionStatefulSet(ctx, function)).To(Succeed())
+	g.Expect(function.Status.Conditions[v1alpha1.StatefulSet].Reason).To(BeEmpty())
+
+	// invalid snapshots fail without a job
+	invalid := &v1alpha1.FunctionStateSnapshot{
+		ObjectMeta: metav1.ObjectMeta{Namespace: TestNameSpace, Name: "invalid"},
+		Spec:       v1alpha1.FunctionStateSnapshotSpec{Function: "word-count", Mode: v1alpha1.StateSnapshotExport},
+	}
+	g.Expect(c.Create(ctx, invalid)).To(Succeed())
+	_, err = r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Namespace: TestNameSpace,
+		Name: "invalid"}})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(c.Get(ctx, types.NamespacedName{Namespace: TestNameSpace, Name: "invalid"}, invalid)).To(Succeed())
+	g.Expect(invalid.Status.Phase).To(Equal(v1alpha1.StateSnapshotFailed))
+}
+
+func TestFunctionStateSnapshotReconcileNotManaged(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	g.Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+	g.Expect(v1alpha1.AddToScheme(scheme)).To(Succeed())
+
+	snapshot := &v1alpha1.FunctionStateSnapshot{
+		ObjectMeta: metav1.ObjectMeta{Namespace: TestNameSpace, Name: "restore",
+			Annotations: map[string]string{spec.AnnotationManaged: "false"}},
+		Spec: v1alpha1.FunctionStateSnapshotSpec{Function: "word-count", Mode: v1alpha1.StateSnapshotRestore},
+	}
+	key := types.NamespacedName{Namespace: TestNameSpace, Name: "restore"}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(snapshot).Build()
+	r := &FunctionStateSnapshotReconciler{Client: c, Log: ctrl.Log, Scheme: scheme}
+	result, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: key})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(result.RequeueAfter).To(BeZero())
+	g.Expect(c.Get(ctx, key, snapshot)).To(Succeed())
+	g.Expect(snapshot.Status.Phase).To(BeEmpty())
+}
@@ -0,0 +1,93 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package controllers
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	"github.com/streamnative/function-mesh/api/compute/v1alpha1"
+	"github.com/streamnative/function-mesh/controllers/spec"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// PulsarConnectionReconciler reconciles a PulsarConnection object
+type PulsarConnectionReconciler struct {
+	client.Client
+	Log    logr.Logger
+	Scheme *runtime.Scheme
+}
+
+// +kubebuilder:rbac:groups=compute.functionmesh.io,resources=pulsarconnections,verbs=get;list;watch
+// +kubebuilder:rbac:groups=compute.functionmesh.io,resources=pulsarconnections/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=core,resources=configmaps,verbs=get;list;watch;create;update;patch;delete
+
+func (r *PulsarConnectionReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	_ = r.Log.WithValues("pulsarconnection", req.NamespacedName)
+
+	connection := &v1alpha1.PulsarConnection{}
+	err := r.Get(ctx, req.NamespacedName, connection)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		r.Log.Error(err, "failed to get pulsar connection")
+		return reconcile.Result{}, err
+	}
+
+	if !spec.IsManaged(connection) {
+		r.Log.Info("Skipping pulsar connection not managed by the controller", "Name", req.String())
+		return reconcile.Result{}, nil
+	}
+
+	desiredConfigMap := spec.MakePulsarConnectionConfigMap(connection)
+	desiredData := desiredConfigMap.Data
+	if _, err := ctrl.CreateOrUpdate(ctx, r.Client, desiredConfigMap, func() error {
+		// pulsar connection config map mutate logic
+		desiredConfigMap.Data = desiredData
+		return nil
+	}); err != nil {
+		r.Log.Error(err, "error create or update config map for pulsar connection",
+			"namespace", connection.Namespace, "name", connection.Name,
+			"config map name", desiredConfigMap.Name)
+		return reconcile.Result{}, err
+	}
+
+	if connection.Status.PulsarConfig != desiredConfigMap.Name ||
+		connection.Status.ObservedGeneration != connection.Generation {
+		connection.Status.PulsarConfig = desiredConfigMap.Name
+		connection.Status.ObservedGeneration = connection.Generation
+		if err = r.Status().Update(ctx, connection); err != nil {
+			r.Log.Error(err, "failed to update pulsar connection status")
+			return ctrl.Result{}, err
+		}
+	}
+	return ctrl.Result{}, nil
+}
+
+func (r *PulsarConnectionReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&v1alpha1.PulsarConnection{}).
+		Owns(&corev1.ConfigMap{}).
+		Complete(r)
+}
@@ -0,0 +1,108 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/streamnative/function-mesh/api/compute/v1alpha1"
+	"github.com/streamnative/function-mesh/controllers/spec"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestPulsarConnectionReconcile(t *testing.T) {
+	g := NewWithT(t)
+	scheme := runtime.NewScheme()
+	g.Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+	g.Expect(v1alpha1.AddToScheme(scheme)).To(Succeed())
+
+	connection := &v1alpha1.PulsarConnection{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "team-a", Name: "default", Generation: 1},
+		Spec: v1alpha1.PulsarConnectionSpec{
+			WebServiceURL:    "http://pulsar-broker:8080",
+			BrokerServiceURL: "pulsar://pulsar-broker:6650",
+			AuthSecret:       "pulsar-auth",
+		},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(connection).Build()
+	r := &PulsarConnectionReconciler{Client: c, Log: ctrl.Log, Scheme: scheme}
+	_, err := r.Reconcile(context.Background(), ctrl.Request{
+		NamespacedName: types.NamespacedName{Namespace: "team-a", Name: "default"}})
+	g.Expect(err).NotTo(HaveOccurred())
+
+	configMap := &corev1.ConfigMap{}
+	g.Expect(c.Get(context.Background(), types.NamespacedName{Namespace: "team-a",
+		Name: "default-pulsar-connection"}, configMap)).To(Succeed())
+	g.Expect(configMap.Data).To(HaveKeyWithValue("brokerServiceURL", "pulsar://pulsar-broker:6650"))
+	g.Expect(c.Get(context.Background(), types.NamespacedName{Namespace: "team-a", Name: "default"},
+		connection)).To(Succeed())
+	g.Expect(connection.Status.PulsarConfig).To(Equal("default-pulsar-connection"))
+	g.Expect(connection.Status.ObservedGeneration).To(Equal(int64(1)))
+
+	// the components without pulsar config fall back to the default connection of the namespace
+	messaging := v1alpha1.Messaging{}
+	policy := v1alpha1.PodPolicy{}
+	g.Expect(resolvePulsarConnection(context.Background(), c, "team-a", &messaging, &policy)).To(Succeed())
+	g.Expect(messaging.Pulsar.PulsarConfig).To(Equal("default-pulsar-connection"))
+	g.Expect(messaging.Pulsar.AuthSecret).To(Equal("pulsar-auth"))
+	g.Expect(policy.Annotations).To(HaveKey(spec.AnnotationPulsarConnectionChecksum))
+
+	messaging = v1alpha1.Messaging{Pulsar: &v1alpha1.PulsarMessaging{PulsarConnection: "missing"}}
+	g.Expect(resolvePulsarConnection(context.Background(), c, "team-a", &messaging, &policy)).NotTo(Succeed())
+
+	// the default connection is optional, it is only required when it is named explicitly
+	messaging = v1alpha1.Messaging{}
+	g.Expect(resolvePulsarConnection(context.Background(), c, "team-b", &messaging, &policy)).To(Succeed())
+	g.Expect(messaging.Pulsar).To(BeNil())
+	messaging = v1alpha1.Messaging{Pulsar: &v1alpha1.PulsarMessaging{PulsarConnection: "default"}}
+	g.Expect(resolvePulsarConnection(context.Background(), c, "team-b", &messaging, &policy)).NotTo(Succeed())
+
+	g.Expect(indexPulsarConnection("team-a", v1alpha1.Messaging{})).To(Equal([]string{"default"}))
+	g.Expect(indexPulsarConnection("team-a", v1alpha1.Messaging{
+		Pulsar: &v1alpha1.PulsarMessaging{PulsarConfig: "pulsar-config"}})).To(BeEmpty())
+	g.Expect(indexPulsarConnection("team-a", v1alpha1.Messaging{
+		Pulsar: &v1alpha1.PulsarMessaging{PulsarConnection: "other"}})).To(Equal([]string{"other"}))
+}
+
+func TestPulsarConnectionReconcileNotManaged(t *testing.T) {
+	g := NewWithT(t)
+	scheme := runtime.NewScheme()
+	g.Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+	g.Expect(v1alpha1.AddToScheme(scheme)).To(Succeed())
+
+	connection := &v1alpha1.PulsarConnection{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "team-a", Name: "default",
+			Annotations: map[string]string{spec.AnnotationManaged: "false"}},
+		Spec: v1alpha1.PulsarConnectionSpec{WebServiceURL: "http://pulsar-broker:8080"},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(connection).Build()
+	r := &PulsarConnectionReconciler{Client: c, Log: ctrl.Log, Scheme: scheme}
+	_, err := r.Reconcile(context.Background(), ctrl.Request{
+		NamespacedName: types.NamespacedName{Namespace: "team-a", Name: "default"}})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(c.Get(context.Background(), types.NamespacedName{Namespace: "team-a",
+		Name: "default-pulsar-connection"}, &corev1.ConfigMap{})).NotTo(Succeed())
+}
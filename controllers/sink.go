@@ -19,6 +19,7 @@ package controllers
 
 import (
 	"context"
+	"time"
 
 	autoscaling "k8s.io/api/autoscaling/v1"
 
@@ -69,20 +70,29 @@ func (r *SinkReconciler) ObserveSinkStatefulSet(ctx context.Context, sink *v1alp
 	}
 	sink.Status.Selector = selector.String()
 
-	if r.checkIfStatefulSetNeedUpdate(statefulSet, sink) {
+	if err := observePackageVerification(ctx, r, statefulSet, selector, &condition); err != nil {
+		return err
+	}
+
+	needUpdate, err := r.checkIfStatefulSetNeedUpdate(ctx, statefulSet, sink)
+	if err != nil {
+		return err
+	}
+	if needUpdate {
 		condition.Status = metav1.ConditionFalse
 		condition.Action = v1alpha1.Update
 		sink.Status.Conditions[v1alpha1.StatefulSet] = condition
 		return nil
 	}
 
-	if statefulSet.Status.ReadyReplicas == *sink.Spec.Replicas {
+	if statefulSet.Status.ReadyReplicas == *statefulSet.Spec.Replicas {
 		condition.Action = v1alpha1.NoAction
 	} else {
 		condition.Action = v1alpha1.Wait
 	}
 	condition.Status = metav1.ConditionTrue
 	sink.Status.Replicas = *statefulSet.Spec.Replicas
+	sink.Status.ReadyReplicas = statefulSet.Status.ReadyReplicas
 	sink.Status.Conditions[v1alpha1.StatefulSet] = condition
 	return nil
 }
@@ -92,7 +102,13 @@ func (r *SinkReconciler) ApplySinkStatefulSet(ctx context.Context, sink *v1alpha
 	if condition.Status == metav1.ConditionTrue && !newGeneration {
 		return nil
 	}
-	desiredStatefulSet := spec.MakeSinkStatefulSet(sink)
+	desiredStatefulSet, err := r.makeSinkStatefulSet(ctx, sink)
+	if err != nil {
+		return err
+	}
+	if deleted, err := deleteStatefulSetOnImmutableChange(ctx, r.Client, desiredStatefulSet); err != nil || deleted {
+		return err
+	}
 	desiredStatefulSetSpec := desiredStatefulSet.Spec
 	if _, err := ctrl.CreateOrUpdate(ctx, r.Client, desiredStatefulSet, func() error {
 		// sink statefulSet mutate logic
@@ -167,6 +183,15 @@ func (r *SinkReconciler) ObserveSinkHPA(ctx context.Context, sink *v1alpha1.Sink
 		return nil
 	}
 
+	if sink.Spec.Paused {
+		// HPA is suspended while the sink is paused
+		sink.Status.Conditions[v1alpha1.HPA] = v1alpha1.CreateCondition(
+			v1alpha1.HPAReady,
+			metav1.ConditionFalse,
+			v1alpha1.Delete)
+		return nil
+	}
+
 	condition, ok := sink.Status.Conditions[v1alpha1.HPA]
 	if !ok {
 		sink.Status.Conditions[v1alpha1.HPA] = v1alpha1.ResourceCondition{
@@ -212,6 +237,17 @@ func (r *SinkReconciler) ApplySinkHPA(ctx context.Context, sink *v1alpha1.Sink,
 		return nil
 	}
 	condition := sink.Status.Conditions[v1alpha1.HPA]
+	if condition.Action == v1alpha1.Delete {
+		err := deleteHPA(ctx, r.Client, types.NamespacedName{Namespace: sink.Namespace,
+			Name: spec.MakeSinkObjectMeta(sink).Name})
+		if err != nil {
+			r.Log.Error(err, "error delete hpa for sink",
+				"namespace", sink.Namespace, "name", sink.Name)
+			return err
+		}
+		delete(sink.Status.Conditions, v1alpha1.HPA)
+		return nil
+	}
 	if condition.Status == metav1.ConditionTrue && !newGeneration {
 		return nil
 	}
@@ -231,8 +267,60 @@ func (r *SinkReconciler) ApplySinkHPA(ctx context.Context, sink *v1alpha1.Sink,
 }
 
 func (r *SinkReconciler) ObserveSinkVPA(ctx context.Context, sink *v1alpha1.Sink) error {
+	vpaSpec := sink.Spec.Pod.VPA
+	if sink.Spec.Paused {
+		// VPA is suspended while the sink is paused
+		vpaSpec = nil
+	}
 	return observeVPA(ctx, r, types.NamespacedName{Namespace: sink.Namespace,
-		Name: spec.MakeSinkObjectMeta(sink).Name}, sink.Spec.Pod.VPA, sink.Status.Conditions)
+		Name: spec.MakeSinkObjectMeta(sink).Name}, vpaSpec, sink.Status.Conditions)
+}
+
+// ApplySinkRetryTopics provisions the dead letter and retry letter topics of the retry policy, it returns when to
+// requeue the sink if they cannot be provisioned
+func (r *SinkReconciler) ApplySinkRetryTopics(ctx context.Context, sink *v1alpha1.Sink, newGeneration bool) time.Duration {
+	topics := spec.MakeRetryTopics(sink.Spec.RetryPolicy)
+	if len(topics) == 0 {
+		// topic provisioning not enabled, skip further action
+		delete(sink.Status.Conditions, v1alpha1.RetryTopics)
+		return 0
+	}
+	condition, ok := sink.Status.Conditions[v1alpha1.RetryTopics]
+	if ok && condition.Status == metav1.ConditionTrue && !newGeneration {
+		return 0
+	}
+	messaging, err := makeResolvedPulsarMessaging(ctx, r, sink.Namespace, sink.Spec.Messaging)
+	if err != nil {
+		return failRetryTopics(r.Log, err, sink.Status.Conditions, "sink", sink.Namespace, sink.Name)
+	}
+	return applyRetryTopics(ctx, r, r.Log, topics, messaging, sink.Status.Conditions,
+		"sink", sink.Namespace, sink.Name)
+}
+
+// CheckSinkStateStore runs the pre-flight check of the state store of a stateful sink, it returns when to
+// requeue the sink if the state store is unreachable
+func (r *SinkReconciler) CheckSinkStateStore(ctx context.Context, sink *v1alpha1.Sink, newGeneration bool) time.Duration {
+	return applyStateStoreCheck(ctx, r.Log, sink.Spec.StateConfig, sink.Status.Conditions, newGeneration,
+		"sink", sink.Namespace, sink.Name)
+}
+
+// ObserveSinkPause records the replica count of a paused sink, so it can be restored on resume
+func (r *SinkReconciler) ObserveSinkPause(sink *v1alpha1.Sink) {
+	if !sink.Spec.Paused {
+		return
+	}
+	observePause(sink.Spec.Replicas, sink.Status.Replicas, &sink.Status.PauseStatus, sink.Status.Conditions)
+}
+
+// RestoreSinkReplicas restores the replica count recorded when the sink was paused, unless the replicas
+// of the sink were changed during the pause
+func (r *SinkReconciler) RestoreSinkReplicas(ctx context.Context, sink *v1alpha1.Sink) error {
+	err := resumeReplicas(ctx, r, sink, &sink.Spec.Replicas, &sink.Status.PauseStatus, sink.Status.Conditions)
+	if err != nil {
+		r.Log.Error(err, "failed to restore the replicas of the resumed sink",
+			"namespace", sink.Namespace, "name", sink.Name)
+	}
+	return err
 }
 
 func (r *SinkReconciler) ApplySinkVPA(ctx context.Context, sink *v1alpha1.Sink) error {
@@ -258,8 +346,31 @@ func (r *SinkReconciler) ApplySinkVPA(ctx context.Context, sink *v1alpha1.Sink)
 	return nil
 }
 
-func (r *SinkReconciler) checkIfStatefulSetNeedUpdate(statefulSet *appsv1.StatefulSet, sink *v1alpha1.Sink) bool {
-	return !spec.CheckIfStatefulSetSpecIsEqual(&statefulSet.Spec, &spec.MakeSinkStatefulSet(sink).Spec)
+func (r *SinkReconciler) checkIfStatefulSetNeedUpdate(ctx context.Context, statefulSet *appsv1.StatefulSet,
+	sink *v1alpha1.Sink) (bool, error) {
+	desiredStatefulSet, err := r.makeSinkStatefulSet(ctx, sink)
+	if err != nil {
+		return false, err
+	}
+	return !spec.CheckIfStatefulSetSpecIsEqual(&statefulSet.Spec, &desiredStatefulSet.Spec), nil
+}
+
+// makeSinkStatefulSet makes the desired statefulSet of the sink with its PulsarConnection resolved
+func (r *SinkReconciler) makeSinkStatefulSet(ctx context.Context, sink *v1alpha1.Sink) (*appsv1.StatefulSet, error) {
+	sink = sink.DeepCopy()
+	err := resolvePulsarConnection(ctx, r, sink.Namespace, &sink.Spec.Messaging, &sink.Spec.Pod)
+	if err != nil {
+		r.Log.Error(err, "failed to resolve the pulsar connection of sink",
+			"namespace", sink.Namespace, "name", sink.Name)
+		return nil, err
+	}
+	statefulSet := spec.MakeSinkStatefulSet(sink)
+	if err = applyConfigChecksum(ctx, r, sink, &statefulSet.Spec.Template); err != nil {
+		r.Log.Error(err, "failed to compute the config checksum of sink",
+			"namespace", sink.Namespace, "name", sink.Name)
+		return nil, err
+	}
+	return statefulSet, nil
 }
 
 func (r *SinkReconciler) checkIfHPANeedUpdate(hpa *autov2beta2.HorizontalPodAutoscaler, sink *v1alpha1.Sink) bool {
@@ -33,8 +33,11 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 )
 
 // SinkReconciler reconciles a Topic object
@@ -43,12 +46,16 @@ type SinkReconciler struct {
 	Log        logr.Logger
 	Scheme     *runtime.Scheme
 	WatchFlags *utils.WatchFlags
+	// ConfigEvents triggers the reconciliation of the sinks affected by reloaded controller configs
+	ConfigEvents <-chan event.GenericEvent
 }
 
 // +kubebuilder:rbac:groups=compute.functionmesh.io,resources=sinks,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=compute.functionmesh.io,resources=sinks/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=apps,resources=statefulsets,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=core,resources=services,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=core,resources=pods,verbs=get;list;watch
+// +kubebuilder:rbac:groups=core,resources=configmaps,verbs=get;list;watch
 // +kubebuilder:rbac:groups=autoscaling,resources=horizontalpodautoscalers,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=autoscaling.k8s.io,resources=verticalpodautoscalers,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=coordination.k8s.io,resources=leases,verbs=get;list;create;update;delete
@@ -72,10 +79,19 @@ func (r *SinkReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.
 		return reconcile.Result{}, nil
 	}
 
+	if !sink.Spec.Paused && sink.Status.PausedReplicas != nil {
+		err = r.RestoreSinkReplicas(ctx, sink)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+	}
+
 	if sink.Status.Conditions == nil {
 		sink.Status.Conditions = make(map[v1alpha1.Component]v1alpha1.ResourceCondition)
 	}
 
+	r.ObserveSinkPause(sink)
+
 	err = r.ObserveSinkStatefulSet(ctx, sink)
 	if err != nil {
 		return reconcile.Result{}, err
@@ -102,6 +118,8 @@ func (r *SinkReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.
 
 	isNewGeneration := r.checkIfSinkGenerationsIsIncreased(sink)
 
+	requeueAfter := minRequeueAfter(r.ApplySinkRetryTopics(ctx, sink, isNewGeneration),
+		r.CheckSinkStateStore(ctx, sink, isNewGeneration))
 	err = r.ApplySinkStatefulSet(ctx, sink, isNewGeneration)
 	if err != nil {
 		return reconcile.Result{}, err
@@ -125,7 +143,7 @@ func (r *SinkReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.
 		r.Log.Error(err, "failed to update sink status")
 		return ctrl.Result{}, err
 	}
-	return ctrl.Result{}, nil
+	return ctrl.Result{RequeueAfter: requeueAfter}, nil
 }
 
 func (r *SinkReconciler) checkIfSinkGenerationsIsIncreased(sink *v1alpha1.Sink) bool {
@@ -133,6 +151,24 @@ func (r *SinkReconciler) checkIfSinkGenerationsIsIncreased(sink *v1alpha1.Sink)
 }
 
 func (r *SinkReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &v1alpha1.Sink{}, configMapReferencesIndex,
+		func(object client.Object) []string {
+			return spec.MakeSinkConfigReferences(object.(*v1alpha1.Sink)).ConfigMaps
+		}); err != nil {
+		return err
+	}
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &v1alpha1.Sink{}, secretReferencesIndex,
+		func(object client.Object) []string {
+			return spec.MakeSinkConfigReferences(object.(*v1alpha1.Sink)).Secrets
+		}); err != nil {
+		return err
+	}
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &v1alpha1.Sink{}, pulsarConnectionIndex,
+		func(object client.Object) []string {
+			return indexPulsarConnection(object.GetNamespace(), object.(*v1alpha1.Sink).Spec.Messaging)
+		}); err != nil {
+		return err
+	}
 	manager := ctrl.NewControllerManagedBy(mgr).
 		For(&v1alpha1.Sink{}).
 		Owns(&appsv1.StatefulSet{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
@@ -141,6 +177,21 @@ func (r *SinkReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	if r.WatchFlags != nil && r.WatchFlags.WatchVPACRDs {
 		manager.Owns(&vpav1.VerticalPodAutoscaler{})
 	}
+	newList := func() client.ObjectList { return &v1alpha1.SinkList{} }
+	manager.Watches(&source.Kind{Type: &corev1.ConfigMap{}},
+		handler.EnqueueRequestsFromMapFunc(findComponentsForConfig(r, r.Log, newList, configMapReferencesIndex)),
+		builder.WithPredicates(configReferencedPredicate(r, r.Log, newList, configMapReferencesIndex)))
+	manager.Watches(&source.Kind{Type: &corev1.Secret{}},
+		handler.EnqueueRequestsFromMapFunc(findComponentsForConfig(r, r.Log, newList, secretReferencesIndex)),
+		builder.WithPredicates(configReferencedPredicate(r, r.Log, newList, secretReferencesIndex)))
+	manager.Watches(&source.Kind{Type: &v1alpha1.PulsarConnection{}},
+		handler.EnqueueRequestsFromMapFunc(findComponentsForPulsarConnection(r, r.Log, newList)))
+	manager.Watches(&source.Kind{Type: &corev1.Pod{}},
+		handler.EnqueueRequestsFromMapFunc(findComponentForPod(spec.ComponentSink)),
+		builder.WithPredicates(packageVerificationFailedPredicate))
+	if r.ConfigEvents != nil {
+		manager.Watches(&source.Channel{Source: r.ConfigEvents}, &handler.EnqueueRequestForObject{})
+	}
 
 	return manager.Complete(r)
 }
@@ -19,6 +19,7 @@ package controllers
 
 import (
 	"context"
+	"time"
 
 	autoscaling "k8s.io/api/autoscaling/v1"
 
@@ -69,20 +70,29 @@ func (r *SourceReconciler) ObserveSourceStatefulSet(ctx context.Context, source
 	}
 	source.Status.Selector = selector.String()
 
-	if r.checkIfStatefulSetNeedUpdate(statefulSet, source) {
+	if err := observePackageVerification(ctx, r, statefulSet, selector, &condition); err != nil {
+		return err
+	}
+
+	needUpdate, err := r.checkIfStatefulSetNeedUpdate(ctx, statefulSet, source)
+	if err != nil {
+		return err
+	}
+	if needUpdate {
 		condition.Status = metav1.ConditionFalse
 		condition.Action = v1alpha1.Update
 		source.Status.Conditions[v1alpha1.StatefulSet] = condition
 		return nil
 	}
 
-	if statefulSet.Status.ReadyReplicas == *source.Spec.Replicas {
+	if statefulSet.Status.ReadyReplicas == *statefulSet.Spec.Replicas {
 		condition.Action = v1alpha1.NoAction
 	} else {
 		condition.Action = v1alpha1.Wait
 	}
 	condition.Status = metav1.ConditionTrue
 	source.Status.Replicas = *statefulSet.Spec.Replicas
+	source.Status.ReadyReplicas = statefulSet.Status.ReadyReplicas
 	source.Status.Conditions[v1alpha1.StatefulSet] = condition
 	return nil
 }
@@ -92,7 +102,13 @@ func (r *SourceReconciler) ApplySourceStatefulSet(ctx context.Context, source *v
 	if condition.Status == metav1.ConditionTrue && !newGeneration {
 		return nil
 	}
-	desiredStatefulSet := spec.MakeSourceStatefulSet(source)
+	desiredStatefulSet, err := r.makeSourceStatefulSet(ctx, source)
+	if err != nil {
+		return err
+	}
+	if deleted, err := deleteStatefulSetOnImmutableChange(ctx, r.Client, desiredStatefulSet); err != nil || deleted {
+		return err
+	}
 	desiredStatefulSetSpec := desiredStatefulSet.Spec
 	if _, err := ctrl.CreateOrUpdate(ctx, r.Client, desiredStatefulSet, func() error {
 		// source statefulSet mutate logic
@@ -104,9 +120,23 @@ func (r *SourceReconciler) ApplySourceStatefulSet(ctx context.Context, source *v
 			"statefulSet name", desiredStatefulSet.Name)
 		return err
 	}
+	observeDiscoveryTrigger(source)
 	return nil
 }
 
+// observeDiscoveryTrigger records the on-demand discovery rolled out with the statefulSet
+func observeDiscoveryTrigger(source *v1alpha1.Source) {
+	trigger := spec.GetDiscoveryTrigger(source)
+	if trigger == source.Status.DiscoveryTrigger {
+		return
+	}
+	source.Status.DiscoveryTrigger = trigger
+	if trigger != "" {
+		now := metav1.Now()
+		source.Status.LastDiscoveryTriggerTime = &now
+	}
+}
+
 func (r *SourceReconciler) ObserveSourceService(ctx context.Context, source *v1alpha1.Source) error {
 	condition, ok := source.Status.Conditions[v1alpha1.Service]
 	if !ok {
@@ -167,6 +197,15 @@ func (r *SourceReconciler) ObserveSourceHPA(ctx context.Context, source *v1alpha
 		return nil
 	}
 
+	if source.Spec.Paused {
+		// HPA is suspended while the source is paused
+		source.Status.Conditions[v1alpha1.HPA] = v1alpha1.CreateCondition(
+			v1alpha1.HPAReady,
+			metav1.ConditionFalse,
+			v1alpha1.Delete)
+		return nil
+	}
+
 	condition, ok := source.Status.Conditions[v1alpha1.HPA]
 	if !ok {
 		source.Status.Conditions[v1alpha1.HPA] = v1alpha1.ResourceCondition{
@@ -212,6 +251,17 @@ func (r *SourceReconciler) ApplySourceHPA(ctx context.Context, source *v1alpha1.
 		return nil
 	}
 	condition := source.Status.Conditions[v1alpha1.HPA]
+	if condition.Action == v1alpha1.Delete {
+		err := deleteHPA(ctx, r.Client, types.NamespacedName{Namespace: source.Namespace,
+			Name: spec.MakeSourceObjectMeta(source).Name})
+		if err != nil {
+			r.Log.Error(err, "error delete hpa for source",
+				"namespace", source.Namespace, "name", source.Name)
+			return err
+		}
+		delete(source.Status.Conditions, v1alpha1.HPA)
+		return nil
+	}
 	if condition.Status == metav1.ConditionTrue && !newGeneration {
 		return nil
 	}
@@ -231,8 +281,39 @@ func (r *SourceReconciler) ApplySourceHPA(ctx context.Context, source *v1alpha1.
 }
 
 func (r *SourceReconciler) ObserveSourceVPA(ctx context.Context, source *v1alpha1.Source) error {
+	vpaSpec := source.Spec.Pod.VPA
+	if source.Spec.Paused {
+		// VPA is suspended while the source is paused
+		vpaSpec = nil
+	}
 	return observeVPA(ctx, r, types.NamespacedName{Namespace: source.Namespace,
-		Name: spec.MakeSourceObjectMeta(source).Name}, source.Spec.Pod.VPA, source.Status.Conditions)
+		Name: spec.MakeSourceObjectMeta(source).Name}, vpaSpec, source.Status.Conditions)
+}
+
+// CheckSourceStateStore runs the pre-flight check of the state store of a stateful source, it returns when to
+// requeue the source if the state store is unreachable
+func (r *SourceReconciler) CheckSourceStateStore(ctx context.Context, source *v1alpha1.Source, newGeneration bool) time.Duration {
+	return applyStateStoreCheck(ctx, r.Log, source.Spec.StateConfig, source.Status.Conditions, newGeneration,
+		"source", source.Namespace, source.Name)
+}
+
+// ObserveSourcePause records the replica count of a paused source, so it can be restored on resume
+func (r *SourceReconciler) ObserveSourcePause(source *v1alpha1.Source) {
+	if !source.Spec.Paused {
+		return
+	}
+	observePause(source.Spec.Replicas, source.Status.Replicas, &source.Status.PauseStatus, source.Status.Conditions)
+}
+
+// RestoreSourceReplicas restores the replica count recorded when the source was paused, unless the replicas
+// of the source were changed during the pause
+func (r *SourceReconciler) RestoreSourceReplicas(ctx context.Context, source *v1alpha1.Source) error {
+	err := resumeReplicas(ctx, r, source, &source.Spec.Replicas, &source.Status.PauseStatus, source.Status.Conditions)
+	if err != nil {
+		r.Log.Error(err, "failed to restore the replicas of the resumed source",
+			"namespace", source.Namespace, "name", source.Name)
+	}
+	return err
 }
 
 func (r *SourceReconciler) ApplySourceVPA(ctx context.Context, source *v1alpha1.Source) error {
@@ -258,8 +339,31 @@ func (r *SourceReconciler) ApplySourceVPA(ctx context.Context, source *v1alpha1.
 	return nil
 }
 
-func (r *SourceReconciler) checkIfStatefulSetNeedUpdate(statefulSet *appsv1.StatefulSet, source *v1alpha1.Source) bool {
-	return !spec.CheckIfStatefulSetSpecIsEqual(&statefulSet.Spec, &spec.MakeSourceStatefulSet(source).Spec)
+func (r *SourceReconciler) checkIfStatefulSetNeedUpdate(ctx context.Context, statefulSet *appsv1.StatefulSet,
+	source *v1alpha1.Source) (bool, error) {
+	desiredStatefulSet, err := r.makeSourceStatefulSet(ctx, source)
+	if err != nil {
+		return false, err
+	}
+	return !spec.CheckIfStatefulSetSpecIsEqual(&statefulSet.Spec, &desiredStatefulSet.Spec), nil
+}
+
+// makeSourceStatefulSet makes the desired statefulSet of the source with its PulsarConnection resolved
+func (r *SourceReconciler) makeSourceStatefulSet(ctx context.Context, source *v1alpha1.Source) (*appsv1.StatefulSet, error) {
+	source = source.DeepCopy()
+	err := resolvePulsarConnection(ctx, r, source.Namespace, &source.Spec.Messaging, &source.Spec.Pod)
+	if err != nil {
+		r.Log.Error(err, "failed to resolve the pulsar connection of source",
+			"namespace", source.Namespace, "name", source.Name)
+		return nil, err
+	}
+	statefulSet := spec.MakeSourceStatefulSet(source)
+	if err = applyConfigChecksum(ctx, r, source, &statefulSet.Spec.Template); err != nil {
+		r.Log.Error(err, "failed to compute the config checksum of source",
+			"namespace", source.Namespace, "name", source.Name)
+		return nil, err
+	}
+	return statefulSet, nil
 }
 
 func (r *SourceReconciler) checkIfHPANeedUpdate(hpa *autov2beta2.HorizontalPodAutoscaler, source *v1alpha1.Source) bool {
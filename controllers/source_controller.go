@@ -33,8 +33,11 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 )
 
 // SourceReconciler reconciles a Source object
@@ -43,12 +46,15 @@ type SourceReconciler struct {
 	Log        logr.Logger
 	Scheme     *runtime.Scheme
 	WatchFlags *utils.WatchFlags
+	// ConfigEvents triggers the reconciliation of the sources affected by reloaded controller configs
+	ConfigEvents <-chan event.GenericEvent
 }
 
 // +kubebuilder:rbac:groups=compute.functionmesh.io,resources=sources,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=compute.functionmesh.io,resources=sources/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=apps,resources=statefulsets,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=core,resources=services,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=core,resources=pods,verbs=get;list;watch
 // +kubebuilder:rbac:groups=autoscaling,resources=horizontalpodautoscalers,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=autoscaling.k8s.io,resources=verticalpodautoscalers,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=coordination.k8s.io,resources=leases,verbs=get;list;create;update;delete
@@ -72,10 +78,19 @@ func (r *SourceReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctr
 		return reconcile.Result{}, nil
 	}
 
+	if !source.Spec.Paused && source.Status.PausedReplicas != nil {
+		err = r.RestoreSourceReplicas(ctx, source)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+	}
+
 	if source.Status.Conditions == nil {
 		source.Status.Conditions = make(map[v1alpha1.Component]v1alpha1.ResourceCondition)
 	}
 
+	r.ObserveSourcePause(source)
+
 	err = r.ObserveSourceStatefulSet(ctx, source)
 	if err != nil {
 		return reconcile.Result{}, err
@@ -102,6 +117,7 @@ func (r *SourceReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctr
 
 	isNewGeneration := r.checkIfSourceGenerationsIsIncreased(source)
 
+	requeueAfter := r.CheckSourceStateStore(ctx, source, isNewGeneration)
 	err = r.ApplySourceStatefulSet(ctx, source, isNewGeneration)
 	if err != nil {
 		return reconcile.Result{}, err
@@ -125,7 +141,7 @@ func (r *SourceReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctr
 		r.Log.Error(err, "failed to update source status")
 		return ctrl.Result{}, err
 	}
-	return ctrl.Result{}, nil
+	return ctrl.Result{RequeueAfter: requeueAfter}, nil
 }
 
 func (r *SourceReconciler) checkIfSourceGenerationsIsIncreased(source *v1alpha1.Source) bool {
@@ -133,6 +149,24 @@ func (r *SourceReconciler) checkIfSourceGenerationsIsIncreased(source *v1alpha1.
 }
 
 func (r *SourceReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &v1alpha1.Source{}, configMapReferencesIndex,
+		func(object client.Object) []string {
+			return spec.MakeSourceConfigReferences(object.(*v1alpha1.Source)).ConfigMaps
+		}); err != nil {
+		return err
+	}
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &v1alpha1.Source{}, secretReferencesIndex,
+		func(object client.Object) []string {
+			return spec.MakeSourceConfigReferences(object.(*v1alpha1.Source)).Secrets
+		}); err != nil {
+		return err
+	}
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &v1alpha1.Source{}, pulsarConnectionIndex,
+		func(object client.Object) []string {
+			return indexPulsarConnection(object.GetNamespace(), object.(*v1alpha1.Source).Spec.Messaging)
+		}); err != nil {
+		return err
+	}
 	manager := ctrl.NewControllerManagedBy(mgr).
 		For(&v1alpha1.Source{}).
 		Owns(&appsv1.StatefulSet{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
@@ -141,5 +175,20 @@ func (r *SourceReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	if r.WatchFlags != nil && r.WatchFlags.WatchVPACRDs {
 		manager.Owns(&vpav1.VerticalPodAutoscaler{})
 	}
+	newList := func() client.ObjectList { return &v1alpha1.SourceList{} }
+	manager.Watches(&source.Kind{Type: &corev1.ConfigMap{}},
+		handler.EnqueueRequestsFromMapFunc(findComponentsForConfig(r, r.Log, newList, configMapReferencesIndex)),
+		builder.WithPredicates(configReferencedPredicate(r, r.Log, newList, configMapReferencesIndex)))
+	manager.Watches(&source.Kind{Type: &corev1.Secret{}},
+		handler.EnqueueRequestsFromMapFunc(findComponentsForConfig(r, r.Log, newList, secretReferencesIndex)),
+		builder.WithPredicates(configReferencedPredicate(r, r.Log, newList, secretReferencesIndex)))
+	manager.Watches(&source.Kind{Type: &v1alpha1.PulsarConnection{}},
+		handler.EnqueueRequestsFromMapFunc(findComponentsForPulsarConnection(r, r.Log, newList)))
+	manager.Watches(&source.Kind{Type: &corev1.Pod{}},
+		handler.EnqueueRequestsFromMapFunc(findComponentForPod(spec.ComponentSource)),
+		builder.WithPredicates(packageVerificationFailedPredicate))
+	if r.ConfigEvents != nil {
+		manager.Watches(&source.Channel{Source: r.ConfigEvents}, &handler.EnqueueRequestForObject{})
+	}
 	return manager.Complete(r)
 }
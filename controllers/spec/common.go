@@ -35,6 +35,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 )
 
 const (
@@ -56,6 +57,8 @@ const (
 	DownloaderImage         = DefaultRunnerPrefix + "pulsarctl:2.10.2.3"
 	DownloadDir             = "/pulsar/download"
 
+	DefaultMaxBufferedTuples int32 = 100
+
 	// for grpc health check
 	GrpcVolume = "grpc-volume"
 	GrpcDir    = "/pulsar/grpc"
@@ -82,6 +85,17 @@ const (
 	AnnotationPrometheusPort   = "prometheus.io/port"
 	AnnotationManaged          = "compute.functionmesh.io/managed"
 
+	AnnotationPulsarConnectionChecksum = "compute.functionmesh.io/pulsar-connection-checksum"
+	AnnotationConfigChecksum           = "compute.functionmesh.io/config-checksum"
+	// AnnotationRestartOnConfigChange set to "false" on a component disables the rolling restart on
+	// changes of its config maps and secrets, set on a config map or secret it excludes the object
+	AnnotationRestartOnConfigChange = "compute.functionmesh.io/restart-on-config-change"
+	// AnnotationTriggerDiscovery changed on a batch source with an on-demand trigger rolls its instances, which
+	// run the discovery when they start
+	AnnotationTriggerDiscovery = "compute.functionmesh.io/trigger-discovery"
+
+	FinalizerOrderedTeardown = "compute.functionmesh.io/ordered-teardown"
+
 	EnvGoFunctionConfigs = "GO_FUNCTION_CONF"
 
 	DefaultRunnerUserID  int64 = 10000
@@ -96,7 +110,55 @@ const (
 	PythonLogConfigFile        = "python_instance_logging.ini"
 	DefaultPythonLogConfigPath = PythonLogConifgDirectory + PythonLogConfigFile
 
-	EnvGoFunctionLogLevel = "LOGGING_LEVEL"
+	PythonLoggingModuleDirectory = "/pulsar/conf/python-logging/"
+	PythonLoggingModule          = "function_mesh_logging"
+
+	EnvGoFunctionLogLevel  = "LOGGING_LEVEL"
+	EnvGoFunctionLogFormat = "LOGGING_FORMAT"
+
+	DefaultJavaConsoleLogPattern = "%d{ISO8601_OFFSET_DATE_TIME_HHMM} [%t] %-5level %logger{36} - %msg%n"
+	DefaultJavaFileLogPattern    = "%d{yyyy-MMM-dd HH:mm:ss a} [%t] %-5level %logger{36} - %msg%n"
+	DefaultPythonLogFormat       = "[%(asctime)s] [%(levelname)s] %(filename)s: %(message)s"
+
+	// pythonLoggingModule provides the formatter and the handler referred by the rendered logging configuration.
+	// The instance swaps the handlers of the root logger with the log topic handler while the function runs, so
+	// the stdout handler keeps itself in the handlers of the root logger, where the records of all the loggers
+	// propagate.
+	pythonLoggingModule = `import json
+import logging
+import sys
+
+
+class JSONFormatter(logging.Formatter):
+    def format(self, record):
+        entry = {
+            'time': self.formatTime(record, self.datefmt),
+            'level': record.levelname,
+            'logger': record.name,
+            'file': record.filename,
+            'message': record.getMessage(),
+        }
+        if record.exc_info:
+            entry['exception'] = self.formatException(record.exc_info)
+        return json.dumps(entry)
+
+
+class RootHandlers(list):
+    def __init__(self, handlers, handler):
+        list.__init__(self, handlers)
+        self.handler = handler
+
+    def remove(self, handler):
+        if handler is not self.handler:
+            list.remove(self, handler)
+
+
+class StdoutHandler(logging.StreamHandler):
+    def __init__(self):
+        logging.StreamHandler.__init__(self, sys.stdout)
+        root = logging.getLogger()
+        root.handlers = RootHandlers([self], self)
+`
 
 	javaLog4jXMLTemplate = `<Configuration>
     <name>pulsar-functions-kubernetes-instance</name>
@@ -115,18 +177,34 @@ const (
         <Console>
             <name>Console</name>
             <target>SYSTEM_OUT</target>
+            {{- if .JSON }}
+            <JsonLayout>
+                <compact>true</compact>
+                <eventEol>true</eventEol>
+                <properties>true</properties>
+            </JsonLayout>
+            {{- else }}
             <PatternLayout>
-                <Pattern>%d{ISO8601_OFFSET_DATE_TIME_HHMM} [%t] %-5level %logger{36} - %msg%n</Pattern>
+                <Pattern>{{ .ConsolePattern }}</Pattern>
             </PatternLayout>
+            {{- end }}
         </Console>
         {{- if .RollingEnabled }}
         <RollingRandomAccessFile>
             <name>RollingRandomAccessFile</name>
             <fileName>\${sys:pulsar.function.log.dir}/\${sys:pulsar.function.log.file}.log</fileName>
             <filePattern>\${sys:pulsar.function.log.dir}/\${sys:pulsar.function.log.file}.%d{yyyy-MM-dd-hh-mm}-%i.log.gz</filePattern>
+            {{- if .JSON }}
+            <JsonLayout>
+                <compact>true</compact>
+                <eventEol>true</eventEol>
+                <properties>true</properties>
+            </JsonLayout>
+            {{- else }}
             <PatternLayout>
-                <Pattern>%d{yyyy-MMM-dd HH:mm:ss a} [%t] %-5level %logger{36} - %msg%n</Pattern>
+                <Pattern>{{ .FilePattern }}</Pattern>
             </PatternLayout>
+            {{- end }}
             <Policies>
                 {{ .Policy }}
             </Policies>
@@ -150,6 +228,12 @@ const (
             </AppenderRef>
             {{- end }}
         </Logger>
+        {{- range .Loggers }}
+        <Logger>
+            <name>{{ .Name }}</name>
+            <level>{{ .Level }}</level>
+        </Logger>
+        {{- end }}
         <Root>
             <level>\${sys:pulsar.log.level}</level>
             <AppenderRef>
@@ -165,7 +249,7 @@ const (
     </Loggers>
 </Configuration>`
 	pythonLoggingINITemplate = `[loggers]
-keys=root
+keys=root{{ range .Loggers }},{{ .Key }}{{ end }}
 
 [handlers]
 keys={{ .Handlers }}
@@ -176,19 +260,38 @@ keys=formatter
 [logger_root]
 level={{ .Level }}
 handlers={{ .Handlers }}
+{{- range .Loggers }}
+
+[logger_{{ .Key }}]
+level={{ .Level }}
+handlers=
+qualname={{ .Name }}
+propagate=1
+{{- end }}
 
 {{- if .RollingEnabled }}
 {{ .Policy }}
 {{- end }}
 
 [handler_stream_handler]
+{{- if .StdoutWithLogTopic }}
+class=function_mesh_logging.StdoutHandler
+level={{ .Level }}
+formatter=formatter
+args=()
+{{- else }}
 class=StreamHandler
 level={{ .Level }}
 formatter=formatter
 args=(sys.stdout,)
+{{- end }}
 
 [formatter_formatter]
-format=[%(asctime)s] [%(levelname)s] aa %(filename)s: %(message)s
+{{- if .JSON }}
+class=function_mesh_logging.JSONFormatter
+{{- else }}
+format={{ .Format }}
+{{- end }}
 datefmt=%Y-%m-%d %H:%M:%S %z`
 )
 
@@ -214,9 +317,18 @@ type TLSConfig interface {
 	GetMountPath() string
 }
 
+// ManagedSelector limits the objects reconciled by the controller to the ones with matching labels, so
+// that the objects of a cluster can be sharded across several controllers
+var ManagedSelector = labels.Everything()
+
+// IsManaged returns whether the object is reconciled by the controller, the objects annotated as not
+// managed and the ones not matching the ManagedSelector are skipped
 func IsManaged(object metav1.Object) bool {
 	managed, exists := object.GetAnnotations()[AnnotationManaged]
-	return !exists || managed != "false"
+	if exists && managed == "false" {
+		return false
+	}
+	return ManagedSelector.Matches(labels.Set(object.GetLabels()))
 }
 
 func MakeService(objectMeta *metav1.ObjectMeta, labels map[string]string) *corev1.Service {
@@ -247,24 +359,35 @@ func MakeStatefulSet(objectMeta *metav1.ObjectMeta, replicas *int32, downloaderI
 	goRuntime *v1alpha1.GoRuntime, definedVolumeMounts []corev1.VolumeMount) *appsv1.StatefulSet {
 
 	volumeMounts := generateDownloaderVolumeMountsForDownloader(javaRuntime, pythonRuntime, goRuntime)
+	downloadPackage := len(volumeMounts) > 0
+	volumeMounts = append(volumeMounts, generateJavaDependenciesDownloaderVolumeMounts(javaRuntime)...)
 	var downloaderContainer *corev1.Container
-	var podVolumes = volumes
-	// there must be a download path specified, we need to create an init container and emptyDir volume
+	var podVolumes = append(volumes, generateJavaDependenciesVolumes(javaRuntime)...)
+	packageCache := GetConfigs().DefaultsFor(objectMeta.Namespace).PackageCache
+	// there must be a download path or java dependencies specified, we need to create an init container and
+	// emptyDir volume
 	if len(volumeMounts) > 0 {
-		var downloadPath, componentPackage string
+		var downloadPath, componentPackage, checksum string
 		if javaRuntime != nil {
 			downloadPath = javaRuntime.JarLocation
 			componentPackage = javaRuntime.Jar
+			checksum = javaRuntime.Sha256
 		} else if pythonRuntime != nil {
 			downloadPath = pythonRuntime.PyLocation
 			componentPackage = pythonRuntime.Py
+			checksum = pythonRuntime.Sha256
 		} else {
 			downloadPath = goRuntime.GoLocation
 			componentPackage = goRuntime.Go
+			checksum = goRuntime.Sha256
+		}
+		if packageCache != nil {
+			volumeMounts = append(volumeMounts, makePackageCacheVolumeMount())
+			podVolumes = append(podVolumes, packageCache.makeVolume())
 		}
 
 		// mount auth and tls related VolumeMounts when download package from pulsar
-		if !hasHTTPPrefix(downloadPath) {
+		if !hasHTTPPrefix(downloadPath) && !hasOCIPrefix(downloadPath) {
 			if pulsar.AuthConfig != nil && pulsar.AuthConfig.OAuth2Config != nil {
 				volumeMounts = append(volumeMounts, generateVolumeMountFromOAuth2Config(pulsar.AuthConfig.OAuth2Config))
 			}
@@ -282,12 +405,33 @@ func MakeStatefulSet(objectMeta *metav1.ObjectMeta, replicas *int32, downloaderI
 
 		componentPackage = fmt.Sprintf("%s/%s", DownloadDir, getFilenameOfComponentPackage(componentPackage))
 
+		var downloadCommand []string
+		if hasOCIPrefix(downloadPath) {
+			image = mergeString(GetConfigs().DefaultsFor(objectMeta.Namespace).OCIDownloaderImage, OCIDownloaderImage)
+			registryVolumes, registryVolumeMounts, registryConfigs := generateOCIRegistryConfigs(policy.ImagePullSecrets)
+			podVolumes = append(podVolumes, registryVolumes...)
+			volumeMounts = append(volumeMounts, registryVolumeMounts...)
+			downloadCommand = getOCIDownloadCommand(downloadPath, componentPackage, registryConfigs)
+		} else {
+			downloadCommand = getDownloadCommand(downloadPath, componentPackage, pulsar.TLSSecret != "",
+				pulsar.AuthSecret != "", pulsar.TLSConfig, pulsar.AuthConfig)
+		}
+
+		var downloadCommands []string
+		if downloadPackage {
+			downloadCommands = append(downloadCommands,
+				makeDownloadCommand(downloadCommand, componentPackage, checksum, packageCache != nil))
+			podVolumes = append(podVolumes, corev1.Volume{
+				Name: DownloaderVolume,
+			})
+		}
+		downloadCommands = append(downloadCommands,
+			getJavaDependenciesDownloadCommands(javaRuntime, pulsar)...)
+
 		downloaderContainer = &corev1.Container{
-			Name:  DownloaderName,
-			Image: image,
-			Command: []string{"sh", "-c",
-				strings.Join(getDownloadCommand(downloadPath, componentPackage, pulsar.TLSSecret != "",
-					pulsar.AuthSecret != "", pulsar.TLSConfig, pulsar.AuthConfig), " ")},
+			Name:            DownloaderName,
+			Image:           image,
+			Command:         []string{"sh", "-c", strings.Join(downloadCommands, " && ")},
 			VolumeMounts:    volumeMounts,
 			ImagePullPolicy: corev1.PullIfNotPresent,
 			Env: []corev1.EnvVar{{
@@ -296,9 +440,14 @@ func MakeStatefulSet(objectMeta *metav1.ObjectMeta, replicas *int32, downloaderI
 			}},
 			EnvFrom: generateContainerEnvFrom(pulsar.PulsarConfig, pulsar.AuthSecret, pulsar.TLSSecret),
 		}
-		podVolumes = append(podVolumes, corev1.Volume{
-			Name: DownloaderVolume,
-		})
+	}
+	// without a downloader container, the instance container downloads the package through the cache too
+	downloadPath := getPackageLocation(javaRuntime, pythonRuntime, goRuntime)
+	if packageCache != nil && downloadPath != "" && !usesDownloaderContainer(downloadPath) {
+		container.VolumeMounts = append(container.VolumeMounts, makePackageCacheVolumeMount())
+		if downloaderContainer == nil {
+			podVolumes = append(podVolumes, packageCache.makeVolume())
+		}
 	}
 	if utils.GrpcurlPersistentVolumeClaim != "" {
 		podVolumes = append(podVolumes, corev1.Volume{
@@ -321,6 +470,15 @@ func MakeStatefulSet(objectMeta *metav1.ObjectMeta, replicas *int32, downloaderI
 	}
 }
 
+// makeReplicas returns the replicas of the StatefulSet, a paused workload is scaled down to zero
+func makeReplicas(replicas *int32, paused bool) *int32 {
+	if paused {
+		zero := int32(0)
+		return &zero
+	}
+	return replicas
+}
+
 func MakeStatefulSetSpec(replicas *int32, container *corev1.Container,
 	volumes []corev1.Volume, labels map[string]string, policy v1alpha1.PodPolicy,
 	serviceName string, downloaderContainer *corev1.Container) *appsv1.StatefulSetSpec {
@@ -330,7 +488,7 @@ func MakeStatefulSetSpec(replicas *int32, container *corev1.Container,
 			MatchLabels: labels,
 		},
 		Template:            *MakePodTemplate(container, volumes, labels, policy, downloaderContainer),
-		PodManagementPolicy: appsv1.ParallelPodManagement,
+		PodManagementPolicy: makePodManagementPolicy(policy),
 		UpdateStrategy: appsv1.StatefulSetUpdateStrategy{
 			Type: appsv1.RollingUpdateStatefulSetStrategyType,
 		},
@@ -338,6 +496,13 @@ func MakeStatefulSetSpec(replicas *int32, container *corev1.Container,
 	}
 }
 
+func makePodManagementPolicy(policy v1alpha1.PodPolicy) appsv1.PodManagementPolicyType {
+	if policy.PodManagementPolicy != "" {
+		return policy.PodManagementPolicy
+	}
+	return appsv1.ParallelPodManagement
+}
+
 func MakePodTemplate(container *corev1.Container, volumes []corev1.Volume,
 	labels map[string]string, policy v1alpha1.PodPolicy,
 	downloaderContainer *corev1.Container) *corev1.PodTemplateSpec {
@@ -351,8 +516,8 @@ func MakePodTemplate(container *corev1.Container, volumes []corev1.Volume,
 	}
 	return &corev1.PodTemplateSpec{
 		ObjectMeta: metav1.ObjectMeta{
-			Labels:      mergeLabels(labels, Configs.ResourceLabels, policy.Labels),
-			Annotations: generateAnnotations(Configs.ResourceAnnotations, policy.Annotations),
+			Labels:      mergeLabels(labels, GetConfigs().ResourceLabels, policy.Labels),
+			Annotations: generateAnnotations(GetConfigs().ResourceAnnotations, policy.Annotations),
 		},
 		Spec: corev1.PodSpec{
 			InitContainers:                initContainers,
@@ -369,34 +534,35 @@ func MakePodTemplate(container *corev1.Container, volumes []corev1.Volume,
 	}
 }
 
-func MakeJavaFunctionCommand(downloadPath, packageFile, name, clusterName, generateLogConfigCommand, logLevel, details, memory, extraDependenciesDir, uid string,
-	javaOpts []string, authProvided, tlsProvided bool, secretMaps map[string]v1alpha1.SecretRef,
+func MakeJavaFunctionCommand(downloadPath, packageFile, packageChecksum string, packageCached bool, name, clusterName, generateLogConfigCommand, logLevel, details, jvmOptions, extraClasspath, uid, functionVersion string,
+	maxBufferedTuples int32, javaOpts []string, authProvided, tlsProvided bool, secretMaps map[string]v1alpha1.SecretRef,
 	state *v1alpha1.Stateful,
 	tlsConfig TLSConfig, authConfig *v1alpha1.AuthConfig, healthCheckInterval int32,
 	maxPendingAsyncRequests *int32) []string {
 	processCommand := setShardIDEnvironmentVariableCommand() + " && " + generateLogConfigCommand +
 		strings.Join(getProcessJavaRuntimeArgs(name, packageFile, clusterName, logLevel, details,
-			memory, extraDependenciesDir, uid, javaOpts, authProvided, tlsProvided, secretMaps, state, tlsConfig,
+			jvmOptions, extraClasspath, uid, functionVersion, maxBufferedTuples, javaOpts, authProvided, tlsProvided, secretMaps, state, tlsConfig,
 			authConfig, healthCheckInterval, maxPendingAsyncRequests), " ")
-	if downloadPath != "" && !utils.EnableInitContainers {
+	if downloadPath != "" && !usesDownloaderContainer(downloadPath) {
 		// prepend download command if the downPath is provided
-		downloadCommand := strings.Join(getLegacyDownloadCommand(downloadPath, packageFile, authProvided, tlsProvided,
-			tlsConfig, authConfig), " ")
+		downloadCommand := makeDownloadCommand(getLegacyDownloadCommand(downloadPath, packageFile, authProvided,
+			tlsProvided, tlsConfig, authConfig), packageFile, packageChecksum, packageCached)
 		processCommand = downloadCommand + " && " + processCommand
 	}
 	return []string{"sh", "-c", processCommand}
 }
 
-func MakePythonFunctionCommand(downloadPath, packageFile, name, clusterName, generateLogConfigCommand, details, uid string,
+func MakePythonFunctionCommand(downloadPath, packageFile, packageChecksum string, packageCached bool, name, clusterName string,
+	dependencyArgs []string, generateLogConfigCommand, details, uid, functionVersion string, maxBufferedTuples int32,
 	authProvided, tlsProvided bool, secretMaps map[string]v1alpha1.SecretRef, state *v1alpha1.Stateful,
 	tlsConfig TLSConfig, authConfig *v1alpha1.AuthConfig, healthCheckInterval int32) []string {
 	processCommand := setShardIDEnvironmentVariableCommand() + " && " + generateLogConfigCommand +
-		strings.Join(getProcessPythonRuntimeArgs(name, packageFile, clusterName,
-			details, uid, authProvided, tlsProvided, secretMaps, state, tlsConfig, authConfig, healthCheckInterval), " ")
-	if downloadPath != "" && !utils.EnableInitContainers {
+		strings.Join(getProcessPythonRuntimeArgs(name, packageFile, clusterName, dependencyArgs,
+			details, uid, functionVersion, maxBufferedTuples, authProvided, tlsProvided, secretMaps, state, tlsConfig, authConfig, healthCheckInterval), " ")
+	if downloadPath != "" && !usesDownloaderContainer(downloadPath) {
 		// prepend download command if the downPath is provided
-		downloadCommand := strings.Join(getLegacyDownloadCommand(downloadPath, packageFile, authProvided, tlsProvided,
-			tlsConfig, authConfig), " ")
+		downloadCommand := makeDownloadCommand(getLegacyDownloadCommand(downloadPath, packageFile, authProvided,
+			tlsProvided, tlsConfig, authConfig), packageFile, packageChecksum, packageCached)
 		processCommand = downloadCommand + " && " + processCommand
 	}
 	return []string{"sh", "-c", processCommand}
@@ -405,11 +571,12 @@ func MakePythonFunctionCommand(downloadPath, packageFile, name, clusterName, gen
 func MakeGoFunctionCommand(downloadPath, goExecFilePath string, function *v1alpha1.Function) []string {
 	processCommand := setShardIDEnvironmentVariableCommand() + " && " +
 		strings.Join(getProcessGoRuntimeArgs(goExecFilePath, function), " ")
-	if downloadPath != "" && !utils.EnableInitContainers {
+	if downloadPath != "" && !usesDownloaderContainer(downloadPath) {
 		// prepend download command if the downPath is provided
-		downloadCommand := strings.Join(getLegacyDownloadCommand(downloadPath, goExecFilePath,
+		downloadCommand := makeDownloadCommand(getLegacyDownloadCommand(downloadPath, goExecFilePath,
 			function.Spec.Pulsar.AuthSecret != "", function.Spec.Pulsar.TLSSecret != "",
-			function.Spec.Pulsar.TLSConfig, function.Spec.Pulsar.AuthConfig), " ")
+			function.Spec.Pulsar.TLSConfig, function.Spec.Pulsar.AuthConfig), goExecFilePath,
+			function.Spec.Golang.Sha256, isPackageCached(function.Namespace))
 		processCommand = downloadCommand + " && ls -al && pwd &&" + processCommand
 	}
 	return []string{"sh", "-c", processCommand}
@@ -637,18 +804,42 @@ func generateJavaLogConfigCommand(runtime *v1alpha1.JavaRuntime) string {
 func renderJavaInstanceLog4jXMLTemplate(runtime *v1alpha1.JavaRuntime) (string, error) {
 	tmpl := template.Must(template.New("spec").Parse(javaLog4jXMLTemplate))
 	var tpl bytes.Buffer
+	type loggerConfig struct {
+		Name  string
+		Level string
+	}
 	type logConfig struct {
 		RollingEnabled bool
 		Level          string
 		Policy         template.HTML
+		JSON           bool
+		ConsolePattern string
+		FilePattern    string
+		Loggers        []loggerConfig
 	}
 	lc := &logConfig{}
 	lc.Level = "INFO"
+	lc.ConsolePattern = DefaultJavaConsoleLogPattern
+	lc.FilePattern = DefaultJavaFileLogPattern
 	if runtime.Log != nil && runtime.Log.Level != "" {
 		if level := parseJavaLogLevel(runtime); level != "" {
 			lc.Level = level
 		}
 	}
+	if runtime.Log != nil {
+		lc.JSON = runtime.Log.Format == v1alpha1.LogFormatJSON
+		if runtime.Log.Pattern != "" {
+			// the quotes are escaped by the template
+			lc.ConsolePattern = escapeShellDoubleQuoted(runtime.Log.Pattern, false)
+			lc.FilePattern = lc.ConsolePattern
+		}
+		for _, name := range sortedLoggerNames(runtime.Log.Loggers) {
+			lc.Loggers = append(lc.Loggers, loggerConfig{
+				Name:  escapeShellDoubleQuoted(name, false),
+				Level: mapLogLevel(javaLogLevels, runtime.Log.Loggers[name]),
+			})
+		}
+	}
 	if runtime.Log != nil && runtime.Log.RotatePolicy != nil {
 		lc.RollingEnabled = true
 		switch *runtime.Log.RotatePolicy {
@@ -691,86 +882,125 @@ func generatePythonLogConfigCommand(name string, runtime *v1alpha1.PythonRuntime
 		return commands
 	}
 	if loggingINI, err := renderPythonInstanceLoggingINITemplate(name, runtime); err == nil {
+		if usesPythonLoggingModule(runtime.Log) {
+			generateModuleCommand := []string{
+				"mkdir", "-p", PythonLoggingModuleDirectory, "&&",
+				"echo", fmt.Sprintf("\"%s\"", escapeShellDoubleQuoted(pythonLoggingModule, true)), ">",
+				PythonLoggingModuleDirectory + PythonLoggingModule + ".py", "&&",
+				"export", fmt.Sprintf("PYTHONPATH=%s${PYTHONPATH:+:${PYTHONPATH}}", PythonLoggingModuleDirectory),
+				"&& ",
+			}
+			commands += strings.Join(generateModuleCommand, " ")
+		}
 		generateConfigFileCommand := []string{
 			"mkdir", "-p", PythonLogConifgDirectory, "logs/functions", "&&",
 			"echo", fmt.Sprintf("\"%s\"", loggingINI), ">", DefaultPythonLogConfigPath,
 			"&& ",
 		}
-		level := parsePythonLogLevel(runtime)
-		hackCmd := ""
-		if level != "" {
-			hackCmd = fmt.Sprintf("sed -i.bak 's/^level=.*/level=%s/g' %s && ", level, DefaultPythonLogConfigPath)
-		}
-		return commands + strings.Join(generateConfigFileCommand, " ") + hackCmd
+		return commands + strings.Join(generateConfigFileCommand, " ")
 	}
 	return ""
 }
 
+// usesPythonLoggingModule returns whether the rendered logging configuration refers to the logging module
+func usesPythonLoggingModule(log *v1alpha1.RuntimeLogConfig) bool {
+	return log != nil && (log.Format == v1alpha1.LogFormatJSON || log.StdoutWithLogTopic)
+}
+
 func renderPythonInstanceLoggingINITemplate(name string, runtime *v1alpha1.PythonRuntime) (string, error) {
 	tmpl := template.Must(template.New("spec").Parse(pythonLoggingINITemplate))
 	var tpl bytes.Buffer
+	type loggerConfig struct {
+		Key   string
+		Name  template.HTML
+		Level string
+	}
 	type logConfig struct {
-		RollingEnabled bool
-		Level          string
-		Policy         template.HTML
-		Handlers       string
+		RollingEnabled     bool
+		Level              string
+		Policy             template.HTML
+		Handlers           string
+		StdoutWithLogTopic bool
+		JSON               bool
+		Format             template.HTML
+		Loggers            []loggerConfig
 	}
 	lc := &logConfig{}
 	lc.Level = "INFO"
-	lc.Handlers = "stream_handler"
+	lc.Format = template.HTML(DefaultPythonLogFormat)
 	if runtime.Log != nil && runtime.Log.Level != "" {
 		if level := parsePythonLogLevel(runtime); level != "" {
 			lc.Level = level
 		}
 	}
+	if runtime.Log != nil {
+		if runtime.Log.Format == v1alpha1.LogFormatJSON {
+			lc.JSON = true
+		} else if runtime.Log.Pattern != "" {
+			lc.Format = template.HTML(escapeShellDoubleQuoted(runtime.Log.Pattern, true))
+		}
+		for i, name := range sortedLoggerNames(runtime.Log.Loggers) {
+			lc.Loggers = append(lc.Loggers, loggerConfig{
+				Key:   fmt.Sprintf("named%d", i),
+				Name:  template.HTML(escapeShellDoubleQuoted(name, true)),
+				Level: mapLogLevel(pythonLogLevels, runtime.Log.Loggers[name]),
+			})
+		}
+	}
 	if runtime.Log != nil && runtime.Log.RotatePolicy != nil {
 		lc.RollingEnabled = true
 		logFile := fmt.Sprintf("logs/functions/%s-${%s}", name, EnvShardID)
 		switch *runtime.Log.RotatePolicy {
 		case v1alpha1.TimedPolicyWithDaily:
-			lc.Handlers = "stream_handler,timed_rotating_file_handler"
+			lc.Handlers = "timed_rotating_file_handler"
 			lc.Policy = template.HTML(fmt.Sprintf(`[handler_timed_rotating_file_handler]
 args=(\"%s\", 'D', 1, 5,)
 class=handlers.TimedRotatingFileHandler
-level=%s 
+level=%s
 formatter=formatter`, logFile, lc.Level))
 		case v1alpha1.TimedPolicyWithWeekly:
-			lc.Handlers = "stream_handler,timed_rotating_file_handler"
+			lc.Handlers = "timed_rotating_file_handler"
 			lc.Policy = template.HTML(fmt.Sprintf(`[handler_timed_rotating_file_handler]
 args=(\"%s\", 'W0', 1, 5,)
 class=handlers.TimedRotatingFileHandler
 level=%s
 formatter=formatter`, logFile, lc.Level))
 		case v1alpha1.TimedPolicyWithMonthly:
-			lc.Handlers = "stream_handler,timed_rotating_file_handler"
+			lc.Handlers = "timed_rotating_file_handler"
 			lc.Policy = template.HTML(fmt.Sprintf(`[handler_timed_rotating_file_handler]
 args=(\"%s\", 'D', 30, 5,)
 class=handlers.TimedRotatingFileHandler
 level=%s
 formatter=formatter`, logFile, lc.Level))
 		case v1alpha1.SizedPolicyWith10MB:
-			lc.Handlers = "stream_handler,rotating_file_handler"
+			lc.Handlers = "rotating_file_handler"
 			lc.Policy = template.HTML(fmt.Sprintf(`[handler_rotating_file_handler]
 args=(\"%s\", 'a', 10485760, 5,)
 class=handlers.RotatingFileHandler
 level=%s
 formatter=formatter`, logFile, lc.Level))
 		case v1alpha1.SizedPolicyWith50MB:
-			lc.Handlers = "handler_stream_handler,rotating_file_handler"
+			lc.Handlers = "rotating_file_handler"
 			lc.Policy = template.HTML(fmt.Sprintf(`[handler_rotating_file_handler]
-args=(%s, 'a', 52428800, 5,)
+args=(\"%s\", 'a', 52428800, 5,)
 class=handlers.RotatingFileHandler
 level=%s
 formatter=formatter`, logFile, lc.Level))
 		case v1alpha1.SizedPolicyWith100MB:
-			lc.Handlers = "handler_stream_handler,rotating_file_handler"
+			lc.Handlers = "rotating_file_handler"
 			lc.Policy = template.HTML(fmt.Sprintf(`[handler_rotating_file_handler]
-args=(%s, 'a', 104857600, 5,)
+args=(\"%s\", 'a', 104857600, 5,)
 class=handlers.RotatingFileHandler
 level=%s
 formatter=formatter`, logFile, lc.Level))
 		}
 	}
+	lc.StdoutWithLogTopic = runtime.Log != nil && runtime.Log.StdoutWithLogTopic
+	if lc.Handlers != "" {
+		lc.Handlers = "stream_handler," + lc.Handlers
+	} else {
+		lc.Handlers = "stream_handler"
+	}
 	if err := tmpl.Execute(&tpl, lc); err != nil {
 		log.Error(err, "failed to render python instance logging template")
 		return "", err
@@ -778,39 +1008,82 @@ formatter=formatter`, logFile, lc.Level))
 	return tpl.String(), nil
 }
 
-func parseJavaLogLevel(runtime *v1alpha1.JavaRuntime) string {
-	var levelMap = map[v1alpha1.LogLevel]string{
-		v1alpha1.LogLevelAll:   "ALL",
-		v1alpha1.LogLevelDebug: "DEBUG",
-		v1alpha1.LogLevelTrace: "TRACE",
-		v1alpha1.LogLevelInfo:  "INFO",
-		v1alpha1.LogLevelWarn:  "WARN",
-		v1alpha1.LogLevelError: "ERROR",
-		v1alpha1.LogLevelFatal: "FATAL",
-		v1alpha1.LogLevelOff:   "OFF",
+var javaLogLevels = map[v1alpha1.LogLevel]string{
+	v1alpha1.LogLevelAll:   "ALL",
+	v1alpha1.LogLevelDebug: "DEBUG",
+	v1alpha1.LogLevelTrace: "TRACE",
+	v1alpha1.LogLevelInfo:  "INFO",
+	v1alpha1.LogLevelWarn:  "WARN",
+	v1alpha1.LogLevelError: "ERROR",
+	v1alpha1.LogLevelFatal: "FATAL",
+	v1alpha1.LogLevelOff:   "OFF",
+}
+
+var pythonLogLevels = map[v1alpha1.LogLevel]string{
+	v1alpha1.LogLevelDebug: "DEBUG",
+	v1alpha1.LogLevelInfo:  "INFO",
+	v1alpha1.LogLevelWarn:  "WARNING",
+	v1alpha1.LogLevelError: "ERROR",
+	v1alpha1.LogLevelFatal: "CRITICAL",
+}
+
+// golangLogLevels maps the levels to the logrus levels, which have no off and all levels
+var golangLogLevels = map[v1alpha1.LogLevel]string{
+	v1alpha1.LogLevelAll:   "trace",
+	v1alpha1.LogLevelDebug: "debug",
+	v1alpha1.LogLevelTrace: "trace",
+	v1alpha1.LogLevelInfo:  "info",
+	v1alpha1.LogLevelWarn:  "warn",
+	v1alpha1.LogLevelError: "error",
+	v1alpha1.LogLevelFatal: "fatal",
+	v1alpha1.LogLevelPanic: "panic",
+	v1alpha1.LogLevelOff:   "panic",
+}
+
+// mapLogLevel returns the level of the runtime, the levels unknown to the runtime fall back to info
+func mapLogLevel(levelMap map[v1alpha1.LogLevel]string, level v1alpha1.LogLevel) string {
+	if mapped, exist := levelMap[level]; exist {
+		return mapped
 	}
-	if runtime.Log != nil && runtime.Log.Level != "" && runtime.Log.LogConfig == nil {
-		if level, exist := levelMap[runtime.Log.Level]; exist {
-			return level
+	return levelMap[v1alpha1.LogLevelInfo]
+}
+
+func sortedLoggerNames(loggers map[string]v1alpha1.LogLevel) []string {
+	names := make([]string, 0, len(loggers))
+	for name := range loggers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// escapeShellDoubleQuoted escapes the user provided values of the log configs, which are written by an echo of a
+// double-quoted string, the double quotes are left to the templates which escape them
+func escapeShellDoubleQuoted(value string, quotes bool) string {
+	chars := "\\$`"
+	if quotes {
+		chars += "\""
+	}
+	var escaped strings.Builder
+	for _, c := range value {
+		if strings.ContainsRune(chars, c) {
+			escaped.WriteRune('\\')
 		}
-		return levelMap[v1alpha1.LogLevelInfo]
+		escaped.WriteRune(c)
+	}
+	return escaped.String()
+}
+
+func parseJavaLogLevel(runtime *v1alpha1.JavaRuntime) string {
+	if runtime.Log != nil && runtime.Log.Level != "" && runtime.Log.LogConfig == nil {
+		return mapLogLevel(javaLogLevels, runtime.Log.Level)
 	}
 	return ""
 }
 
 func parsePythonLogLevel(runtime *v1alpha1.PythonRuntime) string {
-	var levelMap = map[v1alpha1.LogLevel]string{
-		v1alpha1.LogLevelDebug: "DEBUG",
-		v1alpha1.LogLevelInfo:  "INFO",
-		v1alpha1.LogLevelWarn:  "WARNING",
-		v1alpha1.LogLevelError: "ERROR",
-		v1alpha1.LogLevelFatal: "CRITICAL",
-	}
 	if runtime.Log != nil && runtime.Log.Level != "" && runtime.Log.LogConfig == nil {
-		if level, exist := levelMap[runtime.Log.Level]; exist {
-			return level
-		}
-		return levelMap[v1alpha1.LogLevelInfo]
+		return mapLogLevel(pythonLogLevels, runtime.Log.Level)
 	}
 	return ""
 }
@@ -819,21 +1092,19 @@ func parseGolangLogLevel(runtime *v1alpha1.GoRuntime) string {
 	if runtime == nil || (runtime.Log != nil && runtime.Log.LogConfig != nil) {
 		return ""
 	}
-	var levelMap = map[v1alpha1.LogLevel]string{
-		v1alpha1.LogLevelDebug: "debug",
-		v1alpha1.LogLevelTrace: "trace",
-		v1alpha1.LogLevelInfo:  "info",
-		v1alpha1.LogLevelWarn:  "warn",
-		v1alpha1.LogLevelError: "error",
-		v1alpha1.LogLevelFatal: "fatal",
-		v1alpha1.LogLevelPanic: "panic",
-	}
 	if runtime.Log != nil && runtime.Log.Level != "" {
-		if level, exist := levelMap[runtime.Log.Level]; exist {
-			return level
-		}
+		return mapLogLevel(golangLogLevels, runtime.Log.Level)
 	}
-	return levelMap[v1alpha1.LogLevelInfo]
+	return golangLogLevels[v1alpha1.LogLevelInfo]
+}
+
+// parseGolangLogFormat returns the format of the logs of the go runtime, it is empty for the default text format
+func parseGolangLogFormat(runtime *v1alpha1.GoRuntime) string {
+	if runtime == nil || runtime.Log == nil || runtime.Log.LogConfig != nil ||
+		runtime.Log.Format != v1alpha1.LogFormatJSON {
+		return ""
+	}
+	return string(v1alpha1.LogFormatJSON)
 }
 
 // TODO: do a more strict check for the package name https://github.com/streamnative/function-mesh/issues/49
@@ -848,18 +1119,36 @@ func hasHTTPPrefix(packageName string) bool {
 		strings.HasPrefix(packageName, HTTPSPrefix)
 }
 
+// makeFunctionVersion returns the version reported by the instances in their metrics and state, unless it is set
+// it is derived from the checksum of the package, or from the generation of the component without a checksum
+func makeFunctionVersion(version, checksum string, generation int64) string {
+	if version != "" {
+		return version
+	}
+	if checksum != "" {
+		checksum = strings.ToLower(checksum)
+		if len(checksum) > 12 {
+			return checksum[:12]
+		}
+		return checksum
+	}
+	return strconv.FormatInt(generation, 10)
+}
+
+// setShardIDEnvironmentVariableCommand sets the instance ID from the ordinal of the pod, so that the instance
+// IDs are stable across the restarts of the pods whatever the pod management policy of the StatefulSet is
 func setShardIDEnvironmentVariableCommand() string {
 	return fmt.Sprintf("%s=${POD_NAME##*-} && echo shardId=${%s}", EnvShardID, EnvShardID)
 }
 
-func getProcessJavaRuntimeArgs(name, packageName, clusterName, logLevel, details, memory, extraDependenciesDir, uid string,
-	javaOpts []string, authProvided, tlsProvided bool, secretMaps map[string]v1alpha1.SecretRef,
+func getProcessJavaRuntimeArgs(name, packageName, clusterName, logLevel, details, jvmOptions, extraClasspath, uid,
+	functionVersion string, maxBufferedTuples int32, javaOpts []string, authProvided, tlsProvided bool, secretMaps map[string]v1alpha1.SecretRef,
 	state *v1alpha1.Stateful,
 	tlsConfig TLSConfig, authConfig *v1alpha1.AuthConfig,
 	healthCheckInterval int32, maxPendingAsyncRequests *int32) []string {
 	classPath := "/pulsar/instances/java-instance.jar"
-	if extraDependenciesDir != "" {
-		classPath = fmt.Sprintf("%s:%s/*", classPath, extraDependenciesDir)
+	if extraClasspath != "" {
+		classPath = fmt.Sprintf("%s:%s", classPath, extraClasspath)
 	}
 	setLogLevel := ""
 	if logLevel != "" {
@@ -877,31 +1166,24 @@ func getProcessJavaRuntimeArgs(name, packageName, clusterName, logLevel, details
 		classPath,
 		fmt.Sprintf("-D%s=%s", FunctionsInstanceClasspath, "/pulsar/lib/*"),
 		fmt.Sprintf("-Dlog4j.configurationFile=%s", DefaultJavaLogConfigPath),
-		"-Dpulsar.function.log.dir=logs/functions",
+		"-Dpulsar.function.log.dir=" + JavaInstanceLogDir,
 		"-Dpulsar.function.log.file=" + fmt.Sprintf("%s-${%s}", name, EnvShardID),
 		setLogLevel,
-		"-Xmx" + memory,
+		jvmOptions,
 		strings.Join(javaOpts, " "),
+		strings.Join(getStateStoreJavaOpts(state), " "),
 		"org.apache.pulsar.functions.instance.JavaInstanceMain",
 		"--jar",
 		packageName,
 	}
-	sharedArgs := getSharedArgs(details, clusterName, uid, authProvided, tlsProvided, tlsConfig, authConfig, healthCheckInterval)
+	sharedArgs := getSharedArgs(details, clusterName, uid, functionVersion, maxBufferedTuples, authProvided, tlsProvided,
+		tlsConfig, authConfig, healthCheckInterval)
 	args = append(args, sharedArgs...)
 	if len(secretMaps) > 0 {
 		secretProviderArgs := getJavaSecretProviderArgs(secretMaps)
 		args = append(args, secretProviderArgs...)
 	}
-	if state != nil && state.Pulsar != nil && state.Pulsar.ServiceURL != "" {
-		statefulArgs := []string{
-			"--state_storage_serviceurl",
-			state.Pulsar.ServiceURL,
-		}
-		if state.Pulsar.JavaProvider != nil {
-			statefulArgs = append(statefulArgs, "--state_storage_impl_class", state.Pulsar.JavaProvider.ClassName)
-		}
-		args = append(args, statefulArgs...)
-	}
+	args = append(args, getStatefulArgs(state, true)...)
 	if maxPendingAsyncRequests != nil {
 		args = append(args, []string{
 			"--pending_async_requests",
@@ -911,8 +1193,8 @@ func getProcessJavaRuntimeArgs(name, packageName, clusterName, logLevel, details
 	return args
 }
 
-func getProcessPythonRuntimeArgs(name, packageName, clusterName, details, uid string, authProvided, tlsProvided bool,
-	secretMaps map[string]v1alpha1.SecretRef, state *v1alpha1.Stateful, tlsConfig TLSConfig,
+func getProcessPythonRuntimeArgs(name, packageName, clusterName string, dependencyArgs []string, details, uid,
+	functionVersion string, maxBufferedTuples int32, authProvided, tlsProvided bool, secretMaps map[string]v1alpha1.SecretRef, state *v1alpha1.Stateful, tlsConfig TLSConfig,
 	authConfig *v1alpha1.AuthConfig, healthCheckInterval int32) []string {
 	args := []string{
 		"exec",
@@ -926,28 +1208,22 @@ func getProcessPythonRuntimeArgs(name, packageName, clusterName, details, uid st
 		fmt.Sprintf("%s-${%s}", name, EnvShardID),
 		"--logging_config_file",
 		DefaultPythonLogConfigPath,
-		"--install_usercode_dependencies",
-		"true",
-		// TODO: Maybe we don't need installUserCodeDependencies, dependency_repository, and pythonExtraDependencyRepository
 	}
-	sharedArgs := getSharedArgs(details, clusterName, uid, authProvided, tlsProvided, tlsConfig, authConfig, healthCheckInterval)
+	args = append(args, dependencyArgs...)
+	sharedArgs := getSharedArgs(details, clusterName, uid, functionVersion, maxBufferedTuples, authProvided, tlsProvided,
+		tlsConfig, authConfig, healthCheckInterval)
 	args = append(args, sharedArgs...)
 	if len(secretMaps) > 0 {
 		secretProviderArgs := getPythonSecretProviderArgs(secretMaps)
 		args = append(args, secretProviderArgs...)
 	}
-	if state != nil && state.Pulsar != nil && state.Pulsar.ServiceURL != "" {
-		statefulArgs := []string{
-			"--state_storage_serviceurl",
-			state.Pulsar.ServiceURL,
-		}
-		args = append(args, statefulArgs...)
-	}
+	args = append(args, getStatefulArgs(state, false)...)
 	return args
 }
 
 // This method is suitable for Java and Python runtime, not include Go runtime.
-func getSharedArgs(details, clusterName, uid string, authProvided bool, tlsProvided bool,
+func getSharedArgs(details, clusterName, uid, functionVersion string, maxBufferedTuples int32,
+	authProvided bool, tlsProvided bool,
 	tlsConfig TLSConfig, authConfig *v1alpha1.AuthConfig, healthCheckInterval int32) []string {
 	var hInterval int32 = -1
 	if healthCheckInterval > 0 && utils.GrpcurlPersistentVolumeClaim != "" {
@@ -959,13 +1235,13 @@ func getSharedArgs(details, clusterName, uid string, authProvided bool, tlsProvi
 		"--function_id",
 		fmt.Sprintf("${%s}-%s", EnvShardID, uid),
 		"--function_version",
-		"0",
+		functionVersion,
 		"--function_details",
 		"'" + details + "'", //in json format
 		"--pulsar_serviceurl",
 		"$brokerServiceURL",
 		"--max_buffered_tuples",
-		"100", // TODO
+		strconv.Itoa(int(maxBufferedTuples)),
 		"--port",
 		strconv.Itoa(int(GRPCPort.ContainerPort)),
 		"--metrics_port",
@@ -1099,7 +1375,8 @@ func convertSubPosition(pos v1alpha1.SubscribePosition) proto.SubscriptionPositi
 	}
 }
 
-func generateRetryDetails(maxMessageRetry int32, deadLetterTopic string) *proto.RetryDetails {
+func generateRetryDetails(maxMessageRetry int32, deadLetterTopic string, policy *v1alpha1.RetryPolicy) *proto.RetryDetails {
+	maxMessageRetry, deadLetterTopic = getRetryDetails(maxMessageRetry, deadLetterTopic, policy)
 	if maxMessageRetry <= 0 && deadLetterTopic == "" {
 		return nil
 	}
@@ -1109,6 +1386,41 @@ func generateRetryDetails(maxMessageRetry int32, deadLetterTopic string) *proto.
 	}
 }
 
+// getRetryDetails returns the max message retry and the dead letter topic, the retry policy
+// takes precedence over the plain fields
+func getRetryDetails(maxMessageRetry int32, deadLetterTopic string,
+	policy *v1alpha1.RetryPolicy) (int32, string) {
+	if policy == nil {
+		return maxMessageRetry, deadLetterTopic
+	}
+	deadLetterTopic = ""
+	if policy.DeadLetterTopic != nil {
+		deadLetterTopic = policy.DeadLetterTopic.Name
+	}
+	return policy.MaxMessageRetry, deadLetterTopic
+}
+
+func getNegativeAckRedeliveryDelayMs(delayMs int32, policy *v1alpha1.RetryPolicy) uint64 {
+	if policy != nil && policy.NegativeAckRedeliveryDelayMs > 0 {
+		return uint64(policy.NegativeAckRedeliveryDelayMs)
+	}
+	return uint64(delayMs)
+}
+
+// MakeRetryTopics returns the topics of the retry policy which need to be provisioned
+func MakeRetryTopics(policy *v1alpha1.RetryPolicy) []v1alpha1.RetryTopic {
+	var topics []v1alpha1.RetryTopic
+	if policy == nil {
+		return topics
+	}
+	for _, topic := range []*v1alpha1.RetryTopic{policy.DeadLetterTopic, policy.RetryLetterTopic} {
+		if topic != nil && topic.Provision {
+			topics = append(topics, *topic)
+		}
+	}
+	return topics
+}
+
 func generateResource(resources corev1.ResourceList) *proto.Resources {
 	return &proto.Resources{
 		Cpu:  float64(resources.Cpu().Value()),
@@ -1136,6 +1448,19 @@ func generateContainerEnv(function *v1alpha1.Function) []corev1.EnvVar {
 			Value: level,
 		})
 	}
+	if format := parseGolangLogFormat(function.Spec.Golang); format != "" {
+		envs = append(envs, corev1.EnvVar{
+			Name:  EnvGoFunctionLogFormat,
+			Value: format,
+		})
+	}
+	// the python dependencies installed by the init container
+	if function.Spec.Python != nil && function.Spec.Python.HasDependencies() {
+		envs = append(envs, corev1.EnvVar{
+			Name:  EnvPythonPath,
+			Value: PythonSitePackagesDir,
+		})
+	}
 	return envs
 }
 
@@ -1368,12 +1693,8 @@ func generateContainerVolumeMountsFromConsumerConfigs(confs map[string]v1alpha1.
 
 func generateDownloaderVolumeMountsForDownloader(javaRuntime *v1alpha1.JavaRuntime,
 	pythonRuntime *v1alpha1.PythonRuntime, goRuntime *v1alpha1.GoRuntime) []corev1.VolumeMount {
-	if !utils.EnableInitContainers {
-		return nil
-	}
-	if (javaRuntime != nil && javaRuntime.JarLocation != "") ||
-		(pythonRuntime != nil && pythonRuntime.PyLocation != "") ||
-		(goRuntime != nil && goRuntime.GoLocation != "") {
+	downloadPath := getPackageLocation(javaRuntime, pythonRuntime, goRuntime)
+	if downloadPath != "" && usesDownloaderContainer(downloadPath) {
 		return []corev1.VolumeMount{{
 			Name:      DownloaderVolume,
 			MountPath: DownloadDir,
@@ -1447,9 +1768,10 @@ func generateContainerVolumeMounts(volumeMounts []corev1.VolumeMount, producerCo
 			mounts = append(mounts, generateVolumeMountFromOAuth2Config(authConfig.OAuth2Config))
 		}
 	}
-	if utils.EnableInitContainers {
+	if usesDownloaderContainer(getPackageLocation(javaRuntime, pythonRuntime, goRuntime)) {
 		mounts = append(mounts, generateDownloaderVolumeMountsForRuntime(javaRuntime, pythonRuntime, goRuntime)...)
 	}
+	mounts = append(mounts, generateJavaDependenciesVolumeMounts(javaRuntime)...)
 	if utils.GrpcurlPersistentVolumeClaim != "" {
 		mounts = append(mounts, corev1.VolumeMount{
 			Name:      GrpcVolume,
@@ -1517,11 +1839,11 @@ func getFunctionRunnerImage(spec *v1alpha1.FunctionSpec) string {
 	if img != "" {
 		return img
 	} else if runtime.Java != nil && runtime.Java.Jar != "" {
-		return Configs.RunnerImages.Java
+		return GetConfigs().RunnerImages.Java
 	} else if runtime.Python != nil && runtime.Python.Py != "" {
-		return Configs.RunnerImages.Python
+		return GetConfigs().RunnerImages.Python
 	} else if runtime.Golang != nil && runtime.Golang.Go != "" {
-		return Configs.RunnerImages.Go
+		return GetConfigs().RunnerImages.Go
 	}
 	return DefaultRunnerImage
 }
@@ -1533,7 +1855,7 @@ func getSinkRunnerImage(spec *v1alpha1.SinkSpec) string {
 	}
 	if spec.Runtime.Java.Jar != "" && spec.Runtime.Java.JarLocation != "" &&
 		hasPackageNamePrefix(spec.Runtime.Java.JarLocation) {
-		return Configs.RunnerImages.Java
+		return GetConfigs().RunnerImages.Java
 	}
 	return DefaultRunnerImage
 }
@@ -1545,7 +1867,7 @@ func getSourceRunnerImage(spec *v1alpha1.SourceSpec) string {
 	}
 	if spec.Runtime.Java.Jar != "" && spec.Runtime.Java.JarLocation != "" &&
 		hasPackageNamePrefix(spec.Runtime.Java.JarLocation) {
-		return Configs.RunnerImages.Java
+		return GetConfigs().RunnerImages.Java
 	}
 	return DefaultRunnerImage
 }
@@ -1628,6 +1950,30 @@ func CheckIfStatefulSetSpecIsEqual(spec *appsv1.StatefulSetSpec, desiredSpec *ap
 		return false
 	}
 
+	if spec.PodManagementPolicy != desiredSpec.PodManagementPolicy {
+		return false
+	}
+
+	// the checksums roll the pods when the pulsar connection or the referenced configs change
+	for _, annotation := range []string{AnnotationPulsarConnectionChecksum, AnnotationConfigChecksum,
+		AnnotationTriggerDiscovery} {
+		if spec.Template.Annotations[annotation] != desiredSpec.Template.Annotations[annotation] {
+			return false
+		}
+	}
+
+	// the downloader command changes with the package checksum and the package cache
+	if len(spec.Template.Spec.InitContainers) != len(desiredSpec.Template.Spec.InitContainers) {
+		return false
+	}
+	for i, initContainer := range spec.Template.Spec.InitContainers {
+		desiredInitContainer := desiredSpec.Template.Spec.InitContainers[i]
+		if initContainer.Name != desiredInitContainer.Name ||
+			!reflect.DeepEqual(initContainer.Command, desiredInitContainer.Command) {
+			return false
+		}
+	}
+
 	if len(spec.Template.Spec.Containers) != len(desiredSpec.Template.Spec.Containers) {
 		return false
 	}
@@ -24,8 +24,10 @@ import (
 	"github.com/streamnative/function-mesh/api/compute/v1alpha1"
 	"github.com/streamnative/function-mesh/utils"
 	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 )
 
 func TestGetDownloadCommand(t *testing.T) {
@@ -1010,3 +1012,85 @@ func TestGenerateContainerVolumeMounts(t *testing.T) {
 		})
 	}
 }
+
+func TestGenerateRetryDetails(t *testing.T) {
+	assert.Nil(t, generateRetryDetails(0, "", nil))
+	assert.Equal(t, int32(3), generateRetryDetails(3, "dlq", nil).MaxMessageRetries)
+	assert.Equal(t, "dlq", generateRetryDetails(3, "dlq", nil).DeadLetterTopic)
+
+	policy := &v1alpha1.RetryPolicy{
+		MaxMessageRetry:              5,
+		NegativeAckRedeliveryDelayMs: 2000,
+		DeadLetterTopic:              &v1alpha1.RetryTopic{Name: "policy-dlq", Provision: true},
+		RetryLetterTopic:             &v1alpha1.RetryTopic{Name: "policy-retry"},
+	}
+	details := generateRetryDetails(3, "dlq", policy)
+	assert.Equal(t, int32(5), details.MaxMessageRetries)
+	assert.Equal(t, "policy-dlq", details.DeadLetterTopic)
+	assert.Equal(t, uint64(2000), getNegativeAckRedeliveryDelayMs(1000, policy))
+	assert.Equal(t, uint64(1000), getNegativeAckRedeliveryDelayMs(1000, nil))
+
+	assert.Equal(t, []v1alpha1.RetryTopic{*policy.DeadLetterTopic}, MakeRetryTopics(policy))
+	assert.Empty(t, MakeRetryTopics(nil))
+}
+
+func TestIsManaged(t *testing.T) {
+	defer func() { ManagedSelector = labels.Everything() }()
+
+	object := makeSampleObjectMeta("test")
+	assert.True(t, IsManaged(object))
+	object.Annotations = map[string]string{AnnotationManaged: "false"}
+	assert.False(t, IsManaged(object))
+	object.Annotations[AnnotationManaged] = "true"
+	assert.True(t, IsManaged(object))
+
+	selector, err := labels.Parse("functionmesh.io/shard=a")
+	assert.Nil(t, err)
+	ManagedSelector = selector
+	assert.False(t, IsManaged(object))
+	object.Labels = map[string]string{"functionmesh.io/shard": "b"}
+	assert.False(t, IsManaged(object))
+	object.Labels["functionmesh.io/shard"] = "a"
+	assert.True(t, IsManaged(object))
+	object.Annotations[AnnotationManaged] = "false"
+	assert.False(t, IsManaged(object))
+}
+
+func TestMakeFunctionVersion(t *testing.T) {
+	assert.Equal(t, "v2", makeFunctionVersion("v2", testPackageSha256, 3))
+	// the version of a package with a checksum follows its content
+	version := makeFunctionVersion("", testPackageSha256, 3)
+	assert.Equal(t, testPackageSha256[:12], version)
+	assert.Equal(t, version, makeFunctionVersion("", strings.ToUpper(testPackageSha256), 4))
+	// the version of a package without a checksum follows the generation of the component
+	assert.Equal(t, "3", makeFunctionVersion("", "", 3))
+	assert.Equal(t, "4", makeFunctionVersion("", "", 4))
+}
+
+func TestMakeFunctionCommandWithInstanceArgs(t *testing.T) {
+	function := makeFunctionSample(TestFunctionName)
+	command := makeFunctionCommand(function)[2]
+	assert.Contains(t, command, "--max_buffered_tuples 100 ")
+	assert.Contains(t, command, "--function_version "+makeFunctionVersion("", "", function.Generation)+" ")
+
+	maxBufferedTuples := int32(1000)
+	function.Spec.MaxBufferedTuples = &maxBufferedTuples
+	function.Spec.FunctionVersion = "v2"
+	command = makeFunctionCommand(function)[2]
+	assert.Contains(t, command, "--max_buffered_tuples 1000 ")
+	assert.Contains(t, command, "--function_version v2 ")
+
+	goFunction := makeGoFunctionSample(TestFunctionName)
+	goFunction.Spec.MaxBufferedTuples = &maxBufferedTuples
+	goFunction.Spec.FunctionVersion = "v2"
+	conf := convertGoFunctionConfs(goFunction)
+	assert.Equal(t, 1000, conf.MaxBufTuples)
+	assert.Equal(t, "v2", conf.FuncVersion)
+}
+
+func TestMakeStatefulSetPodManagementPolicy(t *testing.T) {
+	function := makeFunctionSample(TestFunctionName)
+	assert.Equal(t, appsv1.ParallelPodManagement, MakeFunctionStatefulSet(function).Spec.PodManagementPolicy)
+	function.Spec.Pod.PodManagementPolicy = appsv1.OrderedReadyPodManagement
+	assert.Equal(t, appsv1.OrderedReadyPodManagement, MakeFunctionStatefulSet(function).Spec.PodManagementPolicy)
+}
@@ -0,0 +1,164 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package spec
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/streamnative/function-mesh/api/compute/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ConfigReferences are the names of the config maps and secrets used by the pods of a component
+type ConfigReferences struct {
+	ConfigMaps []string
+	Secrets    []string
+}
+
+// GetConfigReferences collects the config maps and secrets a pod template loads as volumes or env
+func GetConfigReferences(template *corev1.PodTemplateSpec) ConfigReferences {
+	configMaps := map[string]bool{}
+	secrets := map[string]bool{}
+	for _, volume := range template.Spec.Volumes {
+		if volume.ConfigMap != nil {
+			configMaps[volume.ConfigMap.Name] = true
+		}
+		if volume.Secret != nil {
+			secrets[volume.Secret.SecretName] = true
+		}
+		if volume.Projected != nil {
+			for _, source := range volume.Projected.Sources {
+				if source.ConfigMap != nil {
+					configMaps[source.ConfigMap.Name] = true
+				}
+				if source.Secret != nil {
+					secrets[source.Secret.Name] = true
+				}
+			}
+		}
+	}
+	containers := append([]corev1.Container{}, template.Spec.InitContainers...)
+	containers = append(containers, template.Spec.Containers...)
+	for _, container := range containers {
+		for _, envFrom := range container.EnvFrom {
+			if envFrom.ConfigMapRef != nil {
+				configMaps[envFrom.ConfigMapRef.Name] = true
+			}
+			if envFrom.SecretRef != nil {
+				secrets[envFrom.SecretRef.Name] = true
+			}
+		}
+		for _, env := range container.Env {
+			if env.ValueFrom == nil {
+				continue
+			}
+			if env.ValueFrom.ConfigMapKeyRef != nil {
+				configMaps[env.ValueFrom.ConfigMapKeyRef.Name] = true
+			}
+			if env.ValueFrom.SecretKeyRef != nil {
+				secrets[env.ValueFrom.SecretKeyRef.Name] = true
+			}
+		}
+	}
+	return ConfigReferences{
+		ConfigMaps: sortedKeys(configMaps),
+		Secrets:    sortedKeys(secrets),
+	}
+}
+
+func sortedKeys(set map[string]bool) []string {
+	keys := []string{}
+	for key := range set {
+		if key != "" {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// MakeFunctionConfigReferences returns the config maps and secrets referenced by the function itself,
+// the ones of its PulsarConnection are not included
+func MakeFunctionConfigReferences(function *v1alpha1.Function) ConfigReferences {
+	function = function.DeepCopy()
+	if function.Spec.Pulsar == nil {
+		function.Spec.Pulsar = &v1alpha1.PulsarMessaging{}
+	}
+	return GetConfigReferences(&MakeFunctionStatefulSet(function).Spec.Template)
+}
+
+// MakeSourceConfigReferences returns the config maps and secrets referenced by the source itself,
+// the ones of its PulsarConnection are not included
+func MakeSourceConfigReferences(source *v1alpha1.Source) ConfigReferences {
+	source = source.DeepCopy()
+	if source.Spec.Pulsar == nil {
+		source.Spec.Pulsar = &v1alpha1.PulsarMessaging{}
+	}
+	return GetConfigReferences(&MakeSourceStatefulSet(source).Spec.Template)
+}
+
+// MakeSinkConfigReferences returns the config maps and secrets referenced by the sink itself,
+// the ones of its PulsarConnection are not included
+func MakeSinkConfigReferences(sink *v1alpha1.Sink) ConfigReferences {
+	sink = sink.DeepCopy()
+	if sink.Spec.Pulsar == nil {
+		sink.Spec.Pulsar = &v1alpha1.PulsarMessaging{}
+	}
+	return GetConfigReferences(&MakeSinkStatefulSet(sink).Spec.Template)
+}
+
+// IsRestartOnConfigChangeEnabled returns false when the object opts out of the rolling restart on config changes
+func IsRestartOnConfigChangeEnabled(object metav1.Object) bool {
+	return object.GetAnnotations()[AnnotationRestartOnConfigChange] != "false"
+}
+
+// MakeConfigChecksum returns the checksum of the contents of config maps and secrets, a missing object
+// is passed as nil so that its creation changes the checksum as well
+func MakeConfigChecksum(configMaps map[string]*corev1.ConfigMap, secrets map[string]*corev1.Secret) string {
+	lines := []string{}
+	for name, configMap := range configMaps {
+		lines = append(lines, fmt.Sprintf("configmap/%s", name))
+		if configMap == nil {
+			continue
+		}
+		for key, value := range configMap.Data {
+			lines = append(lines, fmt.Sprintf("configmap/%s/%s=%s", name, key, value))
+		}
+		for key, value := range configMap.BinaryData {
+			lines = append(lines, fmt.Sprintf("configmap/%s/%s=%x", name, key, value))
+		}
+	}
+	for name, secret := range secrets {
+		lines = append(lines, fmt.Sprintf("secret/%s", name))
+		if secret == nil {
+			continue
+		}
+		for key, value := range secret.Data {
+			lines = append(lines, fmt.Sprintf("secret/%s/%s=%x", name, key, value))
+		}
+		for key, value := range secret.StringData {
+			lines = append(lines, fmt.Sprintf("secret/%s/%s=%x", name, key, value))
+		}
+	}
+	sort.Strings(lines)
+	return fmt.Sprintf("%x", sha256.Sum256([]byte(strings.Join(lines, "\n"))))
+}
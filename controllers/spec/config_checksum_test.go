@@ -0,0 +1,86 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package spec
+
+import (
+	"testing"
+
+	"github.com/streamnative/function-mesh/api/compute/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestMakeFunctionConfigReferences(t *testing.T) {
+	function := makeFunctionSample("config-function")
+	function.Spec.Pulsar.AuthSecret = "pulsar-auth"
+	function.Spec.SecretsMap = map[string]v1alpha1.SecretRef{
+		"password": {Path: "db-secret", Key: "password"},
+	}
+	function.Spec.Java.Log = &v1alpha1.RuntimeLogConfig{
+		LogConfig: &v1alpha1.LogConfig{Name: "java-log", Key: "log4j.xml"},
+	}
+	function.Spec.Output.ProducerConf = &v1alpha1.ProducerConfig{
+		CryptoConfig: &v1alpha1.CryptoConfig{
+			CryptoSecrets: []v1alpha1.CryptoSecret{{SecretName: "crypto", SecretKey: "key.pem"}},
+		},
+	}
+	function.Spec.Pod.Volumes = []corev1.Volume{{
+		Name:         "extra",
+		VolumeSource: corev1.VolumeSource{ConfigMap: &corev1.ConfigMapVolumeSource{LocalObjectReference: corev1.LocalObjectReference{Name: "extra-config"}}},
+	}}
+
+	references := MakeFunctionConfigReferences(function)
+	assert.Equal(t, []string{"extra-config", "java-log", TestClusterName}, references.ConfigMaps)
+	assert.Equal(t, []string{"crypto", "db-secret", "pulsar-auth"}, references.Secrets)
+
+	// the components falling back to a PulsarConnection only reference their own configs
+	function.Spec.Pulsar = nil
+	references = MakeFunctionConfigReferences(function)
+	assert.Equal(t, []string{"extra-config", "java-log"}, references.ConfigMaps)
+}
+
+func TestMakeConfigChecksum(t *testing.T) {
+	configMaps := map[string]*corev1.ConfigMap{
+		"pulsar": {Data: map[string]string{"webServiceURL": "http://pulsar:8080"}},
+	}
+	secrets := map[string]*corev1.Secret{
+		"auth": {Data: map[string][]byte{"token": []byte("a")}},
+	}
+	checksum := MakeConfigChecksum(configMaps, secrets)
+	assert.Equal(t, checksum, MakeConfigChecksum(configMaps, secrets))
+
+	secrets["auth"] = &corev1.Secret{StringData: map[string]string{"token": "a"}}
+	assert.Equal(t, checksum, MakeConfigChecksum(configMaps, secrets))
+
+	secrets["auth"] = &corev1.Secret{Data: map[string][]byte{"token": []byte("b")}}
+	rotated := MakeConfigChecksum(configMaps, secrets)
+	assert.NotEqual(t, checksum, rotated)
+
+	secrets["auth"] = nil
+	assert.NotEqual(t, rotated, MakeConfigChecksum(configMaps, secrets))
+}
+
+func TestCheckIfStatefulSetSpecIsEqualWithChecksum(t *testing.T) {
+	function := makeFunctionSample("checksum-function")
+	statefulSet := MakeFunctionStatefulSet(function)
+	desired := MakeFunctionStatefulSet(function)
+	assert.True(t, CheckIfStatefulSetSpecIsEqual(&statefulSet.Spec, &desired.Spec))
+
+	desired.Spec.Template.Annotations[AnnotationConfigChecksum] = "changed"
+	assert.False(t, CheckIfStatefulSetSpecIsEqual(&statefulSet.Spec, &desired.Spec))
+}
@@ -18,8 +18,16 @@
 package spec
 
 import (
+	"fmt"
 	"io/ioutil"
+	"reflect"
+	"strings"
+	"sync"
 
+	"github.com/streamnative/function-mesh/api/compute/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"sigs.k8s.io/yaml"
 )
 
@@ -29,13 +37,36 @@ type RunnerImages struct {
 	Go     string `yaml:"go,omitempty"`
 }
 
+// ComponentDefaults are applied to the functions, sources and sinks that leave the corresponding
+// fields unset, the values of a component always win
+type ComponentDefaults struct {
+	DownloaderImage    string                        `yaml:"downloaderImage,omitempty"`
+	OCIDownloaderImage string                        `yaml:"ociDownloaderImage,omitempty"`
+	StateSnapshotImage string                        `yaml:"stateSnapshotImage,omitempty"`
+	Resources          *corev1.ResourceRequirements  `yaml:"resources,omitempty"`
+	PodSecurityContext *corev1.PodSecurityContext    `yaml:"podSecurityContext,omitempty"`
+	Tolerations        []corev1.Toleration           `yaml:"tolerations,omitempty"`
+	NodeSelector       map[string]string             `yaml:"nodeSelector,omitempty"`
+	ImagePullSecrets   []corev1.LocalObjectReference `yaml:"imagePullSecrets,omitempty"`
+	Messaging          v1alpha1.Messaging            `yaml:"messaging,omitempty"`
+	PackageCache       *PackageCacheConfig           `yaml:"packageCache,omitempty"`
+}
+
 type ControllerConfigs struct {
 	RunnerImages        RunnerImages      `yaml:"runnerImages,omitempty"`
 	ResourceLabels      map[string]string `yaml:"resourceLabels,omitempty"`
 	ResourceAnnotations map[string]string `yaml:"resourceAnnotations,omitempty"`
+
+	ComponentDefaults `yaml:",inline"`
+
+	// Namespaces overrides the component defaults for the components in the given namespaces
+	Namespaces map[string]ComponentDefaults `yaml:"namespaces,omitempty"`
 }
 
-var Configs = DefaultConfigs()
+var (
+	Configs     = DefaultConfigs()
+	configsLock sync.RWMutex
+)
 
 func DefaultConfigs() *ControllerConfigs {
 	return &ControllerConfigs{
@@ -47,6 +78,20 @@ func DefaultConfigs() *ControllerConfigs {
 	}
 }
 
+// GetConfigs returns the controller configs in use, the returned configs must not be modified
+func GetConfigs() *ControllerConfigs {
+	configsLock.RLock()
+	defer configsLock.RUnlock()
+	return Configs
+}
+
+// SetConfigs replaces the controller configs in use
+func SetConfigs(configs *ControllerConfigs) {
+	configsLock.Lock()
+	defer configsLock.Unlock()
+	Configs = configs
+}
+
 func ParseControllerConfigs(configFilePath string) error {
 	yamlFile, err := ioutil.ReadFile(configFilePath)
 	if err != nil {
@@ -55,10 +100,232 @@ func ParseControllerConfigs(configFilePath string) error {
 	if len(yamlFile) == 0 {
 		return nil
 	}
-	err = yaml.Unmarshal(yamlFile, Configs)
+	configs, err := LoadControllerConfigs(yamlFile)
 	if err != nil {
 		return err
 	}
-
+	SetConfigs(configs)
 	return nil
 }
+
+// LoadControllerConfigs parses and validates the controller configs, the unset fields keep their defaults
+func LoadControllerConfigs(data []byte) (*ControllerConfigs, error) {
+	configs := DefaultConfigs()
+	if err := yaml.UnmarshalStrict(data, configs); err != nil {
+		return nil, err
+	}
+	if err := configs.Validate(); err != nil {
+		return nil, err
+	}
+	return configs, nil
+}
+
+// Validate checks the controller configs, all the problems found are reported at once
+func (c *ControllerConfigs) Validate() error {
+	var errs []error
+	if c.RunnerImages.Java == "" || c.RunnerImages.Python == "" || c.RunnerImages.Go == "" {
+		errs = append(errs, fmt.Errorf("runnerImages: the runner images cannot be empty"))
+	}
+	for key := range c.ResourceLabels {
+		for _, msg := range validation.IsQualifiedName(key) {
+			errs = append(errs, fmt.Errorf("resourceLabels: invalid key %q: %s", key, msg))
+		}
+	}
+	for key, value := range c.ResourceLabels {
+		for _, msg := range validation.IsValidLabelValue(value) {
+			errs = append(errs, fmt.Errorf("resourceLabels: invalid value %q of %q: %s", value, key, msg))
+		}
+	}
+	for key := range c.ResourceAnnotations {
+		for _, msg := range validation.IsQualifiedName(strings.ToLower(key)) {
+			errs = append(errs, fmt.Errorf("resourceAnnotations: invalid key %q: %s", key, msg))
+		}
+	}
+	errs = append(errs, c.ComponentDefaults.validate("")...)
+	for namespace, defaults := range c.Namespaces {
+		for _, msg := range validation.IsDNS1123Label(namespace) {
+			errs = append(errs, fmt.Errorf("namespaces: invalid namespace %q: %s", namespace, msg))
+		}
+		errs = append(errs, defaults.validate(fmt.Sprintf("namespaces[%s].", namespace))...)
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
+func (d *ComponentDefaults) validate(prefix string) []error {
+	var errs []error
+	if strings.ContainsAny(d.DownloaderImage, " \t\n") {
+		errs = append(errs, fmt.Errorf("%sdownloaderImage: invalid image %q", prefix, d.DownloaderImage))
+	}
+	if strings.ContainsAny(d.OCIDownloaderImage, " \t\n") {
+		errs = append(errs, fmt.Errorf("%sociDownloaderImage: invalid image %q", prefix, d.OCIDownloaderImage))
+	}
+	if strings.ContainsAny(d.StateSnapshotImage, " \t\n") {
+		errs = append(errs, fmt.Errorf("%sstateSnapshotImage: invalid image %q", prefix, d.StateSnapshotImage))
+	}
+	if d.Resources != nil {
+		for name, request := range d.Resources.Requests {
+			if limit, ok := d.Resources.Limits[name]; ok && request.Cmp(limit) > 0 {
+				errs = append(errs, fmt.Errorf("%sresources: the %s request %s exceeds the limit %s",
+					prefix, name, request.String(), limit.String()))
+			}
+		}
+	}
+	for i, toleration := range d.Tolerations {
+		switch toleration.Operator {
+		case "", corev1.TolerationOpEqual:
+		case corev1.TolerationOpExists:
+			if toleration.Value != "" {
+				errs = append(errs, fmt.Errorf("%stolerations[%d]: the value must be empty with operator Exists",
+					prefix, i))
+			}
+		default:
+			errs = append(errs, fmt.Errorf("%stolerations[%d]: unsupported operator %q", prefix, i,
+				toleration.Operator))
+		}
+	}
+	for key := range d.NodeSelector {
+		for _, msg := range validation.IsQualifiedName(key) {
+			errs = append(errs, fmt.Errorf("%snodeSelector: invalid key %q: %s", prefix, key, msg))
+		}
+	}
+	for i, secret := range d.ImagePullSecrets {
+		if secret.Name == "" {
+			errs = append(errs, fmt.Errorf("%simagePullSecrets[%d]: the name cannot be empty", prefix, i))
+		}
+	}
+	if d.Messaging.Pulsar != nil && d.Messaging.Pulsar.PulsarConfig == "" {
+		errs = append(errs, fmt.Errorf("%smessaging: pulsarConfig cannot be empty", prefix))
+	}
+	errs = append(errs, d.PackageCache.validate(prefix)...)
+	return errs
+}
+
+// DefaultsFor returns the component defaults of a namespace, the namespace overrides win over the
+// cluster wide defaults
+func (c *ControllerConfigs) DefaultsFor(namespace string) ComponentDefaults {
+	defaults := *c.ComponentDefaults.DeepCopy()
+	override, ok := c.Namespaces[namespace]
+	if !ok {
+		return defaults
+	}
+	override = *override.DeepCopy()
+	defaults.DownloaderImage = mergeString(override.DownloaderImage, defaults.DownloaderImage)
+	defaults.OCIDownloaderImage = mergeString(override.OCIDownloaderImage, defaults.OCIDownloaderImage)
+	defaults.StateSnapshotImage = mergeString(override.StateSnapshotImage, defaults.StateSnapshotImage)
+	if override.Resources != nil {
+		defaults.Resources = override.Resources
+	}
+	if override.PodSecurityContext != nil {
+		defaults.PodSecurityContext = override.PodSecurityContext
+	}
+	defaults.Tolerations = mergeByKey(defaults.Tolerations, override.Tolerations, tolerationKey)
+	defaults.NodeSelector = mergeLabels(defaults.NodeSelector, override.NodeSelector)
+	if len(defaults.NodeSelector) == 0 {
+		defaults.NodeSelector = nil
+	}
+	defaults.ImagePullSecrets = mergeByKey(defaults.ImagePullSecrets, override.ImagePullSecrets,
+		localObjectReferenceKey)
+	defaults.Messaging = mergeMessaging(override.Messaging, defaults.Messaging)
+	if override.PackageCache != nil {
+		defaults.PackageCache = override.PackageCache
+	}
+	return defaults
+}
+
+// ChangedNamespaces compares the configs with the previous ones, it returns whether the change affects
+// all namespaces, otherwise the namespaces with changed overrides are returned
+func (c *ControllerConfigs) ChangedNamespaces(previous *ControllerConfigs) (bool, []string) {
+	current, old := *c, *previous
+	current.Namespaces, old.Namespaces = nil, nil
+	if !reflect.DeepEqual(current, old) {
+		return true, nil
+	}
+	var namespaces []string
+	for namespace, defaults := range c.Namespaces {
+		if !reflect.DeepEqual(defaults, previous.Namespaces[namespace]) {
+			namespaces = append(namespaces, namespace)
+		}
+	}
+	for namespace := range previous.Namespaces {
+		if _, ok := c.Namespaces[namespace]; !ok {
+			namespaces = append(namespaces, namespace)
+		}
+	}
+	return false, namespaces
+}
+
+// DeepCopy returns a deep copy of the component defaults
+func (d *ComponentDefaults) DeepCopy() *ComponentDefaults {
+	out := *d
+	if d.Resources != nil {
+		out.Resources = d.Resources.DeepCopy()
+	}
+	if d.PodSecurityContext != nil {
+		out.PodSecurityContext = d.PodSecurityContext.DeepCopy()
+	}
+	if d.Tolerations != nil {
+		out.Tolerations = make([]corev1.Toleration, len(d.Tolerations))
+		for i := range d.Tolerations {
+			d.Tolerations[i].DeepCopyInto(&out.Tolerations[i])
+		}
+	}
+	if d.NodeSelector != nil {
+		out.NodeSelector = mergeLabels(d.NodeSelector)
+	}
+	if d.ImagePullSecrets != nil {
+		out.ImagePullSecrets = append([]corev1.LocalObjectReference{}, d.ImagePullSecrets...)
+	}
+	d.Messaging.DeepCopyInto(&out.Messaging)
+	if d.PackageCache != nil {
+		packageCache := *d.PackageCache
+		out.PackageCache = &packageCache
+	}
+	return &out
+}
+
+// MakePulsarMessaging returns the pulsar messaging config of a component with the controller defaults applied
+func MakePulsarMessaging(namespace string, messaging v1alpha1.Messaging) *v1alpha1.PulsarMessaging {
+	defaults := GetConfigs().DefaultsFor(namespace)
+	return mergeMessaging(*messaging.DeepCopy(), defaults.Messaging).Pulsar
+}
+
+// applyDefaults fills the unset fields of a component with the defaults, the arguments are modified in place
+func (d *ComponentDefaults) applyDefaults(downloaderImage *string, resources *corev1.ResourceRequirements,
+	policy *v1alpha1.PodPolicy, messaging *v1alpha1.Messaging) {
+	*downloaderImage = mergeString(*downloaderImage, d.DownloaderImage)
+	if len(resources.Requests) == 0 && len(resources.Limits) == 0 && d.Resources != nil {
+		*resources = *d.Resources
+	}
+	if policy.SecurityContext == nil {
+		policy.SecurityContext = d.PodSecurityContext
+	}
+	policy.Tolerations = mergeByKey(d.Tolerations, policy.Tolerations, tolerationKey)
+	if len(d.NodeSelector) > 0 {
+		policy.NodeSelector = mergeLabels(d.NodeSelector, policy.NodeSelector)
+	}
+	policy.ImagePullSecrets = mergeByKey(d.ImagePullSecrets, policy.ImagePullSecrets, localObjectReferenceKey)
+	*messaging = mergeMessaging(*messaging, d.Messaging)
+}
+
+func withFunctionDefaults(function *v1alpha1.Function) *v1alpha1.Function {
+	function = function.DeepCopy()
+	defaults := GetConfigs().DefaultsFor(function.Namespace)
+	defaults.applyDefaults(&function.Spec.DownloaderImage, &function.Spec.Resources, &function.Spec.Pod,
+		&function.Spec.Messaging)
+	return function
+}
+
+func withSourceDefaults(source *v1alpha1.Source) *v1alpha1.Source {
+	source = source.DeepCopy()
+	defaults := GetConfigs().DefaultsFor(source.Namespace)
+	defaults.applyDefaults(&source.Spec.DownloaderImage, &source.Spec.Resources, &source.Spec.Pod,
+		&source.Spec.Messaging)
+	return source
+}
+
+func withSinkDefaults(sink *v1alpha1.Sink) *v1alpha1.Sink {
+	sink = sink.DeepCopy()
+	defaults := GetConfigs().DefaultsFor(sink.Namespace)
+	defaults.applyDefaults(&sink.Spec.DownloaderImage, &sink.Spec.Resources, &sink.Spec.Pod, &sink.Spec.Messaging)
+	return sink
+}
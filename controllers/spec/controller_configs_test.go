@@ -21,6 +21,7 @@ import (
 	"testing"
 
 	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
 )
 
 func TestParseConfigFiles(t *testing.T) {
@@ -54,3 +55,108 @@ func TestParseEmptyConfigFiles(t *testing.T) {
 	assert.Assert(t, len(Configs.ResourceLabels) == 0)
 	assert.Assert(t, len(Configs.ResourceAnnotations) == 0)
 }
+
+func TestParseConfigFilesWithDefaults(t *testing.T) {
+	defer SetConfigs(DefaultConfigs())
+	Configs = DefaultConfigs()
+	err := ParseControllerConfigs("../../testdata/controller_configs_defaults.yaml")
+	if err != nil {
+		t.Errorf("ParseControllerConfigs failed: %v", err)
+	}
+	configs := GetConfigs()
+	assert.Assert(t, configs.DownloaderImage == "streamnative/pulsarctl:latest")
+	assert.Assert(t, configs.Resources.Requests.Cpu().String() == "100m")
+	assert.Assert(t, *configs.PodSecurityContext.RunAsUser == 10000)
+	assert.Assert(t, len(configs.Tolerations) == 1)
+	assert.Assert(t, configs.Messaging.Pulsar.PulsarConfig == "default-pulsar")
+
+	defaults := configs.DefaultsFor("default")
+	assert.Assert(t, defaults.DownloaderImage == "streamnative/pulsarctl:latest")
+	assert.Assert(t, defaults.NodeSelector["pool"] == "functions")
+	assert.Assert(t, len(defaults.ImagePullSecrets) == 1)
+
+	defaults = configs.DefaultsFor("team-a")
+	assert.Assert(t, defaults.DownloaderImage == "registry.example.com/pulsarctl:latest")
+	assert.Assert(t, defaults.NodeSelector["pool"] == "team-a")
+	assert.Assert(t, defaults.Resources.Limits.Memory().String() == "512Mi")
+	assert.Assert(t, len(defaults.ImagePullSecrets) == 2)
+	assert.Assert(t, defaults.Messaging.Pulsar.PulsarConfig == "team-a-pulsar")
+	assert.Assert(t, defaults.Messaging.Pulsar.AuthSecret == "default-auth")
+	assert.Assert(t, configs.Namespaces["team-a"].Messaging.Pulsar.AuthSecret == "")
+}
+
+func TestLoadInvalidControllerConfigs(t *testing.T) {
+	invalid := map[string]string{
+		"unknown field":     "runnerImage: foo",
+		"empty runner":      "runnerImages:\n  java: \"\"",
+		"invalid label":     "resourceLabels:\n  \"foo bar\": baz",
+		"request > limit":   "resources:\n  requests:\n    cpu: 2\n  limits:\n    cpu: 1",
+		"toleration":        "tolerations:\n  - key: foo\n    operator: Exists\n    value: bar",
+		"pull secret":       "imagePullSecrets:\n  - name: \"\"",
+		"messaging":         "messaging:\n  pulsar:\n    authSecret: auth",
+		"namespace name":    "namespaces:\n  Team_A:\n    downloaderImage: foo",
+		"namespace default": "namespaces:\n  team-a:\n    downloaderImage: \"foo bar\"",
+	}
+	for name, data := range invalid {
+		_, err := LoadControllerConfigs([]byte(data))
+		assert.Assert(t, err != nil, name)
+	}
+
+	configs, err := LoadControllerConfigs([]byte("downloaderImage: foo"))
+	assert.NilError(t, err)
+	assert.Assert(t, configs.RunnerImages.Java == DefaultJavaRunnerImage)
+}
+
+func TestChangedNamespaces(t *testing.T) {
+	previous, err := LoadControllerConfigs([]byte("namespaces:\n  a:\n    downloaderImage: foo\n  b:\n    downloaderImage: foo"))
+	assert.NilError(t, err)
+	current, err := LoadControllerConfigs([]byte("namespaces:\n  a:\n    downloaderImage: bar\n  c:\n    downloaderImage: foo"))
+	assert.NilError(t, err)
+	all, namespaces := current.ChangedNamespaces(previous)
+	assert.Assert(t, !all)
+	assert.DeepEqual(t, map[string]bool{"a": true, "b": true, "c": true}, toSet(namespaces))
+
+	current, err = LoadControllerConfigs([]byte("downloaderImage: foo"))
+	assert.NilError(t, err)
+	all, _ = current.ChangedNamespaces(previous)
+	assert.Assert(t, all)
+}
+
+func TestMakeStatefulSetWithControllerDefaults(t *testing.T) {
+	defer SetConfigs(DefaultConfigs())
+	err := ParseControllerConfigs("../../testdata/controller_configs_defaults.yaml")
+	assert.NilError(t, err)
+
+	function := makeFunctionSample("defaults-function")
+	function.Spec.Pulsar = nil
+	function.Spec.Pod.NodeSelector = map[string]string{"zone": "a"}
+	statefulSet := MakeFunctionStatefulSet(function)
+	pod := statefulSet.Spec.Template.Spec
+	assert.Assert(t, pod.InitContainers[0].Image == "streamnative/pulsarctl:latest")
+	assert.DeepEqual(t, map[string]string{"pool": "functions", "zone": "a"}, pod.NodeSelector)
+	assert.Assert(t, len(pod.Tolerations) == 1)
+	assert.Assert(t, *pod.SecurityContext.RunAsUser == 10000)
+	assert.DeepEqual(t, []corev1.LocalObjectReference{{Name: "registry"}}, pod.ImagePullSecrets)
+	container := pod.Containers[len(pod.Containers)-1]
+	assert.Assert(t, container.Resources.Limits.Cpu().String() == "200m")
+	assert.Assert(t, container.EnvFrom[0].ConfigMapRef.Name == "default-pulsar")
+	assert.Assert(t, function.Spec.Pulsar == nil)
+	assert.Assert(t, len(function.Spec.Pod.Tolerations) == 0)
+
+	// the values of the function win over the controller defaults
+	function = makeFunctionSample("defaults-function")
+	function.Spec.DownloaderImage = "pulsarctl:custom"
+	statefulSet = MakeFunctionStatefulSet(function)
+	pod = statefulSet.Spec.Template.Spec
+	assert.Assert(t, pod.InitContainers[0].Image == "pulsarctl:custom")
+	container = pod.Containers[len(pod.Containers)-1]
+	assert.Assert(t, container.EnvFrom[0].ConfigMapRef.Name == TestClusterName)
+}
+
+func toSet(values []string) map[string]bool {
+	set := map[string]bool{}
+	for _, v := range values {
+		set[v] = true
+	}
+	return set
+}
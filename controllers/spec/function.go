@@ -54,8 +54,12 @@ func MakeFunctionService(function *v1alpha1.Function) *corev1.Service {
 }
 
 func MakeFunctionStatefulSet(function *v1alpha1.Function) *appsv1.StatefulSet {
+	function = withFunctionDefaults(function)
+	if container := makePythonDependenciesContainer(function); container != nil {
+		function.Spec.Pod.InitContainers = append(function.Spec.Pod.InitContainers, *container)
+	}
 	objectMeta := MakeFunctionObjectMeta(function)
-	return MakeStatefulSet(objectMeta, function.Spec.Replicas, function.Spec.DownloaderImage,
+	return MakeStatefulSet(objectMeta, makeReplicas(function.Spec.Replicas, function.Spec.Paused), function.Spec.DownloaderImage,
 		MakeFunctionContainer(function), makeFunctionVolumes(function), makeFunctionLabels(function), function.Spec.Pod,
 		*function.Spec.Pulsar, function.Spec.Java, function.Spec.Python, function.Spec.Golang,
 		function.Spec.VolumeMounts)
@@ -73,16 +77,18 @@ func MakeFunctionObjectMeta(function *v1alpha1.Function) *metav1.ObjectMeta {
 }
 
 func makeFunctionVolumes(function *v1alpha1.Function) []corev1.Volume {
-	return generatePodVolumes(function.Spec.Pod.Volumes,
+	volumes := generatePodVolumes(function.Spec.Pod.Volumes,
 		function.Spec.Output.ProducerConf,
 		function.Spec.Input.SourceSpecs,
 		function.Spec.Pulsar.TLSConfig,
 		function.Spec.Pulsar.AuthConfig,
 		getRuntimeLogConfigNames(function.Spec.Java, function.Spec.Python, function.Spec.Golang))
+	volumes = append(volumes, generatePythonDependenciesVolumes(function.Spec.Python)...)
+	return append(volumes, generateStateStoreVolumes(function.Spec.StateConfig)...)
 }
 
 func makeFunctionVolumeMounts(function *v1alpha1.Function) []corev1.VolumeMount {
-	return generateContainerVolumeMounts(function.Spec.VolumeMounts,
+	mounts := generateContainerVolumeMounts(function.Spec.VolumeMounts,
 		function.Spec.Output.ProducerConf,
 		function.Spec.Input.SourceSpecs,
 		function.Spec.Pulsar.TLSConfig,
@@ -91,6 +97,8 @@ func makeFunctionVolumeMounts(function *v1alpha1.Function) []corev1.VolumeMount
 		function.Spec.Java,
 		function.Spec.Python,
 		function.Spec.Golang)
+	mounts = append(mounts, generatePythonDependenciesVolumeMounts(function.Spec.Python)...)
+	return append(mounts, generateStateStoreVolumeMounts(function.Spec.StateConfig)...)
 }
 
 func MakeFunctionContainer(function *v1alpha1.Function) *corev1.Container {
@@ -149,23 +157,26 @@ func makeFunctionCommand(function *v1alpha1.Function) []string {
 
 	if spec.Java != nil {
 		if spec.Java.Jar != "" {
-			return MakeJavaFunctionCommand(spec.Java.JarLocation, spec.Java.Jar,
+			return MakeJavaFunctionCommand(spec.Java.JarLocation, spec.Java.Jar, spec.Java.Sha256, isPackageCached(function.Namespace),
 				spec.Name, spec.ClusterName,
 				generateJavaLogConfigCommand(function.Spec.Java),
 				parseJavaLogLevel(function.Spec.Java),
 				generateFunctionDetailsInJSON(function),
-				getDecimalSIMemory(spec.Resources.Requests.Memory()), spec.Java.ExtraDependenciesDir,
-				string(function.UID),
+				makeJVMOptions(spec.Name, spec.Java, spec.Resources), makeJavaClasspath(spec.Java),
+				string(function.UID), makeFunctionVersion(spec.FunctionVersion, spec.Java.Sha256, function.Generation),
+				getInt32FromPtrOrDefault(spec.MaxBufferedTuples, DefaultMaxBufferedTuples),
 				spec.Java.JavaOpts, spec.Pulsar.AuthSecret != "", spec.Pulsar.TLSSecret != "", function.Spec.SecretsMap,
 				function.Spec.StateConfig, function.Spec.Pulsar.TLSConfig, function.Spec.Pulsar.AuthConfig, healthCheckInterval,
 				function.Spec.MaxPendingAsyncRequests)
 		}
 	} else if spec.Python != nil {
 		if spec.Python.Py != "" {
-			return MakePythonFunctionCommand(spec.Python.PyLocation, spec.Python.Py,
-				spec.Name, spec.ClusterName,
+			return MakePythonFunctionCommand(spec.Python.PyLocation, spec.Python.Py, spec.Python.Sha256,
+				isPackageCached(function.Namespace), spec.Name, spec.ClusterName, getPythonDependencyArgs(spec.Python),
 				generatePythonLogConfigCommand(function.Name, function.Spec.Python),
 				generateFunctionDetailsInJSON(function), string(function.UID),
+				makeFunctionVersion(spec.FunctionVersion, spec.Python.Sha256, function.Generation),
+				getInt32FromPtrOrDefault(spec.MaxBufferedTuples, DefaultMaxBufferedTuples),
 				spec.Pulsar.AuthSecret != "", spec.Pulsar.TLSSecret != "", function.Spec.SecretsMap,
 				function.Spec.StateConfig, function.Spec.Pulsar.TLSConfig, function.Spec.Pulsar.AuthConfig, healthCheckInterval)
 		}
@@ -18,13 +18,19 @@
 package spec
 
 import (
+	"fmt"
+	"sort"
+	"strings"
+
 	"github.com/streamnative/function-mesh/api/compute/v1alpha1"
+	pctlutil "github.com/streamnative/pulsarctl/pkg/pulsar/utils"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 func MakeFunctionComponent(functionName string, mesh *v1alpha1.FunctionMesh,
 	spec *v1alpha1.FunctionSpec) *v1alpha1.Function {
-	return &v1alpha1.Function{
+	function := &v1alpha1.Function{
 		TypeMeta: metav1.TypeMeta{
 			APIVersion: "compute.functionmesh.io/v1alpha1",
 			Kind:       "Function",
@@ -32,16 +38,23 @@ func MakeFunctionComponent(functionName string, mesh *v1alpha1.FunctionMesh,
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      functionName,
 			Namespace: mesh.Namespace,
+			Labels:    makeComponentLabels(mesh),
 			OwnerReferences: []metav1.OwnerReference{
 				*metav1.NewControllerRef(mesh, mesh.GroupVersionKind()),
 			},
 		},
-		Spec: *spec,
+		Spec: *spec.DeepCopy(),
 	}
+	applyMeshDefaults(mesh.Spec.Defaults, &function.Spec.Tenant, &function.Spec.Namespace, &function.Spec.ClusterName,
+		&function.Spec.Image, &function.Spec.ImagePullPolicy, &function.Spec.Messaging, &function.Spec.Pod,
+		&function.Spec.StateConfig)
+	// pausing the mesh pauses all its components
+	function.Spec.Paused = function.Spec.Paused || mesh.Spec.Paused
+	return function
 }
 
 func MakeSourceComponent(sourceName string, mesh *v1alpha1.FunctionMesh, spec *v1alpha1.SourceSpec) *v1alpha1.Source {
-	return &v1alpha1.Source{
+	source := &v1alpha1.Source{
 		TypeMeta: metav1.TypeMeta{
 			APIVersion: "compute.functionmesh.io/v1alpha1",
 			Kind:       "Source",
@@ -49,16 +62,23 @@ func MakeSourceComponent(sourceName string, mesh *v1alpha1.FunctionMesh, spec *v
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      sourceName,
 			Namespace: mesh.Namespace,
+			Labels:    makeComponentLabels(mesh),
 			OwnerReferences: []metav1.OwnerReference{
 				*metav1.NewControllerRef(mesh, mesh.GroupVersionKind()),
 			},
 		},
-		Spec: *spec,
+		Spec: *spec.DeepCopy(),
 	}
+	applyMeshDefaults(mesh.Spec.Defaults, &source.Spec.Tenant, &source.Spec.Namespace, &source.Spec.ClusterName,
+		&source.Spec.Image, &source.Spec.ImagePullPolicy, &source.Spec.Messaging, &source.Spec.Pod,
+		&source.Spec.StateConfig)
+	// pausing the mesh pauses all its components
+	source.Spec.Paused = source.Spec.Paused || mesh.Spec.Paused
+	return source
 }
 
 func MakeSinkComponent(sinkName string, mesh *v1alpha1.FunctionMesh, spec *v1alpha1.SinkSpec) *v1alpha1.Sink {
-	return &v1alpha1.Sink{
+	sink := &v1alpha1.Sink{
 		TypeMeta: metav1.TypeMeta{
 			APIVersion: "compute.functionmesh.io/v1alpha1",
 			Kind:       "Sink",
@@ -66,10 +86,405 @@ func MakeSinkComponent(sinkName string, mesh *v1alpha1.FunctionMesh, spec *v1alp
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      sinkName,
 			Namespace: mesh.Namespace,
+			Labels:    makeComponentLabels(mesh),
 			OwnerReferences: []metav1.OwnerReference{
 				*metav1.NewControllerRef(mesh, mesh.GroupVersionKind()),
 			},
 		},
-		Spec: *spec,
+		Spec: *spec.DeepCopy(),
+	}
+	applyMeshDefaults(mesh.Spec.Defaults, &sink.Spec.Tenant, &sink.Spec.Namespace, &sink.Spec.ClusterName,
+		&sink.Spec.Image, &sink.Spec.ImagePullPolicy, &sink.Spec.Messaging, &sink.Spec.Pod,
+		&sink.Spec.StateConfig)
+	// pausing the mesh pauses all its components
+	sink.Spec.Paused = sink.Spec.Paused || mesh.Spec.Paused
+	return sink
+}
+
+// makeComponentLabels copies the labels of the mesh, so the components are managed by the same controller
+func makeComponentLabels(mesh *v1alpha1.FunctionMesh) map[string]string {
+	if len(mesh.Labels) == 0 {
+		return nil
+	}
+	labels := make(map[string]string, len(mesh.Labels))
+	for k, v := range mesh.Labels {
+		labels[k] = v
+	}
+	return labels
+}
+
+// applyMeshDefaults fills the unset fields of a component of a mesh with the mesh defaults, the arguments are
+// modified in place
+func applyMeshDefaults(defaults *v1alpha1.FunctionMeshDefaults, tenant, namespace, clusterName, image *string,
+	imagePullPolicy *corev1.PullPolicy, messaging *v1alpha1.Messaging, policy *v1alpha1.PodPolicy,
+	stateConfig **v1alpha1.Stateful) {
+	if defaults == nil {
+		return
+	}
+	*tenant = mergeString(*tenant, defaults.Tenant)
+	*namespace = mergeString(*namespace, defaults.Namespace)
+	*clusterName = mergeString(*clusterName, defaults.ClusterName)
+	*image = mergeString(*image, defaults.Image)
+	*imagePullPolicy = corev1.PullPolicy(mergeString(string(*imagePullPolicy), string(defaults.ImagePullPolicy)))
+	*messaging = mergeMessaging(*messaging, defaults.Messaging)
+	*policy = mergePodPolicy(*policy, defaults.Pod)
+	if *stateConfig == nil && defaults.StateConfig != nil {
+		*stateConfig = defaults.StateConfig.DeepCopy()
+	}
+}
+
+func mergeString(value, defaultValue string) string {
+	if value != "" {
+		return value
+	}
+	return defaultValue
+}
+
+// mergeMessaging fills the unset pulsar messaging fields of a component from the mesh defaults. A
+// PulsarConnection provides the whole pulsar config, so a component naming one takes nothing from the
+// defaults, and a component without its own pulsar config takes the connection of the defaults.
+func mergeMessaging(messaging, defaults v1alpha1.Messaging) v1alpha1.Messaging {
+	if defaults.Pulsar == nil {
+		return messaging
+	}
+	if messaging.Pulsar == nil {
+		return *defaults.DeepCopy()
+	}
+	pulsar := messaging.Pulsar
+	if pulsar.PulsarConnection != "" {
+		return messaging
+	}
+	if pulsar.PulsarConfig == "" && defaults.Pulsar.PulsarConnection != "" {
+		pulsar.PulsarConnection = defaults.Pulsar.PulsarConnection
+		return messaging
+	}
+	pulsar.PulsarConfig = mergeString(pulsar.PulsarConfig, defaults.Pulsar.PulsarConfig)
+	pulsar.AuthSecret = mergeString(pulsar.AuthSecret, defaults.Pulsar.AuthSecret)
+	pulsar.TLSSecret = mergeString(pulsar.TLSSecret, defaults.Pulsar.TLSSecret)
+	if pulsar.TLSConfig == nil && defaults.Pulsar.TLSConfig != nil {
+		pulsar.TLSConfig = defaults.Pulsar.TLSConfig.DeepCopy()
+	}
+	if pulsar.AuthConfig == nil && defaults.Pulsar.AuthConfig != nil {
+		pulsar.AuthConfig = defaults.Pulsar.AuthConfig.DeepCopy()
+	}
+	return messaging
+}
+
+// mergePodPolicy merges the mesh default pod policy into the pod policy of a component,
+// the values of the component win on conflicts
+func mergePodPolicy(policy, defaults v1alpha1.PodPolicy) v1alpha1.PodPolicy {
+	defaults = *defaults.DeepCopy()
+	policy.Labels = mergeLabels(defaults.Labels, policy.Labels)
+	policy.Annotations = mergeLabels(defaults.Annotations, policy.Annotations)
+	policy.NodeSelector = mergeLabels(defaults.NodeSelector, policy.NodeSelector)
+	if len(policy.Labels) == 0 {
+		policy.Labels = nil
+	}
+	if len(policy.Annotations) == 0 {
+		policy.Annotations = nil
+	}
+	if len(policy.NodeSelector) == 0 {
+		policy.NodeSelector = nil
+	}
+	if policy.Affinity == nil {
+		policy.Affinity = defaults.Affinity
+	}
+	if policy.SecurityContext == nil {
+		policy.SecurityContext = defaults.SecurityContext
+	}
+	if policy.TerminationGracePeriodSeconds == 0 {
+		policy.TerminationGracePeriodSeconds = defaults.TerminationGracePeriodSeconds
+	}
+	if policy.ServiceAccountName == "" {
+		policy.ServiceAccountName = defaults.ServiceAccountName
+	}
+	if policy.Liveness == nil {
+		policy.Liveness = defaults.Liveness
+	}
+	if policy.VPA == nil {
+		policy.VPA = defaults.VPA
+	}
+	if len(policy.BuiltinAutoscaler) == 0 {
+		policy.BuiltinAutoscaler = defaults.BuiltinAutoscaler
+	}
+	if len(policy.AutoScalingMetrics) == 0 {
+		policy.AutoScalingMetrics = defaults.AutoScalingMetrics
+	}
+	if policy.AutoScalingBehavior == nil {
+		policy.AutoScalingBehavior = defaults.AutoScalingBehavior
+	}
+	if policy.PodManagementPolicy == "" {
+		policy.PodManagementPolicy = defaults.PodManagementPolicy
+	}
+	policy.Tolerations = mergeByKey(defaults.Tolerations, policy.Tolerations, tolerationKey)
+	policy.Env = mergeByKey(defaults.Env, policy.Env, func(env corev1.EnvVar) string { return env.Name })
+	policy.Volumes = mergeByKey(defaults.Volumes, policy.Volumes, func(volume corev1.Volume) string {
+		return volume.Name
+	})
+	policy.ImagePullSecrets = mergeByKey(defaults.ImagePullSecrets, policy.ImagePullSecrets, localObjectReferenceKey)
+	policy.InitContainers = mergeByKey(defaults.InitContainers, policy.InitContainers, containerKey)
+	policy.Sidecars = mergeByKey(defaults.Sidecars, policy.Sidecars, containerKey)
+	return policy
+}
+
+// mergeByKey merges the default items into the items of a component, the items of the component win over the
+// defaults with the same key
+func mergeByKey[T any, K comparable](defaults, items []T, key func(T) K) []T {
+	if len(defaults) == 0 {
+		return items
+	}
+	overridden := make(map[K]bool, len(items))
+	for _, item := range items {
+		overridden[key(item)] = true
+	}
+	merged := []T{}
+	for _, d := range defaults {
+		if !overridden[key(d)] {
+			merged = append(merged, d)
+		}
+	}
+	return append(merged, items...)
+}
+
+func tolerationKey(toleration corev1.Toleration) [2]string {
+	return [2]string{toleration.Key, string(toleration.Effect)}
+}
+
+func localObjectReferenceKey(reference corev1.LocalObjectReference) string {
+	return reference.Name
+}
+
+func containerKey(container corev1.Container) string {
+	return container.Name
+}
+
+// MeshComponent identifies a function, source or sink of a FunctionMesh
+type MeshComponent struct {
+	// Kind is one of v1alpha1.FunctionComponent, v1alpha1.SourceComponent and v1alpha1.SinkComponent
+	Kind string
+	Name string
+}
+
+func (c MeshComponent) String() string {
+	return c.Kind + "/" + c.Name
+}
+
+type meshTopicNode struct {
+	component MeshComponent
+	inputs    map[string]bool
+	output    string
+}
+
+// MakeFunctionMeshStartupStages groups the components of a mesh into stages following the topic graph.
+// Stage 0 holds the components without downstream consumers inside the mesh, stage N holds the components
+// producing to topics consumed by the stages before it. Topic patterns are not resolved.
+func MakeFunctionMeshStartupStages(mesh *v1alpha1.FunctionMesh) ([][]MeshComponent, error) {
+	nodes := []*meshTopicNode{}
+	for _, function := range mesh.Spec.Functions {
+		nodes = append(nodes, &meshTopicNode{
+			component: MeshComponent{Kind: v1alpha1.FunctionComponent, Name: function.Name},
+			inputs:    collectMeshInputTopics(function.Input),
+			output:    normalizeMeshTopicName(function.Output.Topic),
+		})
+	}
+	for _, source := range mesh.Spec.Sources {
+		nodes = append(nodes, &meshTopicNode{
+			component: MeshComponent{Kind: v1alpha1.SourceComponent, Name: source.Name},
+			inputs:    map[string]bool{},
+			output:    normalizeMeshTopicName(source.Output.Topic),
+		})
+	}
+	for _, sink := range mesh.Spec.Sinks {
+		nodes = append(nodes, &meshTopicNode{
+			component: MeshComponent{Kind: v1alpha1.SinkComponent, Name: sink.Name},
+			inputs:    collectMeshInputTopics(sink.Input),
+		})
+	}
+
+	downstream := map[*meshTopicNode][]*meshTopicNode{}
+	for _, node := range nodes {
+		if node.output == "" {
+			continue
+		}
+		for _, other := range nodes {
+			if other != node && other.inputs[node.output] {
+				downstream[node] = append(downstream[node], other)
+			}
+		}
+	}
+
+	// the nodes missing from the states are not visited yet
+	const (
+		visiting = iota + 1
+		visited
+	)
+	states := map[*meshTopicNode]int{}
+	levels := map[*meshTopicNode]int{}
+	var visit func(node *meshTopicNode) error
+	visit = func(node *meshTopicNode) error {
+		switch states[node] {
+		case visited:
+			return nil
+		case visiting:
+			return fmt.Errorf("cycle detected in the topic graph at %s", node.component)
+		}
+		states[node] = visiting
+		level := 0
+		for _, next := range downstream[node] {
+			if err := visit(next); err != nil {
+				return err
+			}
+			if levels[next]+1 > level {
+				level = levels[next] + 1
+			}
+		}
+		levels[node] = level
+		states[node] = visited
+		return nil
+	}
+
+	maxLevel := -1
+	for _, node := range nodes {
+		if err := visit(node); err != nil {
+			return nil, err
+		}
+		if levels[node] > maxLevel {
+			maxLevel = levels[node]
+		}
+	}
+
+	stages := make([][]MeshComponent, maxLevel+1)
+	for _, node := range nodes {
+		stages[levels[node]] = append(stages[levels[node]], node.component)
+	}
+	for _, stage := range stages {
+		sort.SliceStable(stage, func(i, j int) bool {
+			return stage[i].String() < stage[j].String()
+		})
+	}
+	return stages, nil
+}
+
+func collectMeshInputTopics(input v1alpha1.InputConf) map[string]bool {
+	topics := map[string]bool{}
+	for _, topic := range input.Topics {
+		topics[normalizeMeshTopicName(topic)] = true
+	}
+	for topic := range input.CustomSerdeSources {
+		topics[normalizeMeshTopicName(topic)] = true
+	}
+	for topic := range input.CustomSchemaSources {
+		topics[normalizeMeshTopicName(topic)] = true
+	}
+	for topic, conf := range input.SourceSpecs {
+		if !conf.IsRegexPattern {
+			topics[normalizeMeshTopicName(topic)] = true
+		}
 	}
+	delete(topics, "")
+	return topics
+}
+
+// normalizeMeshTopicName makes sure `my-topic` and `persistent://public/default/my-topic` are the same node
+func normalizeMeshTopicName(topic string) string {
+	if topic == "" {
+		return ""
+	}
+	topicName, err := pctlutil.GetTopicName(topic)
+	if err != nil {
+		return topic
+	}
+	return topicName.String()
+}
+
+// MakeFunctionMeshComponentsReady summarizes the readiness of the components as `ready/total`
+func MakeFunctionMeshComponentsReady(mesh *v1alpha1.FunctionMesh) string {
+	ready := 0
+	for _, component := range mesh.Status.Components {
+		if component.Ready {
+			ready++
+		}
+	}
+	return fmt.Sprintf("%d/%d", ready, len(mesh.Status.Components))
+}
+
+// MakeFunctionMeshConditions aggregates the status of the components into the
+// `Ready`, `Progressing`, `Degraded` and `Paused` conditions of the mesh
+func MakeFunctionMeshConditions(mesh *v1alpha1.FunctionMesh) []metav1.Condition {
+	var notReady, rollingOut, failed []string
+	isRollingOut := map[string]bool{}
+	for _, component := range mesh.Status.Components {
+		name := MeshComponent{Kind: component.Kind, Name: component.Name}.String()
+		if component.LastError != "" {
+			failed = append(failed, name)
+		}
+		if !component.Ready {
+			notReady = append(notReady, name)
+		}
+		if !component.Ready || component.Generation != component.ObservedGeneration {
+			rollingOut = append(rollingOut, name)
+			isRollingOut[name] = true
+		}
+	}
+	if mesh.Status.StartupProgress != nil {
+		for _, name := range mesh.Status.StartupProgress.Waiting {
+			if !isRollingOut[name] {
+				rollingOut = append(rollingOut, name)
+			}
+		}
+	}
+
+	ready := metav1.Condition{
+		Type:               v1alpha1.FunctionMeshReady,
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: mesh.Generation,
+		Reason:             "ComponentsReady",
+		Message:            "all the components are ready",
+	}
+	if len(notReady) > 0 {
+		ready.Status = metav1.ConditionFalse
+		ready.Reason = "ComponentsNotReady"
+		ready.Message = fmt.Sprintf("components not ready: %s", strings.Join(notReady, ", "))
+	}
+
+	progressing := metav1.Condition{
+		Type:               v1alpha1.FunctionMeshProgressing,
+		Status:             metav1.ConditionFalse,
+		ObservedGeneration: mesh.Generation,
+		Reason:             "RolloutComplete",
+		Message:            "all the components are up to date",
+	}
+	if len(rollingOut) > 0 {
+		progressing.Status = metav1.ConditionTrue
+		progressing.Reason = "RollingOut"
+		progressing.Message = fmt.Sprintf("components rolling out: %s", strings.Join(rollingOut, ", "))
+	}
+
+	degraded := metav1.Condition{
+		Type:               v1alpha1.FunctionMeshDegraded,
+		Status:             metav1.ConditionFalse,
+		ObservedGeneration: mesh.Generation,
+		Reason:             "ReconcileSucceeded",
+		Message:            "all the components are reconciled",
+	}
+	if len(failed) > 0 {
+		degraded.Status = metav1.ConditionTrue
+		degraded.Reason = "ReconcileFailed"
+		degraded.Message = fmt.Sprintf("components failed to reconcile: %s", strings.Join(failed, ", "))
+	}
+
+	paused := metav1.Condition{
+		Type:               v1alpha1.FunctionMeshPaused,
+		Status:             metav1.ConditionFalse,
+		ObservedGeneration: mesh.Generation,
+		Reason:             "MeshRunning",
+		Message:            "the mesh is not paused",
+	}
+	if mesh.Spec.Paused {
+		paused.Status = metav1.ConditionTrue
+		paused.Reason = "MeshPaused"
+		paused.Message = "all the components are paused"
+	}
+
+	return []metav1.Condition{ready, progressing, degraded, paused}
 }
@@ -0,0 +1,312 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package spec
+
+import (
+	"testing"
+
+	"github.com/streamnative/function-mesh/api/compute/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func makeFunctionMeshSample(defaults *v1alpha1.FunctionMeshDefaults) *v1alpha1.FunctionMesh {
+	return &v1alpha1.FunctionMesh{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "FunctionMesh",
+			APIVersion: "compute.functionmesh.io/v1alpha1",
+		},
+		ObjectMeta: *makeSampleObjectMeta("test-mesh"),
+		Spec: v1alpha1.FunctionMeshSpec{
+			Defaults: defaults,
+		},
+	}
+}
+
+func makeFunctionMeshDefaultsSample() *v1alpha1.FunctionMeshDefaults {
+	return &v1alpha1.FunctionMeshDefaults{
+		Tenant:      "mesh-tenant",
+		Namespace:   "mesh-namespace",
+		ClusterName: TestClusterName,
+		Image:       "mesh-image",
+		Messaging: v1alpha1.Messaging{
+			Pulsar: &v1alpha1.PulsarMessaging{
+				PulsarConfig: "mesh-pulsar-config",
+				AuthSecret:   "mesh-auth-secret",
+			},
+		},
+		Pod: v1alpha1.PodPolicy{
+			Labels: map[string]string{
+				"team": "mesh",
+				"tier": "mesh",
+			},
+			Tolerations: []corev1.Toleration{
+				{Key: "dedicated", Value: "mesh", Effect: corev1.TaintEffectNoSchedule},
+				{Key: "gpu", Effect: corev1.TaintEffectNoExecute},
+			},
+			Env: []corev1.EnvVar{
+				{Name: "SHARED", Value: "mesh"},
+				{Name: "OVERRIDDEN", Value: "mesh"},
+			},
+			ServiceAccountName: "mesh-sa",
+		},
+		StateConfig: &v1alpha1.Stateful{
+			Pulsar: &v1alpha1.PulsarStateStore{
+				ServiceURL: "bk://mesh:4181",
+			},
+		},
+	}
+}
+
+func TestMakeFunctionComponentWithoutDefaults(t *testing.T) {
+	mesh := makeFunctionMeshSample(nil)
+	function := makeFunctionSample(TestFunctionName)
+
+	component := MakeFunctionComponent("test-mesh-"+TestFunctionName, mesh, &function.Spec)
+	assert.Equal(t, function.Spec, component.Spec)
+}
+
+func TestMakeFunctionComponentWithDefaults(t *testing.T) {
+	mesh := makeFunctionMeshSample(makeFunctionMeshDefaultsSample())
+	function := makeFunctionSample(TestFunctionName)
+	function.Spec.Tenant = "function-tenant"
+	function.Spec.Image = ""
+	function.Spec.Pulsar.PulsarConfig = ""
+	function.Spec.Pulsar.TLSSecret = "function-tls-secret"
+	function.Spec.Pod = v1alpha1.PodPolicy{
+		Labels: map[string]string{
+			"tier": "function",
+		},
+		Tolerations: []corev1.Toleration{
+			{Key: "dedicated", Value: "function", Effect: corev1.TaintEffectNoSchedule},
+		},
+		Env: []corev1.EnvVar{
+			{Name: "OVERRIDDEN", Value: "function"},
+		},
+	}
+
+	component := MakeFunctionComponent("test-mesh-"+TestFunctionName, mesh, &function.Spec)
+	spec := component.Spec
+
+	assert.Equal(t, "function-tenant", spec.Tenant)
+	assert.Equal(t, "mesh-namespace", spec.Namespace)
+	assert.Equal(t, TestClusterName, spec.ClusterName)
+	assert.Equal(t, "mesh-image", spec.Image)
+
+	assert.Equal(t, "mesh-pulsar-config", spec.Pulsar.PulsarConfig)
+	assert.Equal(t, "mesh-auth-secret", spec.Pulsar.AuthSecret)
+	assert.Equal(t, "function-tls-secret", spec.Pulsar.TLSSecret)
+
+	assert.Equal(t, map[string]string{"team": "mesh", "tier": "function"}, spec.Pod.Labels)
+	assert.Equal(t, []corev1.Toleration{
+		{Key: "gpu", Effect: corev1.TaintEffectNoExecute},
+		{Key: "dedicated", Value: "function", Effect: corev1.TaintEffectNoSchedule},
+	}, spec.Pod.Tolerations)
+	assert.Equal(t, []corev1.EnvVar{
+		{Name: "SHARED", Value: "mesh"},
+		{Name: "OVERRIDDEN", Value: "function"},
+	}, spec.Pod.Env)
+	assert.Equal(t, "mesh-sa", spec.Pod.ServiceAccountName)
+
+	// the function sample has its own state config
+	assert.Equal(t, "bk://localhost:4181", spec.StateConfig.Pulsar.ServiceURL)
+
+	// the spec inside the mesh must not be mutated
+	assert.Equal(t, "", function.Spec.Pulsar.PulsarConfig)
+	assert.Equal(t, 1, len(function.Spec.Pod.Env))
+}
+
+func TestMakeSourceAndSinkComponentWithDefaults(t *testing.T) {
+	mesh := makeFunctionMeshSample(makeFunctionMeshDefaultsSample())
+
+	source := MakeSourceComponent("test-mesh-source", mesh, &v1alpha1.SourceSpec{Name: "source"})
+	assert.Equal(t, "mesh-tenant", source.Spec.Tenant)
+	assert.Equal(t, "mesh-pulsar-config", source.Spec.Pulsar.PulsarConfig)
+	assert.Equal(t, "bk://mesh:4181", source.Spec.StateConfig.Pulsar.ServiceURL)
+	assert.Equal(t, 2, len(source.Spec.Pod.Tolerations))
+
+	sink := MakeSinkComponent("test-mesh-sink", mesh, &v1alpha1.SinkSpec{Name: "sink"})
+	assert.Equal(t, "mesh-namespace", sink.Spec.Namespace)
+	assert.Equal(t, "mesh-auth-secret", sink.Spec.Pulsar.AuthSecret)
+	assert.Equal(t, "mesh-sa", sink.Spec.Pod.ServiceAccountName)
+
+	// the defaults of the mesh must not be shared with the components
+	sink.Spec.Pulsar.PulsarConfig = "changed"
+	assert.Equal(t, "mesh-pulsar-config", mesh.Spec.Defaults.Pulsar.PulsarConfig)
+}
+
+func TestMergeByKey(t *testing.T) {
+	defaults := []corev1.Toleration{
+		{Key: "dedicated", Value: "mesh", Effect: corev1.TaintEffectNoSchedule},
+		{Key: "dedicated", Value: "mesh", Effect: corev1.TaintEffectNoExecute},
+	}
+	tolerations := []corev1.Toleration{{Key: "dedicated", Value: "function", Effect: corev1.TaintEffectNoSchedule}}
+	assert.Equal(t, []corev1.Toleration{
+		{Key: "dedicated", Value: "mesh", Effect: corev1.TaintEffectNoExecute},
+		{Key: "dedicated", Value: "function", Effect: corev1.TaintEffectNoSchedule},
+	}, mergeByKey(defaults, tolerations, tolerationKey))
+	assert.Equal(t, tolerations, mergeByKey(nil, tolerations, tolerationKey))
+	assert.Equal(t, defaults, mergeByKey(defaults, nil, tolerationKey))
+}
+
+func TestMergeMessagingWithPulsarConnection(t *testing.T) {
+	defaults := v1alpha1.Messaging{Pulsar: &v1alpha1.PulsarMessaging{
+		PulsarConfig: "mesh-pulsar-config",
+		AuthSecret:   "mesh-auth",
+		TLSConfig:    &v1alpha1.PulsarTLSConfig{TLSConfig: v1alpha1.TLSConfig{Enabled: true}},
+	}}
+	// the connection of the component provides the whole config
+	messaging := mergeMessaging(v1alpha1.Messaging{Pulsar: &v1alpha1.PulsarMessaging{PulsarConnection: "team-a"}},
+		defaults)
+	assert.Equal(t, &v1alpha1.PulsarMessaging{PulsarConnection: "team-a"}, messaging.Pulsar)
+
+	// the connection of the defaults is kept for the components with their own pulsar block
+	defaults = v1alpha1.Messaging{Pulsar: &v1alpha1.PulsarMessaging{PulsarConnection: "mesh"}}
+	messaging = mergeMessaging(v1alpha1.Messaging{Pulsar: &v1alpha1.PulsarMessaging{AuthSecret: "function-auth"}},
+		defaults)
+	assert.Equal(t, &v1alpha1.PulsarMessaging{AuthSecret: "function-auth", PulsarConnection: "mesh"},
+		messaging.Pulsar)
+	messaging = mergeMessaging(v1alpha1.Messaging{}, defaults)
+	assert.Equal(t, &v1alpha1.PulsarMessaging{PulsarConnection: "mesh"}, messaging.Pulsar)
+
+	// but not for the components setting their pulsar config
+	messaging = mergeMessaging(v1alpha1.Messaging{Pulsar: &v1alpha1.PulsarMessaging{PulsarConfig: "function"}},
+		defaults)
+	assert.Equal(t, &v1alpha1.PulsarMessaging{PulsarConfig: "function"}, messaging.Pulsar)
+}
+
+func TestMakeFunctionMeshStartupStages(t *testing.T) {
+	mesh := makeFunctionMeshSample(nil)
+	mesh.Spec.StartupOrder = v1alpha1.StartupOrderTopological
+	mesh.Spec.Sources = []v1alpha1.SourceSpec{{
+		Name:   "source",
+		Output: v1alpha1.OutputConf{Topic: "persistent://public/default/raw"},
+	}}
+	mesh.Spec.Functions = []v1alpha1.FunctionSpec{{
+		Name:   "function",
+		Input:  v1alpha1.InputConf{Topics: []string{"raw"}},
+		Output: v1alpha1.OutputConf{Topic: "public/default/processed"},
+	}}
+	mesh.Spec.Sinks = []v1alpha1.SinkSpec{{
+		Name:  "sink",
+		Input: v1alpha1.InputConf{Topics: []string{"persistent://public/default/processed"}},
+	}, {
+		Name:  "unrelated-sink",
+		Input: v1alpha1.InputConf{Topics: []string{"other"}},
+	}}
+
+	stages, err := MakeFunctionMeshStartupStages(mesh)
+	assert.Nil(t, err)
+	assert.Equal(t, [][]MeshComponent{
+		{{Kind: v1alpha1.SinkComponent, Name: "sink"}, {Kind: v1alpha1.SinkComponent, Name: "unrelated-sink"}},
+		{{Kind: v1alpha1.FunctionComponent, Name: "function"}},
+		{{Kind: v1alpha1.SourceComponent, Name: "source"}},
+	}, stages)
+}
+
+func TestMakeFunctionMeshStartupStagesWithCycle(t *testing.T) {
+	mesh := makeFunctionMeshSample(nil)
+	mesh.Spec.StartupOrder = v1alpha1.StartupOrderTopological
+	mesh.Spec.Functions = []v1alpha1.FunctionSpec{{
+		Name:   "ping",
+		Input:  v1alpha1.InputConf{Topics: []string{"ping"}},
+		Output: v1alpha1.OutputConf{Topic: "pong"},
+	}, {
+		Name:   "pong",
+		Input:  v1alpha1.InputConf{Topics: []string{"pong"}},
+		Output: v1alpha1.OutputConf{Topic: "ping"},
+	}}
+
+	_, err := MakeFunctionMeshStartupStages(mesh)
+	assert.Error(t, err)
+}
+
+func TestMakeFunctionMeshConditions(t *testing.T) {
+	mesh := makeFunctionMeshSample(nil)
+	mesh.Generation = 2
+	mesh.Status.Components = []v1alpha1.ComponentStatus{
+		{Kind: v1alpha1.FunctionComponent, Name: "function", Generation: 1, ObservedGeneration: 1,
+			Replicas: 1, ReadyReplicas: 1, Ready: true},
+		{Kind: v1alpha1.SinkComponent, Name: "sink", Generation: 1, ObservedGeneration: 1,
+			Replicas: 1, ReadyReplicas: 1, Ready: true},
+	}
+
+	assert.Equal(t, "2/2", MakeFunctionMeshComponentsReady(mesh))
+	conditions := MakeFunctionMeshConditions(mesh)
+	assert.Equal(t, 4, len(conditions))
+	assert.Equal(t, v1alpha1.FunctionMeshReady, conditions[0].Type)
+	assert.Equal(t, metav1.ConditionTrue, conditions[0].Status)
+	assert.Equal(t, int64(2), conditions[0].ObservedGeneration)
+	assert.Equal(t, v1alpha1.FunctionMeshProgressing, conditions[1].Type)
+	assert.Equal(t, metav1.ConditionFalse, conditions[1].Status)
+	assert.Equal(t, v1alpha1.FunctionMeshDegraded, conditions[2].Type)
+	assert.Equal(t, metav1.ConditionFalse, conditions[2].Status)
+	assert.Equal(t, v1alpha1.FunctionMeshPaused, conditions[3].Type)
+	assert.Equal(t, metav1.ConditionFalse, conditions[3].Status)
+
+	mesh.Status.Components[1] = v1alpha1.ComponentStatus{
+		Kind: v1alpha1.SinkComponent, Name: "sink", Generation: 2, ObservedGeneration: 1,
+		Replicas: 2, ReadyReplicas: 1, LastError: "admission webhook denied the request",
+	}
+
+	assert.Equal(t, "1/2", MakeFunctionMeshComponentsReady(mesh))
+	conditions = MakeFunctionMeshConditions(mesh)
+	assert.Equal(t, metav1.ConditionFalse, conditions[0].Status)
+	assert.Equal(t, "components not ready: sink/sink", conditions[0].Message)
+	assert.Equal(t, metav1.ConditionTrue, conditions[1].Status)
+	assert.Equal(t, metav1.ConditionTrue, conditions[2].Status)
+	assert.Equal(t, "ReconcileFailed", conditions[2].Reason)
+}
+
+func TestMakeComponentsWithPausedMesh(t *testing.T) {
+	mesh := makeFunctionMeshSample(nil)
+	function := makeFunctionSample(TestFunctionName)
+
+	component := MakeFunctionComponent("test-mesh-"+TestFunctionName, mesh, &function.Spec)
+	assert.False(t, component.Spec.Paused)
+
+	mesh.Spec.Paused = true
+	component = MakeFunctionComponent("test-mesh-"+TestFunctionName, mesh, &function.Spec)
+	assert.True(t, component.Spec.Paused)
+	assert.False(t, function.Spec.Paused)
+	assert.True(t, MakeSourceComponent("test-mesh-source", mesh, &v1alpha1.SourceSpec{Name: "source"}).Spec.Paused)
+	assert.True(t, MakeSinkComponent("test-mesh-sink", mesh, &v1alpha1.SinkSpec{Name: "sink"}).Spec.Paused)
+
+	statefulSet := MakeFunctionStatefulSet(component)
+	assert.Equal(t, int32(0), *statefulSet.Spec.Replicas)
+	assert.Equal(t, int32(1), *component.Spec.Replicas)
+}
+
+func TestMakeComponentsWithMeshLabels(t *testing.T) {
+	mesh := makeFunctionMeshSample(nil)
+	function := makeFunctionSample(TestFunctionName)
+
+	component := MakeFunctionComponent("test-mesh-"+TestFunctionName, mesh, &function.Spec)
+	assert.Nil(t, component.Labels)
+
+	mesh.Labels = map[string]string{"functionmesh.io/shard": "a"}
+	component = MakeFunctionComponent("test-mesh-"+TestFunctionName, mesh, &function.Spec)
+	assert.Equal(t, mesh.Labels, component.Labels)
+	assert.Equal(t, mesh.Labels, MakeSourceComponent("test-mesh-source", mesh, &v1alpha1.SourceSpec{Name: "source"}).Labels)
+	assert.Equal(t, mesh.Labels, MakeSinkComponent("test-mesh-sink", mesh, &v1alpha1.SinkSpec{Name: "sink"}).Labels)
+
+	// the labels of the components are copies
+	component.Labels["extra"] = "value"
+	assert.NotContains(t, mesh.Labels, "extra")
+}
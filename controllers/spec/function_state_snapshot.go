@@ -0,0 +1,183 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package spec
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/streamnative/function-mesh/api/compute/v1alpha1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// The snapshots are exported and restored by Jobs running the state command of the function-mesh-tools image,
+// they get the connection settings of the function from the same config map and secrets as its instances.
+const (
+	StateSnapshotImage = DefaultRunnerPrefix + "function-mesh-tools:latest"
+
+	stateSnapshotJobSuffix  = "-state-snapshot"
+	stateSnapshotVolume     = "state-snapshot"
+	stateSnapshotMountPath  = "/snapshot"
+	stateSnapshotURLEnv     = "STATE_SNAPSHOT_URL"
+	stateSnapshotExecutable = "/function-mesh-tools"
+)
+
+// MakeFunctionStateSnapshotJobName returns the name of the Job of a FunctionStateSnapshot
+func MakeFunctionStateSnapshotJobName(snapshot *v1alpha1.FunctionStateSnapshot) string {
+	return snapshot.Name + stateSnapshotJobSuffix
+}
+
+// MakeFunctionStateSnapshotJob makes the Job exporting or restoring the state of a function, the messaging is
+// the resolved pulsar messaging of the function
+func MakeFunctionStateSnapshotJob(snapshot *v1alpha1.FunctionStateSnapshot, function *v1alpha1.Function,
+	messaging *v1alpha1.PulsarMessaging) *batchv1.Job {
+	labels := map[string]string{
+		"app.kubernetes.io/managed-by":                    "function-mesh",
+		"compute.functionmesh.io/function-state-snapshot": snapshot.Name,
+		"compute.functionmesh.io/function":                function.Name,
+	}
+	container := corev1.Container{
+		Name:            stateSnapshotVolume,
+		Image:           getStateSnapshotImage(snapshot),
+		ImagePullPolicy: snapshot.Spec.ImagePullPolicy,
+		Command:         makeStateSnapshotCommand(snapshot, function, messaging),
+	}
+	var volumes []corev1.Volume
+	if messaging != nil {
+		container.EnvFrom = generateContainerEnvFrom(messaging.PulsarConfig, messaging.AuthSecret, "")
+		if messaging.TLSConfig != nil && messaging.TLSConfig.HasSecretVolume() {
+			volumes = append(volumes, generateVolumeFromTLSConfig(messaging.TLSConfig))
+			container.VolumeMounts = append(container.VolumeMounts, generateVolumeMountFromTLSConfig(messaging.TLSConfig))
+		}
+	}
+	storage := snapshot.Spec.Storage
+	if storage.PersistentVolumeClaim != nil {
+		volumes = append(volumes, corev1.Volume{
+			Name: stateSnapshotVolume,
+			VolumeSource: corev1.VolumeSource{
+				PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+					ClaimName: storage.PersistentVolumeClaim.ClaimName,
+				},
+			},
+		})
+		container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
+			Name:      stateSnapshotVolume,
+			MountPath: stateSnapshotMountPath,
+		})
+	}
+	if storage.ObjectStore != nil {
+		container.Env = append(container.Env, corev1.EnvVar{
+			Name: stateSnapshotURLEnv,
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: storage.ObjectStore.URLSecret},
+					Key:                  storage.ObjectStore.URLSecretKey,
+				},
+			},
+		})
+	}
+
+	return &batchv1.Job{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Job",
+			APIVersion: "batch/v1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      MakeFunctionStateSnapshotJobName(snapshot),
+			Namespace: snapshot.Namespace,
+			Labels:    labels,
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(snapshot, snapshot.GroupVersionKind()),
+			},
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit: snapshot.Spec.BackoffLimit,
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: labels},
+				Spec: corev1.PodSpec{
+					Containers:       []corev1.Container{container},
+					Volumes:          volumes,
+					RestartPolicy:    corev1.RestartPolicyNever,
+					ImagePullSecrets: function.Spec.Pod.ImagePullSecrets,
+					SecurityContext:  function.Spec.Pod.SecurityContext,
+				},
+			},
+		},
+	}
+}
+
+func getStateSnapshotImage(snapshot *v1alpha1.FunctionStateSnapshot) string {
+	return mergeString(snapshot.Spec.Image,
+		mergeString(GetConfigs().DefaultsFor(snapshot.Namespace).StateSnapshotImage, StateSnapshotImage))
+}
+
+func makeStateSnapshotCommand(snapshot *v1alpha1.FunctionStateSnapshot, function *v1alpha1.Function,
+	messaging *v1alpha1.PulsarMessaging) []string {
+	mode := "export"
+	if snapshot.Spec.Mode == v1alpha1.StateSnapshotRestore {
+		mode = "restore"
+	}
+	command := []string{stateSnapshotExecutable, "state", mode, "--name", function.Name}
+	if function.Spec.Tenant != "" {
+		command = append(command, "--tenant", function.Spec.Tenant)
+	}
+	if function.Spec.Namespace != "" {
+		command = append(command, "--namespace", function.Spec.Namespace)
+	}
+	if mode == "export" {
+		command = append(command, "--keys", strings.Join(snapshot.Spec.Keys, ","))
+	}
+	if claim := snapshot.Spec.Storage.PersistentVolumeClaim; claim != nil {
+		command = append(command, "--file", path.Join(stateSnapshotMountPath, claim.Path))
+	}
+	if snapshot.Spec.StateServiceURL != "" {
+		command = append(command, "--state-service-url", snapshot.Spec.StateServiceURL)
+	}
+	if messaging != nil && messaging.TLSConfig != nil && messaging.TLSConfig.IsEnabled() {
+		if messaging.TLSConfig.AllowInsecure {
+			command = append(command, "--tls-allow-insecure")
+		}
+		if messaging.TLSConfig.HasSecretVolume() {
+			command = append(command, "--tls-trust-cert-path",
+				getTLSTrustCertPath(messaging.TLSConfig, messaging.TLSConfig.SecretKey()))
+		}
+	}
+	return command
+}
+
+// ValidateFunctionStateSnapshot returns the reason why a snapshot cannot run, an empty string is returned for
+// a valid snapshot
+func ValidateFunctionStateSnapshot(snapshot *v1alpha1.FunctionStateSnapshot) string {
+	storage := snapshot.Spec.Storage
+	if (storage.PersistentVolumeClaim == nil) == (storage.ObjectStore == nil) {
+		return "exactly one of storage.persistentVolumeClaim and storage.objectStore must be set"
+	}
+	if claim := storage.PersistentVolumeClaim; claim != nil && (claim.ClaimName == "" || claim.Path == "") {
+		return "storage.persistentVolumeClaim requires claimName and path"
+	}
+	if store := storage.ObjectStore; store != nil && (store.URLSecret == "" || store.URLSecretKey == "") {
+		return "storage.objectStore requires urlSecret and urlSecretKey"
+	}
+	if snapshot.Spec.Mode == v1alpha1.StateSnapshotExport && len(snapshot.Spec.Keys) == 0 {
+		return fmt.Sprintf("keys are required by the %s mode", v1alpha1.StateSnapshotExport)
+	}
+	return ""
+}
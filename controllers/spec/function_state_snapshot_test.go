@@ -0,0 +1,113 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package spec
+
+import (
+	"testing"
+
+	"github.com/streamnative/function-mesh/api/compute/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func makeFunctionStateSnapshotSample(mode v1alpha1.StateSnapshotMode) *v1alpha1.FunctionStateSnapshot {
+	return &v1alpha1.FunctionStateSnapshot{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "FunctionStateSnapshot",
+			APIVersion: "compute.functionmesh.io/v1alpha1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "word-count-backup",
+			Namespace: TestNameSpace,
+			UID:       "dead-beef",
+		},
+		Spec: v1alpha1.FunctionStateSnapshotSpec{
+			Function: "word-count",
+			Mode:     mode,
+			Keys:     []string{"apple", "banana"},
+			Storage: v1alpha1.StateSnapshotStorage{
+				PersistentVolumeClaim: &v1alpha1.StateSnapshotVolume{
+					ClaimName: "snapshots",
+					Path:      "word-count/state.json",
+				},
+			},
+		},
+	}
+}
+
+func TestMakeFunctionStateSnapshotJob(t *testing.T) {
+	function := makeFunctionSample("word-count")
+	messaging := &v1alpha1.PulsarMessaging{
+		PulsarConfig: TestClusterName,
+		AuthSecret:   "pulsar-auth",
+		TLSConfig: &v1alpha1.PulsarTLSConfig{
+			TLSConfig: v1alpha1.TLSConfig{
+				Enabled:        true,
+				CertSecretName: "pulsar-tls",
+				CertSecretKey:  "ca.crt",
+			},
+		},
+	}
+
+	snapshot := makeFunctionStateSnapshotSample(v1alpha1.StateSnapshotExport)
+	job := MakeFunctionStateSnapshotJob(snapshot, function, messaging)
+	assert.Equal(t, "word-count-backup-state-snapshot", job.Name)
+	assert.Equal(t, "FunctionStateSnapshot", job.OwnerReferences[0].Kind)
+	podSpec := job.Spec.Template.Spec
+	assert.Equal(t, corev1.RestartPolicyNever, podSpec.RestartPolicy)
+	container := podSpec.Containers[0]
+	assert.Equal(t, StateSnapshotImage, container.Image)
+	assert.Equal(t, []string{"/function-mesh-tools", "state", "export", "--name", "word-count",
+		"--tenant", "public", "--keys", "apple,banana", "--file", "/snapshot/word-count/state.json",
+		"--tls-trust-cert-path", "/etc/tls/pulsar-functions/ca.crt"}, container.Command)
+	assert.Len(t, container.EnvFrom, 2)
+	assert.Equal(t, "pulsar-auth", container.EnvFrom[1].SecretRef.Name)
+	assert.Len(t, podSpec.Volumes, 2)
+	assert.Equal(t, "snapshots", podSpec.Volumes[1].PersistentVolumeClaim.ClaimName)
+
+	snapshot = makeFunctionStateSnapshotSample(v1alpha1.StateSnapshotRestore)
+	snapshot.Spec.Image = "example/tools:dev"
+	snapshot.Spec.StateServiceURL = "http://pulsar-proxy:8080"
+	snapshot.Spec.Storage = v1alpha1.StateSnapshotStorage{
+		ObjectStore: &v1alpha1.StateSnapshotObjectStore{URLSecret: "snapshot-url", URLSecretKey: "url"},
+	}
+	job = MakeFunctionStateSnapshotJob(snapshot, function, &v1alpha1.PulsarMessaging{PulsarConfig: TestClusterName})
+	container = job.Spec.Template.Spec.Containers[0]
+	assert.Equal(t, "example/tools:dev", container.Image)
+	assert.Equal(t, []string{"/function-mesh-tools", "state", "restore", "--name", "word-count",
+		"--tenant", "public", "--state-service-url", "http://pulsar-proxy:8080"}, container.Command)
+	assert.Equal(t, "STATE_SNAPSHOT_URL", container.Env[0].Name)
+	assert.Equal(t, "snapshot-url", container.Env[0].ValueFrom.SecretKeyRef.Name)
+	assert.Empty(t, job.Spec.Template.Spec.Volumes)
+}
+
+func TestValidateFunctionStateSnapshot(t *testing.T) {
+	snapshot := makeFunctionStateSnapshotSample(v1alpha1.StateSnapshotExport)
+	assert.Empty(t, ValidateFunctionStateSnapshot(snapshot))
+
+	snapshot.Spec.Keys = nil
+	assert.Contains(t, ValidateFunctionStateSnapshot(snapshot), "keys are required")
+
+	snapshot = makeFunctionStateSnapshotSample(v1alpha1.StateSnapshotRestore)
+	snapshot.Spec.Keys = nil
+	assert.Empty(t, ValidateFunctionStateSnapshot(snapshot))
+
+	snapshot.Spec.Storage.ObjectStore = &v1alpha1.StateSnapshotObjectStore{URLSecret: "url", URLSecretKey: "url"}
+	assert.Contains(t, ValidateFunctionStateSnapshot(snapshot), "exactly one")
+}
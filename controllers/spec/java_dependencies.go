@@ -0,0 +1,187 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package spec
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/streamnative/function-mesh/api/compute/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	JavaDependenciesVolume     = "java-dependencies-volume"
+	DefaultJavaDependenciesDir = "/pulsar/java-dependencies"
+	JavaSharedLibrariesDir     = "/pulsar/shared-libraries"
+
+	javaDependencySourceVolumePrefix = "java-dependency-"
+	javaDependencySourceDir          = "/pulsar/java-dependency-sources"
+	javaSharedLibraryVolumePrefix    = "java-shared-library-"
+)
+
+func hasObjectKeyPrefix(location string) bool {
+	lowerCase := strings.ToLower(location)
+	return strings.HasPrefix(lowerCase, v1alpha1.PackageURLConfigMap) ||
+		strings.HasPrefix(lowerCase, v1alpha1.PackageURLSecret)
+}
+
+// parseObjectKeyLocation splits a configmap://<name>/<key> or secret://<name>/<key> location
+func parseObjectKeyLocation(location string) (isSecret bool, name, key string) {
+	isSecret = strings.HasPrefix(strings.ToLower(location), v1alpha1.PackageURLSecret)
+	parts := strings.SplitN(location[strings.Index(location, "://")+3:], "/", 2)
+	if len(parts) == 2 {
+		key = parts[1]
+	}
+	return isSecret, parts[0], key
+}
+
+// makeJavaClasspath returns the classpath entries added to the java instance for the extra dependencies, the
+// fetched dependencies and the shared libraries. The dependencies are fetched into their own directory, so the
+// jars of the extra dependencies directory of the image are not hidden by the volume.
+func makeJavaClasspath(java *v1alpha1.JavaRuntime) string {
+	if java == nil {
+		return ""
+	}
+	var classpath []string
+	if java.ExtraDependenciesDir != "" {
+		classpath = append(classpath, java.ExtraDependenciesDir+"/*")
+	}
+	if len(java.Dependencies) > 0 {
+		classpath = append(classpath, DefaultJavaDependenciesDir+"/*")
+	}
+	for _, library := range java.SharedLibraries {
+		classpath = append(classpath, fmt.Sprintf("%s/%s/*", JavaSharedLibrariesDir, library))
+	}
+	return strings.Join(classpath, ":")
+}
+
+// makeJavaDependencyFilename names the fetched dependencies after their position, so that the dependencies with
+// the same name do not overwrite each other, and with the .jar extension the classpath wildcard requires
+func makeJavaDependencyFilename(index int, dependency string) string {
+	filename := getFilenameOfComponentPackage(strings.SplitN(dependency, "?", 2)[0])
+	if !strings.HasSuffix(strings.ToLower(filename), ".jar") {
+		filename += ".jar"
+	}
+	return fmt.Sprintf("%d-%s", index, filename)
+}
+
+// generateJavaDependenciesVolumes returns the volume the downloader fetches the dependencies into, the volumes of
+// the config map and secret dependencies it copies, and the volumes of the shared libraries
+func generateJavaDependenciesVolumes(java *v1alpha1.JavaRuntime) []corev1.Volume {
+	if java == nil {
+		return nil
+	}
+	var volumes []corev1.Volume
+	if len(java.Dependencies) > 0 {
+		volumes = append(volumes, corev1.Volume{Name: JavaDependenciesVolume})
+	}
+	for i, dependency := range java.Dependencies {
+		if !hasObjectKeyPrefix(dependency) {
+			continue
+		}
+		isSecret, name, key := parseObjectKeyLocation(dependency)
+		items := []corev1.KeyToPath{{Key: key, Path: key}}
+		volume := corev1.Volume{Name: fmt.Sprintf("%s%d", javaDependencySourceVolumePrefix, i)}
+		if isSecret {
+			volume.VolumeSource.Secret = &corev1.SecretVolumeSource{SecretName: name, Items: items}
+		} else {
+			volume.VolumeSource.ConfigMap = &corev1.ConfigMapVolumeSource{
+				LocalObjectReference: corev1.LocalObjectReference{Name: name},
+				Items:                items,
+			}
+		}
+		volumes = append(volumes, volume)
+	}
+	for i, library := range java.SharedLibraries {
+		volumes = append(volumes, corev1.Volume{
+			Name: fmt.Sprintf("%s%d", javaSharedLibraryVolumePrefix, i),
+			VolumeSource: corev1.VolumeSource{
+				ConfigMap: &corev1.ConfigMapVolumeSource{
+					LocalObjectReference: corev1.LocalObjectReference{Name: library},
+				},
+			},
+		})
+	}
+	return volumes
+}
+
+// generateJavaDependenciesVolumeMounts returns the volume mounts of the dependencies for the runtime container
+func generateJavaDependenciesVolumeMounts(java *v1alpha1.JavaRuntime) []corev1.VolumeMount {
+	if java == nil {
+		return nil
+	}
+	var mounts []corev1.VolumeMount
+	if len(java.Dependencies) > 0 {
+		mounts = append(mounts, corev1.VolumeMount{
+			Name:      JavaDependenciesVolume,
+			MountPath: DefaultJavaDependenciesDir,
+			ReadOnly:  true,
+		})
+	}
+	for i, library := range java.SharedLibraries {
+		mounts = append(mounts, corev1.VolumeMount{
+			Name:      fmt.Sprintf("%s%d", javaSharedLibraryVolumePrefix, i),
+			MountPath: fmt.Sprintf("%s/%s", JavaSharedLibrariesDir, library),
+			ReadOnly:  true,
+		})
+	}
+	return mounts
+}
+
+// generateJavaDependenciesDownloaderVolumeMounts returns the volume mounts of the dependencies for the downloader
+func generateJavaDependenciesDownloaderVolumeMounts(java *v1alpha1.JavaRuntime) []corev1.VolumeMount {
+	if java == nil || len(java.Dependencies) == 0 {
+		return nil
+	}
+	mounts := []corev1.VolumeMount{{
+		Name:      JavaDependenciesVolume,
+		MountPath: DefaultJavaDependenciesDir,
+	}}
+	for i, dependency := range java.Dependencies {
+		if hasObjectKeyPrefix(dependency) {
+			mounts = append(mounts, corev1.VolumeMount{
+				Name:      fmt.Sprintf("%s%d", javaDependencySourceVolumePrefix, i),
+				MountPath: fmt.Sprintf("%s/%d", javaDependencySourceDir, i),
+				ReadOnly:  true,
+			})
+		}
+	}
+	return mounts
+}
+
+// getJavaDependenciesDownloadCommands returns the commands of the downloader fetching the dependencies, the
+// dependencies have no checksum so they are not cached
+func getJavaDependenciesDownloadCommands(java *v1alpha1.JavaRuntime, pulsar v1alpha1.PulsarMessaging) []string {
+	if java == nil {
+		return nil
+	}
+	var commands []string
+	for i, dependency := range java.Dependencies {
+		destination := fmt.Sprintf("%s/%s", DefaultJavaDependenciesDir, makeJavaDependencyFilename(i, dependency))
+		if hasObjectKeyPrefix(dependency) {
+			_, _, key := parseObjectKeyLocation(dependency)
+			commands = append(commands, fmt.Sprintf("cp %s/%d/%s %s", javaDependencySourceDir, i, key, destination))
+			continue
+		}
+		commands = append(commands, makeDownloadCommand(getDownloadCommand(dependency, destination,
+			pulsar.TLSSecret != "", pulsar.AuthSecret != "", pulsar.TLSConfig, pulsar.AuthConfig),
+			destination, "", false))
+	}
+	return commands
+}
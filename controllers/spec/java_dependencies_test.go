@@ -0,0 +1,113 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package spec
+
+import (
+	"testing"
+
+	"github.com/streamnative/function-mesh/api/compute/v1alpha1"
+	"github.com/streamnative/function-mesh/utils"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestMakeJavaClasspath(t *testing.T) {
+	assert.Equal(t, "", makeJavaClasspath(nil))
+	assert.Equal(t, "", makeJavaClasspath(&v1alpha1.JavaRuntime{Jar: "function.jar"}))
+	assert.Equal(t, "/pulsar/lib/*", makeJavaClasspath(&v1alpha1.JavaRuntime{ExtraDependenciesDir: "/pulsar/lib"}))
+	assert.Equal(t, "/pulsar/lib/*:/pulsar/java-dependencies/*", makeJavaClasspath(&v1alpha1.JavaRuntime{
+		ExtraDependenciesDir: "/pulsar/lib",
+		Dependencies:         []string{"https://example.com/libs/commons-lang3.jar"},
+	}))
+	assert.Equal(t, "/pulsar/java-dependencies/*:/pulsar/shared-libraries/shared-jars/*",
+		makeJavaClasspath(&v1alpha1.JavaRuntime{
+			Dependencies:    []string{"https://example.com/libs/commons-lang3.jar"},
+			SharedLibraries: []string{"shared-jars"},
+		}))
+}
+
+func TestMakeJavaDependencyFilename(t *testing.T) {
+	assert.Equal(t, "0-commons-lang3.jar", makeJavaDependencyFilename(0, "https://example.com/libs/commons-lang3.jar"))
+	assert.Equal(t, "1-deps.jar", makeJavaDependencyFilename(1, "https://example.com/libs/deps.jar?token=abc"))
+	assert.Equal(t, "2-deps@v1.jar", makeJavaDependencyFilename(2, "function://public/default/deps@v1"))
+}
+
+func TestGetJavaDependenciesDownloadCommands(t *testing.T) {
+	java := &v1alpha1.JavaRuntime{
+		Jar: "function.jar",
+		Dependencies: []string{
+			"https://example.com/libs/commons-lang3.jar",
+			"configmap://java-libs/config.jar",
+			"function://public/default/deps@v1",
+		},
+	}
+	commands := getJavaDependenciesDownloadCommands(java, v1alpha1.PulsarMessaging{PulsarConfig: "test-pulsar"})
+	assert.Len(t, commands, 3)
+	assert.Equal(t, "wget https://example.com/libs/commons-lang3.jar -O /pulsar/java-dependencies/0-commons-lang3.jar",
+		commands[0])
+	assert.Equal(t, "cp /pulsar/java-dependency-sources/1/config.jar /pulsar/java-dependencies/1-config.jar",
+		commands[1])
+	assert.Contains(t, commands[2], "packages download function://public/default/deps@v1")
+	assert.Contains(t, commands[2], "/pulsar/java-dependencies/2-deps@v1.jar")
+}
+
+func TestMakeFunctionStatefulSetWithJavaDependencies(t *testing.T) {
+	enableInitContainers := utils.EnableInitContainers
+	utils.EnableInitContainers = false
+	defer func() { utils.EnableInitContainers = enableInitContainers }()
+
+	function := makeFunctionSample(TestFunctionName)
+	function.Spec.Java.ExtraDependenciesDir = "/pulsar/lib"
+	function.Spec.Java.Dependencies = []string{"https://example.com/libs/commons-lang3.jar",
+		"secret://java-secrets/license.jar"}
+	function.Spec.Java.SharedLibraries = []string{"shared-jars"}
+	statefulSet := MakeFunctionStatefulSet(function)
+	podSpec := statefulSet.Spec.Template.Spec
+
+	// the package is downloaded by the runtime container, the dependencies by the downloader
+	assert.Len(t, podSpec.InitContainers, 1)
+	downloader := podSpec.InitContainers[0]
+	assert.Equal(t, DownloaderName, downloader.Name)
+	assert.NotContains(t, downloader.Command[2], "nlu-test-java-function")
+	assert.Contains(t, downloader.Command[2], "cp /pulsar/java-dependency-sources/1/license.jar "+
+		"/pulsar/java-dependencies/1-license.jar")
+	assert.Contains(t, downloader.VolumeMounts, corev1.VolumeMount{Name: JavaDependenciesVolume,
+		MountPath: DefaultJavaDependenciesDir})
+	assert.NotContains(t, podSpec.Volumes, corev1.Volume{Name: DownloaderVolume})
+	assert.Contains(t, podSpec.Volumes, corev1.Volume{Name: JavaDependenciesVolume})
+	assert.Contains(t, podSpec.Volumes, corev1.Volume{
+		Name: "java-dependency-1",
+		VolumeSource: corev1.VolumeSource{Secret: &corev1.SecretVolumeSource{
+			SecretName: "java-secrets",
+			Items:      []corev1.KeyToPath{{Key: "license.jar", Path: "license.jar"}},
+		}},
+	})
+
+	container := podSpec.Containers[0]
+	assert.Contains(t, container.Command[2],
+		"-cp /pulsar/instances/java-instance.jar:/pulsar/lib/*:/pulsar/java-dependencies/*:"+
+			"/pulsar/shared-libraries/shared-jars/*")
+	// the dependencies do not hide the extra dependencies of the image
+	assert.Contains(t, container.VolumeMounts, corev1.VolumeMount{Name: JavaDependenciesVolume,
+		MountPath: DefaultJavaDependenciesDir, ReadOnly: true})
+	for _, mount := range container.VolumeMounts {
+		assert.NotEqual(t, "/pulsar/lib", mount.MountPath)
+	}
+	assert.Contains(t, container.VolumeMounts, corev1.VolumeMount{Name: "java-shared-library-0",
+		MountPath: "/pulsar/shared-libraries/shared-jars", ReadOnly: true})
+}
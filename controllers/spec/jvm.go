@@ -0,0 +1,84 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package spec
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/streamnative/function-mesh/api/compute/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+const (
+	// JavaInstanceLogDir is the directory of the logs of the java instance
+	JavaInstanceLogDir = "logs/functions"
+
+	mebibyte = 1024 * 1024
+)
+
+var jvmGCOptions = map[v1alpha1.JVMGarbageCollector]string{
+	v1alpha1.G1GC:         "-XX:+UseG1GC",
+	v1alpha1.ParallelGC:   "-XX:+UseParallelGC",
+	v1alpha1.SerialGC:     "-XX:+UseSerialGC",
+	v1alpha1.ZGC:          "-XX:+UseZGC",
+	v1alpha1.ShenandoahGC: "-XX:+UseShenandoahGC",
+}
+
+// makeJVMOptions returns the memory, garbage collection and diagnostics options of the JVM of the java instance.
+// Without a jvm block the heap is sized from the memory request of the container
+func makeJVMOptions(name string, java *v1alpha1.JavaRuntime, resources corev1.ResourceRequirements) string {
+	if java == nil || java.JVM == nil {
+		return "-Xmx" + getDecimalSIMemory(resources.Requests.Memory())
+	}
+	jvm := java.JVM
+	memory := v1alpha1.GetJVMMemory(resources)
+	var options []string
+	if !memory.IsZero() {
+		options = append(options,
+			fmt.Sprintf("-Xmx%dm", percentageOfMebibytes(memory, jvm.GetHeapPercentage(memory))),
+			fmt.Sprintf("-XX:MaxDirectMemorySize=%dm", percentageOfMebibytes(memory, jvm.GetDirectMemoryPercentage())))
+	}
+	if jvm.MaxMetaspaceSize != nil {
+		options = append(options, fmt.Sprintf("-XX:MaxMetaspaceSize=%dm", toMebibytes(*jvm.MaxMetaspaceSize)))
+	}
+	if gc, ok := jvmGCOptions[jvm.GetGC(memory)]; ok {
+		options = append(options, gc)
+	}
+	if jvm.GCLogging {
+		options = append(options, fmt.Sprintf(
+			"\"-Xlog:gc*:file=%s/%s-${%s}-gc.log:time,uptime,level,tags:filecount=5,filesize=10m\"",
+			JavaInstanceLogDir, name, EnvShardID))
+	}
+	if jvm.HeapDumpPath != "" {
+		options = append(options, "-XX:+HeapDumpOnOutOfMemoryError",
+			fmt.Sprintf("\"-XX:HeapDumpPath=%s/%s-${%s}-$(date +%%s).hprof\"",
+				strings.TrimSuffix(path.Clean(jvm.HeapDumpPath), "/"), name, EnvShardID))
+	}
+	return strings.Join(options, " ")
+}
+
+func percentageOfMebibytes(memory resource.Quantity, percentage int32) int64 {
+	return memory.Value() * int64(percentage) / 100 / mebibyte
+}
+
+func toMebibytes(quantity resource.Quantity) int64 {
+	return quantity.Value() / mebibyte
+}
@@ -0,0 +1,72 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package spec
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/streamnative/function-mesh/api/compute/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func TestMakeJVMOptions(t *testing.T) {
+	requests := corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("512Mi")},
+	}
+	limits := corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("1G")},
+		Limits:   corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("2Gi")},
+	}
+
+	assert.Equal(t, "-Xmx536870912", makeJVMOptions("test", &v1alpha1.JavaRuntime{Jar: "function.jar"}, requests))
+	assert.Equal(t, "-Xmx1G", makeJVMOptions("test", nil, limits))
+
+	java := &v1alpha1.JavaRuntime{Jar: "function.jar", JVM: &v1alpha1.JVMOptions{}}
+	assert.Equal(t, "-Xmx256m -XX:MaxDirectMemorySize=102m -XX:+UseSerialGC", makeJVMOptions("test", java, requests))
+	assert.Equal(t, "-Xmx1228m -XX:MaxDirectMemorySize=409m -XX:+UseG1GC", makeJVMOptions("test", java, limits))
+
+	heap := int32(70)
+	direct := int32(10)
+	metaspace := resource.MustParse("128Mi")
+	java.JVM = &v1alpha1.JVMOptions{HeapPercentage: &heap, DirectMemoryPercentage: &direct,
+		MaxMetaspaceSize: &metaspace, GC: v1alpha1.ZGC, GCLogging: true, HeapDumpPath: "/dumps/"}
+	assert.Equal(t, strings.Join([]string{
+		"-Xmx1433m",
+		"-XX:MaxDirectMemorySize=204m",
+		"-XX:MaxMetaspaceSize=128m",
+		"-XX:+UseZGC",
+		"\"-Xlog:gc*:file=logs/functions/test-${SHARD_ID}-gc.log:time,uptime,level,tags:filecount=5,filesize=10m\"",
+		"-XX:+HeapDumpOnOutOfMemoryError",
+		"\"-XX:HeapDumpPath=/dumps/test-${SHARD_ID}-$(date +%s).hprof\"",
+	}, " "), makeJVMOptions("test", java, limits))
+}
+
+func TestMakeJavaFunctionCommandWithJVMOptions(t *testing.T) {
+	function := makeFunctionSample("test")
+	function.Spec.Resources = corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("1Gi")},
+		Limits:   corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("2Gi")},
+	}
+	function.Spec.Java.JVM = &v1alpha1.JVMOptions{}
+	command := strings.Join(makeFunctionCommand(function), " ")
+	assert.Contains(t, command, "-Xmx1228m -XX:MaxDirectMemorySize=409m -XX:+UseG1GC")
+	assert.NotContains(t, command, "-Xmx1073741824")
+}
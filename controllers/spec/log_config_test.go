@@ -0,0 +1,187 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package spec
+
+import (
+	"bytes"
+	"flag"
+	"io/ioutil"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/streamnative/function-mesh/api/compute/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+)
+
+var update = flag.Bool("update", false, "update the golden files")
+
+func assertGolden(t *testing.T, name, actual string) {
+	golden := filepath.Join("testdata", "golden", name)
+	if *update {
+		assert.Nil(t, ioutil.WriteFile(golden, []byte(actual), 0644))
+	}
+	expected, err := ioutil.ReadFile(golden)
+	assert.Nil(t, err)
+	assert.Equal(t, string(expected), actual, golden)
+}
+
+func makeRotatePolicy(policy v1alpha1.TriggeringPolicy) *v1alpha1.TriggeringPolicy {
+	return &policy
+}
+
+var logConfigTestData = []struct {
+	name string
+	log  *v1alpha1.RuntimeLogConfig
+}{
+	{"default", nil},
+	{"debug", &v1alpha1.RuntimeLogConfig{Level: v1alpha1.LogLevelDebug}},
+	{"daily", &v1alpha1.RuntimeLogConfig{RotatePolicy: makeRotatePolicy(v1alpha1.TimedPolicyWithDaily)}},
+	{"50mb", &v1alpha1.RuntimeLogConfig{Level: v1alpha1.LogLevelWarn,
+		RotatePolicy: makeRotatePolicy(v1alpha1.SizedPolicyWith50MB)}},
+	{"json", &v1alpha1.RuntimeLogConfig{Format: v1alpha1.LogFormatJSON,
+		RotatePolicy: makeRotatePolicy(v1alpha1.SizedPolicyWith10MB)}},
+	{"pattern", &v1alpha1.RuntimeLogConfig{Pattern: `"%d" ${sys:app} %m%n`}},
+	{"loggers", &v1alpha1.RuntimeLogConfig{Level: v1alpha1.LogLevelError, Loggers: map[string]v1alpha1.LogLevel{
+		"org.apache.pulsar": v1alpha1.LogLevelWarn,
+		"com.example":       v1alpha1.LogLevelDebug,
+	}}},
+}
+
+func TestRenderJavaInstanceLog4jXMLTemplateGolden(t *testing.T) {
+	for _, v := range logConfigTestData {
+		actual, err := renderJavaInstanceLog4jXMLTemplate(&v1alpha1.JavaRuntime{Jar: "function.jar", Log: v.log})
+		assert.Nil(t, err)
+		assertGolden(t, "java-log4j-"+v.name+".xml", actual)
+	}
+}
+
+func TestRenderPythonInstanceLoggingINITemplateGolden(t *testing.T) {
+	for _, v := range logConfigTestData {
+		actual, err := renderPythonInstanceLoggingINITemplate("test-function",
+			&v1alpha1.PythonRuntime{Py: "function.py", Log: v.log})
+		assert.Nil(t, err)
+		assertGolden(t, "python-logging-"+v.name+".ini", actual)
+	}
+}
+
+func TestRenderPythonInstanceLoggingINITemplateWithStdoutGolden(t *testing.T) {
+	actual, err := renderPythonInstanceLoggingINITemplate("test-function", &v1alpha1.PythonRuntime{Py: "function.py",
+		Log: &v1alpha1.RuntimeLogConfig{Format: v1alpha1.LogFormatJSON, StdoutWithLogTopic: true,
+			RotatePolicy: makeRotatePolicy(v1alpha1.TimedPolicyWithDaily),
+			Loggers:      map[string]v1alpha1.LogLevel{"com.example": v1alpha1.LogLevelDebug}}})
+	assert.Nil(t, err)
+	assertGolden(t, "python-logging-stdout.ini", actual)
+}
+
+func TestGeneratePythonLogConfigCommandWithLoggingModule(t *testing.T) {
+	runtime := &v1alpha1.PythonRuntime{Py: "function.py", Log: &v1alpha1.RuntimeLogConfig{}}
+	command := generatePythonLogConfigCommand("test-function", runtime)
+	assert.NotContains(t, command, PythonLoggingModule)
+	assert.NotContains(t, command, "python_instance.py")
+
+	moduleCommand := "echo \"" + escapeShellDoubleQuoted(pythonLoggingModule, true) + "\" > " +
+		PythonLoggingModuleDirectory + PythonLoggingModule + ".py && export PYTHONPATH=" +
+		PythonLoggingModuleDirectory + "${PYTHONPATH:+:${PYTHONPATH}} && "
+	runtime.Log.StdoutWithLogTopic = true
+	assert.Contains(t, generatePythonLogConfigCommand("test-function", runtime), moduleCommand)
+	runtime.Log = &v1alpha1.RuntimeLogConfig{Format: v1alpha1.LogFormatJSON}
+	assert.Contains(t, generatePythonLogConfigCommand("test-function", runtime), moduleCommand)
+}
+
+// TestPythonLoggingModuleWithLogTopic runs the rendered logging configuration with the handler swap of the
+// Python instance, the records of the named and the library loggers are written to both the log topic and stdout
+func TestPythonLoggingModuleWithLogTopic(t *testing.T) {
+	python, err := exec.LookPath("python3")
+	if err != nil {
+		t.Skip("python3 is not installed")
+	}
+	config, err := renderPythonInstanceLoggingINITemplate("test-function", &v1alpha1.PythonRuntime{Py: "function.py",
+		Log: &v1alpha1.RuntimeLogConfig{Format: v1alpha1.LogFormatJSON, StdoutWithLogTopic: true,
+			Loggers: map[string]v1alpha1.LogLevel{"com.example": v1alpha1.LogLevelDebug}}})
+	assert.Nil(t, err)
+	dir := t.TempDir()
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(dir, PythonLoggingModule+".py"), []byte(pythonLoggingModule), 0644))
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(dir, "logging.ini"), []byte(config), 0644))
+	script := `import logging, logging.config, sys
+logging.config.fileConfig('logging.ini')
+root = logging.getLogger()
+topic = []
+
+class LogTopicHandler(logging.Handler):
+    def emit(self, record):
+        topic.append(record.getMessage())
+
+def remove_all_handlers():
+    retval = None
+    for handler in root.handlers:
+        root.handlers.remove(handler)
+        retval = handler
+    return retval
+
+saved = remove_all_handlers()
+root.addHandler(LogTopicHandler())
+logging.getLogger('com.example').info('named')
+logging.getLogger('urllib3').warning('library')
+remove_all_handlers()
+root.addHandler(saved)
+sys.stderr.write(','.join(topic))
+`
+	cmd := exec.Command(python, "-c", script)
+	cmd.Dir = dir
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	stdout, err := cmd.Output()
+	assert.Nil(t, err, stderr.String())
+	assert.Equal(t, "named,library", stderr.String())
+	assert.Contains(t, string(stdout), `"logger": "com.example"`)
+	assert.Contains(t, string(stdout), `"message": "named"`)
+	assert.Contains(t, string(stdout), `"message": "library"`)
+}
+
+func TestParseGolangLogLevel(t *testing.T) {
+	assert.Equal(t, "", parseGolangLogLevel(nil))
+	assert.Equal(t, "info", parseGolangLogLevel(&v1alpha1.GoRuntime{Go: "go-func"}))
+	testData := map[v1alpha1.LogLevel]string{
+		v1alpha1.LogLevelAll:   "trace",
+		v1alpha1.LogLevelTrace: "trace",
+		v1alpha1.LogLevelDebug: "debug",
+		v1alpha1.LogLevelInfo:  "info",
+		v1alpha1.LogLevelWarn:  "warn",
+		v1alpha1.LogLevelError: "error",
+		v1alpha1.LogLevelFatal: "fatal",
+		v1alpha1.LogLevelPanic: "panic",
+		v1alpha1.LogLevelOff:   "panic",
+	}
+	for level, expected := range testData {
+		assert.Equal(t, expected, parseGolangLogLevel(&v1alpha1.GoRuntime{Go: "go-func",
+			Log: &v1alpha1.RuntimeLogConfig{Level: level}}), level)
+	}
+	assert.Equal(t, "", parseGolangLogLevel(&v1alpha1.GoRuntime{Go: "go-func",
+		Log: &v1alpha1.RuntimeLogConfig{Level: v1alpha1.LogLevelDebug, LogConfig: &v1alpha1.LogConfig{Name: "logs"}}}))
+}
+
+func TestGenerateContainerEnvWithGolangLogFormat(t *testing.T) {
+	function := makeGoFunctionSample(TestFunctionName)
+	function.Spec.Golang.Log = &v1alpha1.RuntimeLogConfig{Level: v1alpha1.LogLevelDebug,
+		Format: v1alpha1.LogFormatJSON}
+	envs := generateContainerEnv(function)
+	assert.Contains(t, envs, corev1.EnvVar{Name: EnvGoFunctionLogLevel, Value: "debug"})
+	assert.Contains(t, envs, corev1.EnvVar{Name: EnvGoFunctionLogFormat, Value: "json"})
+}
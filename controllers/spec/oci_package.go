@@ -0,0 +1,122 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package spec
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/streamnative/function-mesh/api/compute/v1alpha1"
+	"github.com/streamnative/function-mesh/utils"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// The packages stored as OCI artifacts are pulled by the downloader init container with oras. The image volume
+// packages are rejected by the validation, see v1alpha1.PackageURLImage.
+const (
+	OCIPrefix          = "oci://"
+	OCIDownloaderImage = "ghcr.io/oras-project/oras:v1.0.0"
+	OrasExecutableFile = "oras"
+
+	ociRegistryConfigVolume = "oci-registry-config"
+	ociRegistryConfigDir    = "/pulsar/oci-registry-config"
+	ociPullDir              = "/tmp/oci-package"
+)
+
+func hasOCIPrefix(packageName string) bool {
+	return strings.HasPrefix(strings.ToLower(packageName), OCIPrefix)
+}
+
+// usesDownloaderContainer returns whether the package is downloaded by the downloader init container instead of
+// the runner container, the OCI packages always need the downloader
+func usesDownloaderContainer(downloadPath string) bool {
+	return utils.EnableInitContainers || hasOCIPrefix(downloadPath)
+}
+
+func getPackageLocation(javaRuntime *v1alpha1.JavaRuntime, pythonRuntime *v1alpha1.PythonRuntime,
+	goRuntime *v1alpha1.GoRuntime) string {
+	if javaRuntime != nil {
+		return javaRuntime.JarLocation
+	}
+	if pythonRuntime != nil {
+		return pythonRuntime.PyLocation
+	}
+	if goRuntime != nil {
+		return goRuntime.GoLocation
+	}
+	return ""
+}
+
+// generateOCIRegistryConfigs mounts the image pull secrets of the pod as registry configs of the downloader,
+// the secrets which are not of the kubernetes.io/dockerconfigjson type are skipped
+func generateOCIRegistryConfigs(pullSecrets []corev1.LocalObjectReference) ([]corev1.Volume, []corev1.VolumeMount,
+	[]string) {
+	var volumes []corev1.Volume
+	var volumeMounts []corev1.VolumeMount
+	var registryConfigs []string
+	optional := true
+	for i, secret := range pullSecrets {
+		name := fmt.Sprintf("%s-%d", ociRegistryConfigVolume, i)
+		mountPath := fmt.Sprintf("%s/%d", ociRegistryConfigDir, i)
+		volumes = append(volumes, corev1.Volume{
+			Name: name,
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName: secret.Name,
+					Items: []corev1.KeyToPath{{
+						Key:  corev1.DockerConfigJsonKey,
+						Path: "config.json",
+					}},
+					Optional: &optional,
+				},
+			},
+		})
+		volumeMounts = append(volumeMounts, corev1.VolumeMount{
+			Name:      name,
+			MountPath: mountPath,
+			ReadOnly:  true,
+		})
+		registryConfigs = append(registryConfigs, mountPath+"/config.json")
+	}
+	return volumes, volumeMounts, registryConfigs
+}
+
+// getOCIDownloadCommand pulls the artifact with each registry config in turn, then anonymously, and moves the
+// pulled file to the component package. oras names the files after the titles of the layers, the file named like
+// the component package is chosen, else the only file of the artifact, the artifacts with several other files
+// are ambiguous and fail the download.
+func getOCIDownloadCommand(downloadPath, componentPackage string, registryConfigs []string) []string {
+	reference := downloadPath[len(OCIPrefix):]
+	var pulls []string
+	for _, registryConfig := range registryConfigs {
+		pulls = append(pulls, fmt.Sprintf("%s pull --registry-config %s %s -o %s", OrasExecutableFile,
+			registryConfig, reference, ociPullDir))
+	}
+	pulls = append(pulls, fmt.Sprintf("%s pull %s -o %s", OrasExecutableFile, reference, ociPullDir))
+	pulledFile := ociPullDir + "/" + path.Base(componentPackage)
+	return []string{
+		"mkdir -p " + ociPullDir,
+		"&& { " + strings.Join(pulls, " || ") + "; }",
+		"&& if [ -f " + pulledFile + " ]; then mv " + pulledFile + " " + componentPackage + ";",
+		"elif [ \"$(find " + ociPullDir + " -type f | wc -l)\" -eq 1 ]; then",
+		"mv \"$(find " + ociPullDir + " -type f)\" " + componentPackage + ";",
+		"else echo \"" + reference + " must contain " + path.Base(componentPackage) + " or a single file\" >&2;",
+		"exit 1; fi",
+	}
+}
@@ -0,0 +1,80 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package spec
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/streamnative/function-mesh/utils"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestGetOCIDownloadCommand(t *testing.T) {
+	selectCommand := "if [ -f /tmp/oci-package/test.jar ]; then mv /tmp/oci-package/test.jar /pulsar/download/test.jar; " +
+		"elif [ \"$(find /tmp/oci-package -type f | wc -l)\" -eq 1 ]; then " +
+		"mv \"$(find /tmp/oci-package -type f)\" /pulsar/download/test.jar; " +
+		"else echo \"registry.example.com/team/function:v1 must contain test.jar or a single file\" >&2; exit 1; fi"
+	command := getOCIDownloadCommand("oci://registry.example.com/team/function:v1", "/pulsar/download/test.jar", nil)
+	assert.Equal(t, "mkdir -p /tmp/oci-package && "+
+		"{ oras pull registry.example.com/team/function:v1 -o /tmp/oci-package; } && "+
+		selectCommand, strings.Join(command, " "))
+
+	command = getOCIDownloadCommand("oci://registry.example.com/team/function:v1", "/pulsar/download/test.jar",
+		[]string{"/pulsar/oci-registry-config/0/config.json"})
+	assert.Equal(t, "mkdir -p /tmp/oci-package && "+
+		"{ oras pull --registry-config /pulsar/oci-registry-config/0/config.json registry.example.com/team/function:v1 "+
+		"-o /tmp/oci-package || oras pull registry.example.com/team/function:v1 -o /tmp/oci-package; } && "+
+		selectCommand, strings.Join(command, " "))
+}
+
+func TestMakeFunctionStatefulSetWithOCIPackage(t *testing.T) {
+	enableInitContainers := utils.EnableInitContainers
+	utils.EnableInitContainers = false
+	defer func() { utils.EnableInitContainers = enableInitContainers }()
+	function := makeFunctionSample(TestFunctionName)
+	function.Spec.Java.JarLocation = "oci://registry.example.com/team/function:v1"
+	function.Spec.Pod.ImagePullSecrets = []corev1.LocalObjectReference{{Name: "registry"}}
+
+	statefulSet := MakeFunctionStatefulSet(function)
+	podSpec := statefulSet.Spec.Template.Spec
+
+	// the OCI packages are downloaded by the init container even if it is not enabled
+	assert.Len(t, podSpec.InitContainers, 1)
+	downloader := podSpec.InitContainers[0]
+	assert.Equal(t, OCIDownloaderImage, downloader.Image)
+	assert.Contains(t, downloader.Command[2], "oras pull --registry-config /pulsar/oci-registry-config/0/config.json "+
+		"registry.example.com/team/function:v1")
+	assert.Contains(t, downloader.Command[2], "/pulsar/download/pulsar-functions-api-examples.jar")
+	assert.Contains(t, downloader.VolumeMounts, corev1.VolumeMount{Name: "oci-registry-config-0",
+		MountPath: "/pulsar/oci-registry-config/0", ReadOnly: true})
+	var registryVolume *corev1.Volume
+	for i := range podSpec.Volumes {
+		if podSpec.Volumes[i].Name == "oci-registry-config-0" {
+			registryVolume = &podSpec.Volumes[i]
+		}
+	}
+	assert.NotNil(t, registryVolume)
+	assert.Equal(t, "registry", registryVolume.Secret.SecretName)
+
+	// the runner container only runs the downloaded package
+	assert.NotContains(t, podSpec.Containers[0].Command[2], "download")
+	assert.Contains(t, podSpec.Containers[0].VolumeMounts, corev1.VolumeMount{Name: DownloaderVolume,
+		MountPath: "/pulsar/pulsar-functions-api-examples.jar", SubPath: "pulsar-functions-api-examples.jar"})
+}
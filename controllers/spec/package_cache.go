@@ -0,0 +1,144 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package spec
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/streamnative/function-mesh/api/compute/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	PackageCacheVolume = "package-cache-volume"
+	PackageCacheDir    = "/pulsar/package-cache"
+)
+
+// PackageCacheConfig caches the downloaded packages, so that the pods using the same package fetch it once,
+// exactly one of the volume sources must be set
+type PackageCacheConfig struct {
+	// HostPath caches the packages on the nodes
+	HostPath string `yaml:"hostPath,omitempty"`
+	// PersistentVolumeClaim caches the packages on a claim of the namespace, the claim must support the
+	// ReadWriteMany access mode to be shared by the pods
+	PersistentVolumeClaim string `yaml:"persistentVolumeClaim,omitempty"`
+}
+
+func (c *PackageCacheConfig) validate(prefix string) []error {
+	if c == nil {
+		return nil
+	}
+	if (c.HostPath == "") == (c.PersistentVolumeClaim == "") {
+		return []error{fmt.Errorf("%spackageCache: exactly one of hostPath and persistentVolumeClaim must be set",
+			prefix)}
+	}
+	if c.HostPath != "" && !strings.HasPrefix(c.HostPath, "/") {
+		return []error{fmt.Errorf("%spackageCache: hostPath %q must be absolute", prefix, c.HostPath)}
+	}
+	return nil
+}
+
+func (c *PackageCacheConfig) makeVolume() corev1.Volume {
+	if c.HostPath != "" {
+		hostPathType := corev1.HostPathDirectoryOrCreate
+		return corev1.Volume{
+			Name: PackageCacheVolume,
+			VolumeSource: corev1.VolumeSource{
+				HostPath: &corev1.HostPathVolumeSource{
+					Path: c.HostPath,
+					Type: &hostPathType,
+				},
+			},
+		}
+	}
+	return corev1.Volume{
+		Name: PackageCacheVolume,
+		VolumeSource: corev1.VolumeSource{
+			PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+				ClaimName: c.PersistentVolumeClaim,
+			},
+		},
+	}
+}
+
+// isPackageCached returns whether the packages of the components of a namespace go through the package cache
+func isPackageCached(namespace string) bool {
+	return GetConfigs().DefaultsFor(namespace).PackageCache != nil
+}
+
+func makePackageCacheVolumeMount() corev1.VolumeMount {
+	return corev1.VolumeMount{
+		Name:      PackageCacheVolume,
+		MountPath: PackageCacheDir,
+	}
+}
+
+// makePackageCacheKey keys the cached packages by their expected checksum, so that a package is only reused
+// when it has the same content whatever its location
+func makePackageCacheKey(checksum string) string {
+	return strings.ToLower(checksum)
+}
+
+// makeDownloadCommand wraps the command downloading a package with the package cache and the verification of
+// the package checksum when they are configured, only the packages with a checksum are cached as the location
+// of a package does not tell whether its content changed
+func makeDownloadCommand(downloadCommand []string, packageFile, checksum string, cached bool) string {
+	command := strings.Join(downloadCommand, " ")
+	if checksum == "" {
+		return command
+	}
+	if !cached {
+		return command + " && " + makePackageVerificationCommand(packageFile, checksum, "")
+	}
+	cachedFile := PackageCacheDir + "/" + makePackageCacheKey(checksum)
+	tempFile := cachedFile + ".$HOSTNAME"
+	// a failure to fill the cache does not fail the download
+	command = fmt.Sprintf("if [ -f %[1]s ]; then cp %[1]s %[2]s; "+
+		"else { %[3]s; } && { cp %[2]s %[4]s && mv %[4]s %[1]s || true; }; fi",
+		cachedFile, packageFile, command, tempFile)
+	// a corrupted cached package is removed, so that the restarted pod downloads it again
+	return command + " && " + makePackageVerificationCommand(packageFile, checksum, cachedFile)
+}
+
+// makePackageVerificationCommand checks the package against the expected checksum, a mismatch is reported
+// through the termination message of the container
+func makePackageVerificationCommand(packageFile, checksum, cachedFile string) string {
+	cleanup := ""
+	if cachedFile != "" {
+		cleanup = "rm -f " + cachedFile + "; "
+	}
+	return fmt.Sprintf("{ echo \"%s  %s\" | sha256sum -c - >/dev/null 2>&1 || "+
+		"{ %secho \"%s: the sha256 checksum of %s does not match %s\" | tee /dev/termination-log; exit 1; }; }",
+		strings.ToLower(checksum), packageFile, cleanup, v1alpha1.PackageVerificationFailed, packageFile, checksum)
+}
+
+// GetPackageVerificationFailure returns the termination message of a pod container that failed to verify the
+// downloaded package
+func GetPackageVerificationFailure(pod *corev1.Pod) (string, bool) {
+	statuses := append([]corev1.ContainerStatus{}, pod.Status.InitContainerStatuses...)
+	statuses = append(statuses, pod.Status.ContainerStatuses...)
+	for _, status := range statuses {
+		for _, state := range []corev1.ContainerState{status.State, status.LastTerminationState} {
+			if state.Terminated != nil && strings.HasPrefix(state.Terminated.Message, v1alpha1.PackageVerificationFailed) {
+				return strings.TrimSpace(state.Terminated.Message), true
+			}
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,177 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package spec
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/streamnative/function-mesh/api/compute/v1alpha1"
+	"github.com/streamnative/function-mesh/utils"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const testPackageSha256 = "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824"
+
+func TestMakeDownloadCommand(t *testing.T) {
+	download := []string{"wget", "http://example.com/test.jar", "-O", "/pulsar/download/test.jar"}
+
+	command := makeDownloadCommand(download, "/pulsar/download/test.jar", "", false)
+	assert.Equal(t, "wget http://example.com/test.jar -O /pulsar/download/test.jar", command)
+
+	command = makeDownloadCommand(download, "/pulsar/download/test.jar", testPackageSha256, false)
+	assert.Equal(t, "wget http://example.com/test.jar -O /pulsar/download/test.jar && "+
+		"{ echo \""+testPackageSha256+"  /pulsar/download/test.jar\" | sha256sum -c - >/dev/null 2>&1 || "+
+		"{ echo \"PackageVerificationFailed: the sha256 checksum of /pulsar/download/test.jar does not match "+
+		testPackageSha256+"\" | tee /dev/termination-log; exit 1; }; }", command)
+
+	// the packages without a checksum are not cached
+	command = makeDownloadCommand(download, "/pulsar/download/test.jar", "", true)
+	assert.Equal(t, "wget http://example.com/test.jar -O /pulsar/download/test.jar", command)
+
+	cachedFile := PackageCacheDir + "/" + testPackageSha256
+	command = makeDownloadCommand(download, "/pulsar/download/test.jar", testPackageSha256, true)
+	assert.Equal(t, "if [ -f "+cachedFile+" ]; then cp "+cachedFile+" /pulsar/download/test.jar; "+
+		"else { wget http://example.com/test.jar -O /pulsar/download/test.jar; } && "+
+		"{ cp /pulsar/download/test.jar "+cachedFile+".$HOSTNAME && mv "+cachedFile+".$HOSTNAME "+cachedFile+
+		" || true; }; fi && "+makePackageVerificationCommand("/pulsar/download/test.jar", testPackageSha256,
+		cachedFile), command)
+	assert.Contains(t, command, "{ rm -f "+cachedFile+"; echo \"PackageVerificationFailed")
+}
+
+func TestMakePackageCacheKey(t *testing.T) {
+	assert.Equal(t, testPackageSha256, makePackageCacheKey(testPackageSha256))
+	assert.Equal(t, testPackageSha256, makePackageCacheKey(strings.ToUpper(testPackageSha256)))
+}
+
+func TestGetPackageVerificationFailure(t *testing.T) {
+	pod := &corev1.Pod{}
+	_, failed := GetPackageVerificationFailure(pod)
+	assert.False(t, failed)
+
+	pod.Status.InitContainerStatuses = []corev1.ContainerStatus{{
+		Name:  DownloaderName,
+		State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}},
+		LastTerminationState: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{
+			ExitCode: 1,
+			Message:  "PackageVerificationFailed: the sha256 checksum of test.jar does not match abc\n",
+		}},
+	}}
+	message, failed := GetPackageVerificationFailure(pod)
+	assert.True(t, failed)
+	assert.Equal(t, "PackageVerificationFailed: the sha256 checksum of test.jar does not match abc", message)
+
+	pod.Status.InitContainerStatuses[0].LastTerminationState.Terminated.Message = "download failed"
+	_, failed = GetPackageVerificationFailure(pod)
+	assert.False(t, failed)
+}
+
+func TestPackageCacheConfigValidate(t *testing.T) {
+	_, err := LoadControllerConfigs([]byte("packageCache:\n  hostPath: /var/cache/function-mesh\n"))
+	assert.Nil(t, err)
+	_, err = LoadControllerConfigs([]byte("namespaces:\n  team-a:\n    packageCache:\n      persistentVolumeClaim: packages\n"))
+	assert.Nil(t, err)
+	_, err = LoadControllerConfigs([]byte("packageCache:\n  hostPath: cache\n"))
+	assert.NotNil(t, err)
+	_, err = LoadControllerConfigs([]byte("packageCache:\n  hostPath: /cache\n  persistentVolumeClaim: packages\n"))
+	assert.NotNil(t, err)
+	_, err = LoadControllerConfigs([]byte("packageCache: {}\n"))
+	assert.NotNil(t, err)
+}
+
+func TestMakeFunctionStatefulSetWithPackageCache(t *testing.T) {
+	enableInitContainers := utils.EnableInitContainers
+	utils.EnableInitContainers = true
+	defer func() {
+		utils.EnableInitContainers = enableInitContainers
+		SetConfigs(DefaultConfigs())
+	}()
+	configs := DefaultConfigs()
+	configs.PackageCache = &PackageCacheConfig{HostPath: "/var/cache/function-mesh"}
+	configs.Namespaces = map[string]ComponentDefaults{
+		"other": {PackageCache: &PackageCacheConfig{PersistentVolumeClaim: "packages"}},
+	}
+	SetConfigs(configs)
+
+	function := makeFunctionSample(TestFunctionName)
+	function.Spec.Java.JarLocation = "function://public/default/test@v1"
+	function.Spec.Java.Sha256 = testPackageSha256
+
+	statefulSet := MakeFunctionStatefulSet(function)
+	podSpec := statefulSet.Spec.Template.Spec
+	assert.Len(t, podSpec.InitContainers, 1)
+	downloader := podSpec.InitContainers[0]
+	assert.Contains(t, downloader.VolumeMounts, corev1.VolumeMount{Name: PackageCacheVolume, MountPath: PackageCacheDir})
+	assert.Contains(t, downloader.Command[2], PackageCacheDir+"/"+makePackageCacheKey(testPackageSha256))
+	assert.Contains(t, downloader.Command[2], testPackageSha256+"  /pulsar/download/pulsar-functions-api-examples.jar")
+	var cacheVolume *corev1.Volume
+	for i := range podSpec.Volumes {
+		if podSpec.Volumes[i].Name == PackageCacheVolume {
+			cacheVolume = &podSpec.Volumes[i]
+		}
+	}
+	assert.NotNil(t, cacheVolume)
+	assert.Equal(t, "/var/cache/function-mesh", cacheVolume.HostPath.Path)
+
+	function.Namespace = "other"
+	statefulSet = MakeFunctionStatefulSet(function)
+	for _, volume := range statefulSet.Spec.Template.Spec.Volumes {
+		if volume.Name == PackageCacheVolume {
+			assert.Equal(t, "packages", volume.PersistentVolumeClaim.ClaimName)
+		}
+	}
+
+	// the package is verified without the cache as well
+	SetConfigs(DefaultConfigs())
+	statefulSet = MakeFunctionStatefulSet(function)
+	downloader = statefulSet.Spec.Template.Spec.InitContainers[0]
+	assert.NotContains(t, downloader.Command[2], PackageCacheDir)
+	assert.Contains(t, downloader.Command[2], v1alpha1.PackageVerificationFailed)
+}
+
+func TestMakeFunctionStatefulSetWithPackageCacheWithoutDownloader(t *testing.T) {
+	enableInitContainers := utils.EnableInitContainers
+	utils.EnableInitContainers = false
+	defer func() {
+		utils.EnableInitContainers = enableInitContainers
+		SetConfigs(DefaultConfigs())
+	}()
+	configs := DefaultConfigs()
+	configs.PackageCache = &PackageCacheConfig{HostPath: "/var/cache/function-mesh"}
+	SetConfigs(configs)
+
+	function := makeFunctionSample(TestFunctionName)
+	function.Spec.Java.JarLocation = "function://public/default/test@v1"
+	function.Spec.Java.Sha256 = testPackageSha256
+
+	// the instance container downloads the package through the cache
+	statefulSet := MakeFunctionStatefulSet(function)
+	podSpec := statefulSet.Spec.Template.Spec
+	assert.Empty(t, podSpec.InitContainers)
+	container := podSpec.Containers[0]
+	assert.Contains(t, container.VolumeMounts, corev1.VolumeMount{Name: PackageCacheVolume, MountPath: PackageCacheDir})
+	assert.Contains(t, container.Command[2], PackageCacheDir+"/"+makePackageCacheKey(testPackageSha256))
+	cacheVolumes := 0
+	for _, volume := range podSpec.Volumes {
+		if volume.Name == PackageCacheVolume {
+			cacheVolumes++
+		}
+	}
+	assert.Equal(t, 1, cacheVolumes)
+}
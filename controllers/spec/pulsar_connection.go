@@ -0,0 +1,109 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package spec
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+
+	"github.com/streamnative/function-mesh/api/compute/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	pulsarConnectionConfigMapSuffix = "-pulsar-connection"
+
+	webServiceURLKey    = "webServiceURL"
+	brokerServiceURLKey = "brokerServiceURL"
+)
+
+// MakePulsarConnectionConfigMapName returns the name of the config map generated from a PulsarConnection
+func MakePulsarConnectionConfigMapName(connection *v1alpha1.PulsarConnection) string {
+	return connection.Name + pulsarConnectionConfigMapSuffix
+}
+
+// MakePulsarConnectionConfigMap makes the config map with the service URLs of a PulsarConnection, the
+// components load it the same way as the pulsar config of their messaging
+func MakePulsarConnectionConfigMap(connection *v1alpha1.PulsarConnection) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "ConfigMap",
+			APIVersion: "v1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      MakePulsarConnectionConfigMapName(connection),
+			Namespace: connection.Namespace,
+			Labels: map[string]string{
+				"app.kubernetes.io/managed-by":              "function-mesh",
+				"compute.functionmesh.io/pulsar-connection": connection.Name,
+			},
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(connection, connection.GroupVersionKind()),
+			},
+		},
+		Data: map[string]string{
+			webServiceURLKey:    connection.Spec.WebServiceURL,
+			brokerServiceURLKey: connection.Spec.BrokerServiceURL,
+		},
+	}
+}
+
+// GetPulsarConnectionName returns the PulsarConnection used by a component, an empty name is returned when
+// the component sets its pulsar config directly or gets it from the controller defaults. The default connection
+// of the namespace is optional, a component that does not name a connection is left as is when it does not exist
+func GetPulsarConnectionName(namespace string, messaging v1alpha1.Messaging) string {
+	if messaging.Pulsar != nil && messaging.Pulsar.PulsarConnection != "" {
+		return messaging.Pulsar.PulsarConnection
+	}
+	if pulsar := MakePulsarMessaging(namespace, messaging); pulsar != nil && pulsar.PulsarConfig != "" {
+		return ""
+	}
+	return v1alpha1.DefaultPulsarConnectionName
+}
+
+// ApplyPulsarConnection fills the messaging of a component from a PulsarConnection, the settings of the
+// component win. The checksum of the connection is added to the pod annotations so that the pods are
+// rolled when the connection changes.
+func ApplyPulsarConnection(messaging *v1alpha1.Messaging, policy *v1alpha1.PodPolicy,
+	connection *v1alpha1.PulsarConnection) {
+	defaults := v1alpha1.Messaging{
+		Pulsar: &v1alpha1.PulsarMessaging{
+			PulsarConfig: MakePulsarConnectionConfigMapName(connection),
+			AuthSecret:   connection.Spec.AuthSecret,
+			TLSConfig:    connection.Spec.TLSConfig,
+			AuthConfig:   connection.Spec.AuthConfig,
+		},
+	}
+	if messaging.Pulsar != nil {
+		messaging.Pulsar.PulsarConnection = ""
+	}
+	*messaging = mergeMessaging(*messaging, defaults)
+	policy.Annotations = mergeLabels(policy.Annotations, map[string]string{
+		AnnotationPulsarConnectionChecksum: makePulsarConnectionChecksum(connection),
+	})
+}
+
+func makePulsarConnectionChecksum(connection *v1alpha1.PulsarConnection) string {
+	data, err := json.Marshal(connection.Spec)
+	if err != nil {
+		return ""
+	}
+	return fmt.Sprintf("%x", sha256.Sum256(data))
+}
@@ -0,0 +1,122 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package spec
+
+import (
+	"testing"
+
+	"github.com/streamnative/function-mesh/api/compute/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func makePulsarConnectionSample(name string) *v1alpha1.PulsarConnection {
+	return &v1alpha1.PulsarConnection{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "PulsarConnection",
+			APIVersion: "compute.functionmesh.io/v1alpha1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: TestNameSpace,
+			UID:       "dead-beef",
+		},
+		Spec: v1alpha1.PulsarConnectionSpec{
+			WebServiceURL:    "http://pulsar-broker:8080",
+			BrokerServiceURL: "pulsar://pulsar-broker:6650",
+			AuthSecret:       "pulsar-auth",
+			TLSConfig: &v1alpha1.PulsarTLSConfig{
+				TLSConfig: v1alpha1.TLSConfig{
+					Enabled:        true,
+					CertSecretName: "pulsar-tls",
+					CertSecretKey:  "ca.crt",
+				},
+			},
+		},
+	}
+}
+
+func TestMakePulsarConnectionConfigMap(t *testing.T) {
+	connection := makePulsarConnectionSample("default")
+	configMap := MakePulsarConnectionConfigMap(connection)
+	assert.Equal(t, "default-pulsar-connection", configMap.Name)
+	assert.Equal(t, TestNameSpace, configMap.Namespace)
+	assert.Equal(t, map[string]string{
+		"webServiceURL":    "http://pulsar-broker:8080",
+		"brokerServiceURL": "pulsar://pulsar-broker:6650",
+	}, configMap.Data)
+	assert.Len(t, configMap.OwnerReferences, 1)
+	assert.Equal(t, "PulsarConnection", configMap.OwnerReferences[0].Kind)
+}
+
+func TestGetPulsarConnectionName(t *testing.T) {
+	assert.Equal(t, "", GetPulsarConnectionName(TestNameSpace, v1alpha1.Messaging{
+		Pulsar: &v1alpha1.PulsarMessaging{PulsarConfig: "pulsar-config"},
+	}))
+	assert.Equal(t, "team-a", GetPulsarConnectionName(TestNameSpace, v1alpha1.Messaging{
+		Pulsar: &v1alpha1.PulsarMessaging{PulsarConnection: "team-a"},
+	}))
+	assert.Equal(t, v1alpha1.DefaultPulsarConnectionName, GetPulsarConnectionName(TestNameSpace, v1alpha1.Messaging{}))
+	assert.Equal(t, v1alpha1.DefaultPulsarConnectionName, GetPulsarConnectionName(TestNameSpace, v1alpha1.Messaging{
+		Pulsar: &v1alpha1.PulsarMessaging{AuthSecret: "auth"},
+	}))
+}
+
+func TestApplyPulsarConnection(t *testing.T) {
+	connection := makePulsarConnectionSample("default")
+
+	messaging := v1alpha1.Messaging{}
+	policy := v1alpha1.PodPolicy{}
+	ApplyPulsarConnection(&messaging, &policy, connection)
+	assert.Equal(t, "default-pulsar-connection", messaging.Pulsar.PulsarConfig)
+	assert.Equal(t, "pulsar-auth", messaging.Pulsar.AuthSecret)
+	assert.Equal(t, "pulsar-tls", messaging.Pulsar.TLSConfig.CertSecretName)
+	checksum := policy.Annotations[AnnotationPulsarConnectionChecksum]
+	assert.NotEmpty(t, checksum)
+
+	// the settings of the component win and a changed connection changes the checksum
+	messaging = v1alpha1.Messaging{Pulsar: &v1alpha1.PulsarMessaging{
+		PulsarConnection: "default",
+		AuthSecret:       "custom-auth",
+	}}
+	policy = v1alpha1.PodPolicy{Annotations: map[string]string{"foo": "bar"}}
+	connection.Spec.AuthSecret = "rotated-auth"
+	ApplyPulsarConnection(&messaging, &policy, connection)
+	assert.Equal(t, "default-pulsar-connection", messaging.Pulsar.PulsarConfig)
+	assert.Equal(t, "", messaging.Pulsar.PulsarConnection)
+	assert.Equal(t, "custom-auth", messaging.Pulsar.AuthSecret)
+	assert.Equal(t, "bar", policy.Annotations["foo"])
+	assert.NotEqual(t, checksum, policy.Annotations[AnnotationPulsarConnectionChecksum])
+
+	// the statefulSet loads the service URLs from the generated config map and mounts the TLS secret
+	function := makeFunctionSample("connection-function")
+	function.Spec.Pulsar = nil
+	ApplyPulsarConnection(&function.Spec.Messaging, &function.Spec.Pod, makePulsarConnectionSample("default"))
+	statefulSet := MakeFunctionStatefulSet(function)
+	pod := statefulSet.Spec.Template
+	container := pod.Spec.Containers[len(pod.Spec.Containers)-1]
+	assert.Equal(t, "default-pulsar-connection", container.EnvFrom[0].ConfigMapRef.Name)
+	assert.Contains(t, pod.Annotations, AnnotationPulsarConnectionChecksum)
+	found := false
+	for _, volume := range pod.Spec.Volumes {
+		if volume.Secret != nil && volume.Secret.SecretName == "pulsar-tls" {
+			found = true
+		}
+	}
+	assert.True(t, found)
+}
@@ -0,0 +1,161 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package spec
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/streamnative/function-mesh/api/compute/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	PythonDependenciesName   = "python-dependencies"
+	PythonDependenciesVolume = "python-dependencies-volume"
+	PythonDependenciesDir    = "/pulsar/python-dependencies"
+	PythonSitePackagesDir    = PythonDependenciesDir + "/site-packages"
+	EnvPythonPath            = "PYTHONPATH"
+
+	pythonWheelhouseDir = PythonDependenciesDir + "/wheelhouse"
+)
+
+// getPythonDependencyArgs returns the python instance args installing the requirements of the package, the
+// instance skips the installation when the dependencies are installed by the init container
+func getPythonDependencyArgs(python *v1alpha1.PythonRuntime) []string {
+	if python != nil && python.HasDependencies() {
+		return []string{"--install_usercode_dependencies", "false"}
+	}
+	args := []string{"--install_usercode_dependencies", "true"}
+	if python != nil && python.DependencyRepository != "" {
+		args = append(args, "--dependency_repository", python.DependencyRepository)
+	}
+	if python != nil && python.ExtraDependencyRepository != "" {
+		args = append(args, "--extra_dependency_repository", python.ExtraDependencyRepository)
+	}
+	return args
+}
+
+// makePythonDependenciesContainer returns the init container installing the python dependencies into the volume
+// shared with the function container, it runs the python runner image which provides pip and pulsar-admin
+func makePythonDependenciesContainer(function *v1alpha1.Function) *corev1.Container {
+	python := function.Spec.Python
+	if python == nil || !python.HasDependencies() {
+		return nil
+	}
+	pulsar := function.Spec.Pulsar
+	volumeMounts := []corev1.VolumeMount{{
+		Name:      PythonDependenciesVolume,
+		MountPath: PythonDependenciesDir,
+	}}
+	if python.DependenciesLocation != "" && !hasHTTPPrefix(python.DependenciesLocation) {
+		if pulsar.AuthConfig != nil && pulsar.AuthConfig.OAuth2Config != nil {
+			volumeMounts = append(volumeMounts, generateVolumeMountFromOAuth2Config(pulsar.AuthConfig.OAuth2Config))
+		}
+		if !reflect.ValueOf(pulsar.TLSConfig).IsNil() && pulsar.TLSConfig.HasSecretVolume() {
+			volumeMounts = append(volumeMounts, generateVolumeMountFromTLSConfig(pulsar.TLSConfig))
+		}
+	}
+	imagePullPolicy := function.Spec.ImagePullPolicy
+	if imagePullPolicy == "" {
+		imagePullPolicy = corev1.PullIfNotPresent
+	}
+	return &corev1.Container{
+		Name:            PythonDependenciesName,
+		Image:           getFunctionRunnerImage(&function.Spec),
+		Command:         []string{"sh", "-c", makePythonDependenciesCommand(python, pulsar)},
+		ImagePullPolicy: imagePullPolicy,
+		Env: []corev1.EnvVar{{
+			Name:  "HOME",
+			Value: "/tmp",
+		}},
+		EnvFrom:      generateContainerEnvFrom(pulsar.PulsarConfig, pulsar.AuthSecret, pulsar.TLSSecret),
+		VolumeMounts: volumeMounts,
+	}
+}
+
+func makePythonDependenciesCommand(python *v1alpha1.PythonRuntime, pulsar *v1alpha1.PulsarMessaging) string {
+	var commands []string
+	pipArgs := []string{"pip", "install", "--no-cache-dir", "--target", PythonSitePackagesDir}
+	if python.DependencyRepository != "" {
+		pipArgs = append(pipArgs, "--index-url", python.DependencyRepository)
+	}
+	if python.ExtraDependencyRepository != "" {
+		pipArgs = append(pipArgs, "--extra-index-url", python.ExtraDependencyRepository)
+	}
+	offline := python.DependencyRepository == "" && python.ExtraDependencyRepository == ""
+	var packages []string
+	for _, requirement := range python.Requirements {
+		packages = append(packages, "'"+requirement+"'")
+	}
+	if python.DependenciesLocation != "" {
+		archive := fmt.Sprintf("%s/%s", PythonDependenciesDir, getFilenameOfComponentPackage(python.DependenciesLocation))
+		commands = append(commands, getPythonDependenciesDownloadCommand(python.DependenciesLocation, archive, pulsar))
+		switch python.GetDependenciesType() {
+		case v1alpha1.PythonDependenciesWheel:
+			if offline {
+				pipArgs = append(pipArgs, "--no-index")
+			}
+			packages = append([]string{archive}, packages...)
+		case v1alpha1.PythonDependenciesWheelhouse:
+			commands = append(commands, fmt.Sprintf("python -m zipfile -e %s %s", archive, pythonWheelhouseDir))
+			if offline {
+				pipArgs = append(pipArgs, "--no-index")
+			}
+			pipArgs = append(pipArgs, "--find-links", pythonWheelhouseDir)
+			if len(packages) == 0 {
+				packages = []string{pythonWheelhouseDir + "/*.whl"}
+			}
+		case v1alpha1.PythonDependenciesVirtualenv:
+			commands = append(commands, fmt.Sprintf("python -m zipfile -e %s %s", archive, PythonSitePackagesDir))
+		}
+	}
+	if len(packages) > 0 {
+		commands = append(commands, strings.Join(append(pipArgs, packages...), " "))
+	}
+	return strings.Join(commands, " && ")
+}
+
+// getPythonDependenciesDownloadCommand downloads the dependencies package with the tools of the runner image
+func getPythonDependenciesDownloadCommand(location, archive string, pulsar *v1alpha1.PulsarMessaging) string {
+	if hasHTTPPrefix(location) {
+		return fmt.Sprintf("python -c \"import sys, urllib.request; urllib.request.urlretrieve(sys.argv[1], sys.argv[2])\" %s %s",
+			location, archive)
+	}
+	return strings.Join(getLegacyDownloadCommand(location, archive, pulsar.AuthSecret != "", pulsar.TLSSecret != "",
+		pulsar.TLSConfig, pulsar.AuthConfig), " ")
+}
+
+func generatePythonDependenciesVolumes(python *v1alpha1.PythonRuntime) []corev1.Volume {
+	if python == nil || !python.HasDependencies() {
+		return nil
+	}
+	return []corev1.Volume{{Name: PythonDependenciesVolume}}
+}
+
+func generatePythonDependenciesVolumeMounts(python *v1alpha1.PythonRuntime) []corev1.VolumeMount {
+	if python == nil || !python.HasDependencies() {
+		return nil
+	}
+	return []corev1.VolumeMount{{
+		Name:      PythonDependenciesVolume,
+		MountPath: PythonDependenciesDir,
+		ReadOnly:  true,
+	}}
+}
@@ -0,0 +1,135 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package spec
+
+import (
+	"testing"
+
+	"github.com/streamnative/function-mesh/api/compute/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func makePythonFunctionSample(python *v1alpha1.PythonRuntime) *v1alpha1.Function {
+	function := makeFunctionSample(TestFunctionName)
+	function.Spec.Java = nil
+	function.Spec.ClassName = "exclamation_function.ExclamationFunction"
+	function.Spec.Python = python
+	return function
+}
+
+func TestGetPythonDependencyArgs(t *testing.T) {
+	assert.Equal(t, []string{"--install_usercode_dependencies", "true"}, getPythonDependencyArgs(nil))
+	assert.Equal(t, []string{"--install_usercode_dependencies", "true",
+		"--dependency_repository", "https://pypi.example.com/simple",
+		"--extra_dependency_repository", "https://extra.example.com/simple"},
+		getPythonDependencyArgs(&v1alpha1.PythonRuntime{
+			Py:                        "function.zip",
+			DependencyRepository:      "https://pypi.example.com/simple",
+			ExtraDependencyRepository: "https://extra.example.com/simple",
+		}))
+	assert.Equal(t, []string{"--install_usercode_dependencies", "false"},
+		getPythonDependencyArgs(&v1alpha1.PythonRuntime{Py: "function.py", Requirements: []string{"requests"}}))
+}
+
+func TestMakePythonDependenciesCommand(t *testing.T) {
+	pulsar := &v1alpha1.PulsarMessaging{PulsarConfig: "test-pulsar"}
+	testData := []struct {
+		python   *v1alpha1.PythonRuntime
+		expected string
+	}{
+		{
+			&v1alpha1.PythonRuntime{
+				Requirements:         []string{"requests>=2.28", "protobuf==3.20.1"},
+				DependencyRepository: "https://pypi.example.com/simple",
+			},
+			"pip install --no-cache-dir --target /pulsar/python-dependencies/site-packages " +
+				"--index-url https://pypi.example.com/simple 'requests>=2.28' 'protobuf==3.20.1'",
+		},
+		{
+			&v1alpha1.PythonRuntime{DependenciesLocation: "https://example.com/deps/requests-2.28.1-py3-none-any.whl"},
+			"python -c \"import sys, urllib.request; urllib.request.urlretrieve(sys.argv[1], sys.argv[2])\" " +
+				"https://example.com/deps/requests-2.28.1-py3-none-any.whl " +
+				"/pulsar/python-dependencies/requests-2.28.1-py3-none-any.whl && " +
+				"pip install --no-cache-dir --target /pulsar/python-dependencies/site-packages --no-index " +
+				"/pulsar/python-dependencies/requests-2.28.1-py3-none-any.whl",
+		},
+		{
+			&v1alpha1.PythonRuntime{
+				DependenciesLocation: "function://public/default/wheels@v1",
+				DependenciesType:     v1alpha1.PythonDependenciesWheelhouse,
+				Requirements:         []string{"requests"},
+			},
+			"/pulsar/bin/pulsar-admin --admin-url $webServiceURL packages download function://public/default/wheels@v1 " +
+				"--path /pulsar/python-dependencies/wheels@v1 && " +
+				"python -m zipfile -e /pulsar/python-dependencies/wheels@v1 /pulsar/python-dependencies/wheelhouse && " +
+				"pip install --no-cache-dir --target /pulsar/python-dependencies/site-packages --no-index " +
+				"--find-links /pulsar/python-dependencies/wheelhouse 'requests'",
+		},
+		{
+			&v1alpha1.PythonRuntime{
+				DependenciesLocation: "https://example.com/deps/wheelhouse.zip",
+				DependenciesType:     v1alpha1.PythonDependenciesWheelhouse,
+			},
+			"python -c \"import sys, urllib.request; urllib.request.urlretrieve(sys.argv[1], sys.argv[2])\" " +
+				"https://example.com/deps/wheelhouse.zip /pulsar/python-dependencies/wheelhouse.zip && " +
+				"python -m zipfile -e /pulsar/python-dependencies/wheelhouse.zip /pulsar/python-dependencies/wheelhouse && " +
+				"pip install --no-cache-dir --target /pulsar/python-dependencies/site-packages --no-index " +
+				"--find-links /pulsar/python-dependencies/wheelhouse /pulsar/python-dependencies/wheelhouse/*.whl",
+		},
+		{
+			&v1alpha1.PythonRuntime{
+				DependenciesLocation: "https://example.com/deps/venv.zip",
+				DependenciesType:     v1alpha1.PythonDependenciesVirtualenv,
+			},
+			"python -c \"import sys, urllib.request; urllib.request.urlretrieve(sys.argv[1], sys.argv[2])\" " +
+				"https://example.com/deps/venv.zip /pulsar/python-dependencies/venv.zip && " +
+				"python -m zipfile -e /pulsar/python-dependencies/venv.zip /pulsar/python-dependencies/site-packages",
+		},
+	}
+	for _, v := range testData {
+		assert.Equal(t, v.expected, makePythonDependenciesCommand(v.python, pulsar))
+	}
+}
+
+func TestMakeFunctionStatefulSetWithPythonDependencies(t *testing.T) {
+	function := makePythonFunctionSample(&v1alpha1.PythonRuntime{Py: "exclamation_function.py"})
+	statefulSet := MakeFunctionStatefulSet(function)
+	podSpec := statefulSet.Spec.Template.Spec
+	assert.Empty(t, podSpec.InitContainers)
+	assert.Contains(t, podSpec.Containers[0].Command[2], "--install_usercode_dependencies true")
+
+	function = makePythonFunctionSample(&v1alpha1.PythonRuntime{
+		Py:           "exclamation_function.py",
+		Requirements: []string{"requests"},
+	})
+	statefulSet = MakeFunctionStatefulSet(function)
+	podSpec = statefulSet.Spec.Template.Spec
+	assert.Len(t, podSpec.InitContainers, 1)
+	assert.Equal(t, PythonDependenciesName, podSpec.InitContainers[0].Name)
+	assert.Equal(t, DefaultPythonRunnerImage, podSpec.InitContainers[0].Image)
+	assert.Contains(t, podSpec.Volumes, corev1.Volume{Name: PythonDependenciesVolume})
+
+	container := podSpec.Containers[0]
+	assert.Contains(t, container.Command[2], "--install_usercode_dependencies false")
+	assert.Contains(t, container.Env, corev1.EnvVar{Name: EnvPythonPath, Value: PythonSitePackagesDir})
+	assert.Contains(t, container.VolumeMounts, corev1.VolumeMount{Name: PythonDependenciesVolume,
+		MountPath: PythonDependenciesDir, ReadOnly: true})
+	// the init container is not added to the function itself
+	assert.Empty(t, function.Spec.Pod.InitContainers)
+}
@@ -50,8 +50,9 @@ func MakeSinkService(sink *v1alpha1.Sink) *corev1.Service {
 }
 
 func MakeSinkStatefulSet(sink *v1alpha1.Sink) *appsv1.StatefulSet {
+	sink = withSinkDefaults(sink)
 	objectMeta := MakeSinkObjectMeta(sink)
-	return MakeStatefulSet(objectMeta, sink.Spec.Replicas, sink.Spec.DownloaderImage, MakeSinkContainer(sink),
+	return MakeStatefulSet(objectMeta, makeReplicas(sink.Spec.Replicas, sink.Spec.Paused), sink.Spec.DownloaderImage, MakeSinkContainer(sink),
 		makeSinkVolumes(sink), MakeSinkLabels(sink), sink.Spec.Pod, *sink.Spec.Pulsar,
 		sink.Spec.Java, sink.Spec.Python, sink.Spec.Golang, sink.Spec.VolumeMounts)
 }
@@ -118,17 +119,18 @@ func MakeSinkLabels(sink *v1alpha1.Sink) map[string]string {
 }
 
 func makeSinkVolumes(sink *v1alpha1.Sink) []corev1.Volume {
-	return generatePodVolumes(
+	volumes := generatePodVolumes(
 		sink.Spec.Pod.Volumes,
 		nil,
 		sink.Spec.Input.SourceSpecs,
 		sink.Spec.Pulsar.TLSConfig,
 		sink.Spec.Pulsar.AuthConfig,
 		getRuntimeLogConfigNames(sink.Spec.Java, sink.Spec.Python, sink.Spec.Golang))
+	return append(volumes, generateStateStoreVolumes(sink.Spec.StateConfig)...)
 }
 
 func makeSinkVolumeMounts(sink *v1alpha1.Sink) []corev1.VolumeMount {
-	return generateContainerVolumeMounts(
+	mounts := generateContainerVolumeMounts(
 		sink.Spec.VolumeMounts,
 		nil,
 		sink.Spec.Input.SourceSpecs,
@@ -136,6 +138,7 @@ func makeSinkVolumeMounts(sink *v1alpha1.Sink) []corev1.VolumeMount {
 		sink.Spec.Pulsar.AuthConfig,
 		getRuntimeLogConfigNames(sink.Spec.Java, sink.Spec.Python, sink.Spec.Golang),
 		sink.Spec.Java, sink.Spec.Python, sink.Spec.Golang)
+	return append(mounts, generateStateStoreVolumeMounts(sink.Spec.StateConfig)...)
 }
 
 func MakeSinkCommand(sink *v1alpha1.Sink) []string {
@@ -144,12 +147,14 @@ func MakeSinkCommand(sink *v1alpha1.Sink) []string {
 	if spec.Pod.Liveness != nil && spec.Pod.Liveness.PeriodSeconds > 0 && utils.GrpcurlPersistentVolumeClaim != "" {
 		healthCheckInterval = spec.Pod.Liveness.PeriodSeconds
 	}
-	return MakeJavaFunctionCommand(spec.Java.JarLocation, spec.Java.Jar,
+	return MakeJavaFunctionCommand(spec.Java.JarLocation, spec.Java.Jar, spec.Java.Sha256, isPackageCached(sink.Namespace),
 		spec.Name, spec.ClusterName,
 		generateJavaLogConfigCommand(sink.Spec.Java),
 		parseJavaLogLevel(sink.Spec.Java),
 		generateSinkDetailsInJSON(sink),
-		getDecimalSIMemory(spec.Resources.Requests.Memory()), spec.Java.ExtraDependenciesDir, string(sink.UID),
+		makeJVMOptions(spec.Name, spec.Java, spec.Resources), makeJavaClasspath(spec.Java), string(sink.UID),
+		makeFunctionVersion(spec.FunctionVersion, spec.Java.Sha256, sink.Generation),
+		getInt32FromPtrOrDefault(spec.MaxBufferedTuples, DefaultMaxBufferedTuples),
 		spec.Java.JavaOpts, spec.Pulsar.AuthSecret != "", spec.Pulsar.TLSSecret != "", spec.SecretsMap,
 		spec.StateConfig, spec.Pulsar.TLSConfig, spec.Pulsar.AuthConfig, healthCheckInterval, nil)
 }
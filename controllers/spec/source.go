@@ -50,10 +50,28 @@ func MakeSourceService(source *v1alpha1.Source) *corev1.Service {
 }
 
 func MakeSourceStatefulSet(source *v1alpha1.Source) *appsv1.StatefulSet {
+	source = withSourceDefaults(source)
 	objectMeta := MakeSourceObjectMeta(source)
-	return MakeStatefulSet(objectMeta, source.Spec.Replicas, source.Spec.DownloaderImage, MakeSourceContainer(source),
+	statefulSet := MakeStatefulSet(objectMeta, makeReplicas(source.Spec.Replicas, source.Spec.Paused), source.Spec.DownloaderImage, MakeSourceContainer(source),
 		makeSourceVolumes(source), makeSourceLabels(source), source.Spec.Pod, *source.Spec.Pulsar,
 		source.Spec.Java, source.Spec.Python, source.Spec.Golang, source.Spec.VolumeMounts)
+	if trigger := GetDiscoveryTrigger(source); trigger != "" {
+		if statefulSet.Spec.Template.Annotations == nil {
+			statefulSet.Spec.Template.Annotations = map[string]string{}
+		}
+		statefulSet.Spec.Template.Annotations[AnnotationTriggerDiscovery] = trigger
+	}
+	return statefulSet
+}
+
+// GetDiscoveryTrigger returns the trigger-discovery annotation of a batch source with an on-demand trigger, it is
+// copied to the pod template so that a new value rolls the instances and runs the discovery
+func GetDiscoveryTrigger(source *v1alpha1.Source) string {
+	batch := source.Spec.BatchSourceConfig
+	if batch == nil || batch.Trigger == nil || !batch.Trigger.OnDemand {
+		return ""
+	}
+	return source.Annotations[AnnotationTriggerDiscovery]
 }
 
 func MakeSourceObjectMeta(source *v1alpha1.Source) *metav1.ObjectMeta {
@@ -113,17 +131,18 @@ func makeSourceLabels(source *v1alpha1.Source) map[string]string {
 }
 
 func makeSourceVolumes(source *v1alpha1.Source) []corev1.Volume {
-	return generatePodVolumes(
+	volumes := generatePodVolumes(
 		source.Spec.Pod.Volumes,
 		source.Spec.Output.ProducerConf,
 		nil,
 		source.Spec.Pulsar.TLSConfig,
 		source.Spec.Pulsar.AuthConfig,
 		getRuntimeLogConfigNames(source.Spec.Java, source.Spec.Python, source.Spec.Golang))
+	return append(volumes, generateStateStoreVolumes(source.Spec.StateConfig)...)
 }
 
 func makeSourceVolumeMounts(source *v1alpha1.Source) []corev1.VolumeMount {
-	return generateContainerVolumeMounts(
+	mounts := generateContainerVolumeMounts(
 		source.Spec.VolumeMounts,
 		source.Spec.Output.ProducerConf,
 		nil,
@@ -131,6 +150,7 @@ func makeSourceVolumeMounts(source *v1alpha1.Source) []corev1.VolumeMount {
 		source.Spec.Pulsar.AuthConfig,
 		getRuntimeLogConfigNames(source.Spec.Java, source.Spec.Python, source.Spec.Golang),
 		source.Spec.Java, source.Spec.Python, source.Spec.Golang)
+	return append(mounts, generateStateStoreVolumeMounts(source.Spec.StateConfig)...)
 }
 
 func makeSourceCommand(source *v1alpha1.Source) []string {
@@ -139,12 +159,14 @@ func makeSourceCommand(source *v1alpha1.Source) []string {
 	if spec.Pod.Liveness != nil && spec.Pod.Liveness.PeriodSeconds > 0 && utils.GrpcurlPersistentVolumeClaim != "" {
 		healthCheckInterval = spec.Pod.Liveness.PeriodSeconds
 	}
-	return MakeJavaFunctionCommand(spec.Java.JarLocation, spec.Java.Jar,
+	return MakeJavaFunctionCommand(spec.Java.JarLocation, spec.Java.Jar, spec.Java.Sha256, isPackageCached(source.Namespace),
 		spec.Name, spec.ClusterName,
 		generateJavaLogConfigCommand(source.Spec.Java),
 		parseJavaLogLevel(source.Spec.Java),
 		generateSourceDetailsInJSON(source),
-		getDecimalSIMemory(spec.Resources.Requests.Memory()), spec.Java.ExtraDependenciesDir, string(source.UID),
+		makeJVMOptions(spec.Name, spec.Java, spec.Resources), makeJavaClasspath(spec.Java), string(source.UID),
+		makeFunctionVersion(spec.FunctionVersion, spec.Java.Sha256, source.Generation),
+		getInt32FromPtrOrDefault(spec.MaxBufferedTuples, DefaultMaxBufferedTuples),
 		spec.Java.JavaOpts, spec.Pulsar.AuthSecret != "", spec.Pulsar.TLSSecret != "", spec.SecretsMap,
 		spec.StateConfig, spec.Pulsar.TLSConfig, spec.Pulsar.AuthConfig, healthCheckInterval, nil)
 }
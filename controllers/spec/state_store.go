@@ -0,0 +1,177 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package spec
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/streamnative/function-mesh/api/compute/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	StateStoreTLSVolume  = "state-store-tls"
+	StateStoreAuthVolume = "state-store-auth"
+	StateStoreAuthDir    = "/etc/auth/state-store"
+
+	// the system properties set from the state store settings for a custom Java state store provider
+	stateStoreNamespaceKey             = "stateStorageNamespace"
+	stateStoreTableKey                 = "stateStorageTable"
+	stateStoreTLSEnabledKey            = "stateStorageTlsEnabled"
+	stateStoreTLSAllowInsecureKey      = "stateStorageTlsAllowInsecureConnection"
+	stateStoreTLSHostnameVerifyKey     = "stateStorageTlsHostnameVerificationEnabled"
+	stateStoreTLSTrustCertsFilePathKey = "stateStorageTlsTrustCertsFilePath"
+	stateStoreAuthPluginFileKey        = "stateStorageAuthPluginFile"
+	stateStoreAuthParamsFileKey        = "stateStorageAuthParamsFile"
+)
+
+func getPulsarStateStore(state *v1alpha1.Stateful) *v1alpha1.PulsarStateStore {
+	if state == nil || state.Pulsar == nil || state.Pulsar.ServiceURL == "" {
+		return nil
+	}
+	return state.Pulsar
+}
+
+// getStatefulArgs returns the state store arguments of the instance, the runtimes only take the service url
+// of the state store, and the Java runtime the class of the state store provider
+func getStatefulArgs(state *v1alpha1.Stateful, java bool) []string {
+	store := getPulsarStateStore(state)
+	if store == nil {
+		return nil
+	}
+	args := []string{
+		"--state_storage_serviceurl",
+		store.ServiceURL,
+	}
+	if java && store.JavaProvider != nil && store.JavaProvider.ClassName != "" {
+		args = append(args, "--state_storage_impl_class", store.JavaProvider.ClassName)
+	}
+	return args
+}
+
+// getStateStoreJavaOpts returns the system properties of the JVM that carry the state store settings, the
+// Java runtime only hands the service url to the state store provider so a custom provider reads the rest
+// of its config with System.getProperty
+func getStateStoreJavaOpts(state *v1alpha1.Stateful) []string {
+	store := getPulsarStateStore(state)
+	if store == nil {
+		return nil
+	}
+	config := makeStateStoreProviderConfig(store)
+	keys := make([]string, 0, len(config))
+	for key := range config {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	opts := make([]string, 0, len(keys))
+	for _, key := range keys {
+		value, ok := config[key].(string)
+		if !ok {
+			data, err := json.Marshal(config[key])
+			if err != nil {
+				continue
+			}
+			value = string(data)
+		}
+		opts = append(opts, "'"+strings.ReplaceAll(fmt.Sprintf("-D%s=%s", key, value), "'", `'\''`)+"'")
+	}
+	return opts
+}
+
+// makeStateStoreProviderConfig returns the config of a custom Java state store provider, the TLS, auth and
+// naming settings of the state store override the keys of the provider config
+func makeStateStoreProviderConfig(store *v1alpha1.PulsarStateStore) map[string]interface{} {
+	config := map[string]interface{}{}
+	if store.JavaProvider != nil && store.JavaProvider.Config != nil {
+		for key, value := range store.JavaProvider.Config.Data {
+			config[key] = value
+		}
+	}
+	if store.Namespace != "" {
+		config[stateStoreNamespaceKey] = store.Namespace
+	}
+	if store.Table != "" {
+		config[stateStoreTableKey] = store.Table
+	}
+	if store.TLSConfig != nil {
+		config[stateStoreTLSEnabledKey] = store.TLSConfig.Enabled
+		if store.TLSConfig.Enabled {
+			config[stateStoreTLSAllowInsecureKey] = store.TLSConfig.AllowInsecure
+			config[stateStoreTLSHostnameVerifyKey] = store.TLSConfig.HostnameVerification
+			if store.TLSConfig.HasSecretVolume() {
+				config[stateStoreTLSTrustCertsFilePathKey] = store.TLSConfig.GetMountPath() + "/" +
+					store.TLSConfig.SecretKey()
+			}
+		}
+	}
+	if store.AuthSecret != "" {
+		config[stateStoreAuthPluginFileKey] = StateStoreAuthDir + "/clientAuthenticationPlugin"
+		config[stateStoreAuthParamsFileKey] = StateStoreAuthDir + "/clientAuthenticationParameters"
+	}
+	return config
+}
+
+// generateStateStoreVolumes returns the volumes of the state store certificate and auth secret
+func generateStateStoreVolumes(state *v1alpha1.Stateful) []corev1.Volume {
+	store := getPulsarStateStore(state)
+	if store == nil {
+		return nil
+	}
+	var volumes []corev1.Volume
+	if store.TLSConfig != nil && store.TLSConfig.Enabled && store.TLSConfig.HasSecretVolume() {
+		volume := generateVolumeFromTLSConfig(store.TLSConfig)
+		volume.Name = StateStoreTLSVolume
+		volumes = append(volumes, volume)
+	}
+	if store.AuthSecret != "" {
+		volumes = append(volumes, corev1.Volume{
+			Name: StateStoreAuthVolume,
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{SecretName: store.AuthSecret},
+			},
+		})
+	}
+	return volumes
+}
+
+// generateStateStoreVolumeMounts returns the mounts of the state store certificate and auth secret
+func generateStateStoreVolumeMounts(state *v1alpha1.Stateful) []corev1.VolumeMount {
+	store := getPulsarStateStore(state)
+	if store == nil {
+		return nil
+	}
+	var mounts []corev1.VolumeMount
+	if store.TLSConfig != nil && store.TLSConfig.Enabled && store.TLSConfig.HasSecretVolume() {
+		mounts = append(mounts, corev1.VolumeMount{
+			Name:      StateStoreTLSVolume,
+			MountPath: store.TLSConfig.GetMountPath(),
+			ReadOnly:  true,
+		})
+	}
+	if store.AuthSecret != "" {
+		mounts = append(mounts, corev1.VolumeMount{
+			Name:      StateStoreAuthVolume,
+			MountPath: StateStoreAuthDir,
+			ReadOnly:  true,
+		})
+	}
+	return mounts
+}
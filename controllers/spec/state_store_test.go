@@ -0,0 +1,110 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package spec
+
+import (
+	"testing"
+
+	"github.com/streamnative/function-mesh/api/compute/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func makeStateStoreSample() *v1alpha1.Stateful {
+	config := v1alpha1.NewConfig(map[string]interface{}{"stateStorageTable": "ignored", "cacheSize": int64(100)})
+	return &v1alpha1.Stateful{Pulsar: &v1alpha1.PulsarStateStore{
+		ServiceURL: "bk://bookie:4181",
+		JavaProvider: &v1alpha1.PulsarStateStoreJavaProvider{
+			ClassName: "org.example.StateStoreProvider",
+			Config:    &config,
+		},
+		TLSConfig: &v1alpha1.StateStoreTLSConfig{TLSConfig: v1alpha1.TLSConfig{
+			Enabled:        true,
+			CertSecretName: "state-tls",
+			CertSecretKey:  "ca.crt",
+		}},
+		AuthSecret: "state-auth",
+		Namespace:  "public_state",
+		Table:      "counts",
+	}}
+}
+
+func TestGetStatefulArgs(t *testing.T) {
+	assert.Nil(t, getStatefulArgs(nil, true))
+	assert.Nil(t, getStatefulArgs(&v1alpha1.Stateful{Pulsar: &v1alpha1.PulsarStateStore{}}, true))
+
+	state := &v1alpha1.Stateful{Pulsar: &v1alpha1.PulsarStateStore{ServiceURL: "bk://bookie:4181"}}
+	assert.Equal(t, []string{"--state_storage_serviceurl", "bk://bookie:4181"}, getStatefulArgs(state, true))
+
+	state = makeStateStoreSample()
+	assert.Equal(t, []string{"--state_storage_serviceurl", "bk://bookie:4181"}, getStatefulArgs(state, false))
+	assert.Equal(t, []string{
+		"--state_storage_serviceurl", "bk://bookie:4181",
+		"--state_storage_impl_class", "org.example.StateStoreProvider",
+	}, getStatefulArgs(state, true))
+}
+
+func TestGetStateStoreJavaOpts(t *testing.T) {
+	assert.Nil(t, getStateStoreJavaOpts(nil))
+	assert.Empty(t, getStateStoreJavaOpts(&v1alpha1.Stateful{Pulsar: &v1alpha1.PulsarStateStore{
+		ServiceURL: "bk://bookie:4181"}}))
+
+	assert.Equal(t, []string{
+		"'-DcacheSize=100'",
+		"'-DstateStorageAuthParamsFile=/etc/auth/state-store/clientAuthenticationParameters'",
+		"'-DstateStorageAuthPluginFile=/etc/auth/state-store/clientAuthenticationPlugin'",
+		"'-DstateStorageNamespace=public_state'",
+		"'-DstateStorageTable=counts'",
+		"'-DstateStorageTlsAllowInsecureConnection=false'",
+		"'-DstateStorageTlsEnabled=true'",
+		"'-DstateStorageTlsHostnameVerificationEnabled=false'",
+		"'-DstateStorageTlsTrustCertsFilePath=/etc/tls/state-store/ca.crt'",
+	}, getStateStoreJavaOpts(makeStateStoreSample()))
+
+	config := v1alpha1.NewConfig(map[string]interface{}{"prefix": "it's"})
+	state := &v1alpha1.Stateful{Pulsar: &v1alpha1.PulsarStateStore{ServiceURL: "bk://bookie:4181",
+		JavaProvider: &v1alpha1.PulsarStateStoreJavaProvider{ClassName: "org.example.StateStoreProvider",
+			Config: &config}}}
+	assert.Equal(t, []string{`'-Dprefix=it'\''s'`}, getStateStoreJavaOpts(state))
+}
+
+func TestMakeFunctionStatefulSetWithStateStore(t *testing.T) {
+	function := makeFunctionSample(TestFunctionName)
+	function.Spec.StateConfig = makeStateStoreSample()
+	statefulSet := MakeFunctionStatefulSet(function)
+	podSpec := statefulSet.Spec.Template.Spec
+
+	assert.Contains(t, podSpec.Volumes, corev1.Volume{
+		Name: StateStoreTLSVolume,
+		VolumeSource: corev1.VolumeSource{Secret: &corev1.SecretVolumeSource{
+			SecretName: "state-tls",
+			Items:      []corev1.KeyToPath{{Key: "ca.crt", Path: "ca.crt"}},
+		}},
+	})
+	assert.Contains(t, podSpec.Volumes, corev1.Volume{
+		Name:         StateStoreAuthVolume,
+		VolumeSource: corev1.VolumeSource{Secret: &corev1.SecretVolumeSource{SecretName: "state-auth"}},
+	})
+	container := podSpec.Containers[0]
+	assert.Contains(t, container.VolumeMounts, corev1.VolumeMount{Name: StateStoreTLSVolume,
+		MountPath: "/etc/tls/state-store", ReadOnly: true})
+	assert.Contains(t, container.VolumeMounts, corev1.VolumeMount{Name: StateStoreAuthVolume,
+		MountPath: StateStoreAuthDir, ReadOnly: true})
+	assert.Contains(t, container.Command[2], "'-DstateStorageTable=counts'")
+	assert.NotContains(t, container.Command[2], "--state_storage_provider_config")
+}
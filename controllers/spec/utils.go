@@ -35,6 +35,13 @@ import (
 	"github.com/streamnative/function-mesh/utils"
 )
 
+// MakeFunctionDetails returns the function details the instances of a defaulted copy of the Function run with
+func MakeFunctionDetails(function *v1alpha1.Function) *proto.FunctionDetails {
+	function = function.DeepCopy()
+	function.Default()
+	return convertFunctionDetails(function)
+}
+
 func convertFunctionDetails(function *v1alpha1.Function) *proto.FunctionDetails {
 	runtime := proto.FunctionDetails_JAVA
 	if function.Spec.Golang != nil {
@@ -57,7 +64,7 @@ func convertFunctionDetails(function *v1alpha1.Function) *proto.FunctionDetails
 		Sink:                 generateFunctionOutputSpec(function),
 		Resources:            generateResource(function.Spec.Resources.Requests),
 		PackageUrl:           "",
-		RetryDetails:         generateRetryDetails(function.Spec.MaxMessageRetry, function.Spec.DeadLetterTopic),
+		RetryDetails:         generateRetryDetails(function.Spec.MaxMessageRetry, function.Spec.DeadLetterTopic, function.Spec.RetryPolicy),
 		RuntimeFlags:         function.Spec.RuntimeFlags,
 		ComponentType:        proto.FunctionDetails_FUNCTION,
 		CustomRuntimeOptions: "",
@@ -73,17 +80,23 @@ func convertFunctionDetails(function *v1alpha1.Function) *proto.FunctionDetails
 	return fd
 }
 
+// generateFunctionConfig returns the user config of the function with the window config of the executor, the
+// function itself is left untouched
 func generateFunctionConfig(function *v1alpha1.Function) *v1alpha1.Config {
-	if function.Spec.WindowConfig != nil {
-		if function.Spec.FuncConfig == nil {
-			function.Spec.FuncConfig = &v1alpha1.Config{
-				Data: map[string]interface{}{},
-			}
-		}
-		function.Spec.WindowConfig.ActualWindowFunctionClassName = function.Spec.ClassName
-		function.Spec.FuncConfig.Data[WindowFunctionConfigKeyName] = *function.Spec.WindowConfig
+	if function.Spec.WindowConfig == nil {
+		return function.Spec.FuncConfig
+	}
+	config := function.Spec.FuncConfig.DeepCopy()
+	if config == nil {
+		config = &v1alpha1.Config{}
+	}
+	if config.Data == nil {
+		config.Data = map[string]interface{}{}
 	}
-	return function.Spec.FuncConfig
+	windowConfig := function.Spec.WindowConfig.DeepCopy()
+	windowConfig.ActualWindowFunctionClassName = function.Spec.ClassName
+	config.Data[WindowFunctionConfigKeyName] = *windowConfig
+	return config
 }
 
 func fetchClassName(function *v1alpha1.Function) string {
@@ -98,11 +111,14 @@ func convertGoFunctionConfs(function *v1alpha1.Function) *GoFunctionConf {
 	if function.Spec.Pod.Liveness != nil && function.Spec.Pod.Liveness.PeriodSeconds > 0 && utils.GrpcurlPersistentVolumeClaim != "" {
 		hInterval = function.Spec.Pod.Liveness.PeriodSeconds
 	}
+	maxMessageRetry, deadLetterTopic := getRetryDetails(function.Spec.MaxMessageRetry, function.Spec.DeadLetterTopic,
+		function.Spec.RetryPolicy)
 	return &GoFunctionConf{
-		FuncID:               fmt.Sprintf("${%s}-%s", EnvShardID, string(function.UID)),
-		PulsarServiceURL:     "${brokerServiceURL}",
-		FuncVersion:          "0",
-		MaxBufTuples:         100, //TODO
+		FuncID:           fmt.Sprintf("${%s}-%s", EnvShardID, string(function.UID)),
+		PulsarServiceURL: "${brokerServiceURL}",
+		FuncVersion: makeFunctionVersion(function.Spec.FunctionVersion, function.Spec.Golang.Sha256,
+			function.Generation),
+		MaxBufTuples:         int(getInt32FromPtrOrDefault(function.Spec.MaxBufferedTuples, DefaultMaxBufferedTuples)),
 		Port:                 int(GRPCPort.ContainerPort),
 		ClusterName:          function.Spec.ClusterName,
 		Tenant:               function.Spec.Tenant,
@@ -125,8 +141,8 @@ func convertGoFunctionConfs(function *v1alpha1.Function) *GoFunctionConf {
 		CPU:                         float64(function.Spec.Resources.Requests.Cpu().Value()),
 		RAM:                         function.Spec.Resources.Requests.Memory().Value(),
 		Disk:                        function.Spec.Resources.Requests.Storage().Value(),
-		MaxMessageRetries:           function.Spec.MaxMessageRetry,
-		DeadLetterTopic:             function.Spec.DeadLetterTopic,
+		MaxMessageRetries:           maxMessageRetry,
+		DeadLetterTopic:             deadLetterTopic,
 		UserConfig:                  getUserConfig(function.Spec.FuncConfig),
 		MetricsPort:                 int(MetricsPort.ContainerPort),
 		ExpectedHealthCheckInterval: hInterval,
@@ -199,7 +215,7 @@ func generateFunctionInputSpec(function *v1alpha1.Function) *proto.SourceSpec {
 		SubscriptionName:             function.Spec.SubscriptionName,
 		CleanupSubscription:          function.Spec.CleanupSubscription,
 		SubscriptionPosition:         convertSubPosition(function.Spec.SubscriptionPosition),
-		NegativeAckRedeliveryDelayMs: uint64(function.Spec.Timeout),
+		NegativeAckRedeliveryDelayMs: getNegativeAckRedeliveryDelayMs(function.Spec.Timeout, function.Spec.RetryPolicy),
 	}
 }
 
@@ -240,6 +256,13 @@ func generateFunctionOutputSpec(function *v1alpha1.Function) *proto.SinkSpec {
 	return sinkSpec
 }
 
+// MakeSourceDetails returns the function details the instances of a defaulted copy of the Source run with
+func MakeSourceDetails(source *v1alpha1.Source) *proto.FunctionDetails {
+	source = source.DeepCopy()
+	source.Default()
+	return convertSourceDetails(source)
+}
+
 func convertSourceDetails(source *v1alpha1.Source) *proto.FunctionDetails {
 	fd := &proto.FunctionDetails{
 		Tenant:               source.Spec.Tenant,
@@ -269,7 +292,15 @@ func generateSourceInputSpec(source *v1alpha1.Source) *proto.SourceSpec {
 	sourceConfig := source.Spec.SourceConfig
 	className := source.Spec.ClassName
 	if source.Spec.BatchSourceConfig != nil {
-		bytes, _ := json.Marshal(source.Spec.BatchSourceConfig)
+		sourceConfig = sourceConfig.DeepCopy()
+		if sourceConfig == nil || sourceConfig.Data == nil {
+			sourceConfig = &v1alpha1.Config{Data: map[string]interface{}{}}
+		}
+		// the executor gets the triggerer resolved from the trigger preset
+		batchSourceConfig := source.Spec.BatchSourceConfig.DeepCopy()
+		batchSourceConfig.ApplyTrigger()
+		batchSourceConfig.Trigger = nil
+		bytes, _ := json.Marshal(batchSourceConfig)
 		sourceConfig.Data[v1alpha1.BatchSourceConfigKey] = string(bytes)
 		sourceConfig.Data[v1alpha1.BatchSourceClassNameKey] = source.Spec.ClassName
 		className = v1alpha1.BatchSourceClass
@@ -311,6 +342,13 @@ func generateSourceOutputSpec(source *v1alpha1.Source) *proto.SinkSpec {
 	}
 }
 
+// MakeSinkDetails returns the function details the instances of a defaulted copy of the Sink run with
+func MakeSinkDetails(sink *v1alpha1.Sink) *proto.FunctionDetails {
+	sink = sink.DeepCopy()
+	sink.Default()
+	return convertSinkDetails(sink)
+}
+
 func convertSinkDetails(sink *v1alpha1.Sink) *proto.FunctionDetails {
 	fd := &proto.FunctionDetails{
 		Tenant:               sink.Spec.Tenant,
@@ -324,7 +362,7 @@ func convertSinkDetails(sink *v1alpha1.Sink) *proto.FunctionDetails {
 		Source:               generateSinkInputSpec(sink),
 		Sink:                 generateSinkOutputSpec(sink),
 		Resources:            generateResource(sink.Spec.Resources.Requests),
-		RetryDetails:         generateRetryDetails(sink.Spec.MaxMessageRetry, sink.Spec.DeadLetterTopic),
+		RetryDetails:         generateRetryDetails(sink.Spec.MaxMessageRetry, sink.Spec.DeadLetterTopic, sink.Spec.RetryPolicy),
 		RuntimeFlags:         sink.Spec.RuntimeFlags,
 		ComponentType:        proto.FunctionDetails_SINK,
 		RetainOrdering:       sink.Spec.RetainOrdering,
@@ -349,7 +387,7 @@ func generateSinkInputSpec(sink *v1alpha1.Sink) *proto.SourceSpec {
 		SubscriptionName:             sink.Spec.SubscriptionName,
 		CleanupSubscription:          sink.Spec.CleanupSubscription,
 		SubscriptionPosition:         convertSubPosition(sink.Spec.SubscriptionPosition),
-		NegativeAckRedeliveryDelayMs: uint64(sink.Spec.NegativeAckRedeliveryDelayMs),
+		NegativeAckRedeliveryDelayMs: getNegativeAckRedeliveryDelayMs(sink.Spec.NegativeAckRedeliveryDelayMs, sink.Spec.RetryPolicy),
 	}
 }
 
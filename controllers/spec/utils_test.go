@@ -19,6 +19,7 @@ package spec
 
 import (
 	"testing"
+	"time"
 
 	"github.com/streamnative/function-mesh/api/compute/v1alpha1"
 
@@ -51,8 +52,8 @@ func TestMarshalSecretsMap(t *testing.T) {
 	assert.Equal(t, marshaledSecretsNil, `{}`)
 }
 
-func TestBatchSource(t *testing.T) {
-	source := &v1alpha1.Source{
+func makeBatchSourceSample() *v1alpha1.Source {
+	return &v1alpha1.Source{
 		TypeMeta: metav1.TypeMeta{
 			Kind:       "Sink",
 			APIVersion: "compute.functionmesh.io/v1alpha1",
@@ -100,7 +101,63 @@ func TestBatchSource(t *testing.T) {
 			},
 		},
 	}
+}
+
+func TestBatchSource(t *testing.T) {
+	source := makeBatchSourceSample()
 	sourceSpec := generateSourceInputSpec(source)
 	assert.Equal(t, v1alpha1.BatchSourceClass, sourceSpec.ClassName)
 	assert.Equal(t, `{"__BATCHSOURCECLASSNAME__":"org.apache.pulsar.ecosystem.io.bigquery.BigQuerySource","__BATCHSOURCECONFIGS__":"{\"discoveryTriggererClassName\":\"test-trigger-class\",\"discoveryTriggererConfig\":{\"test-key\":\"test-value\"}}","tableName":"test-table"}`, sourceSpec.Configs)
 }
+
+func TestBatchSourceTrigger(t *testing.T) {
+	source := makeBatchSourceSample()
+	source.Spec.BatchSourceConfig = &v1alpha1.BatchSourceConfig{
+		Trigger: &v1alpha1.BatchSourceTrigger{Interval: &metav1.Duration{Duration: 5 * time.Minute}},
+	}
+	original := source.DeepCopy()
+	sourceSpec := generateSourceInputSpec(source)
+	assert.Contains(t, sourceSpec.Configs, `"__BATCHSOURCECONFIGS__":"{\"discoveryTriggererClassName\":`+
+		`\"org.apache.pulsar.io.batchdiscovery.CronTriggerer\",\"discoveryTriggererConfig\":`+
+		`{\"__CRON__\":\"0 0/5 * * * ?\"}}"`)
+	// the conversion does not modify the source
+	assert.Equal(t, original, source)
+
+	// the trigger-discovery annotation only rolls the instances of an on-demand batch source
+	source.Annotations = map[string]string{AnnotationTriggerDiscovery: "2026-10-19T10:00:00Z"}
+	assert.Empty(t, GetDiscoveryTrigger(source))
+	assert.NotContains(t, MakeSourceStatefulSet(source).Spec.Template.Annotations, AnnotationTriggerDiscovery)
+
+	replicas := int32(1)
+	source.Spec.Replicas = &replicas
+	source.Spec.BatchSourceConfig.Trigger = &v1alpha1.BatchSourceTrigger{OnDemand: true}
+	statefulSet := MakeSourceStatefulSet(source)
+	assert.Equal(t, "2026-10-19T10:00:00Z", statefulSet.Spec.Template.Annotations[AnnotationTriggerDiscovery])
+	desired := statefulSet.DeepCopy()
+	desired.Spec.Template.Annotations[AnnotationTriggerDiscovery] = "2026-10-19T11:00:00Z"
+	assert.False(t, CheckIfStatefulSetSpecIsEqual(&statefulSet.Spec, &desired.Spec))
+	assert.Contains(t, generateSourceInputSpec(source).Configs,
+		`\"discoveryTriggererClassName\":\"org.apache.pulsar.io.batchdiscovery.ImmediateTriggerer\"`)
+}
+
+func TestConvertFunctionDetailsWithWindowConfig(t *testing.T) {
+	function := makeFunctionSample("window-function")
+	windowLength := int32(10)
+	function.Spec.WindowConfig = &v1alpha1.WindowConfig{WindowLengthCount: &windowLength}
+	function.Spec.FuncConfig = &v1alpha1.Config{Data: map[string]interface{}{"threshold": int64(3)}}
+	original := function.DeepCopy()
+
+	details := convertFunctionDetails(function)
+	assert.Equal(t, WindowFunctionExecutorClass, details.ClassName)
+	assert.Contains(t, details.UserConfig, `"threshold":3`)
+	assert.Contains(t, details.UserConfig, `"__WINDOWCONFIGS__":{"actualWindowFunctionClassName":`+
+		`"org.apache.pulsar.functions.api.examples.ExclamationFunction","windowLengthCount":10}`)
+	// the conversion does not modify the function, so that it can still be deep copied
+	assert.Equal(t, original, function)
+	assert.NotPanics(t, func() { function.DeepCopy() })
+
+	function.Spec.FuncConfig = nil
+	details = convertFunctionDetails(function)
+	assert.Contains(t, details.UserConfig, "__WINDOWCONFIGS__")
+	assert.Nil(t, function.Spec.FuncConfig)
+}
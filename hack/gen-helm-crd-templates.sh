@@ -149,6 +149,20 @@ function crd::main() {
     "$KUSTOMIZE" build config/crd | "$YQ" eval '. | select(.metadata.name == "connectorcatalogs.compute.functionmesh.io")' > "$source_file"
     crd::generate_template "$source_file" "$target_file" true
 
+    # crd-pulsarconnections
+    file="crd-compute.functionmesh.io-pulsarconnections.yaml"
+    target_file="charts/function-mesh-operator/charts/admission-webhook/templates/$file"
+    source_file="$tmp/$file"
+    "$KUSTOMIZE" build config/crd | "$YQ" eval '. | select(.metadata.name == "pulsarconnections.compute.functionmesh.io")' > "$source_file"
+    crd::generate_template "$source_file" "$target_file" true
+
+    # crd-functionstatesnapshots
+    file="crd-compute.functionmesh.io-functionstatesnapshots.yaml"
+    target_file="charts/function-mesh-operator/charts/admission-webhook/templates/$file"
+    source_file="$tmp/$file"
+    "$KUSTOMIZE" build config/crd | "$YQ" eval '. | select(.metadata.name == "functionstatesnapshots.compute.functionmesh.io")' > "$source_file"
+    crd::generate_template "$source_file" "$target_file" true
+
     # crd-functionmeshes
     file="crd-compute.functionmesh.io-functionmeshes.yaml"
     target_file="charts/function-mesh-operator/charts/admission-webhook/templates/$file"
@@ -22,12 +22,15 @@ import (
 	"net/http"
 	"os"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/go-logr/logr"
 	computev1alpha1 "github.com/streamnative/function-mesh/api/compute/v1alpha1"
 	"github.com/streamnative/function-mesh/controllers"
 	"github.com/streamnative/function-mesh/controllers/spec"
 	"github.com/streamnative/function-mesh/utils"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	vpav1 "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/apis/autoscaling.k8s.io/v1"
@@ -35,7 +38,9 @@ import (
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	_ "k8s.io/client-go/plugin/pkg/client/auth/gcp"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/client/config"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 	// +kubebuilder:scaffold:imports
 )
@@ -61,7 +66,9 @@ func main() {
 	var healthProbeAddr string
 	var enableLeaderElection, enablePprof bool
 	var configFile string
+	var configReloadInterval time.Duration
 	var watchedNamespace string
+	var labelSelector string
 	var enableInitContainers bool
 	var grpcurlPersistentVolumeClaim string
 	flag.StringVar(&metricsAddr, "metrics-addr", lookupEnvOrString("METRICS_ADDR", ":8080"), "The address the metric endpoint binds to.")
@@ -77,8 +84,13 @@ func main() {
 		"CertDir is the directory that contains the server key and certificate.\n\tif not set, webhook server would look up the server key and certificate in\n\t{TempDir}/k8s-webhook-server/serving-certs. The server key and certificate\n\tmust be named tls.key and tls.crt, respectively.")
 	flag.StringVar(&configFile, "config-file", lookupEnvOrString("CONFIG_FILE", ""),
 		"config file path for controller manager")
+	flag.DurationVar(&configReloadInterval, "config-reload-interval",
+		lookupEnvOrDuration("CONFIG_RELOAD_INTERVAL", controllers.DefaultConfigsReloadInterval),
+		"How often the config file is checked for changes, the changed configs are applied without restart.")
 	flag.StringVar(&watchedNamespace, "watched-namespace", lookupEnvOrString("WATCHED_NAMESPACE", ""),
-		"Namespace if specified restricts the manager's cache to watch objects in the desired namespace. Defaults to all namespaces.")
+		"Comma-separated list of namespaces, if specified restricts the manager's cache to watch objects in the desired namespaces. Defaults to all namespaces.")
+	flag.StringVar(&labelSelector, "label-selector", lookupEnvOrString("LABEL_SELECTOR", ""),
+		"Label selector, if specified restricts the controller to reconcile only the objects with matching labels, e.g. functionmesh.io/shard=a. Defaults to all objects.")
 	flag.BoolVar(&enablePprof, "enable-pprof", lookupEnvOrBool("ENABLE_PPROF", false), "Enable pprof for controller manager.")
 	flag.StringVar(&pprofAddr, "pprof-addr", lookupEnvOrString("PPROF_ADDR", ":8090"), "The address the pprof binds to.")
 	flag.BoolVar(&enableInitContainers, "enable-init-containers", lookupEnvOrBool("ENABLE_INIT_CONTAINERS", false), "Whether to use an init container to download package")
@@ -106,7 +118,16 @@ func main() {
 		}
 	}
 
-	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
+	if labelSelector != "" {
+		selector, err := labels.Parse(labelSelector)
+		if err != nil {
+			setupLog.Error(err, "unable to parse the label selector", "selector", labelSelector)
+			os.Exit(1)
+		}
+		spec.ManagedSelector = selector
+	}
+
+	options := ctrl.Options{
 		Scheme:                  scheme,
 		MetricsBindAddress:      metricsAddr,
 		HealthProbeBindAddress:  healthProbeAddr,
@@ -114,14 +135,39 @@ func main() {
 		LeaderElection:          enableLeaderElection,
 		LeaderElectionNamespace: leaderElectionNamespace,
 		LeaderElectionID:        leaderElectionID,
-		Namespace:               watchedNamespace,
 		CertDir:                 certDir,
-	})
+	}
+	namespaces := parseNamespaces(watchedNamespace)
+	if len(namespaces) == 1 {
+		options.Namespace = namespaces[0]
+	} else if len(namespaces) > 1 {
+		options.NewCache = cache.MultiNamespacedCacheBuilder(namespaces)
+	}
+
+	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), options)
 	if err != nil {
 		setupLog.Error(err, "unable to start manager")
 		os.Exit(1)
 	}
 
+	// reload the controller configs on changes, the affected components are reconciled again
+	var functionConfigEvents, sourceConfigEvents, sinkConfigEvents <-chan event.GenericEvent
+	if configFile != "" {
+		watcher, err := controllers.NewControllerConfigsWatcher(mgr.GetClient(),
+			ctrl.Log.WithName("controllers").WithName("ControllerConfigs"), configFile, configReloadInterval)
+		if err != nil {
+			setupLog.Error(err, "unable to watch the controller configs")
+			os.Exit(1)
+		}
+		watcher.Elected = mgr.Elected()
+		if err = mgr.Add(watcher); err != nil {
+			setupLog.Error(err, "unable to watch the controller configs")
+			os.Exit(1)
+		}
+		functionConfigEvents, sourceConfigEvents, sinkConfigEvents =
+			watcher.FunctionEvents, watcher.SourceEvents, watcher.SinkEvents
+	}
+
 	// allow function mesh to be disabled and enable it by default
 	// required because of https://github.com/operator-framework/operator-lifecycle-manager/issues/1523
 	if os.Getenv("ENABLE_FUNCTION_MESH_CONTROLLER") != "false" {
@@ -140,33 +186,53 @@ func main() {
 		os.Exit(1)
 	}
 	if err = (&controllers.FunctionReconciler{
-		Client:     mgr.GetClient(),
-		Log:        ctrl.Log.WithName("controllers").WithName("Function"),
-		Scheme:     mgr.GetScheme(),
-		WatchFlags: &watchFlags,
+		Client:       mgr.GetClient(),
+		Log:          ctrl.Log.WithName("controllers").WithName("Function"),
+		Scheme:       mgr.GetScheme(),
+		WatchFlags:   &watchFlags,
+		ConfigEvents: functionConfigEvents,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Function")
 		os.Exit(1)
 	}
 	if err = (&controllers.SourceReconciler{
-		Client:     mgr.GetClient(),
-		Log:        ctrl.Log.WithName("controllers").WithName("Source"),
-		Scheme:     mgr.GetScheme(),
-		WatchFlags: &watchFlags,
+		Client:       mgr.GetClient(),
+		Log:          ctrl.Log.WithName("controllers").WithName("Source"),
+		Scheme:       mgr.GetScheme(),
+		WatchFlags:   &watchFlags,
+		ConfigEvents: sourceConfigEvents,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Source")
 		os.Exit(1)
 	}
 	if err = (&controllers.SinkReconciler{
-		Client:     mgr.GetClient(),
-		Log:        ctrl.Log.WithName("controllers").WithName("Sink"),
-		Scheme:     mgr.GetScheme(),
-		WatchFlags: &watchFlags,
+		Client:       mgr.GetClient(),
+		Log:          ctrl.Log.WithName("controllers").WithName("Sink"),
+		Scheme:       mgr.GetScheme(),
+		WatchFlags:   &watchFlags,
+		ConfigEvents: sinkConfigEvents,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Sink")
 		os.Exit(1)
 	}
 
+	if err = (&controllers.PulsarConnectionReconciler{
+		Client: mgr.GetClient(),
+		Log:    ctrl.Log.WithName("controllers").WithName("PulsarConnection"),
+		Scheme: mgr.GetScheme(),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "PulsarConnection")
+		os.Exit(1)
+	}
+	if err = (&controllers.FunctionStateSnapshotReconciler{
+		Client: mgr.GetClient(),
+		Log:    ctrl.Log.WithName("controllers").WithName("FunctionStateSnapshot"),
+		Scheme: mgr.GetScheme(),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "FunctionStateSnapshot")
+		os.Exit(1)
+	}
+
 	// enable the webhook service by default
 	// Disable function-mesh webhook with `ENABLE_WEBHOOKS=false` when we run locally.
 	if os.Getenv("ENABLE_WEBHOOKS") != "false" {
@@ -218,6 +284,18 @@ func checkGroupVersions(log logr.Logger) (utils.WatchFlags, error) {
 	return watchFlags, nil
 }
 
+// parseNamespaces splits a comma-separated list of namespaces, ignoring the empty entries
+func parseNamespaces(value string) []string {
+	var namespaces []string
+	for _, namespace := range strings.Split(value, ",") {
+		namespace = strings.TrimSpace(namespace)
+		if namespace != "" {
+			namespaces = append(namespaces, namespace)
+		}
+	}
+	return namespaces
+}
+
 func lookupEnvOrString(key string, defaultVal string) string {
 	if val, ok := os.LookupEnv(key); ok {
 		return val
@@ -235,3 +313,14 @@ func lookupEnvOrBool(key string, defaultVal bool) bool {
 	}
 	return defaultVal
 }
+
+func lookupEnvOrDuration(key string, defaultVal time.Duration) time.Duration {
+	if val, ok := os.LookupEnv(key); ok {
+		v, err := time.ParseDuration(val)
+		if err != nil {
+			setupLog.Error(err, "unable to convert env: %s to duration", key)
+		}
+		return v
+	}
+	return defaultVal
+}
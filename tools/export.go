@@ -0,0 +1,96 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/streamnative/function-mesh/tools/export"
+)
+
+// runExport converts Function Mesh manifests into pulsar-admin configs, the configs are written to
+// stdout or to <output-dir>/<kind>s/<name>.<format> and the dropped fields are reported to stderr
+func runExport(args []string) {
+	flags := flag.NewFlagSet("export", flag.ExitOnError)
+	var files []string
+	var format, outputDir string
+	flags.Func("f", "The manifest file with the functions, sources and sinks, - for stdin, can be repeated",
+		func(file string) error {
+			files = append(files, file)
+			return nil
+		})
+	flags.StringVar(&format, "format", "yaml", "The output format, yaml or json")
+	flags.StringVar(&outputDir, "output-dir", "", "The directory the configs are written to, stdout if not specified")
+	_ = flags.Parse(args)
+	if len(files) == 0 {
+		files = []string{"-"}
+	}
+
+	for _, file := range files {
+		var reader io.Reader = os.Stdin
+		if file != "-" {
+			f, err := os.Open(file)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Open manifest %s failed, err %v\n", file, err)
+				os.Exit(1)
+			}
+			defer f.Close()
+			reader = f
+		}
+		results, err := export.Manifests(reader)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Export manifest %s failed, err %v\n", file, err)
+			os.Exit(1)
+		}
+		for _, result := range results {
+			data, err := result.Marshal(format)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Marshal %s %s failed, err %v\n", result.Kind, result.Name, err)
+				os.Exit(1)
+			}
+			if len(result.Dropped) > 0 {
+				fmt.Fprintf(os.Stderr, "%s %s: dropped Function Mesh only fields %s\n",
+					result.Kind, result.Name, strings.Join(result.Dropped, ", "))
+			}
+			if outputDir == "" {
+				fmt.Printf("# %s %s\n%s", result.Kind, result.Name, data)
+				if format == "yaml" {
+					fmt.Println("---")
+				} else {
+					fmt.Println()
+				}
+				continue
+			}
+			dir := filepath.Join(outputDir, strings.ToLower(result.Kind)+"s")
+			if err = os.MkdirAll(dir, os.ModePerm); err != nil {
+				fmt.Fprintf(os.Stderr, "Create directory %s failed, err %v\n", dir, err)
+				os.Exit(1)
+			}
+			path := filepath.Join(dir, result.Name+"."+format)
+			if err = os.WriteFile(path, data, 0644); err != nil {
+				fmt.Fprintf(os.Stderr, "Write %s failed, err %v\n", path, err)
+				os.Exit(1)
+			}
+		}
+	}
+}
@@ -0,0 +1,409 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/streamnative/function-mesh/api/compute/v1alpha1"
+	"github.com/streamnative/function-mesh/controllers/proto"
+	"github.com/streamnative/function-mesh/controllers/spec"
+	"github.com/streamnative/pulsarctl/pkg/pulsar/utils"
+)
+
+const builtinPrefix = "builtin://"
+
+// FromFunction converts a Function into a function config, the Function Mesh only fields which
+// cannot be expressed in the config are returned as dropped
+func FromFunction(function *v1alpha1.Function) (*FunctionConfig, []string, error) {
+	details := spec.MakeFunctionDetails(function)
+	userConfig, err := unmarshalMap(details.UserConfig)
+	if err != nil {
+		return nil, nil, err
+	}
+	secrets, err := unmarshalMap(details.SecretsMap)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	config := &FunctionConfig{
+		Tenant:                       details.Tenant,
+		Namespace:                    details.Namespace,
+		Name:                         details.Name,
+		ClassName:                    details.ClassName,
+		LogTopic:                     details.LogTopic,
+		ProcessingGuarantees:         details.ProcessingGuarantees.String(),
+		RetainOrdering:               details.RetainOrdering,
+		RetainKeyOrdering:            details.RetainKeyOrdering,
+		UserConfig:                   userConfig,
+		Secrets:                      secrets,
+		Runtime:                      details.Runtime.String(),
+		AutoAck:                      details.AutoAck,
+		Parallelism:                  details.Parallelism,
+		Resources:                    convertResources(details.Resources),
+		RuntimeFlags:                 details.RuntimeFlags,
+		Output:                       details.Sink.Topic,
+		OutputSerdeClassName:         details.Sink.SerDeClassName,
+		OutputSchemaType:             details.Sink.SchemaType,
+		ProducerConfig:               convertProducerSpec(details.Sink.ProducerSpec),
+		ForwardSourceMessageProperty: details.Sink.ForwardSourceMessageProperty,
+		SubName:                      details.Source.SubscriptionName,
+		SubscriptionPosition:         convertSubscriptionPosition(details.Source.SubscriptionPosition),
+		CleanupSubscription:          details.Source.CleanupSubscription,
+		TimeoutMs:                    makeTimeoutMs(details.Source.TimeoutMs),
+		MaxPendingAsyncRequests:      function.Spec.MaxPendingAsyncRequests,
+	}
+	config.Inputs, config.TopicsPattern, config.InputSpecs = convertInputSpecs(details.Source.InputSpecs)
+	if details.RetryDetails != nil {
+		config.MaxMessageRetries = &details.RetryDetails.MaxMessageRetries
+		config.DeadLetterTopic = details.RetryDetails.DeadLetterTopic
+	}
+	// the window function executor wraps the window function, the worker wraps it again
+	if function.Spec.WindowConfig != nil {
+		delete(config.UserConfig, spec.WindowFunctionConfigKeyName)
+		if len(config.UserConfig) == 0 {
+			config.UserConfig = nil
+		}
+		config.ClassName = function.Spec.ClassName
+		config.WindowConfig = function.Spec.WindowConfig.DeepCopy()
+		config.WindowConfig.ActualWindowFunctionClassName = function.Spec.ClassName
+	}
+	switch {
+	case function.Spec.Java != nil:
+		config.Jar, err = packageURL(function.Spec.Java.Jar, function.Spec.Java.JarLocation)
+	case function.Spec.Python != nil:
+		config.Py, err = packageURL(function.Spec.Python.Py, function.Spec.Python.PyLocation)
+	case function.Spec.Golang != nil:
+		config.Go, err = packageURL(function.Spec.Golang.Go, function.Spec.Golang.GoLocation)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	python := function.Spec.Python
+	pythonDependencies := python != nil && (python.HasDependencies() || python.DependencyRepository != "" ||
+		python.ExtraDependencyRepository != "")
+	dropped, err := droppedFields(function.Spec.Pod, runtimeFields(function.Spec.Runtime, map[string]bool{
+		"maxBufferedTuples":   function.Spec.MaxBufferedTuples != nil,
+		"functionVersion":     function.Spec.FunctionVersion != "",
+		"image":               function.Spec.Image != "",
+		"imagePullPolicy":     function.Spec.ImagePullPolicy != "",
+		"downloaderImage":     function.Spec.DownloaderImage != "",
+		"maxReplicas":         function.Spec.MaxReplicas != nil,
+		"volumeMounts":        len(function.Spec.VolumeMounts) > 0,
+		"statefulConfig":      function.Spec.StateConfig != nil,
+		"paused":              function.Spec.Paused,
+		"python.dependencies": pythonDependencies,
+		"java.dependencies":   hasJavaDependencies(function.Spec.Java),
+	}))
+	if err != nil {
+		return nil, nil, err
+	}
+	return config, dropped, nil
+}
+
+// FromSource converts a Source into a source config, the Function Mesh only fields which cannot
+// be expressed in the config are returned as dropped
+func FromSource(source *v1alpha1.Source) (*SourceConfig, []string, error) {
+	details := spec.MakeSourceDetails(source)
+	configs, err := unmarshalMap(details.Source.Configs)
+	if err != nil {
+		return nil, nil, err
+	}
+	secrets, err := unmarshalMap(details.SecretsMap)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	config := &SourceConfig{
+		Tenant:               details.Tenant,
+		Namespace:            details.Namespace,
+		Name:                 details.Name,
+		ClassName:            details.Source.ClassName,
+		TopicName:            details.Sink.Topic,
+		ProducerConfig:       convertProducerSpec(details.Sink.ProducerSpec),
+		SerdeClassName:       details.Sink.SerDeClassName,
+		SchemaType:           details.Sink.SchemaType,
+		Configs:              configs,
+		Secrets:              secrets,
+		Parallelism:          details.Parallelism,
+		ProcessingGuarantees: details.ProcessingGuarantees.String(),
+		Resources:            convertResources(details.Resources),
+		RuntimeFlags:         details.RuntimeFlags,
+	}
+	if config.Archive, err = connectorArchive(source.Spec.SourceType, source.Spec.Java); err != nil {
+		return nil, nil, err
+	}
+	if config.ProducerConfig != nil {
+		config.BatchBuilder = config.ProducerConfig.BatchBuilder
+	}
+	// the batch source executor wraps the batch source, the worker wraps it again
+	if batch := source.Spec.BatchSourceConfig.DeepCopy(); batch != nil {
+		batch.ApplyTrigger()
+		delete(config.Configs, v1alpha1.BatchSourceConfigKey)
+		delete(config.Configs, v1alpha1.BatchSourceClassNameKey)
+		if len(config.Configs) == 0 {
+			config.Configs = nil
+		}
+		config.ClassName = source.Spec.ClassName
+		config.BatchSourceConfig = &BatchSourceConfig{DiscoveryTriggererClassName: batch.DiscoveryTriggererClassName}
+		if batch.DiscoveryTriggererConfig != nil {
+			config.BatchSourceConfig.DiscoveryTriggererConfig = batch.DiscoveryTriggererConfig.DeepCopy().Data
+		}
+	}
+
+	dropped, err := droppedFields(source.Spec.Pod, runtimeFields(source.Spec.Runtime, map[string]bool{
+		"maxBufferedTuples": source.Spec.MaxBufferedTuples != nil,
+		"functionVersion":   source.Spec.FunctionVersion != "",
+		"image":             source.Spec.Image != "",
+		"imagePullPolicy":   source.Spec.ImagePullPolicy != "",
+		"downloaderImage":   source.Spec.DownloaderImage != "",
+		"maxReplicas":       source.Spec.MaxReplicas != nil,
+		"volumeMounts":      len(source.Spec.VolumeMounts) > 0,
+		"statefulConfig":    source.Spec.StateConfig != nil,
+		"paused":            source.Spec.Paused,
+		"java.dependencies": hasJavaDependencies(source.Spec.Java),
+	}))
+	if err != nil {
+		return nil, nil, err
+	}
+	return config, dropped, nil
+}
+
+// FromSink converts a Sink into a sink config, the Function Mesh only fields which cannot be
+// expressed in the config are returned as dropped
+func FromSink(sink *v1alpha1.Sink) (*SinkConfig, []string, error) {
+	details := spec.MakeSinkDetails(sink)
+	configs, err := unmarshalMap(details.Sink.Configs)
+	if err != nil {
+		return nil, nil, err
+	}
+	secrets, err := unmarshalMap(details.SecretsMap)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	config := &SinkConfig{
+		Tenant:                     details.Tenant,
+		Namespace:                  details.Namespace,
+		Name:                       details.Name,
+		ClassName:                  details.Sink.ClassName,
+		SourceSubscriptionName:     details.Source.SubscriptionName,
+		SourceSubscriptionPosition: convertSubscriptionPosition(details.Source.SubscriptionPosition),
+		Configs:                    configs,
+		Secrets:                    secrets,
+		Parallelism:                details.Parallelism,
+		ProcessingGuarantees:       details.ProcessingGuarantees.String(),
+		RetainOrdering:             details.RetainOrdering,
+		RetainKeyOrdering:          details.RetainKeyOrdering,
+		Resources:                  convertResources(details.Resources),
+		AutoAck:                    details.AutoAck,
+		TimeoutMs:                  makeTimeoutMs(details.Source.TimeoutMs),
+		CleanupSubscription:        details.Source.CleanupSubscription,
+		RuntimeFlags:               details.RuntimeFlags,
+	}
+	if config.Archive, err = connectorArchive(sink.Spec.SinkType, sink.Spec.Java); err != nil {
+		return nil, nil, err
+	}
+	config.Inputs, config.TopicsPattern, config.InputSpecs = convertInputSpecs(details.Source.InputSpecs)
+	config.NegativeAckRedeliveryDelayMs = makeTimeoutMs(details.Source.NegativeAckRedeliveryDelayMs)
+	if details.RetryDetails != nil {
+		config.MaxMessageRetries = &details.RetryDetails.MaxMessageRetries
+		config.DeadLetterTopic = details.RetryDetails.DeadLetterTopic
+	}
+
+	dropped, err := droppedFields(sink.Spec.Pod, runtimeFields(sink.Spec.Runtime, map[string]bool{
+		"maxBufferedTuples": sink.Spec.MaxBufferedTuples != nil,
+		"functionVersion":   sink.Spec.FunctionVersion != "",
+		"image":             sink.Spec.Image != "",
+		"imagePullPolicy":   sink.Spec.ImagePullPolicy != "",
+		"downloaderImage":   sink.Spec.DownloaderImage != "",
+		"maxReplicas":       sink.Spec.MaxReplicas != nil,
+		"volumeMounts":      len(sink.Spec.VolumeMounts) > 0,
+		"statefulConfig":    sink.Spec.StateConfig != nil,
+		"paused":            sink.Spec.Paused,
+		"java.dependencies": hasJavaDependencies(sink.Spec.Java),
+	}))
+	if err != nil {
+		return nil, nil, err
+	}
+	return config, dropped, nil
+}
+
+func unmarshalMap(data string) (map[string]interface{}, error) {
+	if data == "" {
+		return nil, nil
+	}
+	var result map[string]interface{}
+	if err := json.Unmarshal([]byte(data), &result); err != nil {
+		return nil, err
+	}
+	if len(result) == 0 {
+		return nil, nil
+	}
+	return result, nil
+}
+
+func convertResources(resources *proto.Resources) *utils.Resources {
+	if resources == nil {
+		return nil
+	}
+	return &utils.Resources{CPU: resources.Cpu, RAM: resources.Ram, Disk: resources.Disk}
+}
+
+func convertProducerSpec(producer *proto.ProducerSpec) *ProducerConfig {
+	if producer == nil || (producer.MaxPendingMessages == 0 && producer.MaxPendingMessagesAcrossPartitions == 0 &&
+		!producer.UseThreadLocalProducers && producer.BatchBuilder == "") {
+		return nil
+	}
+	return &ProducerConfig{
+		MaxPendingMessages:                 producer.MaxPendingMessages,
+		MaxPendingMessagesAcrossPartitions: producer.MaxPendingMessagesAcrossPartitions,
+		UseThreadLocalProducers:            producer.UseThreadLocalProducers,
+		BatchBuilder:                       producer.BatchBuilder,
+	}
+}
+
+func convertSubscriptionPosition(position proto.SubscriptionPosition) string {
+	if position == proto.SubscriptionPosition_EARLIEST {
+		return "Earliest"
+	}
+	return "Latest"
+}
+
+func convertInputSpecs(specs map[string]*proto.ConsumerSpec) ([]string, string, map[string]ConsumerConfig) {
+	var inputs []string
+	var topicsPattern string
+	inputSpecs := map[string]ConsumerConfig{}
+	for topic, consumer := range specs {
+		if consumer.IsRegexPattern {
+			topicsPattern = topic
+			continue
+		}
+		inputs = append(inputs, topic)
+		config := ConsumerConfig{
+			SchemaType:         consumer.SchemaType,
+			SerdeClassName:     consumer.SerdeClassName,
+			SchemaProperties:   consumer.SchemaProperties,
+			ConsumerProperties: consumer.ConsumerProperties,
+		}
+		if consumer.ReceiverQueueSize != nil {
+			config.ReceiverQueueSize = &consumer.ReceiverQueueSize.Value
+		}
+		if config.SchemaType != "" || config.SerdeClassName != "" || config.ReceiverQueueSize != nil ||
+			len(config.SchemaProperties) > 0 || len(config.ConsumerProperties) > 0 {
+			inputSpecs[topic] = config
+		}
+	}
+	sort.Strings(inputs)
+	if len(inputSpecs) == 0 {
+		inputSpecs = nil
+	}
+	return inputs, topicsPattern, inputSpecs
+}
+
+func makeTimeoutMs(timeoutMs uint64) *int64 {
+	if timeoutMs == 0 {
+		return nil
+	}
+	timeout := int64(timeoutMs)
+	return &timeout
+}
+
+// packageURL returns the location of a package, the worker downloads the package from the location
+// or reads it from its local file system
+// packageURL returns the package of the config, the oci packages cannot be fetched by the function worker
+func packageURL(file, location string) (string, error) {
+	if strings.HasPrefix(strings.ToLower(location), v1alpha1.PackageURLOCI) {
+		return "", fmt.Errorf("the oci package %s cannot be exported, the function worker cannot fetch it", location)
+	}
+	if location != "" {
+		return location, nil
+	}
+	return file, nil
+}
+
+func connectorArchive(connectorType string, java *v1alpha1.JavaRuntime) (string, error) {
+	if connectorType != "" {
+		return builtinPrefix + connectorType, nil
+	}
+	if java == nil {
+		return "", nil
+	}
+	return packageURL(java.Jar, java.JarLocation)
+}
+
+// hasJavaDependencies returns whether the java runtime adds dependencies or shared libraries to the classpath
+func hasJavaDependencies(java *v1alpha1.JavaRuntime) bool {
+	return java != nil && (len(java.Dependencies) > 0 || len(java.SharedLibraries) > 0)
+}
+
+// runtimeFields adds the Function Mesh only fields of the runtimes to the fields
+func runtimeFields(runtime v1alpha1.Runtime, fields map[string]bool) map[string]bool {
+	if runtime.Java != nil {
+		fields["java.sha256"] = runtime.Java.Sha256 != ""
+		fields["java.jvm"] = runtime.Java.JVM != nil
+		logFields("java", runtime.Java.Log, fields)
+	}
+	if runtime.Python != nil {
+		fields["python.sha256"] = runtime.Python.Sha256 != ""
+		logFields("python", runtime.Python.Log, fields)
+	}
+	if runtime.Golang != nil {
+		fields["golang.sha256"] = runtime.Golang.Sha256 != ""
+		logFields("golang", runtime.Golang.Log, fields)
+	}
+	return fields
+}
+
+// logFields adds the log settings which are only applied by the rendered log configs to the fields
+func logFields(runtime string, log *v1alpha1.RuntimeLogConfig, fields map[string]bool) {
+	if log == nil {
+		return
+	}
+	fields[runtime+".log.format"] = log.Format != ""
+	fields[runtime+".log.pattern"] = log.Pattern != ""
+	fields[runtime+".log.loggers"] = len(log.Loggers) > 0
+	fields[runtime+".log.stdoutWithLogTopic"] = log.StdoutWithLogTopic
+}
+
+// droppedFields lists the fields of the pod policy and the other Function Mesh only fields which are set
+func droppedFields(pod v1alpha1.PodPolicy, fields map[string]bool) ([]string, error) {
+	var dropped []string
+	data, err := json.Marshal(pod)
+	if err != nil {
+		return nil, err
+	}
+	podFields := map[string]interface{}{}
+	if err = json.Unmarshal(data, &podFields); err != nil {
+		return nil, err
+	}
+	for field := range podFields {
+		dropped = append(dropped, "pod."+field)
+	}
+	for field, set := range fields {
+		if set {
+			dropped = append(dropped, field)
+		}
+	}
+	sort.Strings(dropped)
+	return dropped, nil
+}
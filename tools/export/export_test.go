@@ -0,0 +1,117 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package export
+
+import (
+	"flag"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var update = flag.Bool("update", false, "update the golden files")
+
+func TestManifestsGolden(t *testing.T) {
+	manifests, err := os.Open(filepath.Join("testdata", "manifests.yaml"))
+	assert.Nil(t, err)
+	defer manifests.Close()
+
+	results, err := Manifests(manifests)
+	assert.Nil(t, err)
+	assert.Len(t, results, 3)
+
+	for _, result := range results {
+		actual, err := result.Marshal("yaml")
+		assert.Nil(t, err)
+		golden := filepath.Join("testdata", "golden", strings.ToLower(result.Kind)+"-"+result.Name+".yaml")
+		if *update {
+			assert.Nil(t, ioutil.WriteFile(golden, actual, 0644))
+		}
+		expected, err := ioutil.ReadFile(golden)
+		assert.Nil(t, err)
+		assert.Equal(t, string(expected), string(actual), golden)
+	}
+
+	assert.Equal(t, []string{"image", "maxReplicas", "pod.serviceAccountName", "pod.sidecars", "pod.vpa"},
+		results[0].Dropped)
+	assert.Empty(t, results[1].Dropped)
+	assert.Equal(t, []string{"statefulConfig"}, results[2].Dropped)
+}
+
+func TestResultMarshalJSON(t *testing.T) {
+	results, err := Manifests(strings.NewReader(`{"apiVersion": "compute.functionmesh.io/v1alpha1",
+"kind": "Function", "metadata": {"name": "echo"}, "spec": {"className": "Echo",
+"input": {"topics": ["in"]}, "output": {"topic": "out"}, "python": {"py": "echo.py"}}}`))
+	assert.Nil(t, err)
+	assert.Len(t, results, 1)
+
+	data, err := results[0].Marshal("json")
+	assert.Nil(t, err)
+	assert.Contains(t, string(data), `"name": "echo"`)
+	assert.Contains(t, string(data), `"runtime": "PYTHON"`)
+	assert.Contains(t, string(data), `"py": "echo.py"`)
+	assert.Contains(t, string(data), `"processingGuarantees": "ATLEAST_ONCE"`)
+
+	_, err = results[0].Marshal("toml")
+	assert.NotNil(t, err)
+}
+
+func TestManifestsWithOCIPackage(t *testing.T) {
+	_, err := Manifests(strings.NewReader(`{"apiVersion": "compute.functionmesh.io/v1alpha1",
+"kind": "Sink", "metadata": {"name": "es"}, "spec": {"className": "Sink", "input": {"topics": ["in"]},
+"java": {"jar": "connector.nar", "jarLocation": "oci://registry.example.com/connectors/es:v1"}}}`))
+	assert.EqualError(t, err, "export sink es: the oci package oci://registry.example.com/connectors/es:v1 "+
+		"cannot be exported, the function worker cannot fetch it")
+}
+
+func TestManifestsDroppedFields(t *testing.T) {
+	testData := []struct {
+		kind    string
+		spec    string
+		dropped []string
+	}{
+		{"Function", `"python": {"py": "echo.py", "pyLocation": "function://public/default/echo@v1", "sha256": "abc"}`,
+			[]string{"python.sha256"}},
+		{"Source", `"java": {"jar": "source.jar", "sha256": "abc"}`, []string{"java.sha256"}},
+		{"Sink", `"java": {"jar": "sink.jar", "jarLocation": "sink://public/default/sink@v1", "sha256": "abc"}`,
+			[]string{"java.sha256"}},
+		{"Function", `"golang": {"go": "echo"}, "maxBufferedTuples": 512, "functionVersion": "v1", ` +
+			`"pod": {"podManagementPolicy": "Parallel"}`,
+			[]string{"functionVersion", "maxBufferedTuples", "pod.podManagementPolicy"}},
+		{"Source", `"java": {"jar": "source.jar"}, "functionVersion": "v1"`, []string{"functionVersion"}},
+		{"Sink", `"java": {"jar": "sink.jar"}, "maxBufferedTuples": 512, "pod": {"podManagementPolicy": "Parallel"}`,
+			[]string{"maxBufferedTuples", "pod.podManagementPolicy"}},
+		{"Function", `"python": {"py": "echo.py", "log": {"level": "debug", "format": "json", ` +
+			`"loggers": {"urllib3": "warn"}, "stdoutWithLogTopic": true}}`,
+			[]string{"python.log.format", "python.log.loggers", "python.log.stdoutWithLogTopic"}},
+		{"Sink", `"java": {"jar": "sink.jar", "log": {"pattern": "%m%n"}}`, []string{"java.log.pattern"}},
+		{"Source", `"java": {"jar": "source.jar", "jvm": {"heapPercentage": 70}}`, []string{"java.jvm"}},
+	}
+	for _, v := range testData {
+		results, err := Manifests(strings.NewReader(`{"apiVersion": "compute.functionmesh.io/v1alpha1", "kind": "` +
+			v.kind + `", "metadata": {"name": "test"}, "spec": {"className": "Test", "input": {"topics": ["in"]}, ` +
+			`"output": {"topic": "out"}, ` + v.spec + `}}`))
+		assert.Nil(t, err, v.spec)
+		assert.Len(t, results, 1, v.spec)
+		assert.Equal(t, v.dropped, results[0].Dropped, v.spec)
+	}
+}
@@ -0,0 +1,124 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package export
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/streamnative/function-mesh/api/compute/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"sigs.k8s.io/yaml"
+)
+
+// Result is the config exported from a Function, Source or Sink manifest
+type Result struct {
+	Kind string
+	Name string
+	// Config is a *FunctionConfig, *SourceConfig or *SinkConfig
+	Config interface{}
+	// Dropped lists the Function Mesh only fields which are not exported
+	Dropped []string
+}
+
+// Manifests exports the functions, sources and sinks of a YAML or JSON stream of manifests,
+// the other kinds of objects are skipped
+func Manifests(reader io.Reader) ([]Result, error) {
+	var results []Result
+	decoder := utilyaml.NewYAMLOrJSONDecoder(reader, 4096)
+	for {
+		var raw json.RawMessage
+		err := decoder.Decode(&raw)
+		if errors.Is(err, io.EOF) {
+			return results, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(bytes.TrimSpace(raw)) == 0 || bytes.Equal(bytes.TrimSpace(raw), []byte("null")) {
+			continue
+		}
+		typeMeta := metav1.TypeMeta{}
+		if err = json.Unmarshal(raw, &typeMeta); err != nil {
+			return nil, err
+		}
+		if typeMeta.APIVersion != v1alpha1.GroupVersion.String() {
+			continue
+		}
+		result, err := exportManifest(typeMeta.Kind, raw)
+		if err != nil {
+			return nil, err
+		}
+		if result != nil {
+			results = append(results, *result)
+		}
+	}
+}
+
+func exportManifest(kind string, raw []byte) (*Result, error) {
+	switch kind {
+	case "Function":
+		function := &v1alpha1.Function{}
+		if err := json.Unmarshal(raw, function); err != nil {
+			return nil, err
+		}
+		config, dropped, err := FromFunction(function)
+		if err != nil {
+			return nil, fmt.Errorf("export function %s: %w", function.Name, err)
+		}
+		return &Result{Kind: kind, Name: function.Name, Config: config, Dropped: dropped}, nil
+	case "Source":
+		source := &v1alpha1.Source{}
+		if err := json.Unmarshal(raw, source); err != nil {
+			return nil, err
+		}
+		config, dropped, err := FromSource(source)
+		if err != nil {
+			return nil, fmt.Errorf("export source %s: %w", source.Name, err)
+		}
+		return &Result{Kind: kind, Name: source.Name, Config: config, Dropped: dropped}, nil
+	case "Sink":
+		sink := &v1alpha1.Sink{}
+		if err := json.Unmarshal(raw, sink); err != nil {
+			return nil, err
+		}
+		config, dropped, err := FromSink(sink)
+		if err != nil {
+			return nil, fmt.Errorf("export sink %s: %w", sink.Name, err)
+		}
+		return &Result{Kind: kind, Name: sink.Name, Config: config, Dropped: dropped}, nil
+	default:
+		return nil, nil
+	}
+}
+
+// Marshal encodes the config of a result in the yaml or json format
+func (r *Result) Marshal(format string) ([]byte, error) {
+	switch format {
+	case "yaml":
+		return yaml.Marshal(r.Config)
+	case "json":
+		return json.MarshalIndent(r.Config, "", "  ")
+	default:
+		return nil, fmt.Errorf("unknown format %s, expect yaml or json", format)
+	}
+}
@@ -0,0 +1,137 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// Package export converts Function Mesh resources into the function, source and sink configs of a
+// Pulsar function worker, which can be passed to pulsar-admin with --function-config-file,
+// --source-config-file and --sink-config-file
+package export
+
+import (
+	"github.com/streamnative/function-mesh/api/compute/v1alpha1"
+	"github.com/streamnative/pulsarctl/pkg/pulsar/utils"
+)
+
+// ConsumerConfig is the consumer config of an input topic
+type ConsumerConfig struct {
+	SchemaType         string            `json:"schemaType,omitempty"`
+	SerdeClassName     string            `json:"serdeClassName,omitempty"`
+	RegexPattern       bool              `json:"regexPattern,omitempty"`
+	ReceiverQueueSize  *int32            `json:"receiverQueueSize,omitempty"`
+	SchemaProperties   map[string]string `json:"schemaProperties,omitempty"`
+	ConsumerProperties map[string]string `json:"consumerProperties,omitempty"`
+}
+
+// ProducerConfig is the producer config of the output topic
+type ProducerConfig struct {
+	MaxPendingMessages                 int32  `json:"maxPendingMessages,omitempty"`
+	MaxPendingMessagesAcrossPartitions int32  `json:"maxPendingMessagesAcrossPartitions,omitempty"`
+	UseThreadLocalProducers            bool   `json:"useThreadLocalProducers,omitempty"`
+	BatchBuilder                       string `json:"batchBuilder,omitempty"`
+}
+
+// FunctionConfig is the function config of a Pulsar function worker
+type FunctionConfig struct {
+	Tenant                       string                    `json:"tenant,omitempty"`
+	Namespace                    string                    `json:"namespace,omitempty"`
+	Name                         string                    `json:"name,omitempty"`
+	ClassName                    string                    `json:"className,omitempty"`
+	Inputs                       []string                  `json:"inputs,omitempty"`
+	TopicsPattern                string                    `json:"topicsPattern,omitempty"`
+	InputSpecs                   map[string]ConsumerConfig `json:"inputSpecs,omitempty"`
+	Output                       string                    `json:"output,omitempty"`
+	OutputSerdeClassName         string                    `json:"outputSerdeClassName,omitempty"`
+	OutputSchemaType             string                    `json:"outputSchemaType,omitempty"`
+	ProducerConfig               *ProducerConfig           `json:"producerConfig,omitempty"`
+	LogTopic                     string                    `json:"logTopic,omitempty"`
+	ProcessingGuarantees         string                    `json:"processingGuarantees,omitempty"`
+	RetainOrdering               bool                      `json:"retainOrdering,omitempty"`
+	RetainKeyOrdering            bool                      `json:"retainKeyOrdering,omitempty"`
+	ForwardSourceMessageProperty bool                      `json:"forwardSourceMessageProperty,omitempty"`
+	UserConfig                   map[string]interface{}    `json:"userConfig,omitempty"`
+	Secrets                      map[string]interface{}    `json:"secrets,omitempty"`
+	Runtime                      string                    `json:"runtime,omitempty"`
+	AutoAck                      bool                      `json:"autoAck"`
+	MaxMessageRetries            *int32                    `json:"maxMessageRetries,omitempty"`
+	DeadLetterTopic              string                    `json:"deadLetterTopic,omitempty"`
+	SubName                      string                    `json:"subName,omitempty"`
+	SubscriptionPosition         string                    `json:"subscriptionPosition,omitempty"`
+	CleanupSubscription          bool                      `json:"cleanupSubscription,omitempty"`
+	Parallelism                  int32                     `json:"parallelism,omitempty"`
+	Resources                    *utils.Resources          `json:"resources,omitempty"`
+	TimeoutMs                    *int64                    `json:"timeoutMs,omitempty"`
+	Jar                          string                    `json:"jar,omitempty"`
+	Py                           string                    `json:"py,omitempty"`
+	Go                           string                    `json:"go,omitempty"`
+	WindowConfig                 *v1alpha1.WindowConfig    `json:"windowConfig,omitempty"`
+	RuntimeFlags                 string                    `json:"runtimeFlags,omitempty"`
+	MaxPendingAsyncRequests      *int32                    `json:"maxPendingAsyncRequests,omitempty"`
+}
+
+// BatchSourceConfig is the batch source config of a Pulsar function worker
+type BatchSourceConfig struct {
+	DiscoveryTriggererClassName string                 `json:"discoveryTriggererClassName"`
+	DiscoveryTriggererConfig    map[string]interface{} `json:"discoveryTriggererConfig,omitempty"`
+}
+
+// SourceConfig is the source config of a Pulsar function worker
+type SourceConfig struct {
+	Tenant               string                 `json:"tenant,omitempty"`
+	Namespace            string                 `json:"namespace,omitempty"`
+	Name                 string                 `json:"name,omitempty"`
+	ClassName            string                 `json:"className,omitempty"`
+	TopicName            string                 `json:"topicName,omitempty"`
+	ProducerConfig       *ProducerConfig        `json:"producerConfig,omitempty"`
+	SerdeClassName       string                 `json:"serdeClassName,omitempty"`
+	SchemaType           string                 `json:"schemaType,omitempty"`
+	Configs              map[string]interface{} `json:"configs,omitempty"`
+	Secrets              map[string]interface{} `json:"secrets,omitempty"`
+	Parallelism          int32                  `json:"parallelism,omitempty"`
+	ProcessingGuarantees string                 `json:"processingGuarantees,omitempty"`
+	Resources            *utils.Resources       `json:"resources,omitempty"`
+	Archive              string                 `json:"archive,omitempty"`
+	RuntimeFlags         string                 `json:"runtimeFlags,omitempty"`
+	BatchSourceConfig    *BatchSourceConfig     `json:"batchSourceConfig,omitempty"`
+	BatchBuilder         string                 `json:"batchBuilder,omitempty"`
+}
+
+// SinkConfig is the sink config of a Pulsar function worker
+type SinkConfig struct {
+	Tenant                       string                    `json:"tenant,omitempty"`
+	Namespace                    string                    `json:"namespace,omitempty"`
+	Name                         string                    `json:"name,omitempty"`
+	ClassName                    string                    `json:"className,omitempty"`
+	SourceSubscriptionName       string                    `json:"sourceSubscriptionName,omitempty"`
+	SourceSubscriptionPosition   string                    `json:"sourceSubscriptionPosition,omitempty"`
+	Inputs                       []string                  `json:"inputs,omitempty"`
+	TopicsPattern                string                    `json:"topicsPattern,omitempty"`
+	InputSpecs                   map[string]ConsumerConfig `json:"inputSpecs,omitempty"`
+	Configs                      map[string]interface{}    `json:"configs,omitempty"`
+	Secrets                      map[string]interface{}    `json:"secrets,omitempty"`
+	Parallelism                  int32                     `json:"parallelism,omitempty"`
+	ProcessingGuarantees         string                    `json:"processingGuarantees,omitempty"`
+	RetainOrdering               bool                      `json:"retainOrdering,omitempty"`
+	RetainKeyOrdering            bool                      `json:"retainKeyOrdering,omitempty"`
+	Resources                    *utils.Resources          `json:"resources,omitempty"`
+	AutoAck                      bool                      `json:"autoAck"`
+	TimeoutMs                    *int64                    `json:"timeoutMs,omitempty"`
+	NegativeAckRedeliveryDelayMs *int64                    `json:"negativeAckRedeliveryDelayMs,omitempty"`
+	Archive                      string                    `json:"archive,omitempty"`
+	CleanupSubscription          bool                      `json:"cleanupSubscription,omitempty"`
+	RuntimeFlags                 string                    `json:"runtimeFlags,omitempty"`
+	MaxMessageRetries            *int32                    `json:"maxMessageRetries,omitempty"`
+	DeadLetterTopic              string                    `json:"deadLetterTopic,omitempty"`
+}
@@ -0,0 +1,169 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/streamnative/function-mesh/api/generated/clientset/versioned"
+	"github.com/streamnative/function-mesh/tools/logs"
+	"github.com/streamnative/pulsarctl/pkg/pulsar"
+	"github.com/streamnative/pulsarctl/pkg/pulsar/common"
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/controller-runtime/pkg/client/config"
+)
+
+// runLogs tails the logs of a Function, Source or Sink, read from its log topic when it has one and from the
+// logs of its pods otherwise
+func runLogs(args []string) {
+	flags := flag.NewFlagSet("logs", flag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: %s logs [flags] <function|source|sink> <name>\n", os.Args[0])
+		flags.PrintDefaults()
+	}
+	var namespace, from, instances, subscription string
+	var webServiceURL, authPlugin, authParams string
+	var filter logs.Filter
+	var follow bool
+	var tail int64
+	var pollInterval time.Duration
+	flags.StringVar(&namespace, "namespace", "", "The namespace of the component, all namespaces if not specified")
+	flags.StringVar(&from, "from", "auto",
+		"Where the logs are read from: topic, pods, or auto for the log topic when the component has one")
+	flags.StringVar(&filter.Level, "level", "", "The minimum level of the printed lines")
+	flags.StringVar(&instances, "instances", "", "The comma separated IDs of the instances whose lines are printed")
+	flags.BoolVar(&follow, "follow", true, "Keep printing the new lines")
+	flags.Int64Var(&tail, "tail", -1, "The number of lines printed from the end of the pod logs, all if negative")
+	flags.StringVar(&subscription, "subscription", "",
+		"The temporary subscription used to read the log topic, a random name if not specified")
+	flags.DurationVar(&pollInterval, "poll-interval", logs.DefaultPollInterval,
+		"How long to wait before reading the drained log topic again")
+	flags.StringVar(&webServiceURL, "web-service-url", "",
+		"The web service URL of the Pulsar cluster, read from the messaging of the component if not specified")
+	flags.StringVar(&authPlugin, "auth-plugin", "", "The client authentication plugin of the Pulsar admin client")
+	flags.StringVar(&authParams, "auth-params", "", "The client authentication parameters of the Pulsar admin client")
+	_ = flags.Parse(args)
+	if flags.NArg() != 2 {
+		flags.Usage()
+		os.Exit(2)
+	}
+	if err := filter.Validate(); err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid --level, err %v\n", err)
+		os.Exit(2)
+	}
+	for _, instance := range strings.Split(instances, ",") {
+		if instance = strings.TrimSpace(instance); instance == "" {
+			continue
+		}
+		id, err := strconv.Atoi(instance)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Invalid --instances %s, err %v\n", instances, err)
+			os.Exit(2)
+		}
+		filter.Instances = append(filter.Instances, id)
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer cancel()
+
+	restConfig, err := config.GetConfig()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Load kubeconfig failed, err %v\n", err)
+		os.Exit(1)
+	}
+	clientset, err := versioned.NewForConfig(restConfig)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Create Kubernetes client failed, err %v\n", err)
+		os.Exit(1)
+	}
+	kube, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Create Kubernetes client failed, err %v\n", err)
+		os.Exit(1)
+	}
+
+	target, err := logs.Resolve(ctx, clientset, flags.Arg(0), namespace, flags.Arg(1))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Resolve %s %s failed, err %v\n", flags.Arg(0), flags.Arg(1), err)
+		os.Exit(1)
+	}
+	if from == "auto" {
+		from = "pods"
+		if target.LogTopic != "" {
+			from = "topic"
+		}
+	}
+
+	switch from {
+	case "pods":
+		options := logs.PodOptions{Follow: follow}
+		if tail >= 0 {
+			options.TailLines = &tail
+		}
+		err = logs.TailPods(ctx, kube, target, options, filter, os.Stdout)
+	case "topic":
+		if target.LogTopic == "" {
+			fmt.Fprintf(os.Stderr, "%s %s/%s has no log topic\n", target.Kind, target.Namespace, target.Name)
+			os.Exit(1)
+		}
+		adminConfig, configErr := logs.MakeAdminConfig(ctx, kube, clientset, target)
+		if configErr != nil && webServiceURL == "" {
+			fmt.Fprintf(os.Stderr, "Resolve the Pulsar cluster of %s %s/%s failed, err %v\n",
+				target.Kind, target.Namespace, target.Name, configErr)
+			os.Exit(1)
+		}
+		if configErr != nil {
+			adminConfig = &common.Config{PulsarAPIVersion: common.V2}
+		}
+		if webServiceURL != "" {
+			adminConfig.WebServiceURL = webServiceURL
+		}
+		if authPlugin != "" {
+			adminConfig.AuthPlugin, adminConfig.AuthParams = authPlugin, authParams
+		}
+		admin, clientErr := pulsar.New(adminConfig)
+		if clientErr != nil {
+			fmt.Fprintf(os.Stderr, "Create client failed for service %s, err %v\n", adminConfig.WebServiceURL,
+				clientErr)
+			os.Exit(1)
+		}
+		if subscription == "" {
+			subscription = fmt.Sprintf("function-mesh-logs-%d", time.Now().UnixNano())
+		}
+		err = logs.TailTopic(ctx, admin.Subscriptions(), target.LogTopic, logs.TopicOptions{
+			Subscription: subscription,
+			Follow:       follow,
+			PollInterval: pollInterval,
+		}, filter, os.Stdout)
+	default:
+		fmt.Fprintf(os.Stderr, "Invalid --from %s, must be one of topic, pods or auto\n", from)
+		os.Exit(2)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Tail the logs of %s %s/%s failed, err %v\n", target.Kind, target.Namespace,
+			target.Name, err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,164 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// Package logs resolves the log topic and the pods of the Function Mesh components and tails their logs
+package logs
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/streamnative/function-mesh/api/compute/v1alpha1"
+	"github.com/streamnative/function-mesh/api/generated/clientset/versioned"
+	"github.com/streamnative/function-mesh/controllers/admin"
+	"github.com/streamnative/function-mesh/controllers/spec"
+	"github.com/streamnative/pulsarctl/pkg/pulsar/common"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Target is a Function, Source or Sink whose logs are tailed
+type Target struct {
+	Kind      string
+	Namespace string
+	Name      string
+	LogTopic  string
+	Messaging v1alpha1.Messaging
+}
+
+// ContainerName returns the name of the container running the instances of the target
+func (t *Target) ContainerName() string {
+	return "pulsar-" + t.Kind
+}
+
+// LabelSelector returns the selector of the pods running the instances of the target
+func (t *Target) LabelSelector() string {
+	return fmt.Sprintf("compute.functionmesh.io/component=%s,compute.functionmesh.io/name=%s", t.Kind, t.Name)
+}
+
+// Resolve gets a Function, Source or Sink from the Kubernetes cluster, the component is looked up in all the
+// namespaces when the namespace is empty and must be unique
+func Resolve(ctx context.Context, clientset versioned.Interface, kind, namespace, name string) (*Target, error) {
+	kind = strings.ToLower(kind)
+	var targets []*Target
+	switch kind {
+	case spec.ComponentFunction:
+		list, err := clientset.ComputeV1alpha1().Functions(namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list functions: %w", err)
+		}
+		for i := range list.Items {
+			if item := &list.Items[i]; item.Name == name {
+				targets = append(targets, &Target{Kind: kind, Namespace: item.Namespace, Name: item.Name,
+					LogTopic: item.Spec.LogTopic, Messaging: item.Spec.Messaging})
+			}
+		}
+	case spec.ComponentSource:
+		list, err := clientset.ComputeV1alpha1().Sources(namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list sources: %w", err)
+		}
+		for i := range list.Items {
+			if item := &list.Items[i]; item.Name == name {
+				targets = append(targets, &Target{Kind: kind, Namespace: item.Namespace, Name: item.Name,
+					Messaging: item.Spec.Messaging})
+			}
+		}
+	case spec.ComponentSink:
+		list, err := clientset.ComputeV1alpha1().Sinks(namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list sinks: %w", err)
+		}
+		for i := range list.Items {
+			if item := &list.Items[i]; item.Name == name {
+				targets = append(targets, &Target{Kind: kind, Namespace: item.Namespace, Name: item.Name,
+					Messaging: item.Spec.Messaging})
+			}
+		}
+	default:
+		return nil, fmt.Errorf("unsupported kind %s, must be one of function, source or sink", kind)
+	}
+
+	switch len(targets) {
+	case 0:
+		if namespace == "" {
+			return nil, fmt.Errorf("%s %s not found in any namespace", kind, name)
+		}
+		return nil, fmt.Errorf("%s %s/%s not found", kind, namespace, name)
+	case 1:
+		return targets[0], nil
+	default:
+		namespaces := make([]string, 0, len(targets))
+		for _, target := range targets {
+			namespaces = append(namespaces, target.Namespace)
+		}
+		return nil, fmt.Errorf("%s %s exists in namespaces %s, specify the namespace",
+			kind, name, strings.Join(namespaces, ", "))
+	}
+}
+
+// ResolveMessaging returns the pulsar messaging of the target with its PulsarConnection applied
+func ResolveMessaging(ctx context.Context, clientset versioned.Interface,
+	target *Target) (*v1alpha1.PulsarMessaging, error) {
+	messaging := *target.Messaging.DeepCopy()
+	if name := spec.GetPulsarConnectionName(target.Namespace, messaging); name != "" {
+		connection, err := clientset.ComputeV1alpha1().PulsarConnections(target.Namespace).Get(ctx, name,
+			metav1.GetOptions{})
+		switch {
+		case apierrors.IsNotFound(err) && (messaging.Pulsar == nil || messaging.Pulsar.PulsarConnection == ""):
+			// the default connection of the namespace is optional
+			return spec.MakePulsarMessaging(target.Namespace, messaging), nil
+		case err != nil:
+			return nil, fmt.Errorf("failed to get pulsar connection %s/%s: %w", target.Namespace, name, err)
+		}
+		spec.ApplyPulsarConnection(&messaging, &v1alpha1.PodPolicy{}, connection)
+	}
+	return spec.MakePulsarMessaging(target.Namespace, messaging), nil
+}
+
+// MakeAdminConfig returns the config of the Pulsar admin client of the target, it is read from the config
+// map and the secrets of its messaging
+func MakeAdminConfig(ctx context.Context, kube kubernetes.Interface, clientset versioned.Interface,
+	target *Target) (*common.Config, error) {
+	messaging, err := ResolveMessaging(ctx, clientset, target)
+	if err != nil {
+		return nil, err
+	}
+	return admin.MakePulsarAdminConfig(ctx, clientsetDataGetter{kube: kube}, target.Namespace, messaging)
+}
+
+type clientsetDataGetter struct {
+	kube kubernetes.Interface
+}
+
+func (g clientsetDataGetter) GetConfigMapData(ctx context.Context, namespace, name string) (map[string]string, error) {
+	configMap, err := g.kube.CoreV1().ConfigMaps(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return configMap.Data, nil
+}
+
+func (g clientsetDataGetter) GetSecretData(ctx context.Context, namespace, name string) (map[string]string, error) {
+	secret, err := g.kube.CoreV1().Secrets(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return admin.MakeSecretData(secret), nil
+}
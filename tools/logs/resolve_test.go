@@ -0,0 +1,106 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package logs
+
+import (
+	"context"
+	"testing"
+
+	"github.com/streamnative/function-mesh/api/compute/v1alpha1"
+	"github.com/streamnative/function-mesh/api/generated/clientset/versioned/fake"
+	"github.com/streamnative/function-mesh/controllers/spec"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+)
+
+func TestResolve(t *testing.T) {
+	clientset := fake.NewSimpleClientset(
+		&v1alpha1.Function{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "functions", Name: "word-count"},
+			Spec: v1alpha1.FunctionSpec{
+				LogTopic:  "persistent://public/default/word-count-logs",
+				Messaging: v1alpha1.Messaging{Pulsar: &v1alpha1.PulsarMessaging{PulsarConfig: "pulsar"}},
+			},
+		},
+		&v1alpha1.Sink{ObjectMeta: metav1.ObjectMeta{Namespace: "pulsar-io", Name: "es-sink"}},
+		&v1alpha1.Sink{ObjectMeta: metav1.ObjectMeta{Namespace: "staging", Name: "es-sink"}},
+	)
+	ctx := context.Background()
+
+	target, err := Resolve(ctx, clientset, "Function", "", "word-count")
+	assert.Nil(t, err)
+	assert.Equal(t, &Target{
+		Kind:      spec.ComponentFunction,
+		Namespace: "functions",
+		Name:      "word-count",
+		LogTopic:  "persistent://public/default/word-count-logs",
+		Messaging: v1alpha1.Messaging{Pulsar: &v1alpha1.PulsarMessaging{PulsarConfig: "pulsar"}},
+	}, target)
+	assert.Equal(t, "pulsar-function", target.ContainerName())
+	assert.Equal(t, "compute.functionmesh.io/component=function,compute.functionmesh.io/name=word-count",
+		target.LabelSelector())
+
+	target, err = Resolve(ctx, clientset, "sink", "staging", "es-sink")
+	assert.Nil(t, err)
+	assert.Equal(t, "staging", target.Namespace)
+
+	_, err = Resolve(ctx, clientset, "sink", "", "es-sink")
+	assert.EqualError(t, err, "sink es-sink exists in namespaces pulsar-io, staging, specify the namespace")
+	_, err = Resolve(ctx, clientset, "function", "pulsar-io", "word-count")
+	assert.EqualError(t, err, "function pulsar-io/word-count not found")
+	_, err = Resolve(ctx, clientset, "source", "", "word-count")
+	assert.EqualError(t, err, "source word-count not found in any namespace")
+	_, err = Resolve(ctx, clientset, "connector", "", "word-count")
+	assert.NotNil(t, err)
+}
+
+func TestMakeAdminConfig(t *testing.T) {
+	clientset := fake.NewSimpleClientset(&v1alpha1.PulsarConnection{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "functions", Name: v1alpha1.DefaultPulsarConnectionName},
+		Spec: v1alpha1.PulsarConnectionSpec{
+			WebServiceURL: "http://pulsar-broker.pulsar:8080",
+			AuthSecret:    "pulsar-auth",
+		},
+	})
+	connection, err := clientset.ComputeV1alpha1().PulsarConnections("functions").Get(context.Background(),
+		v1alpha1.DefaultPulsarConnectionName, metav1.GetOptions{})
+	assert.Nil(t, err)
+	kube := k8sfake.NewSimpleClientset(
+		spec.MakePulsarConnectionConfigMap(connection),
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "functions", Name: "pulsar-auth"},
+			Data: map[string][]byte{
+				"clientAuthenticationPlugin":     []byte("org.apache.pulsar.client.impl.auth.AuthenticationToken"),
+				"clientAuthenticationParameters": []byte("token:secret"),
+			},
+		},
+	)
+	target := &Target{Kind: spec.ComponentFunction, Namespace: "functions", Name: "word-count"}
+
+	config, err := MakeAdminConfig(context.Background(), kube, clientset, target)
+	assert.Nil(t, err)
+	assert.Equal(t, "http://pulsar-broker.pulsar:8080", config.WebServiceURL)
+	assert.Equal(t, "org.apache.pulsar.client.impl.auth.AuthenticationToken", config.AuthPlugin)
+	assert.Equal(t, "token:secret", config.AuthParams)
+
+	target.Messaging = v1alpha1.Messaging{Pulsar: &v1alpha1.PulsarMessaging{PulsarConfig: "missing"}}
+	_, err = MakeAdminConfig(context.Background(), kube, clientset, target)
+	assert.NotNil(t, err)
+}
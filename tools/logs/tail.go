@@ -0,0 +1,334 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package logs
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/streamnative/pulsarctl/pkg/cli"
+	"github.com/streamnative/pulsarctl/pkg/pulsar/utils"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// DefaultPollInterval is how long to wait before peeking the log topic again once it is drained
+	DefaultPollInterval = time.Second
+
+	// the message properties set by the log appender of the instances
+	levelProperty    = "loglevel"
+	instanceProperty = "instance"
+)
+
+// the log levels ordered by severity
+var levels = []string{"trace", "debug", "info", "warn", "error", "fatal"}
+
+// the names of the log levels printed by the runtimes
+var levelAliases = map[string]string{
+	"trace":    "trace",
+	"debug":    "debug",
+	"info":     "info",
+	"warn":     "warn",
+	"warning":  "warn",
+	"error":    "error",
+	"fatal":    "fatal",
+	"critical": "fatal",
+	"panic":    "fatal",
+}
+
+var (
+	// the level field of the JSON and the logfmt lines
+	levelFieldPattern = regexp.MustCompile(`(?i)(?:"level"\s*:\s*"|\blevel=)(\w+)`)
+	// the upper case level of the pattern layouts, lower case words are left to the messages
+	levelTokenPattern = regexp.MustCompile(`\b(TRACE|DEBUG|INFO|WARN|WARNING|ERROR|FATAL|CRITICAL)\b`)
+)
+
+// Filter selects the log lines by level and instance
+type Filter struct {
+	// Level is the minimum level of the lines, the lines without a level are always kept
+	Level string
+	// Instances are the IDs of the instances whose lines are kept, all the instances if empty
+	Instances []int
+}
+
+// Validate checks the level of the filter
+func (f Filter) Validate() error {
+	if f.Level != "" && levelSeverity(f.Level) < 0 {
+		return fmt.Errorf("invalid level %s, must be one of %s", f.Level, strings.Join(levels, ", "))
+	}
+	return nil
+}
+
+// MatchInstance returns true if the lines of an instance are kept
+func (f Filter) MatchInstance(instance int) bool {
+	if len(f.Instances) == 0 {
+		return true
+	}
+	for _, i := range f.Instances {
+		if i == instance {
+			return true
+		}
+	}
+	return false
+}
+
+// MatchLevel returns true if the lines of a level are kept
+func (f Filter) MatchLevel(level string) bool {
+	if f.Level == "" {
+		return true
+	}
+	severity := levelSeverity(level)
+	return severity < 0 || severity >= levelSeverity(f.Level)
+}
+
+func levelSeverity(level string) int {
+	level, ok := levelAliases[strings.ToLower(level)]
+	if !ok {
+		return -1
+	}
+	for i, l := range levels {
+		if l == level {
+			return i
+		}
+	}
+	return -1
+}
+
+// ParseLevel returns the level of a log line printed by the Java, Python or Go runtime, or an empty string
+// if the line has no level
+func ParseLevel(line string) string {
+	match := levelFieldPattern.FindStringSubmatch(line)
+	if match == nil {
+		match = levelTokenPattern.FindStringSubmatch(line)
+	}
+	if match == nil {
+		return ""
+	}
+	return levelAliases[strings.ToLower(match[1])]
+}
+
+// Subscriptions is the part of the Pulsar admin API used to tail a topic
+type Subscriptions interface {
+	Create(utils.TopicName, string, utils.MessageID) error
+	Delete(utils.TopicName, string) error
+	PeekMessages(utils.TopicName, string, int) ([]*utils.Message, error)
+	SkipMessages(utils.TopicName, string, int64) error
+}
+
+// TopicOptions are the options of tailing a log topic
+type TopicOptions struct {
+	// Subscription is the temporary subscription created to read the topic, it is deleted once done
+	Subscription string
+	// Follow keeps polling the topic once it is drained
+	Follow bool
+	// PollInterval is how long to wait before peeking the drained topic again
+	PollInterval time.Duration
+}
+
+// TailTopic prints the messages published to the log topic from now on, each message is prefixed with the
+// ID of the instance that logged it
+func TailTopic(ctx context.Context, subscriptions Subscriptions, topic string, options TopicOptions,
+	filter Filter, out io.Writer) (err error) {
+	topicName, err := utils.GetTopicName(topic)
+	if err != nil {
+		return fmt.Errorf("invalid log topic %s: %w", topic, err)
+	}
+	if options.PollInterval <= 0 {
+		options.PollInterval = DefaultPollInterval
+	}
+	if err := subscriptions.Create(*topicName, options.Subscription, utils.Latest); err != nil {
+		return fmt.Errorf("failed to subscribe to the log topic %s: %w", topic, err)
+	}
+	defer func() {
+		if deleteErr := subscriptions.Delete(*topicName, options.Subscription); deleteErr != nil && err == nil {
+			err = fmt.Errorf("failed to delete the subscription %s: %w", options.Subscription, deleteErr)
+		}
+	}()
+
+	for {
+		messages, err := subscriptions.PeekMessages(*topicName, options.Subscription, 1)
+		if isNotFound(err) || err == nil && len(messages) == 0 {
+			if !options.Follow {
+				return nil
+			}
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-time.After(options.PollInterval):
+			}
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to peek the log topic %s: %w", topic, err)
+		}
+		for _, message := range messages {
+			printMessage(message, filter, out)
+		}
+		// a peeked entry holds all the messages of a batch, skipping one entry skips all of them
+		if err := subscriptions.SkipMessages(*topicName, options.Subscription, 1); err != nil {
+			return fmt.Errorf("failed to skip the peeked log messages: %w", err)
+		}
+		if ctx.Err() != nil {
+			return nil
+		}
+	}
+}
+
+func printMessage(message *utils.Message, filter Filter, out io.Writer) {
+	line := strings.TrimRight(string(message.Payload), "\n")
+	instance, hasInstance := "", false
+	level := ""
+	// the property names come from HTTP headers so their case is not preserved
+	for key, value := range message.Properties {
+		switch strings.ToLower(key) {
+		case instanceProperty:
+			instance, hasInstance = value, true
+		case levelProperty:
+			level = value
+		}
+	}
+	if level == "" {
+		level = ParseLevel(line)
+	}
+	if !filter.MatchLevel(level) {
+		return
+	}
+	if hasInstance {
+		id, err := strconv.Atoi(instance)
+		if err == nil && !filter.MatchInstance(id) {
+			return
+		}
+		fmt.Fprintf(out, "[%s] %s\n", instance, line)
+		return
+	}
+	fmt.Fprintln(out, line)
+}
+
+func isNotFound(err error) bool {
+	var cliErr cli.Error
+	if errors.As(err, &cliErr) {
+		return cliErr.Code == http.StatusNotFound
+	}
+	return false
+}
+
+// PodOptions are the options of tailing the logs of the pods
+type PodOptions struct {
+	// Follow streams the new lines of the logs
+	Follow bool
+	// TailLines is the number of lines printed from the end of the logs, all the lines if nil
+	TailLines *int64
+}
+
+type pod struct {
+	name     string
+	instance int
+}
+
+// TailPods prints the logs of the pods of all the StatefulSet ordinals of the target, each line is prefixed
+// with the ID of the instance, which is the ordinal of its pod
+func TailPods(ctx context.Context, kube kubernetes.Interface, target *Target, options PodOptions,
+	filter Filter, out io.Writer) error {
+	list, err := kube.CoreV1().Pods(target.Namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: target.LabelSelector(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list the pods of %s %s/%s: %w", target.Kind, target.Namespace, target.Name, err)
+	}
+	var pods []pod
+	for _, item := range list.Items {
+		instance, err := podOrdinal(item.Name)
+		if err != nil || !filter.MatchInstance(instance) {
+			continue
+		}
+		pods = append(pods, pod{name: item.Name, instance: instance})
+	}
+	if len(pods) == 0 {
+		return fmt.Errorf("no pods found for %s %s/%s", target.Kind, target.Namespace, target.Name)
+	}
+	sort.Slice(pods, func(i, j int) bool {
+		return pods[i].instance < pods[j].instance
+	})
+
+	var lock sync.Mutex
+	var wg sync.WaitGroup
+	errs := make([]error, len(pods))
+	for i := range pods {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs[i] = tailPod(ctx, kube, target, pods[i], options, filter, out, &lock)
+		}(i)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func tailPod(ctx context.Context, kube kubernetes.Interface, target *Target, pod pod, options PodOptions,
+	filter Filter, out io.Writer, lock *sync.Mutex) error {
+	stream, err := kube.CoreV1().Pods(target.Namespace).GetLogs(pod.name, &corev1.PodLogOptions{
+		Container: target.ContainerName(),
+		Follow:    options.Follow,
+		TailLines: options.TailLines,
+	}).Stream(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get the logs of pod %s: %w", pod.name, err)
+	}
+	defer stream.Close()
+
+	scanner := bufio.NewScanner(stream)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !filter.MatchLevel(ParseLevel(line)) {
+			continue
+		}
+		lock.Lock()
+		fmt.Fprintf(out, "[%d] %s\n", pod.instance, line)
+		lock.Unlock()
+	}
+	if err := scanner.Err(); err != nil && ctx.Err() == nil {
+		return fmt.Errorf("failed to read the logs of pod %s: %w", pod.name, err)
+	}
+	return nil
+}
+
+// podOrdinal returns the ordinal of a StatefulSet pod, which is the suffix of its name
+func podOrdinal(name string) (int, error) {
+	i := strings.LastIndex(name, "-")
+	if i < 0 {
+		return 0, fmt.Errorf("pod %s has no ordinal", name)
+	}
+	return strconv.Atoi(name[i+1:])
+}
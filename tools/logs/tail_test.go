@@ -0,0 +1,140 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package logs
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/streamnative/pulsarctl/pkg/cli"
+	"github.com/streamnative/pulsarctl/pkg/pulsar/utils"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+)
+
+type fakeSubscriptions struct {
+	created  []string
+	deleted  []string
+	messages [][]*utils.Message
+}
+
+func (s *fakeSubscriptions) Create(topic utils.TopicName, name string, _ utils.MessageID) error {
+	s.created = append(s.created, topic.String()+"/"+name)
+	return nil
+}
+
+func (s *fakeSubscriptions) Delete(topic utils.TopicName, name string) error {
+	s.deleted = append(s.deleted, topic.String()+"/"+name)
+	return nil
+}
+
+func (s *fakeSubscriptions) PeekMessages(utils.TopicName, string, int) ([]*utils.Message, error) {
+	if len(s.messages) == 0 {
+		return nil, cli.Error{Code: http.StatusNotFound, Reason: "Message not found"}
+	}
+	return s.messages[0], nil
+}
+
+func (s *fakeSubscriptions) SkipMessages(utils.TopicName, string, int64) error {
+	s.messages = s.messages[1:]
+	return nil
+}
+
+func TestParseLevel(t *testing.T) {
+	assert.Equal(t, "info", ParseLevel("2022-07-01T10:00:00.000+0000 [main] INFO  org.example.WordCount - started"))
+	assert.Equal(t, "warn", ParseLevel("[2022-07-01 10:00:00,000] [WARNING] python_instance.py: slow"))
+	assert.Equal(t, "debug", ParseLevel(`time="2022-07-01T10:00:00Z" level=debug msg="an error message"`))
+	assert.Equal(t, "error", ParseLevel(`{"instant":{},"level":"ERROR","message":"failed"}`))
+	assert.Equal(t, "", ParseLevel("an error without level"))
+}
+
+func TestFilter(t *testing.T) {
+	filter := Filter{Level: "warn", Instances: []int{0, 2}}
+	assert.Nil(t, filter.Validate())
+	assert.True(t, filter.MatchLevel("ERROR"))
+	assert.True(t, filter.MatchLevel("warning"))
+	assert.False(t, filter.MatchLevel("info"))
+	assert.True(t, filter.MatchLevel(""))
+	assert.True(t, filter.MatchInstance(2))
+	assert.False(t, filter.MatchInstance(1))
+	assert.True(t, Filter{}.MatchInstance(1))
+	assert.NotNil(t, Filter{Level: "verbose"}.Validate())
+}
+
+func TestTailTopic(t *testing.T) {
+	topic := "persistent://public/default/word-count-logs"
+	subscriptions := &fakeSubscriptions{messages: [][]*utils.Message{
+		{utils.NewMessage(topic, utils.Latest, []byte("started\n"),
+			map[string]string{"Loglevel": "INFO", "Instance": "0"})},
+		{
+			utils.NewMessage(topic, utils.Latest, []byte("slow"), map[string]string{"Loglevel": "WARN", "Instance": "1"}),
+			utils.NewMessage(topic, utils.Latest, []byte("failed"), map[string]string{"Loglevel": "ERROR", "Instance": "0"}),
+		},
+		{utils.NewMessage(topic, utils.Latest, []byte("2022-07-01 [main] ERROR no properties"), nil)},
+	}}
+	out := &bytes.Buffer{}
+
+	err := TailTopic(context.Background(), subscriptions, topic, TopicOptions{Subscription: "tail"},
+		Filter{Level: "warn", Instances: []int{0}}, out)
+	assert.Nil(t, err)
+	assert.Equal(t, "[0] failed\n2022-07-01 [main] ERROR no properties\n", out.String())
+	assert.Equal(t, []string{topic + "/tail"}, subscriptions.created)
+	assert.Equal(t, []string{topic + "/tail"}, subscriptions.deleted)
+
+	err = TailTopic(context.Background(), subscriptions, "persistent://invalid", TopicOptions{}, Filter{}, out)
+	assert.NotNil(t, err)
+}
+
+func TestTailPods(t *testing.T) {
+	labels := map[string]string{
+		"compute.functionmesh.io/component": "function",
+		"compute.functionmesh.io/name":      "word-count",
+	}
+	kube := k8sfake.NewSimpleClientset(
+		&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "functions", Name: "word-count-function-10",
+			Labels: labels}},
+		&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "functions", Name: "word-count-function-2",
+			Labels: labels}},
+		&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "functions", Name: "word-count-function-0",
+			Labels: labels}},
+		&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "functions", Name: "other-function-0",
+			Labels: map[string]string{"compute.functionmesh.io/name": "other"}}},
+	)
+	target := &Target{Kind: "function", Namespace: "functions", Name: "word-count"}
+
+	out := &bytes.Buffer{}
+	err := TailPods(context.Background(), kube, target, PodOptions{}, Filter{Instances: []int{0, 10}}, out)
+	assert.Nil(t, err)
+	// the fake clientset returns the same logs for all the pods
+	assert.Equal(t, "[0] fake logs\n[10] fake logs\n", sortLines(out.String()))
+
+	err = TailPods(context.Background(), kube, target, PodOptions{}, Filter{Instances: []int{5}}, out)
+	assert.EqualError(t, err, "no pods found for function functions/word-count")
+}
+
+func sortLines(s string) string {
+	lines := strings.SplitAfter(s, "\n")
+	sort.Strings(lines)
+	return strings.Join(lines, "")
+}
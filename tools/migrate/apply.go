@@ -0,0 +1,323 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package migrate
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/streamnative/function-mesh/api/compute/v1alpha1"
+	"github.com/streamnative/function-mesh/api/generated/clientset/versioned"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	DefaultReadyTimeout = 5 * time.Minute
+	DefaultPollInterval = 5 * time.Second
+)
+
+// ApplyOptions controls how the converted resources are applied to a Kubernetes cluster
+type ApplyOptions struct {
+	// DryRun prints the difference with the existing resources instead of applying them
+	DryRun bool
+	// StopOriginal stops the component in the function worker once the Function Mesh copy is ready
+	StopOriginal bool
+	// ReadyTimeout is how long to wait for the Function Mesh copy to be ready
+	ReadyTimeout time.Duration
+	// PollInterval is how often the readiness of the Function Mesh copy is checked
+	PollInterval time.Duration
+	// Out receives the dry run differences and the progress
+	Out io.Writer
+}
+
+// ApplyWriter creates or updates the converted resources in a Kubernetes cluster
+type ApplyWriter struct {
+	ctx       context.Context
+	clientset versioned.Interface
+	client    *Client
+	options   ApplyOptions
+}
+
+// NewApplyWriter creates an ApplyWriter, the client is used to stop the original components
+func NewApplyWriter(ctx context.Context, clientset versioned.Interface, client *Client,
+	options ApplyOptions) *ApplyWriter {
+	if options.ReadyTimeout == 0 {
+		options.ReadyTimeout = DefaultReadyTimeout
+	}
+	if options.PollInterval == 0 {
+		options.PollInterval = DefaultPollInterval
+	}
+	if options.Out == nil {
+		options.Out = io.Discard
+	}
+	return &ApplyWriter{ctx: ctx, clientset: clientset, client: client, options: options}
+}
+
+// Write implements Writer
+func (w *ApplyWriter) Write(namespace string, obj client.Object) error {
+	compute := w.clientset.ComputeV1alpha1()
+	switch o := obj.(type) {
+	case *v1alpha1.Function:
+		return apply(w, o, applyTarget[*v1alpha1.Function]{
+			kind:    KindFunction,
+			client:  compute.Functions(o.Namespace),
+			spec:    func(function *v1alpha1.Function) interface{} { return function.Spec },
+			setSpec: func(existing, desired *v1alpha1.Function) { existing.Spec = desired.Spec },
+			ready: func(function *v1alpha1.Function) bool {
+				return isReady(function.Generation, function.Status.ObservedGeneration,
+					function.Spec.Replicas, function.Status.ReadyReplicas)
+			},
+			stop: func(function *v1alpha1.Function) error {
+				return w.client.Admin().Functions().StopFunction(function.Spec.Tenant, function.Spec.Namespace,
+					function.Spec.Name)
+			},
+		})
+	case *v1alpha1.Source:
+		return apply(w, o, applyTarget[*v1alpha1.Source]{
+			kind:    KindSource,
+			client:  compute.Sources(o.Namespace),
+			spec:    func(source *v1alpha1.Source) interface{} { return source.Spec },
+			setSpec: func(existing, desired *v1alpha1.Source) { existing.Spec = desired.Spec },
+			ready: func(source *v1alpha1.Source) bool {
+				return isReady(source.Generation, source.Status.ObservedGeneration,
+					source.Spec.Replicas, source.Status.ReadyReplicas)
+			},
+			stop: func(source *v1alpha1.Source) error {
+				return w.client.Admin().Sources().StopSource(source.Spec.Tenant, source.Spec.Namespace,
+					source.Spec.Name)
+			},
+		})
+	case *v1alpha1.Sink:
+		return apply(w, o, applyTarget[*v1alpha1.Sink]{
+			kind:    KindSink,
+			client:  compute.Sinks(o.Namespace),
+			spec:    func(sink *v1alpha1.Sink) interface{} { return sink.Spec },
+			setSpec: func(existing, desired *v1alpha1.Sink) { existing.Spec = desired.Spec },
+			ready: func(sink *v1alpha1.Sink) bool {
+				return isReady(sink.Generation, sink.Status.ObservedGeneration,
+					sink.Spec.Replicas, sink.Status.ReadyReplicas)
+			},
+			stop: func(sink *v1alpha1.Sink) error {
+				return w.client.Admin().Sinks().StopSink(sink.Spec.Tenant, sink.Spec.Namespace, sink.Spec.Name)
+			},
+		})
+	default:
+		return fmt.Errorf("unsupported object %T", obj)
+	}
+}
+
+// component is a Function, Source or Sink, defaulted by the admission webhook
+type component interface {
+	client.Object
+	Default()
+}
+
+// componentClient is the part of the typed Function, Source and Sink clients used to apply them
+type componentClient[T component] interface {
+	Get(ctx context.Context, name string, opts metav1.GetOptions) (T, error)
+	Create(ctx context.Context, obj T, opts metav1.CreateOptions) (T, error)
+	Update(ctx context.Context, obj T, opts metav1.UpdateOptions) (T, error)
+}
+
+// applyTarget holds what differs between applying a Function, a Source and a Sink
+type applyTarget[T component] struct {
+	kind    string
+	client  componentClient[T]
+	spec    func(T) interface{}
+	setSpec func(existing, desired T)
+	ready   func(T) bool
+	stop    func(T) error
+}
+
+func apply[T component](w *ApplyWriter, desired T, target applyTarget[T]) error {
+	existing, err := target.client.Get(w.ctx, desired.GetName(), metav1.GetOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	found := err == nil
+	if w.options.DryRun && !found {
+		w.printf("+ %s %s/%s will be created\n", target.kind, desired.GetNamespace(), desired.GetName())
+		return nil
+	}
+	if w.options.DryRun {
+		// the existing spec went through the defaulting webhook, default the desired spec the same way
+		// so that the defaulted fields are not reported as changes
+		defaulted := desired.DeepCopyObject().(T)
+		defaulted.Default()
+		return w.printDiff(target.kind, desired, target.spec(existing), target.spec(defaulted))
+	}
+	if found {
+		target.setSpec(existing, desired)
+		_, err = target.client.Update(w.ctx, existing, metav1.UpdateOptions{})
+	} else {
+		_, err = target.client.Create(w.ctx, desired, metav1.CreateOptions{})
+	}
+	if err != nil {
+		return err
+	}
+	w.printf("%s %s/%s applied\n", target.kind, desired.GetNamespace(), desired.GetName())
+	if !w.options.StopOriginal {
+		return nil
+	}
+	err = w.waitReady(target.kind, desired, func() (bool, error) {
+		current, err := target.client.Get(w.ctx, desired.GetName(), metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		return target.ready(current), nil
+	})
+	if err != nil {
+		return err
+	}
+	return target.stop(desired)
+}
+
+func (w *ApplyWriter) waitReady(kind string, obj client.Object, condition wait.ConditionFunc) error {
+	err := wait.PollImmediate(w.options.PollInterval, w.options.ReadyTimeout, condition)
+	if err != nil {
+		return fmt.Errorf("wait for %s %s/%s to be ready: %w", kind, obj.GetNamespace(), obj.GetName(), err)
+	}
+	w.printf("%s %s/%s is ready, stopping the original %s\n", kind, obj.GetNamespace(), obj.GetName(),
+		strings.ToLower(kind))
+	return nil
+}
+
+func (w *ApplyWriter) printDiff(kind string, desired client.Object, existingSpec, desiredSpec interface{}) error {
+	changes, err := Diff(existingSpec, desiredSpec)
+	if err != nil {
+		return err
+	}
+	if len(changes) == 0 {
+		w.printf("= %s %s/%s is up to date\n", kind, desired.GetNamespace(), desired.GetName())
+		return nil
+	}
+	w.printf("~ %s %s/%s will be updated\n", kind, desired.GetNamespace(), desired.GetName())
+	for _, change := range changes {
+		w.printf("    %s\n", change)
+	}
+	return nil
+}
+
+func (w *ApplyWriter) printf(format string, args ...interface{}) {
+	_, _ = fmt.Fprintf(w.options.Out, format, args...)
+}
+
+func isReady(generation, observedGeneration int64, replicas *int32, readyReplicas int32) bool {
+	desired := int32(1)
+	if replicas != nil {
+		desired = *replicas
+	}
+	return observedGeneration >= generation && readyReplicas >= desired
+}
+
+// Change is a field that differs between two specs
+type Change struct {
+	Path string
+	Old  interface{}
+	New  interface{}
+}
+
+func (c Change) String() string {
+	switch {
+	case c.Old == nil:
+		return fmt.Sprintf("+ spec.%s: %s", c.Path, formatValue(c.New))
+	case c.New == nil:
+		return fmt.Sprintf("- spec.%s: %s", c.Path, formatValue(c.Old))
+	default:
+		return fmt.Sprintf("~ spec.%s: %s -> %s", c.Path, formatValue(c.Old), formatValue(c.New))
+	}
+}
+
+func formatValue(value interface{}) string {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+	return string(data)
+}
+
+// Diff compares the JSON representations of two specs field by field, the changes are sorted by path
+func Diff(old, new interface{}) ([]Change, error) {
+	oldFields, err := flatten(old)
+	if err != nil {
+		return nil, err
+	}
+	newFields, err := flatten(new)
+	if err != nil {
+		return nil, err
+	}
+	var changes []Change
+	for path, oldValue := range oldFields {
+		newValue, ok := newFields[path]
+		if !ok {
+			changes = append(changes, Change{Path: path, Old: oldValue})
+		} else if !reflect.DeepEqual(oldValue, newValue) {
+			changes = append(changes, Change{Path: path, Old: oldValue, New: newValue})
+		}
+	}
+	for path, newValue := range newFields {
+		if _, ok := oldFields[path]; !ok {
+			changes = append(changes, Change{Path: path, New: newValue})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Path < changes[j].Path })
+	return changes, nil
+}
+
+func flatten(obj interface{}) (map[string]interface{}, error) {
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+	var value interface{}
+	if err = json.Unmarshal(data, &value); err != nil {
+		return nil, err
+	}
+	fields := map[string]interface{}{}
+	flattenValue("", value, fields)
+	return fields, nil
+}
+
+func flattenValue(path string, value interface{}, fields map[string]interface{}) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, child := range v {
+			childPath := key
+			if path != "" {
+				childPath = path + "." + key
+			}
+			flattenValue(childPath, child, fields)
+		}
+	case []interface{}:
+		for i, child := range v {
+			flattenValue(fmt.Sprintf("%s[%d]", path, i), child, fields)
+		}
+	case nil:
+	default:
+		fields[path] = v
+	}
+}
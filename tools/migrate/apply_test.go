@@ -0,0 +1,183 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package migrate
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/streamnative/function-mesh/api/compute/v1alpha1"
+	"github.com/streamnative/function-mesh/api/generated/clientset/versioned/fake"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func makeExistingSink() *v1alpha1.Sink {
+	replicas := int32(1)
+	return &v1alpha1.Sink{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "pulsar-io", Name: "es-sink", ResourceVersion: "1"},
+		Spec: v1alpha1.SinkSpec{
+			Name:       "es-sink",
+			Tenant:     "public",
+			Namespace:  "default",
+			Replicas:   &replicas,
+			Image:      "streamnative/pulsar-io-elastic-search:2.9.2.0",
+			SinkConfig: &v1alpha1.Config{Data: map[string]interface{}{"indexName": "events"}},
+		},
+	}
+}
+
+func TestParseNamespaceMapping(t *testing.T) {
+	mapping, err := ParseNamespaceMapping("public/default=functions, public/io=pulsar-io")
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]string{"public/default": "functions", "public/io": "pulsar-io"}, mapping)
+
+	_, err = ParseNamespaceMapping("public=functions")
+	assert.NotNil(t, err)
+	_, err = ParseNamespaceMapping("public/default=")
+	assert.NotNil(t, err)
+}
+
+func TestDiff(t *testing.T) {
+	existing := makeExistingSink().Spec
+	desired := existing.DeepCopy()
+	replicas := int32(3)
+	desired.Replicas = &replicas
+	desired.Image = ""
+	desired.SinkConfig.Data["elasticSearchUrl"] = "http://elasticsearch:9200"
+
+	changes, err := Diff(existing, desired)
+	assert.Nil(t, err)
+	assert.Equal(t, []Change{
+		{Path: "image", Old: "streamnative/pulsar-io-elastic-search:2.9.2.0"},
+		{Path: "replicas", Old: float64(1), New: float64(3)},
+		{Path: "sinkConfig.elasticSearchUrl", New: "http://elasticsearch:9200"},
+	}, changes)
+	assert.Equal(t, `- spec.image: "streamnative/pulsar-io-elastic-search:2.9.2.0"`, changes[0].String())
+	assert.Equal(t, `~ spec.replicas: 1 -> 3`, changes[1].String())
+	assert.Equal(t, `+ spec.sinkConfig.elasticSearchUrl: "http://elasticsearch:9200"`, changes[2].String())
+}
+
+func TestApplyWriterDryRun(t *testing.T) {
+	// the existing sink went through the defaulting webhook
+	existing := makeExistingSink()
+	existing.Default()
+	clientset := fake.NewSimpleClientset(existing)
+	out := &bytes.Buffer{}
+	fakeAdmin, client := newTestClientWithServer(t)
+	writer := NewApplyWriter(context.Background(), clientset, client, ApplyOptions{
+		DryRun:       true,
+		StopOriginal: true,
+		Out:          out,
+	})
+	migrator := NewMigrator(client, writer, Options{ClusterName: "pulsar-west", Namespace: "functions",
+		NamespaceMapping: map[string]string{"other/ns": "pulsar-io"}})
+	migrator.ContinueOnError = true
+
+	report, err := migrator.Run()
+	assert.Nil(t, err)
+	assert.Len(t, report.Migrated, 4)
+	assert.Contains(t, out.String(), "+ Function functions/word-count will be created")
+	assert.Contains(t, out.String(), "+ Sink functions/es-sink will be created")
+
+	// the dry run neither writes to the cluster nor stops the original components
+	for _, action := range clientset.Actions() {
+		assert.Equal(t, "get", action.GetVerb())
+	}
+	assert.Empty(t, fakeAdmin.requests)
+
+	out.Reset()
+	migrator.options.NamespaceMapping["public/default"] = "pulsar-io"
+	_, err = migrator.Run()
+	assert.Nil(t, err)
+	assert.Contains(t, out.String(), "~ Sink pulsar-io/es-sink will be updated")
+	assert.Contains(t, out.String(), "~ spec.replicas: 1 -> 3")
+	assert.Contains(t, out.String(), `- spec.image: "streamnative/pulsar-io-elastic-search:2.9.2.0"`)
+	assert.NotContains(t, out.String(), "spec.autoAck")
+	assert.NotContains(t, out.String(), "spec.input.typeClassName")
+}
+
+func TestApplyWriterStopOriginal(t *testing.T) {
+	clientset := fake.NewSimpleClientset(makeExistingSink())
+	// the Function Mesh copies report ready as soon as they are written
+	markReady := func(action k8stesting.Action) (bool, runtime.Object, error) {
+		switch obj := action.(k8stesting.CreateAction).GetObject().(type) {
+		case *v1alpha1.Function:
+			obj.Status.ReadyReplicas = *obj.Spec.Replicas
+		case *v1alpha1.Source:
+			obj.Status.ReadyReplicas = *obj.Spec.Replicas
+		case *v1alpha1.Sink:
+			obj.Status.ReadyReplicas = *obj.Spec.Replicas
+		}
+		return false, nil, nil
+	}
+	clientset.PrependReactor("create", "*", markReady)
+	clientset.PrependReactor("update", "*", markReady)
+
+	fakeAdmin, client := newTestClientWithServer(t)
+	writer := NewApplyWriter(context.Background(), clientset, client, ApplyOptions{
+		StopOriginal: true,
+		PollInterval: 10 * time.Millisecond,
+		ReadyTimeout: time.Second,
+	})
+	migrator := NewMigrator(client, writer, Options{ClusterName: "pulsar-west",
+		NamespaceMapping: map[string]string{"public/default": "pulsar-io"}})
+	migrator.ContinueOnError = true
+
+	report, err := migrator.Run()
+	assert.Nil(t, err)
+	assert.Len(t, report.Migrated, 4)
+
+	function, err := clientset.ComputeV1alpha1().Functions("pulsar-io").Get(context.Background(),
+		"word-count", metav1.GetOptions{})
+	assert.Nil(t, err)
+	assert.Equal(t, "word-count", function.Spec.Name)
+	sink, err := clientset.ComputeV1alpha1().Sinks("pulsar-io").Get(context.Background(),
+		"es-sink", metav1.GetOptions{})
+	assert.Nil(t, err)
+	assert.Equal(t, int32(3), *sink.Spec.Replicas)
+	assert.Equal(t, "", sink.Spec.Image)
+
+	assert.ElementsMatch(t, []string{
+		"POST /admin/v3/functions/public/default/word-count/stop",
+		"POST /admin/v3/sources/public/default/batch-source/stop",
+		"POST /admin/v3/sources/public/default/kafka-source/stop",
+		"POST /admin/v3/sinks/public/default/es-sink/stop",
+	}, fakeAdmin.requests)
+}
+
+func TestApplyWriterReadyTimeout(t *testing.T) {
+	clientset := fake.NewSimpleClientset()
+	fakeAdmin, client := newTestClientWithServer(t)
+	writer := NewApplyWriter(context.Background(), clientset, client, ApplyOptions{
+		StopOriginal: true,
+		PollInterval: 10 * time.Millisecond,
+		ReadyTimeout: 50 * time.Millisecond,
+	})
+	config, err := client.GetSink("public/default", "es-sink")
+	assert.Nil(t, err)
+	sink, err := ConvertSink(config, Options{ClusterName: "pulsar-west"})
+	assert.Nil(t, err)
+
+	assert.NotNil(t, writer.Write("public/default", sink))
+	assert.Empty(t, fakeAdmin.requests)
+}
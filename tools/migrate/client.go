@@ -0,0 +1,206 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// Package migrate converts the functions, sources and sinks running in a Pulsar function worker
+// into Function Mesh resources
+package migrate
+
+import (
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/streamnative/pulsarctl/pkg/auth"
+	"github.com/streamnative/pulsarctl/pkg/cli"
+	"github.com/streamnative/pulsarctl/pkg/pulsar"
+	"github.com/streamnative/pulsarctl/pkg/pulsar/common"
+	"github.com/streamnative/pulsarctl/pkg/pulsar/utils"
+)
+
+// Client reads the functions, sources and sinks from the admin API of a Pulsar cluster
+type Client struct {
+	// admin serves the tenants and namespaces on the v2 admin API
+	admin pulsar.Client
+	// worker serves the functions, sources and sinks on the v3 admin API
+	worker pulsar.Client
+	// rest reads the configs which are not fully modeled by pulsarctl
+	rest *cli.Client
+}
+
+// NewClient creates a Client from a pulsarctl config
+func NewClient(config *common.Config) (*Client, error) {
+	adminConfig, workerConfig := *config, *config
+	adminConfig.PulsarAPIVersion = common.V2
+	workerConfig.PulsarAPIVersion = common.V3
+	if workerConfig.WebServiceURL == "" {
+		workerConfig.WebServiceURL = pulsar.DefaultWebServiceURL
+	}
+
+	admin, err := pulsar.New(&adminConfig)
+	if err != nil {
+		return nil, err
+	}
+	worker, err := pulsar.New(&workerConfig)
+	if err != nil {
+		return nil, err
+	}
+	transport, err := newTransport(&workerConfig)
+	if err != nil {
+		return nil, err
+	}
+	return &Client{
+		admin:  admin,
+		worker: worker,
+		rest: &cli.Client{
+			ServiceURL:  workerConfig.WebServiceURL,
+			VersionInfo: pulsar.ReleaseVersion,
+			HTTPClient: &http.Client{
+				Timeout:   pulsar.DefaultHTTPTimeOutDuration,
+				Transport: transport,
+			},
+		},
+	}, nil
+}
+
+func newTransport(config *common.Config) (http.RoundTripper, error) {
+	provider, err := auth.GetAuthProvider(config)
+	if err != nil {
+		return nil, err
+	}
+	if provider != nil {
+		return provider, nil
+	}
+	return auth.NewDefaultTransport(config)
+}
+
+// Admin returns the pulsarctl client of the function worker
+func (c *Client) Admin() pulsar.Client {
+	return c.worker
+}
+
+// Namespaces lists all the namespaces of the cluster in the tenant/namespace format
+func (c *Client) Namespaces() ([]string, error) {
+	tenants, err := c.admin.Tenants().List()
+	if err != nil {
+		return nil, err
+	}
+	var namespaces []string
+	for _, tenant := range tenants {
+		tenantNamespaces, err := c.admin.Namespaces().GetNamespaces(tenant)
+		if err != nil {
+			return nil, err
+		}
+		namespaces = append(namespaces, tenantNamespaces...)
+	}
+	return namespaces, nil
+}
+
+// ListFunctions lists the names of the functions in a tenant/namespace
+func (c *Client) ListFunctions(namespace string) ([]string, error) {
+	tenant, ns := splitNamespace(namespace)
+	return c.worker.Functions().GetFunctions(tenant, ns)
+}
+
+// GetFunction returns the config of a function
+func (c *Client) GetFunction(namespace, name string) (*utils.FunctionConfig, error) {
+	tenant, ns := splitNamespace(namespace)
+	config, err := c.worker.Functions().GetFunction(tenant, ns, name)
+	if err != nil {
+		return nil, err
+	}
+	return &config, nil
+}
+
+// GetFunctionWorkerID returns the worker ID of the first instance of a function
+func (c *Client) GetFunctionWorkerID(namespace, name string) (string, error) {
+	tenant, ns := splitNamespace(namespace)
+	status, err := c.worker.Functions().GetFunctionStatus(tenant, ns, name)
+	if err != nil || len(status.Instances) == 0 {
+		return "", err
+	}
+	return status.Instances[0].Status.WorkerID, nil
+}
+
+// ListSources lists the names of the sources in a tenant/namespace
+func (c *Client) ListSources(namespace string) ([]string, error) {
+	tenant, ns := splitNamespace(namespace)
+	return c.worker.Sources().ListSources(tenant, ns)
+}
+
+// GetSource returns the config of a source, including the batch source settings
+func (c *Client) GetSource(namespace, name string) (*SourceConfig, error) {
+	tenant, ns := splitNamespace(namespace)
+	config := &SourceConfig{}
+	err := c.rest.Get(c.endpoint("/sources", tenant, ns, name), config)
+	if err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// GetSourceWorkerID returns the worker ID of the first instance of a source
+func (c *Client) GetSourceWorkerID(namespace, name string) (string, error) {
+	tenant, ns := splitNamespace(namespace)
+	status, err := c.worker.Sources().GetSourceStatus(tenant, ns, name)
+	if err != nil || len(status.Instances) == 0 {
+		return "", err
+	}
+	return status.Instances[0].Status.WorkerID, nil
+}
+
+// ListSinks lists the names of the sinks in a tenant/namespace
+func (c *Client) ListSinks(namespace string) ([]string, error) {
+	tenant, ns := splitNamespace(namespace)
+	return c.worker.Sinks().ListSinks(tenant, ns)
+}
+
+// GetSink returns the config of a sink
+func (c *Client) GetSink(namespace, name string) (*utils.SinkConfig, error) {
+	tenant, ns := splitNamespace(namespace)
+	config, err := c.worker.Sinks().GetSink(tenant, ns, name)
+	if err != nil {
+		return nil, err
+	}
+	return &config, nil
+}
+
+// GetSinkWorkerID returns the worker ID of the first instance of a sink
+func (c *Client) GetSinkWorkerID(namespace, name string) (string, error) {
+	tenant, ns := splitNamespace(namespace)
+	status, err := c.worker.Sinks().GetSinkStatus(tenant, ns, name)
+	if err != nil || len(status.Instances) == 0 {
+		return "", err
+	}
+	return status.Instances[0].Status.WorkerID, nil
+}
+
+func (c *Client) endpoint(componentPath string, parts ...string) string {
+	escapedParts := make([]string, len(parts))
+	for i, part := range parts {
+		escapedParts[i] = url.PathEscape(part)
+	}
+	return path.Join(utils.MakeHTTPPath(common.V3.String(), componentPath), path.Join(escapedParts...))
+}
+
+func splitNamespace(namespace string) (string, string) {
+	parts := strings.SplitN(namespace, "/", 2)
+	if len(parts) < 2 {
+		return parts[0], ""
+	}
+	return parts[0], parts[1]
+}
@@ -0,0 +1,293 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package migrate
+
+import (
+	"fmt"
+	"math"
+	"net/url"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/streamnative/function-mesh/api/compute/v1alpha1"
+	"github.com/streamnative/pulsarctl/pkg/pulsar/utils"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	APIVersion = "compute.functionmesh.io/v1alpha1"
+
+	builtinPrefix = "builtin://"
+
+	packageNameFunctionPrefix = "function://"
+	packageNameSinkPrefix     = "sink://"
+	packageNameSourcePrefix   = "source://"
+
+	httpPrefix  = "http://"
+	httpsPrefix = "https://"
+
+	connectorImage = "streamnative/pulsar-io-%s:%s"
+
+	// secretNameSuffix is appended to the object name to stub the secret of the secrets that
+	// are not fetched from Kubernetes secrets by the function worker
+	secretNameSuffix = "-secrets"
+)
+
+var invalidObjectNameChars = regexp.MustCompile("[^a-z0-9-]+")
+
+// Options controls how the Pulsar configs are translated into Function Mesh resources
+type Options struct {
+	// ClusterName is the name of the Pulsar cluster, it is derived from the worker ID when empty
+	ClusterName string
+	// ConnectorVersion is the version of the pulsar-io images and archives used by builtin connectors
+	ConnectorVersion string
+	// PulsarConfig is the config map with the service URLs of the cluster, it defaults to the cluster name
+	PulsarConfig string
+	// AuthSecret is the secret with the client authentication plugin and parameters
+	AuthSecret string
+	// TLSSecret is the secret with the TLS settings of the client
+	TLSSecret string
+	// Namespace is the Kubernetes namespace of the tenant/namespaces missing from the NamespaceMapping
+	Namespace string
+	// NamespaceMapping maps a Pulsar tenant/namespace to a Kubernetes namespace
+	NamespaceMapping map[string]string
+}
+
+// ParseNamespaceMapping parses a tenant/namespace=namespace,... mapping
+func ParseNamespaceMapping(mapping string) (map[string]string, error) {
+	result := map[string]string{}
+	for _, entry := range strings.Split(mapping, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		parts := strings.SplitN(entry, "=", 2)
+		if len(parts) != 2 || !strings.Contains(parts[0], "/") || parts[1] == "" {
+			return nil, fmt.Errorf("invalid namespace mapping %s, expect tenant/namespace=namespace", entry)
+		}
+		result[parts[0]] = parts[1]
+	}
+	return result, nil
+}
+
+// targetNamespace returns the Kubernetes namespace of the components of a Pulsar tenant/namespace
+func (o Options) targetNamespace(tenant, namespace string) string {
+	if target, ok := o.NamespaceMapping[tenant+"/"+namespace]; ok {
+		return target
+	}
+	if o.Namespace != "" {
+		return o.Namespace
+	}
+	return metav1.NamespaceDefault
+}
+
+// ClusterNameFromWorkerID extracts the cluster name from a worker ID in the c-<cluster>-fw-<host>-<port> format
+func ClusterNameFromWorkerID(workerID string) string {
+	name := strings.TrimPrefix(workerID, "c-")
+	if idx := strings.LastIndex(name, "-fw-"); idx >= 0 {
+		return name[:idx]
+	}
+	parts := strings.Split(workerID, "-")
+	if len(parts) > 1 {
+		return parts[1]
+	}
+	return ""
+}
+
+// makeObjectName turns the name of a Pulsar component into a valid Kubernetes object name
+func makeObjectName(name string) (string, error) {
+	objectName := strings.Trim(invalidObjectNameChars.ReplaceAllString(strings.ToLower(name), "-"), "-")
+	if objectName == "" {
+		return "", fmt.Errorf("cannot make a valid object name from %s", name)
+	}
+	return objectName, nil
+}
+
+func convertMessaging(options Options) v1alpha1.Messaging {
+	pulsarConfig := options.PulsarConfig
+	if pulsarConfig == "" {
+		pulsarConfig = options.ClusterName
+	}
+	return v1alpha1.Messaging{
+		Pulsar: &v1alpha1.PulsarMessaging{
+			PulsarConfig: pulsarConfig,
+			AuthSecret:   options.AuthSecret,
+			TLSSecret:    options.TLSSecret,
+		},
+	}
+}
+
+// convertSecrets maps the secrets of the Kubernetes secrets provider, which are in the {path, key}
+// format, to secret references. The other secrets are referenced from the stub secret <name>-secrets
+// which needs to be created before the component is applied.
+func convertSecrets(secrets map[string]interface{}, objectName string) map[string]v1alpha1.SecretRef {
+	if len(secrets) == 0 {
+		return nil
+	}
+	refs := make(map[string]v1alpha1.SecretRef, len(secrets))
+	for name, value := range secrets {
+		ref := v1alpha1.SecretRef{Path: objectName + secretNameSuffix, Key: name}
+		if secret, ok := value.(map[string]interface{}); ok {
+			secretPath, pathOK := secret["path"].(string)
+			key, keyOK := secret["key"].(string)
+			if pathOK && keyOK {
+				ref = v1alpha1.SecretRef{Path: secretPath, Key: key}
+			}
+		}
+		refs[name] = ref
+	}
+	return refs
+}
+
+func convertResources(resources *utils.Resources) corev1.ResourceRequirements {
+	if resources == nil {
+		return corev1.ResourceRequirements{}
+	}
+	list := corev1.ResourceList{}
+	if resources.CPU > 0 {
+		list[corev1.ResourceCPU] = *resource.NewMilliQuantity(int64(math.Round(resources.CPU*1000)), resource.DecimalSI)
+	}
+	if resources.RAM > 0 {
+		list[corev1.ResourceMemory] = *resource.NewQuantity(resources.RAM, resource.BinarySI)
+	}
+	if len(list) == 0 {
+		return corev1.ResourceRequirements{}
+	}
+	return corev1.ResourceRequirements{
+		Limits:   list,
+		Requests: list.DeepCopy(),
+	}
+}
+
+func convertProcessingGuarantee(guarantee string) (v1alpha1.ProcessGuarantee, error) {
+	switch strings.ToLower(guarantee) {
+	case "":
+		return "", nil
+	case string(v1alpha1.AtleastOnce):
+		return v1alpha1.AtleastOnce, nil
+	case string(v1alpha1.AtmostOnce):
+		return v1alpha1.AtmostOnce, nil
+	case string(v1alpha1.EffectivelyOnce):
+		return v1alpha1.EffectivelyOnce, nil
+	default:
+		return "", fmt.Errorf("unknown processing guarantee %s", guarantee)
+	}
+}
+
+func convertConfig(configs map[string]interface{}) *v1alpha1.Config {
+	if len(configs) == 0 {
+		return nil
+	}
+	config := v1alpha1.NewConfig(configs)
+	return &config
+}
+
+func convertReplicas(parallelism int) *int32 {
+	replicas := int32(parallelism)
+	if replicas < 1 {
+		replicas = 1
+	}
+	return &replicas
+}
+
+func convertInput(inputs []string, topicsPattern *string, serdes, schemas map[string]string,
+	specs map[string]utils.ConsumerConfig) v1alpha1.InputConf {
+	input := v1alpha1.InputConf{
+		Topics:              inputs,
+		CustomSerdeSources:  serdes,
+		CustomSchemaSources: schemas,
+	}
+	if topicsPattern != nil {
+		input.TopicPattern = *topicsPattern
+	}
+	if len(specs) > 0 {
+		input.SourceSpecs = make(map[string]v1alpha1.ConsumerConfig, len(specs))
+		for topic, spec := range specs {
+			consumerConfig := v1alpha1.ConsumerConfig{
+				SchemaType:     spec.SchemaType,
+				SerdeClassName: spec.SerdeClassName,
+				IsRegexPattern: spec.IsRegexPattern,
+			}
+			if spec.ReceiverQueueSize > 0 {
+				receiverQueueSize := int32(spec.ReceiverQueueSize)
+				consumerConfig.ReceiverQueueSize = &receiverQueueSize
+			}
+			input.SourceSpecs[topic] = consumerConfig
+		}
+		if len(input.Topics) == 0 {
+			for topic := range specs {
+				input.Topics = append(input.Topics, topic)
+			}
+			sort.Strings(input.Topics)
+		}
+	}
+	return input
+}
+
+// convertConnectorArchive translates the archive of a source or sink, builtin connectors are
+// mapped to the connector type and the pulsar-io image of the connector
+func convertConnectorArchive(archive string, options Options) (connectorType, image string, java *v1alpha1.JavaRuntime) {
+	if strings.HasPrefix(archive, builtinPrefix) {
+		connectorType = strings.TrimPrefix(archive, builtinPrefix)
+		jar := fmt.Sprintf("connectors/pulsar-io-%s.nar", connectorType)
+		if options.ConnectorVersion != "" {
+			image = fmt.Sprintf(connectorImage, connectorType, options.ConnectorVersion)
+			jar = fmt.Sprintf("connectors/pulsar-io-%s-%s.nar", connectorType, options.ConnectorVersion)
+		}
+		return connectorType, image, &v1alpha1.JavaRuntime{Jar: jar}
+	}
+	jar, location := convertPackage(archive, ".nar")
+	return "", "", &v1alpha1.JavaRuntime{Jar: jar, JarLocation: location}
+}
+
+// convertPackage translates a package URL into the file name in the pod and the download location,
+// the location is empty when the package is stored by the function worker itself
+func convertPackage(archive, extension string) (file, location string) {
+	switch {
+	case archive == "":
+		return "", ""
+	case hasPackageNamePrefix(archive):
+		name := path.Base(archive)
+		if idx := strings.LastIndex(name, "@"); idx >= 0 {
+			name = name[:idx]
+		}
+		return name + extension, archive
+	case hasHTTPPrefix(archive):
+		file = path.Base(archive)
+		if u, err := url.Parse(archive); err == nil {
+			file = path.Base(u.Path)
+		}
+		return file, archive
+	default:
+		return path.Base(archive), ""
+	}
+}
+
+func hasPackageNamePrefix(archive string) bool {
+	return strings.HasPrefix(archive, packageNameFunctionPrefix) ||
+		strings.HasPrefix(archive, packageNameSinkPrefix) ||
+		strings.HasPrefix(archive, packageNameSourcePrefix)
+}
+
+func hasHTTPPrefix(archive string) bool {
+	return strings.HasPrefix(archive, httpPrefix) || strings.HasPrefix(archive, httpsPrefix)
+}
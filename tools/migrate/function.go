@@ -0,0 +1,139 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package migrate
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/streamnative/function-mesh/api/compute/v1alpha1"
+	"github.com/streamnative/pulsarctl/pkg/pulsar/utils"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ConvertFunction converts the config of a Pulsar function into a Function
+func ConvertFunction(config *utils.FunctionConfig, options Options) (*v1alpha1.Function, error) {
+	if config == nil || config.Name == "" {
+		return nil, errors.New("function name is empty")
+	}
+	objectName, err := makeObjectName(config.Name)
+	if err != nil {
+		return nil, err
+	}
+	processingGuarantee, err := convertProcessingGuarantee(config.ProcessingGuarantees)
+	if err != nil {
+		return nil, err
+	}
+	runtime, err := convertFunctionRuntime(config)
+	if err != nil {
+		return nil, err
+	}
+
+	autoAck := config.AutoAck
+	spec := v1alpha1.FunctionSpec{
+		Name:        config.Name,
+		ClassName:   config.ClassName,
+		Tenant:      config.Tenant,
+		Namespace:   config.Namespace,
+		ClusterName: options.ClusterName,
+		Replicas:    convertReplicas(config.Parallelism),
+		Input: convertInput(config.Inputs, config.TopicsPattern, config.CustomSerdeInputs,
+			config.CustomSchemaInputs, config.InputSpecs),
+		Output: v1alpha1.OutputConf{
+			Topic:              config.Output,
+			SinkSerdeClassName: config.OutputSerdeClassName,
+			SinkSchemaType:     config.OutputSchemaType,
+		},
+		LogTopic:            config.LogTopic,
+		FuncConfig:          convertConfig(config.UserConfig),
+		Resources:           convertResources(config.Resources),
+		SecretsMap:          convertSecrets(config.Secrets, objectName),
+		AutoAck:             &autoAck,
+		ProcessingGuarantee: processingGuarantee,
+		RetainOrdering:      config.RetainOrdering,
+		DeadLetterTopic:     config.DeadLetterTopic,
+		RuntimeFlags:        config.RuntimeFlags,
+		SubscriptionName:    config.SubName,
+		CleanupSubscription: config.CleanupSubscription,
+		WindowConfig:        convertWindowConfig(config.WindowConfig),
+		Messaging:           convertMessaging(options),
+		Runtime:             runtime,
+	}
+	if config.TimeoutMs != nil {
+		spec.Timeout = int32(*config.TimeoutMs)
+	}
+	if config.MaxMessageRetries != nil {
+		spec.MaxMessageRetry = int32(*config.MaxMessageRetries)
+	}
+
+	return &v1alpha1.Function{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: APIVersion,
+			Kind:       "Function",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: options.targetNamespace(config.Tenant, config.Namespace),
+			Name:      objectName,
+		},
+		Spec: spec,
+	}, nil
+}
+
+func convertFunctionRuntime(config *utils.FunctionConfig) (v1alpha1.Runtime, error) {
+	switch {
+	case config.Jar != nil && *config.Jar != "":
+		jar, location := convertPackage(*config.Jar, ".jar")
+		return v1alpha1.Runtime{Java: &v1alpha1.JavaRuntime{Jar: jar, JarLocation: location}}, nil
+	case config.Py != nil && *config.Py != "":
+		py, location := convertPackage(*config.Py, ".py")
+		return v1alpha1.Runtime{Python: &v1alpha1.PythonRuntime{Py: py, PyLocation: location}}, nil
+	case config.Go != nil && *config.Go != "":
+		goFile, location := convertPackage(*config.Go, "")
+		return v1alpha1.Runtime{Golang: &v1alpha1.GoRuntime{Go: goFile, GoLocation: location}}, nil
+	default:
+		return v1alpha1.Runtime{}, fmt.Errorf("function %s has no jar, py or go package", config.Name)
+	}
+}
+
+func convertWindowConfig(config *utils.WindowConfig) *v1alpha1.WindowConfig {
+	if config == nil {
+		return nil
+	}
+	windowConfig := &v1alpha1.WindowConfig{
+		WindowLengthDurationMs:      config.WindowLengthDurationMs,
+		SlidingIntervalDurationMs:   config.SlidingIntervalDurationMs,
+		MaxLagMs:                    config.MaxLagMs,
+		WatermarkEmitIntervalMs:     config.WatermarkEmitIntervalMs,
+		TimestampExtractorClassName: config.TimestampExtractorClassName,
+	}
+	if config.ActualWindowFunctionClassName != nil {
+		windowConfig.ActualWindowFunctionClassName = *config.ActualWindowFunctionClassName
+	}
+	if config.LateDataTopic != nil {
+		windowConfig.LateDataTopic = *config.LateDataTopic
+	}
+	if config.WindowLengthCount != nil {
+		count := int32(*config.WindowLengthCount)
+		windowConfig.WindowLengthCount = &count
+	}
+	if config.SlidingIntervalCount != nil {
+		count := int32(*config.SlidingIntervalCount)
+		windowConfig.SlidingIntervalCount = &count
+	}
+	return windowConfig
+}
@@ -0,0 +1,79 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package migrate
+
+import (
+	"encoding/json"
+	"flag"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/streamnative/pulsarctl/pkg/pulsar/utils"
+	"github.com/stretchr/testify/assert"
+	"sigs.k8s.io/yaml"
+)
+
+var update = flag.Bool("update", false, "update the golden files")
+
+func TestConvertFunctionGolden(t *testing.T) {
+	inputs, err := filepath.Glob(filepath.Join("testdata", "functions", "*.json"))
+	assert.Nil(t, err)
+	assert.NotEmpty(t, inputs)
+
+	for _, input := range inputs {
+		name := strings.TrimSuffix(filepath.Base(input), ".json")
+		t.Run(name, func(t *testing.T) {
+			data, err := ioutil.ReadFile(input)
+			assert.Nil(t, err)
+			config := &utils.FunctionConfig{}
+			assert.Nil(t, json.Unmarshal(data, config))
+
+			function, err := ConvertFunction(config, Options{ClusterName: "pulsar-west", AuthSecret: "pulsar-auth",
+				Namespace: "pulsar-io"})
+			assert.Nil(t, err)
+			actual, err := yaml.Marshal(function)
+			assert.Nil(t, err)
+
+			golden := filepath.Join("testdata", "golden", name+".yaml")
+			if *update {
+				assert.Nil(t, ioutil.WriteFile(golden, actual, 0644))
+			}
+			expected, err := ioutil.ReadFile(golden)
+			assert.Nil(t, err)
+			assert.Equal(t, string(expected), string(actual))
+		})
+	}
+}
+
+func TestConvertFunctionErrors(t *testing.T) {
+	_, err := ConvertFunction(&utils.FunctionConfig{}, Options{})
+	assert.NotNil(t, err)
+
+	_, err = ConvertFunction(&utils.FunctionConfig{Name: "no-package"}, Options{})
+	assert.NotNil(t, err)
+
+	jar := "function.jar"
+	_, err = ConvertFunction(&utils.FunctionConfig{Name: "unknown-guarantee", Jar: &jar,
+		ProcessingGuarantees: "EXACTLY_ONCE"}, Options{})
+	assert.NotNil(t, err)
+
+	_, err = ConvertFunction(&utils.FunctionConfig{Name: "__", Jar: &jar}, Options{})
+	assert.NotNil(t, err)
+}
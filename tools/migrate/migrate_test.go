@@ -0,0 +1,257 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package migrate
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/streamnative/function-mesh/api/compute/v1alpha1"
+	"github.com/streamnative/pulsarctl/pkg/pulsar/common"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+)
+
+// fakeAdminServer serves the admin API responses recorded under testdata/admin, the response of
+// /admin/v3/sinks/public/default is stored in v3/sinks/public/default.json. The other requests
+// than GET are recorded and succeed.
+type fakeAdminServer struct {
+	sync.Mutex
+	requests []string
+}
+
+func newFakeAdminServer(t *testing.T) (*fakeAdminServer, *httptest.Server) {
+	fake := &fakeAdminServer{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			fake.Lock()
+			defer fake.Unlock()
+			fake.requests = append(fake.requests, r.Method+" "+r.URL.Path)
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		file := filepath.Join("testdata", "admin", filepath.FromSlash(strings.TrimPrefix(r.URL.Path, "/admin/"))+".json")
+		http.ServeFile(w, r, file)
+	}))
+	t.Cleanup(server.Close)
+	return fake, server
+}
+
+func newTestClient(t *testing.T) *Client {
+	_, client := newTestClientWithServer(t)
+	return client
+}
+
+func newTestClientWithServer(t *testing.T) (*fakeAdminServer, *Client) {
+	fake, server := newFakeAdminServer(t)
+	client, err := NewClient(&common.Config{WebServiceURL: server.URL})
+	assert.Nil(t, err)
+	return fake, client
+}
+
+func TestClientListComponents(t *testing.T) {
+	client := newTestClient(t)
+
+	namespaces, err := client.Namespaces()
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"public/default"}, namespaces)
+
+	sources, err := client.ListSources("public/default")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"batch-source", "kafka-source"}, sources)
+
+	sinks, err := client.ListSinks("public/default")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"es-sink"}, sinks)
+
+	workerID, err := client.GetSinkWorkerID("public/default", "es-sink")
+	assert.Nil(t, err)
+	assert.Equal(t, "pulsar-west", ClusterNameFromWorkerID(workerID))
+
+	_, err = client.GetSource("public/default", "missing-source")
+	assert.NotNil(t, err)
+}
+
+func TestConvertBuiltinSource(t *testing.T) {
+	config, err := newTestClient(t).GetSource("public/default", "kafka-source")
+	assert.Nil(t, err)
+
+	source, err := ConvertSource(config, Options{ClusterName: "pulsar-west", ConnectorVersion: "2.10.1.0",
+		Namespace: "pulsar-io"})
+	assert.Nil(t, err)
+	assert.Equal(t, "Source", source.Kind)
+	assert.Equal(t, "pulsar-io", source.Namespace)
+	assert.Equal(t, "kafka-source", source.Name)
+
+	spec := source.Spec
+	assert.Equal(t, "kafka", spec.SourceType)
+	assert.Equal(t, "pulsar-west", spec.ClusterName)
+	assert.Equal(t, "streamnative/pulsar-io-kafka:2.10.1.0", spec.Image)
+	assert.Equal(t, "connectors/pulsar-io-kafka-2.10.1.0.nar", spec.Java.Jar)
+	assert.Equal(t, "", spec.Java.JarLocation)
+	assert.Equal(t, int32(2), *spec.Replicas)
+	assert.Nil(t, spec.MaxReplicas)
+	assert.Equal(t, "persistent://public/default/kafka-events", spec.Output.Topic)
+	assert.Equal(t, "bytes", spec.Output.SinkSchemaType)
+	assert.Equal(t, v1alpha1.AtleastOnce, spec.ProcessingGuarantee)
+	assert.Equal(t, "kafka:9092", spec.SourceConfig.Data["bootstrapServers"])
+	assert.Nil(t, spec.BatchSourceConfig)
+
+	cpu := spec.Resources.Requests.Cpu()
+	assert.True(t, cpu.Equal(resource.MustParse("500m")), cpu.String())
+	memory := spec.Resources.Limits.Memory()
+	assert.True(t, memory.Equal(resource.MustParse("1Gi")), memory.String())
+}
+
+func TestConvertBatchSource(t *testing.T) {
+	config, err := newTestClient(t).GetSource("public/default", "batch-source")
+	assert.Nil(t, err)
+
+	source, err := ConvertSource(config, Options{})
+	assert.Nil(t, err)
+
+	spec := source.Spec
+	assert.Equal(t, "", spec.SourceType)
+	assert.Equal(t, "", spec.Image)
+	assert.Equal(t, "batch-source.nar", spec.Java.Jar)
+	assert.Equal(t, "source://public/default/batch-source@v1", spec.Java.JarLocation)
+	assert.Equal(t, v1alpha1.AtmostOnce, spec.ProcessingGuarantee)
+	assert.NotNil(t, spec.BatchSourceConfig)
+	assert.Equal(t, "org.apache.pulsar.io.batchdiscovery.CronTriggerer",
+		spec.BatchSourceConfig.DiscoveryTriggererClassName)
+	assert.Equal(t, "0 0/5 * * * ?", spec.BatchSourceConfig.DiscoveryTriggererConfig.Data["__CRON__"])
+
+	memory := spec.Resources.Requests.Memory()
+	assert.True(t, memory.Equal(resource.MustParse("512Mi")), memory.String())
+}
+
+func TestConvertSink(t *testing.T) {
+	config, err := newTestClient(t).GetSink("public/default", "es-sink")
+	assert.Nil(t, err)
+
+	sink, err := ConvertSink(config, Options{ClusterName: "pulsar-west",
+		NamespaceMapping: map[string]string{"public/default": "pulsar-io"}})
+	assert.Nil(t, err)
+	assert.Equal(t, "Sink", sink.Kind)
+	assert.Equal(t, "pulsar-io", sink.Namespace)
+	assert.Equal(t, "es-sink", sink.Name)
+
+	spec := sink.Spec
+	assert.Equal(t, "elastic-search", spec.SinkType)
+	assert.Equal(t, "connectors/pulsar-io-elastic-search.nar", spec.Java.Jar)
+	assert.Equal(t, int32(3), *spec.Replicas)
+	assert.Equal(t, []string{"persistent://public/default/events"}, spec.Input.Topics)
+	consumerConfig := spec.Input.SourceSpecs["persistent://public/default/events"]
+	assert.Equal(t, "json", consumerConfig.SchemaType)
+	assert.Equal(t, int32(500), *consumerConfig.ReceiverQueueSize)
+	assert.Equal(t, "es-sink-sub", spec.SubscriptionName)
+	assert.Equal(t, v1alpha1.Earliest, spec.SubscriptionPosition)
+	assert.Equal(t, v1alpha1.EffectivelyOnce, spec.ProcessingGuarantee)
+	assert.Equal(t, int32(30000), spec.Timeout)
+	assert.True(t, *spec.AutoAck)
+	assert.True(t, spec.RetainOrdering)
+	assert.True(t, spec.CleanupSubscription)
+	assert.Equal(t, "events", spec.SinkConfig.Data["indexName"])
+
+	cpu := spec.Resources.Limits.Cpu()
+	assert.True(t, cpu.Equal(resource.MustParse("2")), cpu.String())
+}
+
+func TestConvertPackage(t *testing.T) {
+	file, location := convertPackage("function://public/default/word-count@v2", ".jar")
+	assert.Equal(t, "word-count.jar", file)
+	assert.Equal(t, "function://public/default/word-count@v2", location)
+
+	file, location = convertPackage("https://repo.example.com/functions/word-count.jar?token=abc", ".jar")
+	assert.Equal(t, "word-count.jar", file)
+	assert.Equal(t, "https://repo.example.com/functions/word-count.jar?token=abc", location)
+
+	file, location = convertPackage("/tmp/word-count.jar", ".jar")
+	assert.Equal(t, "word-count.jar", file)
+	assert.Equal(t, "", location)
+}
+
+// memoryWriter keeps the written resources in memory
+type memoryWriter struct {
+	objects map[string]client.Object
+}
+
+func (w *memoryWriter) Write(namespace string, obj client.Object) error {
+	w.objects[obj.GetObjectKind().GroupVersionKind().Kind+"/"+namespace+"/"+obj.GetName()] = obj
+	return nil
+}
+
+func TestMigratorStopsOnError(t *testing.T) {
+	writer := &memoryWriter{objects: map[string]client.Object{}}
+	migrator := NewMigrator(newTestClient(t), writer, Options{ClusterName: "pulsar-west"})
+
+	report, err := migrator.Run()
+	assert.NotNil(t, err)
+	assert.Len(t, report.Migrated, 1)
+	assert.Len(t, report.Failed, 1)
+	assert.Equal(t, "broken", report.Failed[0].Name)
+	assert.Len(t, writer.objects, 1)
+}
+
+func TestMigratorContinueOnError(t *testing.T) {
+	writer := &memoryWriter{objects: map[string]client.Object{}}
+	migrator := NewMigrator(newTestClient(t), writer, Options{ClusterName: "pulsar-west"})
+	migrator.ContinueOnError = true
+
+	report, err := migrator.Run()
+	assert.Nil(t, err)
+	assert.Len(t, report.Migrated, 4)
+	assert.Len(t, report.Failed, 1)
+	assert.Equal(t, KindFunction, report.Failed[0].Kind)
+	assert.Equal(t, "broken", report.Failed[0].Name)
+	// the components without running instances are not migrated
+	assert.Equal(t, []Item{{Kind: KindFunction, Namespace: "public/default", Name: "idle", Err: errNotRunning}},
+		report.Skipped)
+
+	for _, key := range []string{"Function/public/default/word-count", "Source/public/default/kafka-source",
+		"Source/public/default/batch-source", "Sink/public/default/es-sink"} {
+		assert.Contains(t, writer.objects, key)
+	}
+	function := writer.objects["Function/public/default/word-count"].(*v1alpha1.Function)
+	assert.Equal(t, "pulsar-west", function.Spec.Pulsar.PulsarConfig)
+	assert.Equal(t, "default", function.Namespace)
+}
+
+func TestFileWriter(t *testing.T) {
+	config, err := newTestClient(t).GetSink("public/default", "es-sink")
+	assert.Nil(t, err)
+	sink, err := ConvertSink(config, Options{ClusterName: "pulsar-west"})
+	assert.Nil(t, err)
+
+	writer := &FileWriter{Dir: t.TempDir()}
+	assert.Nil(t, writer.Write("public/default", sink))
+
+	data, err := ioutil.ReadFile(filepath.Join(writer.Dir, "sinks", "public", "default", "es-sink.yaml"))
+	assert.Nil(t, err)
+	actual := &v1alpha1.Sink{}
+	assert.Nil(t, yaml.Unmarshal(data, actual))
+	assert.Equal(t, sink.Spec.SinkConfig.Data, actual.Spec.SinkConfig.Data)
+	assert.Equal(t, "Sink", actual.Kind)
+}
@@ -0,0 +1,222 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package migrate
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	KindFunction = "Function"
+	KindSource   = "Source"
+	KindSink     = "Sink"
+)
+
+// errNotRunning is returned for the components without instances in the function worker, they are skipped
+var errNotRunning = errors.New("no running instances")
+
+// Writer stores the converted resources of a tenant/namespace
+type Writer interface {
+	Write(namespace string, obj client.Object) error
+}
+
+// FileWriter writes the resources to <dir>/<kind>s/<tenant>/<namespace>/<name>.yaml
+type FileWriter struct {
+	Dir string
+}
+
+// Write implements Writer
+func (w *FileWriter) Write(namespace string, obj client.Object) error {
+	data, err := yaml.Marshal(obj)
+	if err != nil {
+		return err
+	}
+	dir := filepath.Join(w.Dir, strings.ToLower(obj.GetObjectKind().GroupVersionKind().Kind)+"s",
+		filepath.FromSlash(namespace))
+	if err = os.MkdirAll(dir, os.ModePerm); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, obj.GetName()+".yaml"), data, 0644)
+}
+
+// Item is a function, source or sink of the migration
+type Item struct {
+	Kind      string
+	Namespace string
+	Name      string
+	Err       error
+}
+
+func (i Item) String() string {
+	if i.Name == "" {
+		return fmt.Sprintf("%ss of %s", strings.ToLower(i.Kind), i.Namespace)
+	}
+	return fmt.Sprintf("%s %s/%s", strings.ToLower(i.Kind), i.Namespace, i.Name)
+}
+
+// Report is the result of a migration
+type Report struct {
+	Migrated []Item
+	Failed   []Item
+	// Skipped are the components without running instances
+	Skipped []Item
+}
+
+// Print writes a summary of the report
+func (r *Report) Print(w io.Writer) {
+	_, _ = fmt.Fprintf(w, "Migrated %d components, %d failed, %d skipped\n", len(r.Migrated), len(r.Failed),
+		len(r.Skipped))
+	for _, item := range r.Failed {
+		_, _ = fmt.Fprintf(w, "  %s: %v\n", item, item.Err)
+	}
+	for _, item := range r.Skipped {
+		_, _ = fmt.Fprintf(w, "  %s: skipped, %v\n", item, item.Err)
+	}
+}
+
+// Migrator converts all the functions, sources and sinks of a cluster and hands them to a Writer
+type Migrator struct {
+	client  *Client
+	writer  Writer
+	options Options
+
+	// ContinueOnError records the failed components in the report and moves on to the next one,
+	// the migration stops at the first failure otherwise
+	ContinueOnError bool
+}
+
+// NewMigrator creates a Migrator
+func NewMigrator(client *Client, writer Writer, options Options) *Migrator {
+	return &Migrator{client: client, writer: writer, options: options}
+}
+
+// Run migrates the components of all the namespaces
+func (m *Migrator) Run() (*Report, error) {
+	report := &Report{}
+	namespaces, err := m.client.Namespaces()
+	if err != nil {
+		return report, err
+	}
+	for _, namespace := range namespaces {
+		for _, kind := range []string{KindFunction, KindSource, KindSink} {
+			names, err := m.list(kind, namespace)
+			if err != nil {
+				if err = m.fail(report, Item{Kind: kind, Namespace: namespace, Err: err}); err != nil {
+					return report, err
+				}
+				continue
+			}
+			for _, name := range names {
+				item := Item{Kind: kind, Namespace: namespace, Name: name}
+				obj, err := m.convert(kind, namespace, name)
+				if errors.Is(err, errNotRunning) {
+					item.Err = err
+					report.Skipped = append(report.Skipped, item)
+					continue
+				}
+				if err == nil {
+					err = m.writer.Write(namespace, obj)
+				}
+				if err != nil {
+					item.Err = err
+					if err = m.fail(report, item); err != nil {
+						return report, err
+					}
+					continue
+				}
+				report.Migrated = append(report.Migrated, item)
+			}
+		}
+	}
+	return report, nil
+}
+
+func (m *Migrator) fail(report *Report, item Item) error {
+	report.Failed = append(report.Failed, item)
+	if m.ContinueOnError {
+		return nil
+	}
+	return fmt.Errorf("migrate %s: %w", item, item.Err)
+}
+
+func (m *Migrator) list(kind, namespace string) ([]string, error) {
+	switch kind {
+	case KindFunction:
+		return m.client.ListFunctions(namespace)
+	case KindSource:
+		return m.client.ListSources(namespace)
+	default:
+		return m.client.ListSinks(namespace)
+	}
+}
+
+func (m *Migrator) convert(kind, namespace, name string) (client.Object, error) {
+	workerID, err := m.workerID(kind, namespace, name)
+	if err != nil {
+		return nil, err
+	}
+	if workerID == "" {
+		return nil, errNotRunning
+	}
+	options := m.options
+	if options.ClusterName == "" {
+		options.ClusterName = ClusterNameFromWorkerID(workerID)
+		if options.ClusterName == "" {
+			return nil, fmt.Errorf("cannot determine the cluster name from the worker ID %q", workerID)
+		}
+	}
+	switch kind {
+	case KindFunction:
+		config, err := m.client.GetFunction(namespace, name)
+		if err != nil {
+			return nil, err
+		}
+		return ConvertFunction(config, options)
+	case KindSource:
+		config, err := m.client.GetSource(namespace, name)
+		if err != nil {
+			return nil, err
+		}
+		return ConvertSource(config, options)
+	default:
+		config, err := m.client.GetSink(namespace, name)
+		if err != nil {
+			return nil, err
+		}
+		return ConvertSink(config, options)
+	}
+}
+
+func (m *Migrator) workerID(kind, namespace, name string) (string, error) {
+	switch kind {
+	case KindFunction:
+		return m.client.GetFunctionWorkerID(namespace, name)
+	case KindSource:
+		return m.client.GetSourceWorkerID(namespace, name)
+	default:
+		return m.client.GetSinkWorkerID(namespace, name)
+	}
+}
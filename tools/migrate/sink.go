@@ -0,0 +1,102 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package migrate
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/streamnative/function-mesh/api/compute/v1alpha1"
+	"github.com/streamnative/pulsarctl/pkg/pulsar/utils"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ConvertSink converts the config of a Pulsar sink into a Sink
+func ConvertSink(config *utils.SinkConfig, options Options) (*v1alpha1.Sink, error) {
+	if config == nil || config.Name == "" {
+		return nil, errors.New("sink name is empty")
+	}
+	objectName, err := makeObjectName(config.Name)
+	if err != nil {
+		return nil, err
+	}
+	processingGuarantee, err := convertProcessingGuarantee(config.ProcessingGuarantees)
+	if err != nil {
+		return nil, err
+	}
+	subscriptionPosition, err := convertSubscriptionPosition(config.SourceSubscriptionPosition)
+	if err != nil {
+		return nil, err
+	}
+	sinkType, image, java := convertConnectorArchive(config.Archive, options)
+
+	autoAck := config.AutoAck
+	spec := v1alpha1.SinkSpec{
+		Name:        config.Name,
+		ClassName:   config.ClassName,
+		ClusterName: options.ClusterName,
+		Tenant:      config.Tenant,
+		Namespace:   config.Namespace,
+		SinkType:    sinkType,
+		Replicas:    convertReplicas(config.Parallelism),
+		Input: convertInput(config.Inputs, config.TopicsPattern, config.TopicToSerdeClassName,
+			config.TopicToSchemaType, config.InputSpecs),
+		SinkConfig:           convertConfig(config.Configs),
+		SecretsMap:           convertSecrets(config.Secrets, objectName),
+		Resources:            convertResources(config.Resources),
+		AutoAck:              &autoAck,
+		ProcessingGuarantee:  processingGuarantee,
+		RetainOrdering:       config.RetainOrdering,
+		RuntimeFlags:         config.RuntimeFlags,
+		SubscriptionName:     config.SourceSubscriptionName,
+		CleanupSubscription:  config.CleanupSubscription,
+		SubscriptionPosition: subscriptionPosition,
+		Messaging:            convertMessaging(options),
+		Runtime:              v1alpha1.Runtime{Java: java},
+		Image:                image,
+	}
+	if config.TimeoutMs != nil {
+		spec.Timeout = int32(*config.TimeoutMs)
+	}
+
+	return &v1alpha1.Sink{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: APIVersion,
+			Kind:       "Sink",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: options.targetNamespace(config.Tenant, config.Namespace),
+			Name:      objectName,
+		},
+		Spec: spec,
+	}, nil
+}
+
+func convertSubscriptionPosition(position string) (v1alpha1.SubscribePosition, error) {
+	switch strings.ToLower(position) {
+	case "":
+		return "", nil
+	case string(v1alpha1.Latest):
+		return v1alpha1.Latest, nil
+	case string(v1alpha1.Earliest):
+		return v1alpha1.Earliest, nil
+	default:
+		return "", fmt.Errorf("unknown subscription position %s", position)
+	}
+}
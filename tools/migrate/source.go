@@ -0,0 +1,96 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package migrate
+
+import (
+	"errors"
+
+	"github.com/streamnative/function-mesh/api/compute/v1alpha1"
+	"github.com/streamnative/pulsarctl/pkg/pulsar/utils"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// SourceConfig is the source config of the function worker, the batch source settings are not
+// modeled by the pulsarctl source config
+type SourceConfig struct {
+	utils.SourceConfig
+	BatchSourceConfig *BatchSourceConfig `json:"batchSourceConfig,omitempty"`
+}
+
+// BatchSourceConfig is the batch source config of the function worker
+type BatchSourceConfig struct {
+	DiscoveryTriggererClassName string                 `json:"discoveryTriggererClassName,omitempty"`
+	DiscoveryTriggererConfig    map[string]interface{} `json:"discoveryTriggererConfig,omitempty"`
+}
+
+// ConvertSource converts the config of a Pulsar source into a Source
+func ConvertSource(config *SourceConfig, options Options) (*v1alpha1.Source, error) {
+	if config == nil || config.Name == "" {
+		return nil, errors.New("source name is empty")
+	}
+	objectName, err := makeObjectName(config.Name)
+	if err != nil {
+		return nil, err
+	}
+	processingGuarantee, err := convertProcessingGuarantee(config.ProcessingGuarantees)
+	if err != nil {
+		return nil, err
+	}
+	sourceType, image, java := convertConnectorArchive(config.Archive, options)
+
+	spec := v1alpha1.SourceSpec{
+		Name:        config.Name,
+		ClassName:   config.ClassName,
+		Tenant:      config.Tenant,
+		Namespace:   config.Namespace,
+		ClusterName: options.ClusterName,
+		SourceType:  sourceType,
+		Replicas:    convertReplicas(config.Parallelism),
+		Output: v1alpha1.OutputConf{
+			Topic:              config.TopicName,
+			SinkSerdeClassName: config.SerdeClassName,
+			SinkSchemaType:     config.SchemaType,
+		},
+		SourceConfig:        convertConfig(config.Configs),
+		SecretsMap:          convertSecrets(config.Secrets, objectName),
+		Resources:           convertResources(config.Resources),
+		ProcessingGuarantee: processingGuarantee,
+		RuntimeFlags:        config.RuntimeFlags,
+		Messaging:           convertMessaging(options),
+		Runtime:             v1alpha1.Runtime{Java: java},
+		Image:               image,
+	}
+	if config.BatchSourceConfig != nil {
+		spec.BatchSourceConfig = &v1alpha1.BatchSourceConfig{
+			DiscoveryTriggererClassName: config.BatchSourceConfig.DiscoveryTriggererClassName,
+			DiscoveryTriggererConfig:    convertConfig(config.BatchSourceConfig.DiscoveryTriggererConfig),
+		}
+	}
+
+	return &v1alpha1.Source{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: APIVersion,
+			Kind:       "Source",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: options.targetNamespace(config.Tenant, config.Namespace),
+			Name:      objectName,
+		},
+		Spec: spec,
+	}, nil
+}
@@ -18,221 +18,103 @@
 package main
 
 import (
-	"encoding/json"
+	"context"
+	"flag"
 	"fmt"
 	"os"
-	"strings"
 
-	"github.com/streamnative/function-mesh/api/compute/v1alpha1"
+	"github.com/streamnative/function-mesh/api/generated/clientset/versioned"
+	"github.com/streamnative/function-mesh/tools/migrate"
 	cmdutils "github.com/streamnative/pulsarctl/pkg/cmdutils"
 	"github.com/streamnative/pulsarctl/pkg/pulsar/common"
-	corev1 "k8s.io/api/core/v1"
-	"k8s.io/apimachinery/pkg/api/resource"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"sigs.k8s.io/yaml"
+	"sigs.k8s.io/controller-runtime/pkg/client/config"
 )
 
 func main() {
-	admin := cmdutils.NewPulsarClient()
-	functionAdmin := cmdutils.NewPulsarClientWithAPIVersion(common.V3)
-	tenants, err := admin.Tenants().List()
+	if len(os.Args) > 1 && os.Args[1] == "export" {
+		runExport(os.Args[2:])
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "logs" {
+		runLogs(os.Args[2:])
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "state" {
+		runState(os.Args[2:])
+		return
+	}
+
+	var options migrate.Options
+	var outputDir string
+	var continueOnError bool
+	var apply bool
+	var namespaceMapping string
+	var applyOptions migrate.ApplyOptions
+	flag.StringVar(&options.ClusterName, "cluster-name", "",
+		"The name of the Pulsar cluster, derived from the worker ID of the instances if not specified")
+	flag.StringVar(&options.ConnectorVersion, "connector-version", "",
+		"The version of the pulsar-io images used by the builtin connectors")
+	flag.StringVar(&options.PulsarConfig, "pulsar-config", "",
+		"The config map with the service URLs of the Pulsar cluster, defaults to the cluster name")
+	flag.StringVar(&options.AuthSecret, "auth-secret", "",
+		"The secret with the client authentication plugin and parameters")
+	flag.StringVar(&options.TLSSecret, "tls-secret", "", "The secret with the TLS settings of the client")
+	flag.StringVar(&outputDir, "output-dir", ".", "The directory the YAML files are written to")
+	flag.BoolVar(&continueOnError, "continue-on-error", false,
+		"Keep migrating the other components when a component fails and report the failures at the end")
+	flag.BoolVar(&apply, "apply", false,
+		"Create or update the resources in the Kubernetes cluster instead of writing the YAML files")
+	flag.BoolVar(&applyOptions.DryRun, "dry-run", false,
+		"Print the difference with the resources in the Kubernetes cluster without applying them, implies --apply")
+	flag.StringVar(&options.Namespace, "namespace", "default",
+		"The Kubernetes namespace of the Pulsar namespaces missing from --namespace-mapping")
+	flag.StringVar(&namespaceMapping, "namespace-mapping", "",
+		"The Kubernetes namespaces of the Pulsar namespaces, in the tenant/namespace=namespace,... format")
+	flag.BoolVar(&applyOptions.StopOriginal, "stop-original", false,
+		"Stop the components in the function worker once the Function Mesh copies are ready")
+	flag.DurationVar(&applyOptions.ReadyTimeout, "ready-timeout", migrate.DefaultReadyTimeout,
+		"How long to wait for the Function Mesh copies to be ready before stopping the originals")
+	flag.Parse()
+
+	mapping, err := migrate.ParseNamespaceMapping(namespaceMapping)
+	if err != nil {
+		fmt.Printf("Parse namespace mapping failed, err %v\n", err)
+		os.Exit(1)
+	}
+	options.NamespaceMapping = mapping
+
+	pulsarConfig := common.Config(*cmdutils.PulsarCtlConfig)
+	client, err := migrate.NewClient(&pulsarConfig)
 	if err != nil {
-		fmt.Printf("List tenant failed from service %s\n", cmdutils.PulsarCtlConfig.WebServiceURL)
+		fmt.Printf("Create client failed for service %s, err %v\n", cmdutils.PulsarCtlConfig.WebServiceURL, err)
 		os.Exit(1)
 	}
-	for _, tenant := range tenants {
-		namespaces, err := admin.Namespaces().GetNamespaces(tenant)
+
+	var writer migrate.Writer = &migrate.FileWriter{Dir: outputDir}
+	if apply || applyOptions.DryRun {
+		restConfig, err := config.GetConfig()
 		if err != nil {
-			fmt.Printf("List namespace failed from tenant %s service %s, error %v",
-				tenant, cmdutils.PulsarCtlConfig.WebServiceURL, err)
+			fmt.Printf("Load kubeconfig failed, err %v\n", err)
 			os.Exit(1)
 		}
-		for _, namespace := range namespaces {
-			tenantNamespace := strings.Split(namespace, "/")
-			functions, err := functionAdmin.Functions().GetFunctions(tenantNamespace[0], tenantNamespace[1])
-			if err != nil {
-				fmt.Printf("List functions failed from tenant %s namespace %s service %s, err %v",
-					tenant, namespace, cmdutils.PulsarCtlConfig.WebServiceURL, err)
-				os.Exit(1)
-			}
-			for _, function := range functions {
-				functionConfig, err := functionAdmin.Functions().GetFunction(
-					tenantNamespace[0], tenantNamespace[1], function)
-				if err != nil {
-					fmt.Printf("Get function %s config failed from tenant %s namespace %s service %s err %v",
-						function, tenant, namespace, cmdutils.PulsarCtlConfig.WebServiceURL, err)
-					os.Exit(1)
-				}
-				functionStatus, err := functionAdmin.Functions().GetFunctionStatus(
-					tenantNamespace[0], tenantNamespace[1], function)
-				if err != nil {
-					fmt.Printf("Get function %s status failed from tenant %s namespace %s service %s err %v",
-						function, tenant, namespace, cmdutils.PulsarCtlConfig.WebServiceURL, err)
-					os.Exit(1)
-				}
-				if len(functionStatus.Instances) > 0 {
-					workerID := functionStatus.Instances[0].Status.WorkerID
-					workerIDList := strings.Split(workerID, "-")
-					pulsarCluster := workerIDList[1]
-					replicas := int32(functionConfig.Parallelism)
-					sourceSpecs := make(map[string]v1alpha1.ConsumerConfig)
-					for s := range functionConfig.InputSpecs {
-						receiveQueueSize := int32(functionConfig.InputSpecs[s].ReceiverQueueSize)
-						sourceSpecs[s] = v1alpha1.ConsumerConfig{
-							SchemaType:        functionConfig.InputSpecs[s].SchemaType,
-							SerdeClassName:    functionConfig.InputSpecs[s].SerdeClassName,
-							IsRegexPattern:    functionConfig.InputSpecs[s].IsRegexPattern,
-							ReceiverQueueSize: &receiveQueueSize,
-						}
-					}
-					timeoutMs := int32(0)
-					if functionConfig.TimeoutMs != nil {
-						timeoutMs = int32(*functionConfig.TimeoutMs)
-					}
-					topicPattern := ""
-					if functionConfig.TopicsPattern != nil {
-						topicPattern = *functionConfig.TopicsPattern
-					}
-					topics := functionConfig.Inputs
-					if topics == nil {
-						topics = make([]string, 0, len(functionConfig.InputSpecs))
-						for k := range functionConfig.InputSpecs {
-							topics = append(topics, k)
-						}
-					}
-					funcConfig := make(map[string]interface{})
-					for key, value := range functionConfig.UserConfig {
-						strKey := fmt.Sprintf("%v", key)
-						strValue := fmt.Sprintf("%v", value)
-						funcConfig[strKey] = strValue
-					}
-					funcConfigData := v1alpha1.NewConfig(funcConfig)
-					maxMessageRetry := int32(0)
-					if functionConfig.MaxMessageRetries != nil {
-						maxMessageRetry = int32(*functionConfig.MaxMessageRetries)
-					}
-					functionSpec := v1alpha1.FunctionSpec{
-						Name:                functionConfig.Name,
-						ClassName:           functionConfig.ClassName,
-						Tenant:              functionConfig.Tenant,
-						ClusterName:         pulsarCluster,
-						AutoAck:             &functionConfig.AutoAck,
-						CleanupSubscription: functionConfig.CleanupSubscription,
-						RetainOrdering:      functionConfig.RetainOrdering,
-						// Need to be added in pulsarctl
-						// https://github.com/streamnative/pulsarctl/blob/master/pkg/pulsar/utils/function_confg.go
-						// RetainKeyOrdering:   functionConfig.RetainKeyOrdering,
-						Replicas: &replicas,
-						Input: v1alpha1.InputConf{
-							Topics:              topics,
-							TopicPattern:        topicPattern,
-							CustomSerdeSources:  functionConfig.CustomSerdeInputs,
-							CustomSchemaSources: functionConfig.CustomSchemaInputs,
-							SourceSpecs:         sourceSpecs,
-						},
-						MaxReplicas: &replicas,
-						Timeout:     timeoutMs,
-						Output: v1alpha1.OutputConf{
-							Topic:              functionConfig.Output,
-							SinkSerdeClassName: functionConfig.OutputSerdeClassName,
-							SinkSchemaType:     functionConfig.OutputSchemaType,
-							// Need to be added in pulsarctl
-							// https://github.com/streamnative/pulsarctl/blob/master/pkg/pulsar/utils/function_confg.go
-							// CustomSchemaSinks:  functionConfig.CustomSchemaOutputs,
-						},
-						DeadLetterTopic: functionConfig.DeadLetterTopic,
-						Resources: corev1.ResourceRequirements{
-							Limits: corev1.ResourceList{
-								corev1.ResourceCPU:    resource.MustParse(fmt.Sprintf("%f", functionConfig.Resources.CPU)),
-								corev1.ResourceMemory: resource.MustParse(fmt.Sprintf("%d", functionConfig.Resources.RAM/1024/1024/1024)),
-							},
-							Requests: corev1.ResourceList{
-								corev1.ResourceCPU:    resource.MustParse(fmt.Sprintf("%f", functionConfig.Resources.CPU)),
-								corev1.ResourceMemory: resource.MustParse(fmt.Sprintf("%d", functionConfig.Resources.RAM/1024/1024/1024)),
-							},
-						},
-						FuncConfig:      &funcConfigData,
-						MaxMessageRetry: maxMessageRetry,
-					}
-					if functionConfig.ProcessingGuarantees != "" {
-						switch strings.ToLower(functionConfig.ProcessingGuarantees) {
-						case "atleast_once":
-							functionSpec.ProcessingGuarantee = v1alpha1.AtleastOnce
-						case "atmost_once":
-							functionSpec.ProcessingGuarantee = v1alpha1.AtmostOnce
-						case "effectively_once":
-							functionSpec.ProcessingGuarantee = v1alpha1.EffectivelyOnce
-						}
-					}
-					if functionConfig.SubName != "" {
-						functionSpec.SubscriptionName = functionConfig.SubName
-					}
-					if functionConfig.RuntimeFlags != "" {
-						functionSpec.RuntimeFlags = functionConfig.RuntimeFlags
-					}
-					if functionConfig.Jar != nil && *functionConfig.Jar != "" {
-						functionSpec.Java.Jar = *functionConfig.Jar
-					}
-					if functionConfig.Py != nil && *functionConfig.Py != "" {
-						functionSpec.Python.Py = *functionConfig.Py
-					}
-					if functionConfig.Go != nil && *functionConfig.Go != "" {
-						functionSpec.Golang.Go = *functionConfig.Go
-					}
-					typeMeta := metav1.TypeMeta{
-						APIVersion: "compute.functionmesh.io/v1alpha1",
-						Kind:       "Function",
-					}
-					objectMeta := metav1.ObjectMeta{
-						Namespace: functionConfig.Namespace,
-						Name:      functionConfig.Name,
-					}
-					functionData := v1alpha1.Function{
-						TypeMeta:   typeMeta,
-						ObjectMeta: objectMeta,
-						Spec:       functionSpec,
-					}
-					if functionConfig.LogTopic != "" {
-						functionSpec.LogTopic = functionConfig.LogTopic
-					}
-					data, err := json.Marshal(&functionData)
-					if err != nil {
-						fmt.Printf("Convert function %s config to json failed"+
-							" from tenant %s namespace %s service %s err %v",
-							function, tenant, namespace, cmdutils.PulsarCtlConfig.WebServiceURL, err)
-						os.Exit(1)
-					}
-					y, err := yaml.JSONToYAML(data)
-					if err != nil {
-						fmt.Printf("Convert function %s config to yaml failed"+
-							" from tenant %s namespace %s service %s err %v",
-							function, tenant, namespace, cmdutils.PulsarCtlConfig.WebServiceURL, err)
-						os.Exit(1)
-					}
-					path := "functions/" + namespace
-					err = os.MkdirAll(path, os.ModePerm)
-					if err != nil {
-						fmt.Printf("Create directory failed for function %s from tenant %s namespace %s service %s err %v",
-							function, tenant, namespace, cmdutils.PulsarCtlConfig.WebServiceURL, err)
-						os.Exit(1)
-					}
-					filePath := path + "/" + function + ".yaml"
-					f, err := os.Create(filePath)
-					if err != nil {
-						fmt.Printf("Create yaml file failed for function %s from tenant %s namespace %s service %s err %v",
-							function, tenant, namespace, cmdutils.PulsarCtlConfig.WebServiceURL, err)
-						os.Exit(1)
-					}
-					_, err = f.WriteString(string(y))
-					if err != nil {
-						fmt.Printf("Write yaml file failed for function %s from tenant %s namespace %s service %s err %v",
-							function, tenant, namespace, cmdutils.PulsarCtlConfig.WebServiceURL, err)
-						os.Exit(1)
-					}
-					f.Sync()
-					f.Close()
-				}
-			}
+		clientset, err := versioned.NewForConfig(restConfig)
+		if err != nil {
+			fmt.Printf("Create Kubernetes client failed, err %v\n", err)
+			os.Exit(1)
 		}
+		applyOptions.Out = os.Stdout
+		writer = migrate.NewApplyWriter(context.Background(), clientset, client, applyOptions)
+	}
+
+	migrator := migrate.NewMigrator(client, writer, options)
+	migrator.ContinueOnError = continueOnError
+	report, err := migrator.Run()
+	report.Print(os.Stdout)
+	if err != nil {
+		fmt.Printf("Migration failed from service %s, err %v\n", cmdutils.PulsarCtlConfig.WebServiceURL, err)
+		os.Exit(1)
+	}
+	if len(report.Failed) > 0 {
+		os.Exit(1)
 	}
 }
@@ -0,0 +1,110 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/streamnative/function-mesh/tools/state"
+	"github.com/streamnative/pulsarctl/pkg/pulsar/common"
+)
+
+// runState exports the state of a function to a snapshot or restores it from one, it is run by the Jobs of
+// the FunctionStateSnapshots and reads its connection settings from the same environment as the instances
+func runState(args []string) {
+	if len(args) == 0 || (args[0] != "export" && args[0] != "restore") {
+		fmt.Fprintf(os.Stderr, "Usage: %s state <export|restore> [flags]\n", os.Args[0])
+		os.Exit(2)
+	}
+	mode := args[0]
+	flags := flag.NewFlagSet("state "+mode, flag.ExitOnError)
+	var function state.Function
+	var storage state.Storage
+	var keys string
+	config := common.Config{PulsarAPIVersion: common.V3}
+	flags.StringVar(&function.Tenant, "tenant", "public", "The tenant of the function")
+	flags.StringVar(&function.Namespace, "namespace", "default", "The namespace of the function")
+	flags.StringVar(&function.Name, "name", "", "The name of the function")
+	flags.StringVar(&keys, "keys", "", "The comma separated keys exported")
+	flags.StringVar(&storage.File, "file", "", "The file the snapshot is written to or read from")
+	flags.StringVar(&storage.ObjectURL, "object-url", os.Getenv("STATE_SNAPSHOT_URL"),
+		"The URL the snapshot is uploaded to or downloaded from, defaults to $STATE_SNAPSHOT_URL")
+	flags.StringVar(&config.WebServiceURL, "state-service-url", os.Getenv("webServiceURL"),
+		"The HTTP URL of the functions state API, defaults to $webServiceURL")
+	flags.StringVar(&config.AuthPlugin, "auth-plugin", os.Getenv("clientAuthenticationPlugin"),
+		"The client authentication plugin, defaults to $clientAuthenticationPlugin")
+	flags.StringVar(&config.AuthParams, "auth-params", os.Getenv("clientAuthenticationParameters"),
+		"The client authentication parameters, defaults to $clientAuthenticationParameters")
+	flags.BoolVar(&config.TLSAllowInsecureConnection, "tls-allow-insecure", false,
+		"Allow insecure TLS connections to the state service")
+	flags.StringVar(&config.TLSTrustCertsFilePath, "tls-trust-cert-path", "",
+		"The trusted TLS certificates of the state service")
+	_ = flags.Parse(args[1:])
+	if storage.File != "" {
+		// the file wins over the object URL of the environment
+		storage.ObjectURL = ""
+	}
+	if function.Name == "" || config.WebServiceURL == "" || (storage.File == "" && storage.ObjectURL == "") {
+		fmt.Fprintln(os.Stderr, "--name, --state-service-url and one of --file or --object-url are required")
+		os.Exit(2)
+	}
+
+	client, err := state.NewClient(&config)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Create client failed for service %s, err %v\n", config.WebServiceURL, err)
+		os.Exit(1)
+	}
+	ctx := context.Background()
+	if mode == "export" {
+		var keyList []string
+		for _, key := range strings.Split(keys, ",") {
+			if key = strings.TrimSpace(key); key != "" {
+				keyList = append(keyList, key)
+			}
+		}
+		snapshot := &bytes.Buffer{}
+		exported, err := state.Export(ctx, client, function, keyList, snapshot)
+		if err == nil {
+			err = storage.Write(ctx, snapshot.Bytes())
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Export the state of %s failed, err %v\n", function, err)
+			os.Exit(1)
+		}
+		fmt.Printf("Exported %d of %d keys of %s\n", exported, len(keyList), function)
+		return
+	}
+
+	snapshot, err := storage.Open(ctx)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Open the snapshot failed, err %v\n", err)
+		os.Exit(1)
+	}
+	defer snapshot.Close()
+	restored, err := state.Restore(ctx, client, function, snapshot)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Restore the state of %s failed after %d keys, err %v\n", function, restored, err)
+		os.Exit(1)
+	}
+	fmt.Printf("Restored %d keys of %s\n", restored, function)
+}
@@ -0,0 +1,236 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// Package state exports the state of the Pulsar Functions through the functions state API and restores it
+package state
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/streamnative/pulsarctl/pkg/auth"
+	"github.com/streamnative/pulsarctl/pkg/pulsar/common"
+)
+
+// DefaultTimeout bounds each request to the state service
+const DefaultTimeout = 30 * time.Second
+
+// ErrFunctionNotFound is returned when the state service does not know the function. The state API only
+// serves the functions registered with the function worker behind it, so an export must not read the keys
+// of an unknown function as missing and write an empty snapshot.
+var ErrFunctionNotFound = errors.New("function not found by the state service")
+
+// Function identifies the state table of a function
+type Function struct {
+	Tenant    string
+	Namespace string
+	Name      string
+}
+
+func (f Function) String() string {
+	return f.Tenant + "/" + f.Namespace + "/" + f.Name
+}
+
+// State is the value of a key, exactly one of the values is set. It differs from the FunctionState of
+// pulsarctl, which always sends a number value and so turns every put into a counter increment.
+type State struct {
+	Key         string  `json:"key"`
+	StringValue *string `json:"stringValue,omitempty"`
+	ByteValue   []byte  `json:"byteValue,omitempty"`
+	NumberValue *int64  `json:"numberValue,omitempty"`
+}
+
+// Client reads and writes the state of the functions through the functions state API
+type Client struct {
+	// URL is the web service URL of the state service, such as http://pulsar-broker:8080
+	URL        string
+	HTTPClient *http.Client
+}
+
+// NewClient creates a client of the state service with the auth and TLS settings of the config
+func NewClient(config *common.Config) (*Client, error) {
+	provider, err := auth.GetAuthProvider(config)
+	if err != nil {
+		return nil, err
+	}
+	var transport http.RoundTripper = provider
+	if provider == nil || provider.Transport() == nil {
+		if transport, err = auth.NewDefaultTransport(config); err != nil {
+			return nil, err
+		}
+	}
+	return &Client{
+		URL:        config.WebServiceURL,
+		HTTPClient: &http.Client{Transport: transport, Timeout: DefaultTimeout},
+	}, nil
+}
+
+func (c *Client) functionEndpoint(function Function) string {
+	return fmt.Sprintf("%s/admin/v3/functions/%s/%s/%s", strings.TrimRight(c.URL, "/"),
+		url.PathEscape(function.Tenant), url.PathEscape(function.Namespace), url.PathEscape(function.Name))
+}
+
+func (c *Client) endpoint(function Function, key string) string {
+	return c.functionEndpoint(function) + "/state/" + url.PathEscape(key)
+}
+
+// CheckFunction returns ErrFunctionNotFound if the state service does not know the function
+func (c *Client) CheckFunction(ctx context.Context, function Function) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.functionEndpoint(function), nil)
+	if err != nil {
+		return err
+	}
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return fmt.Errorf("%s: %w", function, ErrFunctionNotFound)
+	}
+	if err := checkResponse(resp); err != nil {
+		return fmt.Errorf("failed to get the function %s: %w", function, err)
+	}
+	return nil
+}
+
+// Get returns the state of a key, or nil if the key does not exist
+func (c *Client) Get(ctx context.Context, function Function, key string) (*State, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.endpoint(function, key), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if err := checkResponse(resp); err != nil {
+		return nil, fmt.Errorf("failed to get the state of key %s: %w", key, err)
+	}
+	state := &State{}
+	if err := json.NewDecoder(resp.Body).Decode(state); err != nil {
+		return nil, fmt.Errorf("failed to decode the state of key %s: %w", key, err)
+	}
+	return state, nil
+}
+
+// Put writes the state of a key
+func (c *Client) Put(ctx context.Context, function Function, state *State) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	if err := writer.WriteField("state", string(data)); err != nil {
+		return err
+	}
+	if err := writer.Close(); err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpoint(function, state.Key), body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if err := checkResponse(resp); err != nil {
+		return fmt.Errorf("failed to put the state of key %s: %w", state.Key, err)
+	}
+	return nil
+}
+
+func checkResponse(resp *http.Response) error {
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+	body, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 1024))
+	return fmt.Errorf("%s: %s", resp.Status, strings.TrimSpace(string(body)))
+}
+
+// Export writes the state of the keys to the snapshot, one JSON state per line, the keys missing from the
+// state table are skipped. It fails if the state service does not know the function. It returns the number
+// of exported keys.
+func Export(ctx context.Context, client *Client, function Function, keys []string, w io.Writer) (int, error) {
+	if err := client.CheckFunction(ctx, function); err != nil {
+		return 0, err
+	}
+	encoder := json.NewEncoder(w)
+	exported := 0
+	for _, key := range keys {
+		state, err := client.Get(ctx, function, key)
+		if err != nil {
+			return exported, err
+		}
+		if state == nil {
+			continue
+		}
+		state.Key = key
+		if err := encoder.Encode(state); err != nil {
+			return exported, err
+		}
+		exported++
+	}
+	return exported, nil
+}
+
+// Restore writes the states of the snapshot to the state table of the function. It fails if the state
+// service does not know the function. It returns the number of restored keys.
+func Restore(ctx context.Context, client *Client, function Function, r io.Reader) (int, error) {
+	if err := client.CheckFunction(ctx, function); err != nil {
+		return 0, err
+	}
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 64*1024*1024)
+	restored := 0
+	for line := 1; scanner.Scan(); line++ {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+		state := &State{}
+		if err := json.Unmarshal(scanner.Bytes(), state); err != nil {
+			return restored, fmt.Errorf("invalid state at line %d of the snapshot: %w", line, err)
+		}
+		if state.Key == "" {
+			return restored, fmt.Errorf("missing key at line %d of the snapshot", line)
+		}
+		if err := client.Put(ctx, function, state); err != nil {
+			return restored, err
+		}
+		restored++
+	}
+	return restored, scanner.Err()
+}
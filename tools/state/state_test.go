@@ -0,0 +1,173 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package state
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/streamnative/pulsarctl/pkg/pulsar/common"
+	"github.com/stretchr/testify/assert"
+)
+
+// stubStateService serves the functions state API from memory
+type stubStateService struct {
+	lock      sync.Mutex
+	functions map[string]bool
+	states    map[string]map[string]json.RawMessage
+	auth      string
+}
+
+func (s *stubStateService) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if s.auth != "" && r.Header.Get("Authorization") != s.auth {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	path := strings.TrimPrefix(r.URL.EscapedPath(), "/admin/v3/functions/")
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	i := strings.Index(path, "/state/")
+	if i < 0 {
+		if r.Method != http.MethodGet || !s.functions[path] {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(`{}`))
+		return
+	}
+	function := path[:i]
+	key := path[i+len("/state/"):]
+	if !s.functions[function] {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	switch r.Method {
+	case http.MethodGet:
+		state, ok := s.states[function][key]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write(state)
+	case http.MethodPost:
+		if s.states[function] == nil {
+			s.states[function] = map[string]json.RawMessage{}
+		}
+		s.states[function][key] = json.RawMessage(r.FormValue("state"))
+		w.WriteHeader(http.StatusOK)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func TestExportAndRestore(t *testing.T) {
+	service := &stubStateService{
+		functions: map[string]bool{"public/default/word-count": true, "team-a/default/word-count": true},
+		states: map[string]map[string]json.RawMessage{"public/default/word-count": {
+			"apple":         json.RawMessage(`{"key":"apple","numberValue":3,"version":7}`),
+			"banana%20peel": json.RawMessage(`{"key":"banana peel","stringValue":"yellow","version":1}`),
+			"cherry":        json.RawMessage(`{"key":"cherry","byteValue":"AQI=","version":2}`),
+		}},
+		auth: "Bearer secret",
+	}
+	server := httptest.NewServer(service)
+	defer server.Close()
+	ctx := context.Background()
+
+	client, err := NewClient(&common.Config{WebServiceURL: server.URL,
+		AuthPlugin: "org.apache.pulsar.client.impl.auth.AuthenticationToken", AuthParams: "token:secret"})
+	assert.Nil(t, err)
+	source := Function{Tenant: "public", Namespace: "default", Name: "word-count"}
+	snapshot := &bytes.Buffer{}
+	exported, err := Export(ctx, client, source, []string{"apple", "banana peel", "cherry", "durian"}, snapshot)
+	assert.Nil(t, err)
+	assert.Equal(t, 3, exported)
+	assert.Equal(t, `{"key":"apple","numberValue":3}
+{"key":"banana peel","stringValue":"yellow"}
+{"key":"cherry","byteValue":"AQI="}
+`, snapshot.String())
+
+	target := Function{Tenant: "team-a", Namespace: "default", Name: "word-count"}
+	restored, err := Restore(ctx, client, target, bytes.NewReader(snapshot.Bytes()))
+	assert.Nil(t, err)
+	assert.Equal(t, 3, restored)
+	assert.JSONEq(t, `{"key":"banana peel","stringValue":"yellow"}`,
+		string(service.states["team-a/default/word-count"]["banana%20peel"]))
+	assert.JSONEq(t, `{"key":"apple","numberValue":3}`, string(service.states["team-a/default/word-count"]["apple"]))
+
+	_, err = Restore(ctx, client, target, strings.NewReader(`{"stringValue":"no key"}`))
+	assert.EqualError(t, err, "missing key at line 1 of the snapshot")
+
+	unknown := Function{Tenant: "public", Namespace: "default", Name: "unknown"}
+	snapshot.Reset()
+	_, err = Export(ctx, client, unknown, []string{"apple"}, snapshot)
+	assert.True(t, errors.Is(err, ErrFunctionNotFound))
+	assert.Empty(t, snapshot.String())
+	_, err = Restore(ctx, client, unknown, strings.NewReader(`{"key":"apple","numberValue":3}`))
+	assert.True(t, errors.Is(err, ErrFunctionNotFound))
+
+	client.HTTPClient = http.DefaultClient
+	_, err = Export(ctx, client, source, []string{"apple"}, snapshot)
+	assert.NotNil(t, err)
+}
+
+func TestStorage(t *testing.T) {
+	ctx := context.Background()
+	file := Storage{File: filepath.Join(t.TempDir(), "snapshots", "word-count.jsonl")}
+	assert.Nil(t, file.Write(ctx, []byte("{}\n")))
+	reader, err := file.Open(ctx)
+	assert.Nil(t, err)
+	data, err := ioutil.ReadAll(reader)
+	assert.Nil(t, err)
+	assert.Nil(t, reader.Close())
+	assert.Equal(t, "{}\n", string(data))
+
+	var object []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("signature") != "abc" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		if r.Method == http.MethodPut {
+			object, _ = ioutil.ReadAll(r.Body)
+			return
+		}
+		_, _ = w.Write(object)
+	}))
+	defer server.Close()
+	store := Storage{ObjectURL: server.URL + "/snapshots/word-count.jsonl?signature=abc"}
+	assert.Nil(t, store.Write(ctx, []byte("{}\n")))
+	reader, err = store.Open(ctx)
+	assert.Nil(t, err)
+	data, err = ioutil.ReadAll(reader)
+	assert.Nil(t, err)
+	assert.Nil(t, reader.Close())
+	assert.Equal(t, "{}\n", string(data))
+
+	_, err = Storage{ObjectURL: server.URL + "/snapshots/word-count.jsonl"}.Open(ctx)
+	assert.EqualError(t, err, "failed to download the snapshot: 403 Forbidden: ")
+}
@@ -0,0 +1,91 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package state
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+)
+
+// Storage holds a snapshot, either as a file or as an object uploaded with an HTTP PUT and downloaded with
+// an HTTP GET
+type Storage struct {
+	File      string
+	ObjectURL string
+}
+
+// Write stores the snapshot
+func (s Storage) Write(ctx context.Context, data []byte) error {
+	if s.File != "" {
+		if err := os.MkdirAll(filepath.Dir(s.File), 0755); err != nil {
+			return err
+		}
+		return ioutil.WriteFile(s.File, data, 0644)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, s.ObjectURL, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.ContentLength = int64(len(data))
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to upload the snapshot: %w", redactURL(err))
+	}
+	defer resp.Body.Close()
+	if err := checkResponse(resp); err != nil {
+		return fmt.Errorf("failed to upload the snapshot: %w", err)
+	}
+	return nil
+}
+
+// Open returns the content of the snapshot
+func (s Storage) Open(ctx context.Context) (io.ReadCloser, error) {
+	if s.File != "" {
+		return os.Open(s.File)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.ObjectURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download the snapshot: %w", redactURL(err))
+	}
+	if err := checkResponse(resp); err != nil {
+		resp.Body.Close()
+		return nil, fmt.Errorf("failed to download the snapshot: %w", err)
+	}
+	return resp.Body, nil
+}
+
+// redactURL drops the object URL from the request errors, the pre-signed URLs carry credentials
+func redactURL(err error) error {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		return urlErr.Err
+	}
+	return err
+}